@@ -0,0 +1,110 @@
+package tbdd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Causes of the cancellation of the context passed to an action by
+// GWTContext, WTContext, or WithActContext, as reported by context.Cause.
+var (
+	// ErrScenarioDone is the cause once the then phase of the scenario has
+	// completed.
+	ErrScenarioDone = errors.New("tbdd: scenario completed")
+	// ErrTestDeadline is the cause when the deadline of the go test -timeout
+	// flag is about to be reached.
+	ErrTestDeadline = errors.New("tbdd: test deadline reached")
+)
+
+// deadlineGrace is how long before the deadline of the test binary the
+// context of an action is cancelled, leaving time to report the failure
+// before go test panics.
+const deadlineGrace = time.Second
+
+// GWTContext is like GWT except whenF also receives a context which is
+// cancelled with the cause ErrScenarioDone once the then phase of the
+// scenario completes, or with ErrTestDeadline shortly before the deadline of
+// the go test -timeout flag.
+//
+// Goroutines and requests started by the action can observe the context to
+// stop once the scenario is over. When the scenario fails after its context
+// was cancelled, the cause is logged along with the failure.
+//
+// See GWT for more detail, including the panic conditions.
+func GWTContext[T, R any](
+	tc T,
+	given string, givenF func(*testing.T, *T),
+	when string, whenF func(context.Context, *testing.T, T) R,
+	then string, thenF func(*testing.T, T, R),
+) Lifecycle[T, R] {
+	return GWT(
+		tc,
+		given, givenF,
+		when, contextAct(whenF),
+		then, thenF,
+	)
+}
+
+// WTContext is a convenience wrapper around GWTContext for use when there is
+// no given context to convey.
+//
+// See GWT for more detail.
+func WTContext[T, R any](
+	tc T,
+	when string, whenF func(context.Context, *testing.T, T) R,
+	then string, thenF func(*testing.T, T, R),
+) Lifecycle[T, R] {
+	return GWTContext(
+		tc,
+		"", nil,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+// WithActContext sets the Act function to f, which receives a context as
+// described by GWTContext, without altering the When description.
+func WithActContext[T, R any](f func(context.Context, *testing.T, T) R) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Act = contextAct(f)
+	}
+}
+
+// contextAct adapts an action taking a context into an Act function,
+// preserving nil-ness so constructor validation still applies.
+func contextAct[T, R any](whenF func(context.Context, *testing.T, T) R) func(*testing.T, T) R {
+	if whenF == nil {
+		return nil
+	}
+
+	return func(t *testing.T, tc T) R {
+		return whenF(actContext(t), t, tc)
+	}
+}
+
+// actContext returns the context of an action run within t, cancelled once
+// t and its then subtest complete.
+//
+// It is not derived from t.Context, which is cancelled with a bare
+// context.Canceled before the cleanup reporting the cause runs.
+func actContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	stop := context.CancelFunc(func() {})
+	if deadline, ok := t.Deadline(); ok {
+		ctx, stop = context.WithDeadlineCause(ctx, deadline.Add(-deadlineGrace), ErrTestDeadline)
+	}
+
+	t.Cleanup(func() {
+		if t.Failed() && ctx.Err() != nil {
+			t.Logf("tbdd: the context of the action was cancelled before the scenario completed: %v", context.Cause(ctx))
+		}
+
+		cancel(ErrScenarioDone)
+		stop()
+	})
+
+	return ctx
+}
@@ -0,0 +1,75 @@
+package tbdd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGWTContext(t *testing.T) {
+	if os.Getenv("TBDD_ACTCTX_HELPER") == "1" {
+		WTContext(
+			struct{}{},
+			"the behavior outlives the test deadline", func(ctx context.Context, t *testing.T, _ struct{}) error {
+				<-ctx.Done()
+				return context.Cause(ctx)
+			},
+			"it is stopped", func(t *testing.T, _ struct{}, err error) {
+				t.Errorf("stopped: %v", err)
+			},
+		).New(t)(t)
+		return
+	}
+
+	var ctxs []context.Context
+	act := func(ctx context.Context, _ *testing.T, _ int) struct{} {
+		ctxs = append(ctxs, ctx)
+		return struct{}{}
+	}
+	thenF := func(t *testing.T, _ int, _ struct{}) {
+		if err := ctxs[len(ctxs)-1].Err(); err != nil {
+			t.Errorf("expected the context to be live during the then phase but got %v", err)
+		}
+	}
+
+	GWTContext(0, "g", func(*testing.T, *int) {}, "w", act, "t", thenF).New(t)(t)
+	WT(0, "w", func(*testing.T, int) struct{} { return struct{}{} }, "t", thenF).With(WithActContext[int, struct{}](act)).New(t)(t)
+
+	if len(ctxs) != 2 {
+		t.Fatalf("expected both actions to receive a context but got %d", len(ctxs))
+	}
+
+	for _, ctx := range ctxs {
+		if cause := context.Cause(ctx); !errors.Is(cause, ErrScenarioDone) {
+			t.Errorf("expected the context to be cancelled once the scenario completed but got %v", cause)
+		}
+	}
+
+	if contextAct[int, int](nil) != nil {
+		t.Error("expected a nil when function to stay nil")
+	}
+
+	//
+	// actions are cancelled ahead of the test deadline with the cause logged
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-test.timeout=1500ms")
+	cmd.Env = append(os.Environ(), "TBDD_ACTCTX_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"stopped: tbdd: test deadline reached",
+		"tbdd: the context of the action was cancelled before the scenario completed: tbdd: test deadline reached",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
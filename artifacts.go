@@ -0,0 +1,215 @@
+package tbdd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Artifacts is the sanctioned location for files a scenario produces, such
+// as logs, screenshots, or generated output. Each scenario run, including
+// every variant, has its own Artifacts.
+//
+// The directory is only created when Dir is first called. When the
+// -tbdd.artifacts flag names a root directory it is created at a stable path
+// below that root derived from the test name, variant Kind, and scenario ID;
+// otherwise it is a new temporary directory.
+//
+// Once the scenario completes the directory is removed if the scenario
+// passed and retained, with its path logged, if it failed or Keep was called.
+type Artifacts struct {
+	mu   sync.Mutex
+	root string
+	// test and prefix identify the scenario; they are only turned into a
+	// path when the directory is created.
+	test, prefix string
+	// id, when non-nil, returns the ID of the scenario as its directory is
+	// created, keeping apart the scenarios whose names sanitize alike.
+	id    func() string
+	dir   string
+	masks []Mask
+	// keep retains the directory even when the scenario passes.
+	keep bool
+}
+
+// Dir returns the path of the artifact directory, creating it on first use.
+// The test is failed via t.Fatalf if the directory cannot be created.
+func (a *Artifacts) Dir(t *testing.T) string {
+	t.Helper()
+
+	return a.create(t)
+}
+
+func (a *Artifacts) create(t assertT) string {
+	t.Helper()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.dir != "" {
+		return a.dir
+	}
+
+	if a.root == "" {
+		dir, err := os.MkdirTemp("", "tbdd-artifacts-")
+		if err != nil {
+			t.Fatalf("failed to create artifact directory: %v", err)
+			return ""
+		}
+
+		a.dir = dir
+		return dir
+	}
+
+	var id string
+	if a.id != nil {
+		id = a.id()
+	}
+
+	dir := filepath.Join(a.root, artifactsName(a.test, a.prefix, id))
+
+	// artifacts of previous runs must not be mistaken for those of this one
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("failed to clear artifact directory: %v", err)
+		return ""
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create artifact directory: %v", err)
+		return ""
+	}
+
+	a.dir = dir
+	return dir
+}
+
+// Keep retains the artifact directory once the scenario completes even if
+// it passes, such as when it holds evidence of a slow run.
+func (a *Artifacts) Keep() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.keep = true
+}
+
+// Masks returns the masking rules of the Lifecycle which ran the scenario.
+func (a *Artifacts) Masks() []Mask {
+	if a == nil {
+		return nil
+	}
+
+	return a.masks
+}
+
+// WriteJSON encodes v as indented JSON with the masks of the Lifecycle
+// applied and writes it to the file name within the artifact directory.
+// Strings, byte slices, and json.RawMessage values are treated as JSON
+// documents.
+//
+// The test is failed via t.Fatalf if v cannot be masked or written.
+func (a *Artifacts) WriteJSON(t *testing.T, name string, v any) {
+	t.Helper()
+
+	a.writeJSON(t, name, v)
+}
+
+func (a *Artifacts) writeJSON(t assertT, name string, v any) {
+	t.Helper()
+
+	b, err := maskAny(v, a.masks)
+	if err != nil {
+		t.Fatalf("failed to mask artifact %s: %v", name, err)
+		return
+	}
+
+	dir := a.create(t)
+	if dir == "" {
+		return
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), append(b, '\n'), 0o644); err != nil {
+		t.Fatalf("failed to write artifact %s: %v", name, err)
+	}
+}
+
+// finalizeT is the subset of *testing.T that artifact finalization depends on.
+type finalizeT interface {
+	Failed() bool
+	Logf(format string, args ...any)
+}
+
+// finalize removes the artifact directory when t passed and it was not
+// kept, and logs where it was retained otherwise.
+func (a *Artifacts) finalize(t finalizeT) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.dir == "" {
+		return
+	}
+
+	if t.Failed() || a.keep {
+		t.Logf("tbdd: artifacts retained in %s", a.dir)
+		return
+	}
+
+	_ = os.RemoveAll(a.dir)
+}
+
+// newArtifacts returns the Artifacts of a scenario run below the test named
+// testName with the given subtest prefix, masking persisted JSON with masks.
+func newArtifacts(testName, prefix string, masks []Mask) *Artifacts {
+	return &Artifacts{root: config.artifacts, test: testName, prefix: prefix, masks: masks}
+}
+
+// artifactsName returns the relative path of the artifact directory of the
+// scenario with the given ID run below the test named testName with the
+// given subtest prefix. The ID, when non-empty, suffixes the last segment.
+func artifactsName(testName, prefix, id string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		prefix = "basis"
+	}
+
+	var segments []string
+	for _, s := range strings.Split(testName+"/"+prefix, "/") {
+		segments = append(segments, sanitizePathSegment(s))
+	}
+
+	if id != "" {
+		segments[len(segments)-1] += "-" + id
+	}
+
+	return filepath.Join(segments...)
+}
+
+// trackArtifacts registers the finalization of a with the scenario's
+// outermost subtest t.
+func trackArtifacts(t *testing.T, a *Artifacts) {
+	if t == nil {
+		return
+	}
+
+	t.Cleanup(func() {
+		a.finalize(t)
+	})
+}
+
+func sanitizePathSegment(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+
+		return '_'
+	}, s)
+
+	if s == "" || s == "." || s == ".." {
+		return "_"
+	}
+
+	return s
+}
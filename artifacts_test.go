@@ -0,0 +1,211 @@
+package tbdd
+
+import (
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var _ finalizeT = (*testing.T)(nil)
+
+func TestArtifacts(t *testing.T) {
+	var dirs []string
+
+	b := GWTN(
+		"basis",
+		"a scenario producing files", func(*testing.T, *string) {},
+		"it writes output", func(*testing.T, string) {},
+		"the output is in the artifact directory", func(*testing.T, string) {},
+	).With(
+		WithHooks(Hooks[string, struct{}]{
+			AfterAssert: func(t *testing.T, cfg AfterAssert[string, struct{}]) {
+				dir := cfg.Artifacts.Dir(t)
+				if dir != cfg.Artifacts.Dir(t) {
+					t.Error("expected the directory to be created once")
+				}
+
+				if err := os.WriteFile(filepath.Join(dir, "out.txt"), []byte(*cfg.TC), 0o644); err != nil {
+					t.Fatal(err)
+				}
+
+				dirs = append(dirs, dir)
+			},
+		}),
+		WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+			return func(yield func(TestVariant[string]) bool) {
+				yield(TestVariant[string]{Kind: "odd kind/..", TC: "variant"})
+			}
+		}),
+	)
+
+	f := b.New(t)
+	f(t)
+
+	if len(dirs) != 2 || dirs[0] == dirs[1] {
+		t.Fatalf("expected a distinct artifact directory per scenario but got %v", dirs)
+	}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected artifacts of a passing scenario to be removed: %s", dir)
+		}
+	}
+
+	//
+	// artifacts below a configured root
+	//
+
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.artifacts = t.TempDir()
+	dirs = nil
+
+	f = b.New(t)
+	f(t)
+
+	sentence := "given a scenario producing files when it writes output then the output is in the artifact directory"
+	exp := []string{
+		filepath.Join(config.artifacts, "TestArtifacts", "basis-"+scenarioID(t.Name(), "", sentence)),
+		filepath.Join(config.artifacts, "TestArtifacts", "odd_kind", "_-"+scenarioID(t.Name(), "", "odd kind/..: "+sentence)),
+	}
+	if len(dirs) != 2 || dirs[0] != exp[0] || dirs[1] != exp[1] {
+		t.Errorf("expected artifact directories %v but got %v", exp, dirs)
+	}
+}
+
+func TestArtifacts_collisions(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.artifacts = t.TempDir()
+
+	// every scenario keeps a file in its directory, which another scenario
+	// sharing the directory would clear
+	var files []string
+	keep := WithHooks(Hooks[string, struct{}]{
+		AfterAssert: func(t *testing.T, cfg AfterAssert[string, struct{}]) {
+			cfg.Artifacts.Keep()
+
+			file := filepath.Join(cfg.Artifacts.Dir(t), "out.txt")
+			if err := os.WriteFile(file, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			files = append(files, file)
+		},
+	})
+	variants := WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+		return func(yield func(TestVariant[string]) bool) {
+			_ = yield(TestVariant[string]{Kind: "a b"}) && yield(TestVariant[string]{Kind: "a_b"})
+		}
+	})
+
+	for _, then := range []string{"it keeps its files", "it keeps its other files"} {
+		f := WTN("", "a scenario runs", func(*testing.T, string) {}, then, func(*testing.T, string) {}).With(keep, variants).New(t)
+		f(t)
+	}
+
+	dirs := map[string]bool{}
+	for _, file := range files {
+		dirs[filepath.Dir(file)] = true
+
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("expected the artifacts of each scenario to be retained: %v", err)
+		}
+	}
+
+	if len(files) != 6 || len(dirs) != len(files) {
+		t.Errorf("expected a distinct artifact directory per scenario but got %v", files)
+	}
+}
+
+type mFinalizeT struct {
+	failed bool
+	logs   []string
+}
+
+func (t *mFinalizeT) Failed() bool {
+	return t.failed
+}
+
+func (t *mFinalizeT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, format)
+}
+
+func TestArtifacts_finalize(t *testing.T) {
+	t.Parallel()
+
+	a := &Artifacts{root: t.TempDir(), test: "kept"}
+	dir := a.create(&mT{})
+
+	mt := &mFinalizeT{failed: true}
+	a.finalize(mt)
+
+	if _, err := os.Stat(dir); err != nil || len(mt.logs) != 1 || !strings.HasPrefix(mt.logs[0], "tbdd: artifacts retained") {
+		t.Errorf("expected artifacts of a failed scenario to be retained and logged: %v %v", err, mt.logs)
+	}
+
+	// finalizing before the directory is used is a no-op
+	(&Artifacts{}).finalize(mt)
+
+	// artifacts are not tracked without a real *testing.T
+	trackArtifacts(nil, a)
+}
+
+func TestArtifacts_createErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		a   *Artifacts
+		exp string
+	}{
+		{&Artifacts{root: file, test: "x"}, "failed to clear artifact directory: %v"},
+	} {
+		mt := &mT{}
+		if dir := v.a.create(mt); dir != "" || len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %q %v", v.exp, dir, mt.fatalfCalls)
+		}
+	}
+
+	t.Setenv("TMPDIR", filepath.Join(file, "missing"))
+
+	mt := &mT{}
+	if dir := (&Artifacts{}).create(mt); dir != "" || len(mt.fatalfCalls) != 1 {
+		t.Errorf("expected a temporary directory failure but got %q %v", dir, mt.fatalfCalls)
+	}
+}
+
+func TestArtifacts_writeJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		a    *Artifacts
+		name string
+		v    any
+		exp  string
+	}{
+		{&Artifacts{masks: []Mask{{Path: "X"}}}, "a.json", `{}`, "failed to mask artifact %s: %v"},
+		{&Artifacts{root: file, test: "x"}, "a.json", `{}`, "failed to clear artifact directory: %v"},
+		{&Artifacts{dir: file}, "a.json", `{}`, "failed to write artifact %s: %v"},
+	} {
+		mt := &mT{}
+		if v.a.writeJSON(mt, v.name, v.v); len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
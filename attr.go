@@ -0,0 +1,51 @@
+package tbdd
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// emitAttrs emits the ID, variant Kind, sentence, and metadata of r as test
+// attributes of t, the outermost subtest of the scenario, so that consumers
+// of go test -json can read them:
+//
+//	tbdd.id        the stable scenario ID
+//	tbdd.scenario  the scenario sentence
+//	tbdd.kind      the variant Kind, omitted for the basis test case
+//	tbdd.source    the file:line defining the scenario, when known
+//	tbdd.meta.KEY  each metadata value
+//
+// The Class of the scenario is emitted as tbdd.class once it completes, along
+// with the Doubles it injected, if any, as tbdd.doubles.
+func emitAttrs(t *testing.T, r *ScenarioResult) {
+	if t == nil {
+		return
+	}
+
+	t.Attr("tbdd.id", r.ID)
+	t.Attr("tbdd.scenario", attrValue(r.Scenario()))
+
+	if r.Kind != "" {
+		t.Attr("tbdd.kind", attrValue(r.Kind))
+	}
+
+	if r.Source != "" {
+		t.Attr("tbdd.source", attrValue(r.Source))
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(r.Meta)) {
+		t.Attr("tbdd.meta."+attrKey(k), attrValue(r.Meta[k]))
+	}
+}
+
+// attrKey replaces the whitespace test attribute keys must not contain.
+func attrKey(s string) string {
+	return strings.Join(strings.Fields(s), "_")
+}
+
+// attrValue replaces the line breaks test attribute values must not contain.
+func attrValue(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s)
+}
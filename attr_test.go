@@ -0,0 +1,127 @@
+package tbdd
+
+import (
+	"iter"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_attrs(t *testing.T) {
+	if os.Getenv("TBDD_ATTR_HELPER") == "1" {
+		b := GWTN(
+			"basis",
+			"a user", func(*testing.T, *string) {},
+			"they log in", func(*testing.T, string) {},
+			"they see their\ndashboard", func(*testing.T, string) {},
+		).With(
+			WithMeta[string, struct{}]("requirement id", "REQ-7"),
+			WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+				return func(yield func(TestVariant[string]) bool) {
+					yield(TestVariant[string]{Kind: "admin", TC: "admin"})
+				}
+			}),
+		)
+
+		f := b.New(t)
+		f(t)
+
+		f = WTN(
+			struct{}{},
+			"the service starts", func(*testing.T, struct{}) {},
+			"it is healthy", func(*testing.T, struct{}) {},
+		).New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_ATTR_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected the helper test to pass: %v\n%s", err, b)
+	}
+
+	out := string(b)
+
+	_, file, _, _ := runtime.Caller(0)
+
+	given := t.Name() + "/given_a_user"
+	admin := t.Name() + "/admin/given_a_user"
+	when := t.Name() + "/when_the_service_starts"
+
+	for _, exp := range []string{
+		"=== ATTR  " + given + " tbdd.id " + scenarioID(t.Name(), "", "given a user when they log in then they see their\ndashboard") + "\n",
+		"=== ATTR  " + given + " tbdd.scenario given a user when they log in then they see their dashboard\n",
+		"=== ATTR  " + given + " tbdd.meta.requirement_id REQ-7\n",
+		"=== ATTR  " + admin + " tbdd.kind admin\n",
+		"=== ATTR  " + admin + " tbdd.id " + scenarioID(t.Name(), "", "admin: given a user when they log in then they see their\ndashboard") + "\n",
+		"=== ATTR  " + when + " tbdd.scenario when the service starts then it is healthy\n",
+		"=== ATTR  " + given + " tbdd.source " + file + ":",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	if strings.Contains(out, "=== ATTR  "+given+" tbdd.kind") {
+		t.Errorf("expected no kind attribute for the basis test case:\n%s", out)
+	}
+}
+
+func Test_scenarioID(t *testing.T) {
+	t.Parallel()
+
+	id := scenarioID("TestX", "1/", "when a then b")
+	if len(id) != 12 || id != scenarioID("TestX", "1/", "when a then b") {
+		t.Errorf("expected a stable 12 character ID but got '%s'", id)
+	}
+
+	for _, other := range []string{
+		scenarioID("TestY", "1/", "when a then b"),
+		scenarioID("TestX", "2/", "when a then b"),
+		scenarioID("TestX", "1/", "when a then c"),
+	} {
+		if other == id {
+			t.Errorf("expected distinct scenarios to have distinct IDs but both are '%s'", id)
+		}
+	}
+
+	// IDs built from results hash their Scenario sentences
+	for _, r := range []ScenarioResult{
+		{When: "a", Then: "b"},
+		{Given: "g", When: "a", Then: "b"},
+		{Given: "g", When: "a", Then: "b", Kind: "k"},
+		{When: "a", Then: "b", Kind: "k"},
+	} {
+		if id, exp := resultID("TestX", "1/", &r), scenarioID("TestX", "1/", r.Scenario()); id != exp {
+			t.Errorf("expected the ID '%s' of %+v but got '%s'", exp, r, id)
+		}
+	}
+
+	// attributes are not emitted without a real *testing.T
+	emitAttrs(nil, &ScenarioResult{})
+}
+
+func BenchmarkScenarioID(b *testing.B) {
+	r := ScenarioResult{Given: "a user", When: "they log in", Then: "they see their dashboard", Kind: "variant 12345"}
+
+	// sentence builds the Scenario sentence as a string before hashing it,
+	// which the pooled input of resultID avoids
+	b.Run("sentence", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			scenarioID("TestBenchmark/table", "7/", r.Scenario())
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			resultID("TestBenchmark/table", "7/", &r)
+		}
+	})
+}
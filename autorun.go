@@ -0,0 +1,71 @@
+package tbdd
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestFactory is anything Register accepts: a Lifecycle, a Group, or any
+// other Scenario.
+type TestFactory = Scenario
+
+type registration struct {
+	name    string
+	factory TestFactory
+}
+
+// registered holds every TestFactory passed to Register by the process.
+var registered struct {
+	mu   sync.Mutex
+	list []registration
+}
+
+// Register records factory so that RunRegistered will execute it, then
+// returns factory unchanged. It is safe to call from init functions and
+// package level variable declarations, which keeps scenarios next to the
+// code they describe:
+//
+//	var _ = tbdd.Register(tbdd.GWT(
+//		// ...
+//	))
+//
+// Each registered factory runs as a subtest named after the file and line of
+// the Register call.
+func Register[F TestFactory](factory F) F {
+	name := "unknown"
+	if _, file, line, ok := runtime.Caller(1); ok {
+		name = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+
+	registered.list = append(registered.list, registration{name, factory})
+
+	return factory
+}
+
+// RunRegistered executes every TestFactory passed to Register so far, in
+// registration order, each as its own subtest of t.
+//
+// A test binary holds a single package's tests, so a single
+//
+//	func TestRegistered(t *testing.T) {
+//		tbdd.RunRegistered(t)
+//	}
+//
+// runs every scenario registered by that package.
+func RunRegistered(t *testing.T) {
+	t.Helper()
+
+	registered.mu.Lock()
+	list := append([]registration(nil), registered.list...)
+	registered.mu.Unlock()
+
+	for _, r := range list {
+		t.Run(r.name, r.factory.New(t))
+	}
+}
@@ -0,0 +1,42 @@
+package tbdd
+
+import (
+	"strings"
+	"testing"
+)
+
+var registeredRuns []string
+
+var registeredLifecycle = Register(WTN(
+	"registered",
+	"a scenario is declared at file scope", func(_ *testing.T, tc string) {
+		registeredRuns = append(registeredRuns, tc)
+	},
+	"RunRegistered executes it", func(*testing.T, string) {},
+))
+
+func TestRunRegistered(t *testing.T) {
+	if registeredLifecycle.TC != "registered" {
+		t.Fatal("expected Register to return its argument unchanged")
+	}
+
+	var names []string
+	registered.mu.Lock()
+	for _, r := range registered.list {
+		names = append(names, r.name)
+	}
+	registered.mu.Unlock()
+
+	if len(names) != 1 || !strings.HasPrefix(names[0], "autorun_test.go:") {
+		t.Errorf("expected one registration named after its source location but got %v", names)
+	}
+
+	// only the runs of this test count, as -count runs it again
+	registeredRuns = nil
+
+	RunRegistered(t)
+
+	if strings.Join(registeredRuns, ",") != "registered" {
+		t.Errorf("expected the registered scenario to run once but got %v", registeredRuns)
+	}
+}
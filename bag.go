@@ -0,0 +1,60 @@
+package tbdd
+
+import "sync"
+
+// Bag is mutable storage scoped to a single scenario run. The same Bag is
+// passed to every hook payload of the scenario, so a hook can stash values,
+// such as timers or captured state, for a later hook without adding fields to
+// the test case.
+//
+// Values are read and written through typed keys created with NewKey.
+type Bag struct {
+	mu sync.Mutex
+	m  map[any]any
+}
+
+// Key identifies a value of type V in a Bag. Keys are compared by identity,
+// so two keys created with the same name are distinct.
+type Key[V any] struct {
+	name string
+}
+
+// NewKey returns a new key for values of type V. The name is only used for
+// diagnostics.
+func NewKey[V any](name string) *Key[V] {
+	return &Key[V]{name}
+}
+
+// String returns the name of the key.
+func (k *Key[V]) String() string {
+	return k.name
+}
+
+// Get returns the value stored for k in b and whether it is present.
+func (k *Key[V]) Get(b *Bag) (V, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	v, ok := b.m[k].(V)
+	return v, ok
+}
+
+// Set stores v for k in b, replacing any previous value.
+func (k *Key[V]) Set(b *Bag, v V) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.m == nil {
+		b.m = map[any]any{}
+	}
+
+	b.m[k] = v
+}
+
+// Delete removes any value stored for k in b.
+func (k *Key[V]) Delete(b *Bag) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.m, k)
+}
@@ -0,0 +1,79 @@
+package tbdd
+
+import (
+	"iter"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	k := NewKey[int]("count")
+	if k.String() != "count" {
+		t.Errorf("expected key name 'count' but got '%s'", k.String())
+	}
+
+	var b Bag
+	if _, ok := k.Get(&b); ok {
+		t.Error("expected an empty bag to have no value")
+	}
+
+	k.Set(&b, 3)
+	if v, ok := k.Get(&b); !ok || v != 3 {
+		t.Errorf("expected 3 but got %d (%v)", v, ok)
+	}
+
+	if _, ok := NewKey[int]("count").Get(&b); ok {
+		t.Error("expected keys to be compared by identity")
+	}
+
+	k.Delete(&b)
+	if _, ok := k.Get(&b); ok {
+		t.Error("expected the value to be deleted")
+	}
+}
+
+func TestBag_sharedByHooks(t *testing.T) {
+	kind := NewKey[string]("kind")
+
+	var seen []string
+	var bags []*Bag
+
+	f := WTN(
+		"basis",
+		"it acts", func(*testing.T, string) {},
+		"it asserts", func(*testing.T, string) {},
+	).With(
+		WithHooks(Hooks[string, struct{}]{
+			AfterArrange: func(_ *testing.T, cfg AfterArrange[string]) {
+				if _, ok := kind.Get(cfg.Bag); ok {
+					t.Error("expected every scenario to start with an empty bag")
+				}
+
+				bags = append(bags, cfg.Bag)
+			},
+			AfterGiven: func(_ *testing.T, cfg AfterGiven[string]) {
+				kind.Set(cfg.Bag, *cfg.TC)
+			},
+			AfterAct: func(_ *testing.T, cfg AfterAct[string, struct{}]) {
+				if cfg.Bag != bags[len(bags)-1] {
+					t.Error("expected the same bag for every hook of a scenario")
+				}
+			},
+			AfterAssert: func(_ *testing.T, cfg AfterAssert[string, struct{}]) {
+				v, _ := kind.Get(cfg.Bag)
+				seen = append(seen, v)
+			},
+		}),
+		WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+			return func(yield func(TestVariant[string]) bool) {
+				yield(TestVariant[string]{Kind: "variant", TC: "variant"})
+			}
+		}),
+	).New(t)
+	f(t)
+
+	if len(seen) != 2 || seen[0] != "basis" || seen[1] != "variant" || bags[0] == bags[1] {
+		t.Errorf("expected a distinct bag per scenario but saw %v", seen)
+	}
+}
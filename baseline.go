@@ -0,0 +1,188 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Measurement is the cost of one Act as recorded in a BaselineFile.
+type Measurement struct {
+	Duration time.Duration
+	// Allocs and Bytes are the number of heap objects and bytes allocated.
+	Allocs, Bytes uint64
+}
+
+// BaselineTolerance is the fraction by which a measurement may exceed its
+// baseline before the scenario fails, such as 0.25 for 25%. A negative
+// tolerance disables the comparison.
+type BaselineTolerance struct {
+	Duration, Allocs, Bytes float64
+}
+
+// BaselineFile records the Act measurements of scenarios from lifecycles
+// configured with WithBaseline and compares them against those stored in a
+// JSON file, usually below testdata, keyed by the name of the subtest which
+// ran the Act.
+type BaselineFile struct {
+	path string
+
+	mu       sync.Mutex
+	loaded   bool
+	err      error
+	baseline map[string]Measurement
+	measured map[string]Measurement
+}
+
+// NewBaselineFile returns a BaselineFile stored at path.
+func NewBaselineFile(path string) *BaselineFile {
+	return &BaselineFile{path: path, measured: map[string]Measurement{}}
+}
+
+// WithBaseline measures the Act of every scenario of the Lifecycle, which
+// implies MemStats, and fails the scenario when a measurement exceeds its
+// baseline in f by more than tol.
+//
+// Only Acts which did not fail are measured. When the -tbdd.update-golden
+// flag is set nothing is compared; the measurements are written by
+// BaselineFile.Check instead.
+func WithBaseline[T, R any](f *BaselineFile, tol BaselineTolerance) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.MemStats = true
+		b.baseline = func(t *testing.T, elapsed time.Duration, mem *MemDelta) {
+			t.Helper()
+
+			m := Measurement{elapsed, mem.Mallocs, mem.TotalAlloc}
+			for _, msg := range f.measure(t.Name(), m, tol) {
+				t.Error(msg)
+			}
+		}
+	}
+}
+
+// measure records m as the measurement of the scenario named name and
+// returns a message for each way it regressed beyond tol.
+func (f *BaselineFile) measure(name string, m Measurement, tol BaselineTolerance) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.measured[name] = m
+
+	if config.updateGolden {
+		return nil
+	}
+
+	if err := f.load(); err != nil {
+		return []string{fmt.Sprintf("failed to read baseline file %s: %v", f.path, err)}
+	}
+
+	want, ok := f.baseline[name]
+	if !ok {
+		return nil
+	}
+
+	var msgs []string
+	if exceeds(float64(m.Duration), float64(want.Duration), tol.Duration) {
+		msgs = append(msgs, fmt.Sprintf("Act took %s, more than %g%% over its baseline of %s in %s", m.Duration, tol.Duration*100, want.Duration, f.path))
+	}
+	if exceeds(float64(m.Allocs), float64(want.Allocs), tol.Allocs) {
+		msgs = append(msgs, fmt.Sprintf("Act made %d allocations, more than %g%% over its baseline of %d in %s", m.Allocs, tol.Allocs*100, want.Allocs, f.path))
+	}
+	if exceeds(float64(m.Bytes), float64(want.Bytes), tol.Bytes) {
+		msgs = append(msgs, fmt.Sprintf("Act allocated %d bytes, more than %g%% over its baseline of %d in %s", m.Bytes, tol.Bytes*100, want.Bytes, f.path))
+	}
+
+	return msgs
+}
+
+func exceeds(got, want, tol float64) bool {
+	return tol >= 0 && got > want*(1+tol)
+}
+
+// load reads the stored baseline once, treating a missing file as empty.
+// f.mu must be held.
+func (f *BaselineFile) load() error {
+	if f.loaded {
+		return f.err
+	}
+
+	f.loaded = true
+	f.baseline = map[string]Measurement{}
+
+	b, err := os.ReadFile(f.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		f.err = err
+	default:
+		f.err = json.Unmarshal(b, &f.baseline)
+	}
+
+	return f.err
+}
+
+// Check should be called once every lifecycle contributing measurements
+// has run. It fails the test when scenarios which ran have no baseline in
+// the file.
+//
+// When the -tbdd.update-golden flag is set the measurements are written
+// to the file instead, keeping the baselines of scenarios which did not run.
+func (f *BaselineFile) Check(t *testing.T) {
+	t.Helper()
+
+	f.check(t)
+}
+
+func (f *BaselineFile) check(t goldenT) {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		t.Fatalf("failed to read baseline file %s: %v", f.path, err)
+		return
+	}
+
+	if config.updateGolden {
+		merged := maps.Clone(f.baseline)
+		maps.Copy(merged, f.measured)
+
+		// a map of measurements cannot fail to encode
+		b, _ := json.MarshalIndent(merged, "", "  ")
+
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			t.Fatalf("failed to create baseline directory: %v", err)
+			return
+		}
+
+		if err := os.WriteFile(f.path, append(b, '\n'), 0o644); err != nil {
+			t.Fatalf("failed to update baseline file %s: %v", f.path, err)
+			return
+		}
+
+		t.Logf("updated baseline file %s", f.path)
+		RecordUpdate("baseline", f.path)
+		return
+	}
+
+	var missing []string
+	for _, name := range slices.Sorted(maps.Keys(f.measured)) {
+		if _, ok := f.baseline[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		t.Fatalf("baseline file %s has no measurements of:\n\t%s\nrun with -tbdd.update-golden to record them", f.path, strings.Join(missing, "\n\t"))
+	}
+}
@@ -0,0 +1,178 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBaselineFile(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	path := filepath.Join(t.TempDir(), "testdata", "baseline.json")
+	baseline := NewBaselineFile(path)
+
+	b := WTN(
+		struct{}{},
+		"the behavior allocates", func(*testing.T, struct{}) {
+			memSink = append(memSink, make([]byte, 64))
+			memSink = nil
+		},
+		"it is measured", func(*testing.T, struct{}) {},
+	).With(WithBaseline[struct{}, struct{}](baseline, BaselineTolerance{-1, -1, -1}))
+
+	if !b.MemStats {
+		t.Fatal("expected a baseline to measure memory statistics")
+	}
+
+	f := b.New(t)
+	f(t)
+
+	name := t.Name() + "/when_the_behavior_allocates"
+	if m, ok := baseline.measured[name]; !ok || m.Duration <= 0 || m.Allocs == 0 || m.Bytes < 64 {
+		t.Fatalf("expected the Act to be measured but got %+v", baseline.measured)
+	}
+
+	//
+	// scenarios without a baseline fail the check
+	//
+
+	mt := &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].args[1] != name {
+		t.Errorf("expected the scenario to have no baseline but got %v", mt.fatalfCalls)
+	}
+
+	//
+	// updating writes the measurements while keeping other baselines
+	//
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"TestOther": {"Duration": 5, "Allocs": 1, "Bytes": 2}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config.updateGolden = true
+	baseline = NewBaselineFile(path)
+	baseline.measured[name] = Measurement{time.Millisecond, 10, 100}
+
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 0 || !slices.Equal(mt.logs, []string{"updated baseline file " + path}) || !slices.Contains(Updates(), Update{"baseline", path}) {
+		t.Fatalf("expected the baseline file to be updated: %v %v", mt.fatalfCalls, mt.logs)
+	}
+
+	var stored map[string]Measurement
+	if b, err := os.ReadFile(path); err != nil || json.Unmarshal(b, &stored) != nil {
+		t.Fatalf("expected a valid baseline file: %v", err)
+	}
+
+	exp := map[string]Measurement{"TestOther": {5, 1, 2}, name: {time.Millisecond, 10, 100}}
+	if !reflect.DeepEqual(stored, exp) {
+		t.Errorf("expected baselines %v but got %v", exp, stored)
+	}
+
+	// nothing is compared while updating
+	if msgs := baseline.measure(name, Measurement{time.Hour, 1000, 1000}, BaselineTolerance{}); msgs != nil {
+		t.Errorf("expected no comparison while updating but got %q", msgs)
+	}
+
+	//
+	// measurements are compared against their baselines
+	//
+
+	config.updateGolden = false
+	baseline = NewBaselineFile(path)
+
+	if msgs := baseline.measure("TestNew", Measurement{time.Hour, 1000, 1000}, BaselineTolerance{}); msgs != nil {
+		t.Errorf("expected scenarios without a baseline to pass but got %q", msgs)
+	}
+
+	tol := BaselineTolerance{Duration: 0.5, Allocs: 0.1, Bytes: -1}
+	if msgs := baseline.measure(name, Measurement{1500 * time.Microsecond, 11, 1000}, tol); msgs != nil {
+		t.Errorf("expected measurements within tolerance to pass but got %q", msgs)
+	}
+
+	msgs := baseline.measure(name, Measurement{2 * time.Millisecond, 12, 1000}, tol)
+	expMsgs := []string{
+		"Act took 2ms, more than 50% over its baseline of 1ms in " + path,
+		"Act made 12 allocations, more than 10% over its baseline of 10 in " + path,
+	}
+	if !slices.Equal(msgs, expMsgs) {
+		t.Errorf("expected %q but got %q", expMsgs, msgs)
+	}
+
+	msgs = baseline.measure(name, Measurement{Bytes: 101}, BaselineTolerance{})
+	if !slices.Equal(msgs, []string{"Act allocated 101 bytes, more than 0% over its baseline of 100 in " + path}) {
+		t.Errorf("expected the bytes to regress but got %q", msgs)
+	}
+
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || !strings.Contains(mt.fatalfCalls[0].args[1].(string), "TestNew") {
+		t.Errorf("expected only the new scenario to have no baseline but got %v", mt.fatalfCalls)
+	}
+
+	//
+	// invalid baseline files fail
+	//
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline = NewBaselineFile(path)
+	if msgs := baseline.measure(name, Measurement{}, BaselineTolerance{}); len(msgs) != 1 || !strings.HasPrefix(msgs[0], "failed to read baseline file "+path+": ") {
+		t.Errorf("expected the file to be unreadable but got %q", msgs)
+	}
+
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "failed to read baseline file %s: %v" {
+		t.Errorf("expected the file to be unreadable but got %v", mt.fatalfCalls)
+	}
+
+	//
+	// failures to write are reported
+	//
+
+	config.updateGolden = true
+
+	blocked := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline = NewBaselineFile(filepath.Join(blocked, "baseline.json"))
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "failed to read baseline file %s: %v" {
+		t.Errorf("expected the file below a file to be unreadable but got %v", mt.fatalfCalls)
+	}
+
+	baseline = NewBaselineFile(filepath.Join(blocked, "dir", "baseline.json"))
+	baseline.loaded = true
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "failed to create baseline directory: %v" {
+		t.Errorf("expected the directory to not be created but got %v", mt.fatalfCalls)
+	}
+
+	baseline = NewBaselineFile(t.TempDir())
+	baseline.loaded = true
+	mt = &mGoldenT{}
+	baseline.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "failed to update baseline file %s: %v" {
+		t.Errorf("expected a directory to not be writable but got %v", mt.fatalfCalls)
+	}
+}
@@ -0,0 +1,34 @@
+package tbdd
+
+import (
+	"testing"
+	"time"
+)
+
+// skipOverBudget skips t, classifying sr accordingly, once the deadline set
+// by the -tbdd.budget flag has passed, so the scenarios which have not
+// started by then are reported as not run rather than the run timing out
+// within one of them. A nil t is a no-op.
+func skipOverBudget(t *testing.T, sr *scenario) {
+	if config.deadline.IsZero() || t == nil || time.Now().Before(config.deadline) {
+		return
+	}
+
+	t.Helper()
+
+	sr.class = ClassSkippedBudget
+	skip(t, "not run due to budget: the -tbdd.budget of "+config.budget.String()+" was spent")
+}
+
+// overBudget returns how many of rs were not run due to the -tbdd.budget
+// flag.
+func overBudget(rs []ScenarioResult) int {
+	var n int
+	for _, r := range rs {
+		if r.Class == ClassSkippedBudget {
+			n++
+		}
+	}
+
+	return n
+}
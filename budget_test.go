@@ -0,0 +1,62 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	if os.Getenv("TBDD_BUDGET_HELPER") == "1" {
+		WTN(0, "a slow scenario runs", func(*testing.T, int) {
+			time.Sleep(300 * time.Millisecond)
+		}, "it finishes", func(*testing.T, int) {}).New(t)(t)
+
+		GWTN(0, "the budget is spent", func(*testing.T, *int) {}, "another scenario starts", func(*testing.T, int) {
+			t.Error("expected the scenario not to run")
+		}, "it is not run", func(*testing.T, int) {}).New(t)(t)
+		return
+	}
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.budget=200ms", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_BUDGET_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected the helper test to pass: %v\n%s", err, out)
+	}
+
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/when_a_slow_scenario_runs/then_it_finishes ",
+		"--- SKIP: " + t.Name() + "/given_the_budget_is_spent ",
+		"not run due to budget: the -tbdd.budget of 200ms was spent",
+		"tbdd: 1 scenarios not run due to the -tbdd.budget of 200ms\n",
+	} {
+		True(t, strings.Contains(string(out), exp))
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ Class, SkipPhase string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	Equal(t, len(r.Results), 2)
+	if len(r.Results) == 2 {
+		Equal(t, r.Results[0].Class, "passed")
+		Equal(t, r.Results[1].Class, "skipped-budget")
+		Equal(t, r.Results[1].SkipPhase, "given")
+	}
+}
@@ -0,0 +1,138 @@
+package tbdd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ElementsMatch fails the test unless got holds the same elements as want,
+// with the same multiplicity, in any order. The failure lists every missing
+// and unexpected element.
+func ElementsMatch[E comparable](t *testing.T, want, got []E) {
+	t.Helper()
+
+	elementsMatch(linkedT{T: t}, want, got)
+}
+
+// IsSortedBy fails the test unless s is sorted according to cmp, which
+// returns a negative number when a sorts before b and a positive number when
+// it sorts after. The failure identifies the first pair out of order.
+func IsSortedBy[E any](t *testing.T, s []E, cmp func(a, b E) int) {
+	t.Helper()
+
+	isSortedBy(linkedT{T: t}, s, cmp)
+}
+
+// ContainsAll fails the test unless s contains every element of want. The
+// failure lists every missing element.
+func ContainsAll[E comparable](t *testing.T, s []E, want ...E) {
+	t.Helper()
+
+	containsAll(linkedT{T: t}, s, want)
+}
+
+// Unique fails the test if any element of s appears more than once. The
+// failure lists every duplicated element along with its indexes.
+func Unique[E comparable](t *testing.T, s []E) {
+	t.Helper()
+
+	unique(linkedT{T: t}, s)
+}
+
+func elementsMatch[E comparable](t assertT, want, got []E) {
+	t.Helper()
+
+	counts := make(map[E]int, len(want))
+	for _, e := range want {
+		counts[e]++
+	}
+
+	var extra []E
+	for _, e := range got {
+		if counts[e] == 0 {
+			extra = append(extra, e)
+			continue
+		}
+
+		counts[e]--
+	}
+
+	var missing []E
+	for _, e := range want {
+		if counts[e] > 0 {
+			counts[e]--
+			missing = append(missing, e)
+		}
+	}
+
+	if len(missing) == 0 && len(extra) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	for _, e := range missing {
+		fmt.Fprintf(&sb, "\n\t- %#v", e)
+	}
+	for _, e := range extra {
+		fmt.Fprintf(&sb, "\n\t+ %#v", e)
+	}
+
+	t.Fatalf("elements do not match (- missing, + unexpected):%s", sb.String())
+}
+
+func isSortedBy[E any](t assertT, s []E, cmp func(a, b E) int) {
+	t.Helper()
+
+	for i := 1; i < len(s); i++ {
+		if cmp(s[i-1], s[i]) > 0 {
+			t.Fatalf("not sorted: element %d (%#v) sorts after element %d (%#v)", i-1, s[i-1], i, s[i])
+			return
+		}
+	}
+}
+
+func containsAll[E comparable](t assertT, s, want []E) {
+	t.Helper()
+
+	present := make(map[E]bool, len(s))
+	for _, e := range s {
+		present[e] = true
+	}
+
+	var sb strings.Builder
+	for _, e := range want {
+		if !present[e] {
+			fmt.Fprintf(&sb, "\n\t- %#v", e)
+		}
+	}
+
+	if sb.Len() > 0 {
+		t.Fatalf("missing elements:%s", sb.String())
+	}
+}
+
+func unique[E comparable](t assertT, s []E) {
+	t.Helper()
+
+	indexes := make(map[E][]int, len(s))
+	var order []E
+	for i, e := range s {
+		if len(indexes[e]) == 0 {
+			order = append(order, e)
+		}
+
+		indexes[e] = append(indexes[e], i)
+	}
+
+	var sb strings.Builder
+	for _, e := range order {
+		if idx := indexes[e]; len(idx) > 1 {
+			fmt.Fprintf(&sb, "\n\t%#v at indexes %v", e, idx)
+		}
+	}
+
+	if sb.Len() > 0 {
+		t.Fatalf("duplicate elements:%s", sb.String())
+	}
+}
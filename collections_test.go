@@ -0,0 +1,60 @@
+package tbdd
+
+import (
+	"cmp"
+	"fmt"
+	"testing"
+)
+
+func TestCollections(t *testing.T) {
+	t.Parallel()
+
+	ElementsMatch(t, []int{1, 2, 2, 3}, []int{2, 3, 2, 1})
+	ElementsMatch[string](t, nil, []string{})
+	IsSortedBy(t, []int{1, 1, 2}, cmp.Compare[int])
+	ContainsAll(t, []string{"a", "b", "c"}, "c", "a")
+	Unique(t, []int{3, 1, 2})
+}
+
+func TestCollections_failures(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		f    func(assertT)
+		exp  string
+		args string
+	}{
+		{
+			func(t assertT) { elementsMatch(t, []int{1, 2, 2, 4}, []int{2, 3, 1}) },
+			"elements do not match (- missing, + unexpected):%s",
+			"[\n\t- 2\n\t- 4\n\t+ 3]",
+		},
+		{
+			func(t assertT) { isSortedBy(t, []string{"a", "c", "b"}, cmp.Compare[string]) },
+			"not sorted: element %d (%#v) sorts after element %d (%#v)",
+			"[1 c 2 b]",
+		},
+		{
+			func(t assertT) { containsAll(t, []int{1}, []int{1, 2, 3}) },
+			"missing elements:%s",
+			"[\n\t- 2\n\t- 3]",
+		},
+		{
+			func(t assertT) { unique(t, []string{"a", "b", "a", "b", "a", "c"}) },
+			"duplicate elements:%s",
+			"[\n\t\"a\" at indexes [0 2 4]\n\t\"b\" at indexes [1 3]]",
+		},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+			continue
+		}
+
+		if args := fmt.Sprintf("%v", mt.fatalfCalls[0].args); args != v.args {
+			t.Errorf("expected fatalf args %q but got %q", v.args, args)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tbdd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Orders holds what was observed after running two operations in each of
+// their possible orders; see BothOrders.
+type Orders[O any] struct {
+	FirstThenSecond O
+	SecondThenFirst O
+}
+
+// BothOrders returns an Act function which checks two operations in both
+// orders. For each order it arranges fresh state from the test case, applies
+// the operations to it, and observes the final state. Paired with
+// ExpectCommutative this declares that the operations are order independent:
+//
+//	tbdd.WT(tc,
+//		"a deposit and a withdrawal are applied", tbdd.BothOrders(openAccount, deposit, withdraw, balance),
+//		"the order does not matter", tbdd.ExpectCommutative[TC, int](nil),
+//	)
+//
+// Each order runs in its own subtest, named "first then second" and
+// "second then first". When either fails the Act fails once both have
+// completed.
+//
+// BothOrders panics if any of its arguments are nil.
+func BothOrders[T, S, O any](
+	arrange func(*testing.T, T) S,
+	first, second func(*testing.T, S),
+	observe func(*testing.T, S) O,
+) func(*testing.T, T) Orders[O] {
+	if arrange == nil || first == nil || second == nil || observe == nil {
+		panic("tbdd.BothOrders: arrange, operation, and observe functions must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) Orders[O] {
+		t.Helper()
+
+		refuseBubble(t, "BothOrders")
+
+		var r Orders[O]
+		for _, o := range []struct {
+			name string
+			ops  [2]func(*testing.T, S)
+			dst  *O
+		}{
+			{"first then second", [2]func(*testing.T, S){first, second}, &r.FirstThenSecond},
+			{"second then first", [2]func(*testing.T, S){second, first}, &r.SecondThenFirst},
+		} {
+			t.Run(o.name, func(t *testing.T) {
+				s := arrange(t, tc)
+				o.ops[0](t, s)
+				o.ops[1](t, s)
+				*o.dst = observe(t, s)
+			})
+		}
+
+		if t.Failed() {
+			t.Fatalf("operations could not be applied in both orders")
+		}
+
+		return r
+	}
+}
+
+// ExpectCommutative returns a then function which fails the test unless the
+// states observed by BothOrders are equal according to equal, or to
+// reflect.DeepEqual when equal is nil.
+func ExpectCommutative[T, O any](equal func(a, b O) bool) func(*testing.T, T, Orders[O]) {
+	return func(t *testing.T, _ T, r Orders[O]) {
+		t.Helper()
+
+		expectCommutative(t, r, equal)
+	}
+}
+
+func expectCommutative[O any](t assertT, r Orders[O], equal func(a, b O) bool) {
+	t.Helper()
+
+	var same bool
+	if equal != nil {
+		same = equal(r.FirstThenSecond, r.SecondThenFirst)
+	} else {
+		same = reflect.DeepEqual(r.FirstThenSecond, r.SecondThenFirst)
+	}
+
+	if !same {
+		t.Fatalf("operations are order dependent: first then second observed %#v but second then first observed %#v", r.FirstThenSecond, r.SecondThenFirst)
+	}
+}
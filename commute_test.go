@@ -0,0 +1,108 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+type account struct {
+	balance int
+	log     []string
+}
+
+func openAccount(*testing.T, int) *account {
+	return &account{balance: 10}
+}
+
+func deposit(_ *testing.T, a *account) {
+	a.balance += 5
+	a.log = append(a.log, "deposit")
+}
+
+func withdraw(_ *testing.T, a *account) {
+	a.balance -= 3
+	a.log = append(a.log, "withdraw")
+}
+
+func TestBothOrders(t *testing.T) {
+	WT(
+		0,
+		"a deposit and a withdrawal are applied", BothOrders(openAccount, deposit, withdraw, func(_ *testing.T, a *account) int {
+			return a.balance
+		}),
+		"the order does not matter", ExpectCommutative[int, int](nil),
+	).New(t)(t)
+
+	WT(
+		0,
+		"a deposit and a withdrawal are logged", BothOrders(openAccount, deposit, withdraw, func(_ *testing.T, a *account) []string {
+			return a.log
+		}),
+		"the entries match regardless of order", ExpectCommutative[int](func(a, b []string) bool {
+			return len(a) == len(b)
+		}),
+	).New(t)(t)
+}
+
+func TestBothOrders_panics(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != "tbdd.BothOrders: arrange, operation, and observe functions must be non-nil" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	BothOrders[int, *account, int](openAccount, deposit, nil, nil)
+}
+
+func TestExpectCommutative_failure(t *testing.T) {
+	t.Parallel()
+
+	mt := &mT{}
+	expectCommutative(mt, Orders[[]string]{[]string{"a", "b"}, []string{"b", "a"}}, nil)
+
+	if exp := "operations are order dependent: first then second observed %#v but second then first observed %#v"; len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != exp {
+		t.Errorf("expected one fatalf call with format '%s' but got %v", exp, mt.fatalfCalls)
+	}
+}
+
+func TestBothOrders_failure(t *testing.T) {
+	if os.Getenv("TBDD_BOTH_ORDERS_HELPER") == "1" {
+		f := WT(
+			0,
+			"an operation fails", BothOrders(openAccount, deposit, func(t *testing.T, a *account) {
+				if a.balance == 10 {
+					t.Error("insufficient funds")
+				}
+			}, func(_ *testing.T, a *account) int {
+				return a.balance
+			}),
+			"it is not asserted", ExpectCommutative[int, int](nil),
+		).New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_BOTH_ORDERS_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/when_an_operation_fails/first_then_second ",
+		"--- FAIL: " + t.Name() + "/when_an_operation_fails/second_then_first ",
+		"operations could not be applied in both orders",
+		"--- SKIP: " + t.Name() + "/when_an_operation_fails/then_it_is_not_asserted ",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
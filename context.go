@@ -0,0 +1,92 @@
+package tbdd
+
+import (
+	"slices"
+	"testing"
+)
+
+// Nested is a scenario with test cases of type T which can run within a
+// Context, such as a Lifecycle or another Context.
+type Nested[T any] interface {
+	Scenario
+	runNested(t *testing.T, givens []func(*testing.T, *T))
+}
+
+// Context is a given context shared by several nested contexts and
+// lifecycles, each refining it with their own given, when, and then phases.
+// It is rendered as a "given" subtest enclosing the subtests of its children:
+//
+//	TestCart/given_a_signed_in_user/given_an_empty_cart/when_an_item_is_added/then_...
+//
+// Contexts are scenarios themselves, so they can be run directly or composed
+// within a Group.
+type Context[T any] struct {
+	given    string
+	givenF   func(*testing.T, *T)
+	children []Nested[T]
+}
+
+// GivenContext returns a Context described by given whose children run with
+// the test cases refined by givenF.
+//
+// Before a nested Lifecycle runs, the givenF of every enclosing context is
+// applied to its TC from the outermost context inwards, so each Lifecycle
+// and its variants start from the shared context without observing the
+// changes of their siblings. givenF is called with the subtest of the
+// innermost context, which is also where any failure it reports or cleanup
+// it registers is attributed; a givenF which stops the test stops the rest
+// of that context.
+//
+// givenF may be nil for contexts which only group their children under a
+// description. GivenContext panics if given is empty or a child is nil.
+func GivenContext[T any](given string, givenF func(*testing.T, *T), children ...Nested[T]) Context[T] {
+	if given == "" {
+		panic("tbdd.GivenContext: given description must be non-empty")
+	}
+
+	for _, c := range children {
+		if c == nil {
+			panic("tbdd.GivenContext: children must be non-nil")
+		}
+	}
+
+	return Context[T]{given, givenF, children}
+}
+
+// New takes a *testing.T to construct sub-tests for the context and all of
+// its children.
+func (c Context[T]) New(t *testing.T) func(*testing.T) {
+	t.Helper()
+
+	return func(t *testing.T) {
+		t.Helper()
+
+		c.runNested(t, nil)
+	}
+}
+
+func (c Context[T]) runNested(t *testing.T, givens []func(*testing.T, *T)) {
+	t.Helper()
+
+	if c.givenF != nil {
+		givens = append(slices.Clip(givens), c.givenF)
+	}
+
+	t.Run("given "+c.given, func(t *testing.T) {
+		t.Helper()
+
+		for _, child := range c.children {
+			child.runNested(t, givens)
+		}
+	})
+}
+
+func (b Lifecycle[T, R]) runNested(t *testing.T, givens []func(*testing.T, *T)) {
+	t.Helper()
+
+	for _, f := range givens {
+		f(t, &b.TC)
+	}
+
+	b.New(t)(t)
+}
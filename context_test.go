@@ -0,0 +1,90 @@
+package tbdd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGivenContext(t *testing.T) {
+	type cart struct {
+		user  string
+		items []string
+	}
+
+	var got []string
+	then := func(t *testing.T, tc cart, n int) {
+		got = append(got, t.Name()[strings.Index(t.Name(), "/")+1:]+": "+tc.user+" "+strings.Join(tc.items, ","))
+	}
+
+	c := GivenContext(
+		"a signed in user", func(_ *testing.T, tc *cart) {
+			tc.user = "ann"
+		},
+		GivenContext[cart](
+			"an empty cart", nil,
+			WT(cart{}, "an item is added", func(_ *testing.T, tc cart) int {
+				return len(tc.items)
+			}, "the cart has one item", then),
+		),
+		GivenContext(
+			"a full cart", func(_ *testing.T, tc *cart) {
+				tc.items = append(tc.items, "pear")
+			},
+			GWT(cart{}, "a coupon", func(_ *testing.T, tc *cart) {
+				tc.items = append(tc.items, "coupon")
+			}, "checking out", func(*testing.T, cart) int {
+				return 0
+			}, "the coupon applies", then),
+			WT(cart{}, "removing the item", func(*testing.T, cart) int {
+				return 0
+			}, "the cart is empty", then),
+		),
+	)
+
+	Independent(c).New(t)(t)
+
+	exp := []string{
+		"0/given_a_signed_in_user/given_an_empty_cart/when_an_item_is_added/then_the_cart_has_one_item: ann ",
+		"0/given_a_signed_in_user/given_a_full_cart/given_a_coupon/when_checking_out/then_the_coupon_applies: ann pear,coupon",
+		"0/given_a_signed_in_user/given_a_full_cart/when_removing_the_item/then_the_cart_is_empty: ann pear",
+	}
+	if !slices.Equal(got, exp) {
+		t.Errorf("expected:\n%s\nbut got:\n%s", strings.Join(exp, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestGivenContext_panics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		exp  string
+	}{
+		{
+			"empty given",
+			func() {
+				GivenContext[int]("", nil)
+			},
+			"tbdd.GivenContext: given description must be non-empty",
+		},
+		{
+			"nil child",
+			func() {
+				GivenContext[int]("a context", nil, nil)
+			},
+			"tbdd.GivenContext: children must be non-nil",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			tc.f()
+		})
+	}
+}
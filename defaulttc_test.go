@@ -0,0 +1,99 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestLifecycle_defaultTC(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		currency string
+		quantity int
+	}
+
+	var basis order
+	var arranged []order
+	b := GWTN(order{}, "an order", func(_ *testing.T, tc *order) {
+		arranged = append(arranged, *tc)
+	}, "it is placed", func(*testing.T, order) {}, "it is accepted", func(*testing.T, order) {}).With(
+		WithDefaultTC[order, struct{}](func(tc *order) {
+			if tc.currency == "" {
+				tc.currency = "USD"
+			}
+		}, nil),
+		WithDefaultTC[order, struct{}](func(tc *order) {
+			if tc.quantity == 0 {
+				tc.quantity = 1
+			}
+		}),
+		WithVariants[order, struct{}](func(_ *testing.T, tc order) iter.Seq[TestVariant[order]] {
+			basis = tc
+			return func(yield func(TestVariant[order]) bool) {
+				_ = yield(TestVariant[order]{Kind: "in euros", TC: order{currency: "EUR"}}) &&
+					yield(TestVariant[order]{Kind: "in bulk", TC: order{quantity: 100}})
+			}
+		}),
+	)
+
+	b.New(t)(t)
+
+	Equal(t, arranged, []order{{"USD", 1}, {"EUR", 1}, {"USD", 100}})
+	Equal(t, basis, order{})
+}
+
+func TestLifecycle_defaultTC_clone(t *testing.T) {
+	t.Parallel()
+
+	type cart struct {
+		Items map[string]int
+	}
+
+	// the default writes through the map, so it must only reach the clone
+	shared := map[string]int{}
+	var described, ran []string
+	b := WTN(cart{shared}, "the cart is checked out", func(_ *testing.T, tc cart) {
+		ran = append(ran, "apple="+string(rune('0'+tc.Items["apple"])))
+	}, "it is paid", func(*testing.T, cart) {}).With(
+		WithDefaultTC[cart, struct{}](func(tc *cart) {
+			tc.Items["apple"]++
+		}),
+		WithVariants[cart, struct{}](func(*testing.T, cart) iter.Seq[TestVariant[cart]] {
+			return slices.Values([]TestVariant[cart]{{Kind: "shared", TC: cart{shared}}})
+		}),
+	)
+	b.CloneTC = func(tc cart) cart {
+		tc.Items = maps.Clone(tc.Items)
+		return tc
+	}
+	b.Describe = func(_ *testing.T, d Describe[cart]) DescribeResponse {
+		described = append(described, "apple="+string(rune('0'+d.TC.Items["apple"])))
+		return DescribeResponse{d.When, d.Then}
+	}
+
+	p := b.Plan(t)
+	b.New(t)(t)
+
+	Equal(t, described, []string{"apple=1", "apple=1", "apple=1", "apple=1"})
+	Equal(t, ran, []string{"apple=1", "apple=1"})
+	Equal(t, len(shared), 0)
+	True(t, p.Basis.Err == nil && p.Variants[0].Err == nil)
+}
+
+func TestLifecycle_Validate_defaultTC(t *testing.T) {
+	t.Parallel()
+
+	b := WTN(0, "w", func(*testing.T, int) {}, "t", func(*testing.T, int) {})
+	b.DefaultTC = []func(*int){nil, func(*int) {}}
+
+	err := b.Validate()
+
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Field != "DefaultTC[0]" || !errors.Is(err, ErrNilDefaultTC) {
+		t.Errorf("expected a nil DefaultTC error but got %v", err)
+	}
+}
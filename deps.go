@@ -0,0 +1,82 @@
+package tbdd
+
+import (
+	"slices"
+	"testing"
+)
+
+// dependent is implemented by the scenarios which depend on the scenarios
+// of other Lifecycles, so a Group can run them after their prerequisites.
+type dependent interface {
+	dependencies() []string
+}
+
+func (b Lifecycle[T, R]) dependencies() []string {
+	return b.DependsOn
+}
+
+func (g Group) dependencies() []string {
+	var ids []string
+	for _, s := range g.scenarios {
+		if d, ok := s.(dependent); ok {
+			ids = append(ids, d.dependencies()...)
+		}
+	}
+
+	return ids
+}
+
+// resultOf returns the latest ScenarioResult recorded with the given ID.
+func resultOf(id string) (ScenarioResult, bool) {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+
+	for _, r := range slices.Backward(results.list) {
+		if r.ID == id {
+			return r, true
+		}
+	}
+
+	return ScenarioResult{}, false
+}
+
+// recorded reports whether a result has been recorded for every one of ids.
+func recorded(ids []string) bool {
+	for _, id := range ids {
+		if _, ok := resultOf(id); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skipDependent skips t, classifying sr accordingly, unless every scenario
+// of ids has passed. A nil t is a no-op.
+func skipDependent(t *testing.T, sr *scenario, ids []string) {
+	if len(ids) == 0 || t == nil {
+		return
+	}
+
+	t.Helper()
+
+	for _, id := range ids {
+		r, ok := resultOf(id)
+
+		var reason string
+		switch {
+		case !ok:
+			reason = "not run: prerequisite scenario " + id + " has not run"
+		case r.Status == StatusFailed:
+			reason = "not run: prerequisite scenario " + id + " (" + r.Test + ") failed"
+		case r.Status == StatusSkipped:
+			reason = "not run: prerequisite scenario " + id + " (" + r.Test + ") was skipped"
+		default:
+			continue
+		}
+
+		sr.class = ClassSkippedDependency
+		skip(t, reason)
+		return
+	}
+}
@@ -0,0 +1,97 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_dependsOn(t *testing.T) {
+	id := func(member string, sentence string) string {
+		return scenarioID(t.Name()+"/"+member, "", sentence)
+	}
+
+	smoke := id("1", "when the service starts then it is healthy")
+	ping := id("3", "when it is pinged then it answers")
+
+	if os.Getenv("TBDD_DEPENDS_ON_HELPER") == "1" {
+		noop := func(*testing.T, struct{}) {}
+
+		Independent(
+			WTN(struct{}{}, "a deep dive runs", noop, "it finds nothing", noop).With(WithDependsOn[struct{}, struct{}](smoke)),
+			WTN(struct{}{}, "the service starts", noop, "it is healthy", func(t *testing.T, _ struct{}) {
+				t.Error("unhealthy")
+			}),
+			GWTN(struct{}{}, "a healthy service", func(*testing.T, *struct{}) {}, "it is queried", noop, "it answers", noop).With(WithDependsOn[struct{}, struct{}](ping)),
+			WTN(struct{}{}, "it is pinged", noop, "it answers", noop),
+			WTN(struct{}{}, "an orphan runs", noop, "it is skipped", noop).With(WithDependsOn[struct{}, struct{}]("000000000000")),
+		).New(t)(t)
+		return
+	}
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_DEPENDS_ON_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	var ran []string
+	for line := range strings.Lines(string(out)) {
+		if name, ok := strings.CutPrefix(line, "=== RUN   "+t.Name()+"/"); ok && !strings.Contains(name, "/") {
+			ran = append(ran, strings.TrimSpace(name))
+		}
+	}
+
+	Equal(t, ran, []string{"1", "0", "3", "2", "4"})
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct {
+			Test       string
+			Class      string
+			SkipReason string
+		}
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string][2]string{}
+	for _, res := range r.Results {
+		got[res.Test[len(t.Name())+1:]] = [2]string{res.Class, res.SkipReason}
+	}
+
+	Equal(t, got, map[string][2]string{
+		"0/when_a_deep_dive_runs":   {"skipped-dependency", "not run: prerequisite scenario " + smoke + " (" + t.Name() + "/1/when_the_service_starts) failed"},
+		"1/when_the_service_starts": {"failed-assert", ""},
+		"2/given_a_healthy_service": {"passed", ""},
+		"3/when_it_is_pinged":       {"passed", ""},
+		"4/when_an_orphan_runs":     {"skipped-dependency", "not run: prerequisite scenario 000000000000 has not run"},
+	})
+}
+
+func TestLifecycle_Validate_dependsOn(t *testing.T) {
+	t.Parallel()
+
+	b := WTN(0, "w", func(*testing.T, int) {}, "t", func(*testing.T, int) {})
+	b.DependsOn = []string{"0123456789ab", ""}
+
+	err := b.Validate()
+
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Field != "DependsOn[1]" || !errors.Is(err, ErrEmptyDependsOn) {
+		t.Errorf("expected an empty DependsOn error but got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package tbdd
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// DoubleMode is the kind of a test double standing in for a real dependency.
+type DoubleMode uint8
+
+const (
+	// DoubleDummy is passed around but never used.
+	DoubleDummy DoubleMode = iota + 1
+	// DoubleStub returns canned answers.
+	DoubleStub
+	// DoubleSpy is a stub which also records how it was called.
+	DoubleSpy
+	// DoubleMock verifies it was called as expected.
+	DoubleMock
+	// DoubleFake is a working but simplified implementation, such as an
+	// in-memory database.
+	DoubleFake
+)
+
+func (m DoubleMode) String() string {
+	switch m {
+	case DoubleDummy:
+		return "dummy"
+	case DoubleStub:
+		return "stub"
+	case DoubleSpy:
+		return "spy"
+	case DoubleMock:
+		return "mock"
+	case DoubleFake:
+		return "fake"
+	}
+
+	return "DoubleMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+func (m DoubleMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Double describes a test double injected into a scenario by InjectDouble.
+type Double struct {
+	// Name identifies the dependency the double stands in for, such as
+	// "payments API".
+	Name string
+	// Type is the Go type of the double, such as "*fakes.Payments".
+	Type string
+	Mode DoubleMode
+}
+
+// doubles holds the Doubles injected by each running test, by name.
+var doubles struct {
+	mu sync.Mutex
+	m  map[string][]Double
+}
+
+// InjectDouble records that the scenario running t uses d in place of the
+// real dependency name, and returns d so it can be installed inline:
+//
+//	tc.payments = tbdd.InjectDouble(t, "payments API", tbdd.DoubleFake, fakes.NewPayments())
+//
+// The doubles of a scenario are recorded as ScenarioResult.Doubles and
+// emitted as its tbdd.doubles test attribute, so reports can tell scenarios
+// which run fully real from those relying on doubles. A dependency injected
+// more than once by a scenario is recorded once.
+//
+// InjectDouble may be called from any phase of a scenario, such as its given
+// or act phase.
+func InjectDouble[D any](t *testing.T, name string, mode DoubleMode, d D) D {
+	typ := reflect.TypeOf(d)
+	if typ == nil {
+		typ = reflect.TypeFor[D]()
+	}
+
+	doubles.mu.Lock()
+	defer doubles.mu.Unlock()
+
+	if doubles.m == nil {
+		doubles.m = map[string][]Double{}
+	}
+
+	test := t.Name()
+	if !slices.ContainsFunc(doubles.m[test], func(d Double) bool { return d.Name == name }) {
+		doubles.m[test] = append(doubles.m[test], Double{name, typ.String(), mode})
+	}
+
+	return d
+}
+
+// doublesOf returns the Doubles injected by the test named name and its
+// subtests, forgetting them.
+func doublesOf(name string) []Double {
+	doubles.mu.Lock()
+	defer doubles.mu.Unlock()
+
+	var found []string
+	for n := range doubles.m {
+		if n == name || strings.HasPrefix(n, name+"/") {
+			found = append(found, n)
+		}
+	}
+
+	// map iteration is random, so the doubles are listed by test name
+	slices.Sort(found)
+
+	var ds []Double
+	for _, n := range found {
+		for _, d := range doubles.m[n] {
+			if !slices.ContainsFunc(ds, func(e Double) bool { return e.Name == d.Name }) {
+				ds = append(ds, d)
+			}
+		}
+		delete(doubles.m, n)
+	}
+
+	return ds
+}
+
+// doublesAttr formats ds as the value of the tbdd.doubles test attribute,
+// such as "payments API (fake *fakes.Payments), clock (stub *fakes.Clock)".
+func doublesAttr(ds []Double) string {
+	s := make([]string, len(ds))
+	for i, d := range ds {
+		s[i] = d.Name + " (" + d.Mode.String() + " " + d.Type + ")"
+	}
+
+	return attrValue(strings.Join(s, ", "))
+}
@@ -0,0 +1,102 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type fakeStore struct{}
+
+type clock interface {
+	Now() int
+}
+
+func TestInjectDouble(t *testing.T) {
+	type TC struct {
+		store *fakeStore
+		clock clock
+		real  bool
+	}
+
+	b := GWTN(
+		TC{},
+		"the dependencies of the test case", func(t *testing.T, tc *TC) {
+			if !tc.real {
+				tc.store = InjectDouble(t, "store", DoubleFake, &fakeStore{})
+			}
+		},
+		"it runs", func(t *testing.T, tc TC) {
+			if !tc.real {
+				// a nil interface is recorded with its static type
+				_ = InjectDouble[clock](t, "clock", DoubleStub, nil)
+				_ = InjectDouble(t, "store", DoubleMock, tc.store)
+			}
+		},
+		"it completes", func(*testing.T, TC) {},
+	)
+	b.Variants = func(*testing.T, TC) iter.Seq[TestVariant[TC]] {
+		return slices.Values([]TestVariant[TC]{{Kind: "real", TC: TC{real: true}}})
+	}
+
+	// doubles recorded under the name of a scenario before it starts are not
+	// its own
+	doubles.mu.Lock()
+	if doubles.m == nil {
+		doubles.m = map[string][]Double{}
+	}
+	doubles.m[t.Name()+"/scenarios/given_the_dependencies_of_the_test_case"] = []Double{{"stale", "int", DoubleDummy}}
+	doubles.mu.Unlock()
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	t.Run("scenarios", b.New(t))
+
+	var got []ScenarioResult
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			got = append(got, r)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 results but got %d: %+v", len(got), got)
+	}
+
+	exp := []Double{{"store", "*tbdd.fakeStore", DoubleFake}, {"clock", "tbdd.clock", DoubleStub}}
+	if !slices.Equal(got[0].Doubles, exp) {
+		t.Errorf("expected the doubles %+v but got %+v", exp, got[0].Doubles)
+	}
+
+	if got[1].Kind != "real" || len(got[1].Doubles) != 0 {
+		t.Errorf("expected the real variant to have no doubles but got %+v", got[1])
+	}
+
+	if b, err := json.Marshal(got[0].Doubles[0]); err != nil || string(b) != `{"Name":"store","Type":"*tbdd.fakeStore","Mode":"fake"}` {
+		t.Errorf("unexpected JSON: %s: %v", b, err)
+	}
+
+	if s, exp := doublesAttr(exp), "store (fake *tbdd.fakeStore), clock (stub tbdd.clock)"; s != exp {
+		t.Errorf("expected the attribute '%s' but got '%s'", exp, s)
+	}
+}
+
+func TestDoubleMode_String(t *testing.T) {
+	t.Parallel()
+
+	for m, exp := range map[DoubleMode]string{
+		DoubleDummy: "dummy",
+		DoubleStub:  "stub",
+		DoubleSpy:   "spy",
+		DoubleMock:  "mock",
+		DoubleFake:  "fake",
+		0:           "DoubleMode(0)",
+	} {
+		if s := m.String(); s != exp {
+			t.Errorf("expected %q but got %q", exp, s)
+		}
+	}
+}
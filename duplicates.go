@@ -0,0 +1,82 @@
+package tbdd
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// The modes of the -tbdd.duplicates flag.
+const (
+	duplicatesWarn = "warn"
+	duplicatesFail = "fail"
+	duplicatesOff  = "off"
+)
+
+// occurrence is the first scenario run with a given sentence.
+type occurrence struct {
+	test, source string
+}
+
+// sentences holds the first occurrence of every scenario sentence run by the
+// process.
+var sentences struct {
+	mu sync.Mutex
+	m  map[string]occurrence
+}
+
+// duplicateOf returns the first occurrence of the sentence of r when it was
+// defined by code other than its Source, recording r as the first occurrence
+// of its sentence otherwise.
+//
+// Running one definition many times, such as from a table or with -count,
+// is not a duplicate; two definitions of the same sentence usually are a
+// copy which was meant to change.
+func duplicateOf(r ScenarioResult) (occurrence, bool) {
+	if r.When == "" {
+		// the scenario failed before it was described
+		return occurrence{}, false
+	}
+
+	sentence := r.Scenario()
+
+	sentences.mu.Lock()
+	defer sentences.mu.Unlock()
+
+	first, ok := sentences.m[sentence]
+	if !ok {
+		if sentences.m == nil {
+			sentences.m = map[string]occurrence{}
+		}
+
+		sentences.m[sentence] = occurrence{r.Test, r.Source}
+
+		return occurrence{}, false
+	}
+
+	return first, first.source != r.Source
+}
+
+// checkDuplicate handles the scenario of s, whose result is r, as the
+// -tbdd.duplicates flag selects when it duplicates the sentence of a
+// scenario defined elsewhere.
+func checkDuplicate(t *testing.T, s *scenario, r ScenarioResult) {
+	if config.duplicates == duplicatesOff {
+		return
+	}
+
+	first, ok := duplicateOf(r)
+	if !ok {
+		return
+	}
+
+	msg := "scenario " + strconv.Quote(r.Scenario()) + " defined at " + sourceOrUnknown(r.Source) + " duplicates the scenario of " + first.test + " defined at " + sourceOrUnknown(first.source)
+
+	if config.duplicates == duplicatesFail {
+		s.fail(ClassFailedConfig)
+		t.Error("tbdd: " + msg)
+		return
+	}
+
+	warn(t, Warning{Message: msg})
+}
@@ -0,0 +1,153 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDuplicates(t *testing.T) {
+	pass := func(*testing.T, int) {}
+
+	// written on their own lines so they are defined apart
+	first := func() Lifecycle[int, struct{}] { return WTN(0, "the copy runs", pass, "it was meant to change", pass) }
+	second := func() Lifecycle[int, struct{}] { return WTN(0, "the copy runs", pass, "it was meant to change", pass) }
+
+	if os.Getenv("TBDD_DUPLICATES_HELPER") == "1" {
+		t.Run("first", first().New(t))
+		t.Run("second", second().New(t))
+		return
+	}
+
+	// only the warnings of this run count, as -count runs the test again
+	n := len(Warnings())
+
+	// one definition run many times is not a duplicate
+	once := first()
+	for i := range 2 {
+		t.Run("once", once.NewI(t, i))
+	}
+
+	if ws := duplicateWarnings(t, n); len(ws) != 0 {
+		t.Fatalf("expected no duplicate warnings but got %q", ws)
+	}
+
+	t.Run("second", second().New(t))
+
+	ws := duplicateWarnings(t, n)
+	if len(ws) != 1 {
+		t.Fatalf("expected one duplicate warning but got %q", ws)
+	}
+
+	exp := `scenario "when the copy runs then it was meant to change" defined at ` + second().Source + " duplicates the scenario of " + t.Name() + "/once/0/when_the_copy_runs defined at " + first().Source
+	if ws[0] != exp {
+		t.Errorf("expected the warning '%s' but got '%s'", exp, ws[0])
+	}
+
+	// duplicates are ignored under -tbdd.duplicates=off
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.duplicates = duplicatesOff
+	t.Run("ignored", second().New(t))
+
+	if ws := duplicateWarnings(t, n); len(ws) != 1 {
+		t.Errorf("expected the duplicate to be ignored but got %q", ws)
+	}
+
+	//
+	// duplicates fail under -tbdd.duplicates=fail
+	//
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.duplicates=fail", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_DUPLICATES_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/first/when_the_copy_runs ",
+		"--- FAIL: " + t.Name() + "/second/when_the_copy_runs ",
+		"tbdd: scenario \"when the copy runs then it was meant to change\" defined at " + second().Source,
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ Test, Class string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Results) != 2 || r.Results[0].Class != "passed" || r.Results[1].Class != "failed-config" {
+		t.Errorf("expected the duplicate to fail as misconfigured but got %+v", r.Results)
+	}
+}
+
+// duplicateWarnings returns the messages of the duplicate warnings recorded
+// by the subtests of t after the first n warnings.
+func duplicateWarnings(t *testing.T, n int) []string {
+	var ws []string
+	for _, w := range Warnings()[n:] {
+		if strings.HasPrefix(w.Test, t.Name()+"/") && strings.Contains(w.Message, " duplicates the scenario of ") {
+			ws = append(ws, w.Message)
+		}
+	}
+
+	return ws
+}
+
+func Test_duplicateOf(t *testing.T) {
+	t.Parallel()
+
+	r := ScenarioResult{Test: "a", When: "a duplicate is checked " + t.Name(), Then: "it is found"}
+
+	if _, ok := duplicateOf(withSource(r, "a.go:1")); ok {
+		t.Error("expected the first occurrence not to be a duplicate")
+	}
+
+	r.Test = "b"
+	if _, ok := duplicateOf(withSource(r, "a.go:1")); ok {
+		t.Error("expected another run of the same definition not to be a duplicate")
+	}
+
+	if first, ok := duplicateOf(withSource(r, "b.go:2")); !ok || first != (occurrence{"a", "a.go:1"}) {
+		t.Errorf("expected the duplicate of the first occurrence but got %+v, %v", first, ok)
+	}
+
+	// variants are told apart by their Kind
+	r.Kind = "variant"
+	if _, ok := duplicateOf(withSource(r, "b.go:2")); ok {
+		t.Error("expected a variant not to duplicate its basis")
+	}
+
+	// scenarios which failed before they were described are not compared
+	if _, ok := duplicateOf(ScenarioResult{Kind: "variant", Source: "c.go:3"}); ok {
+		t.Error("expected an undescribed scenario not to be a duplicate")
+	}
+	if _, ok := duplicateOf(ScenarioResult{Kind: "variant", Source: "d.go:4"}); ok {
+		t.Error("expected an undescribed scenario not to be a duplicate")
+	}
+}
+
+func withSource(r ScenarioResult, source string) ScenarioResult {
+	r.Source = source
+	return r
+}
@@ -0,0 +1,132 @@
+package tbdd
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Sentinel errors describing harness misconfiguration.
+//
+// They are wrapped by ConfigError values returned from Lifecycle.Validate and
+// passed to the Hooks.ConfigError hook, so callers can detect them with
+// errors.Is rather than matching on failure message strings. Running a
+// misconfigured scenario or variant fails a subtest named tbdd-config-error
+// below it rather than the test running the lifecycle.
+var (
+	ErrEmptyWhen        = errors.New("When string of BDD test must not be empty")
+	ErrEmptyThen        = errors.New("Then string of BDD test must not be empty")
+	ErrNilAct           = errors.New("Act function of BDD test is not defined")
+	ErrNilAssert        = errors.New("Assert function of BDD test is not defined")
+	ErrNilGivenFunc     = errors.New("Arrange returned a nil given function")
+	ErrEmptyGiven       = errors.New("Arrange function returned an empty Given string")
+	ErrEmptyVariantKind = errors.New("test case variant has no Kind detail")
+	ErrVariantsPanic    = errors.New("variant generator panicked")
+	ErrCloneTCPanic     = errors.New("CloneTC function panicked")
+	ErrTooManyVariants  = errors.New("too many test case variants")
+	ErrNilDefaultTC     = errors.New("DefaultTC function of BDD test is not defined")
+	ErrEmptyDependsOn   = errors.New("DependsOn ID of BDD test must not be empty")
+	ErrNilInvariant     = errors.New("Invariant function of BDD test is not defined")
+	ErrNilNormalizer    = errors.New("NormalizeResult function of BDD test is not defined")
+)
+
+// ConfigError describes a single misconfigured field of a Lifecycle.
+type ConfigError struct {
+	// Field is the name of the Lifecycle or TestVariant field that is misconfigured.
+	Field string
+	// Prefix is the subtest name prefix of the test case the error applies to, if any.
+	Prefix string
+	// VariantIndex is the index of the offending test case variant or -1 when the
+	// error does not relate to a specific variant.
+	VariantIndex int
+	// Err is the sentinel error describing the misconfiguration.
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	s := "tbdd: invalid " + e.Field
+	if e.VariantIndex >= 0 {
+		s += " of variant " + strconv.Itoa(e.VariantIndex)
+	}
+	if e.Prefix != "" {
+		s += ` (prefix = "` + e.Prefix + `")`
+	}
+
+	return s + ": " + e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// constructorError is returned by the Try* constructors. Its message matches
+// the panic value of the corresponding panicking constructor.
+type constructorError struct {
+	msg string
+	err error
+}
+
+func (e constructorError) Error() string {
+	return e.msg
+}
+
+func (e constructorError) Unwrap() error {
+	return e.err
+}
+
+// Validate reports the configuration problems of a Lifecycle that can be
+// detected without executing it.
+//
+// Fields which Arrange or Describe are able to set later are only required
+// when the phase that could set them is not defined. The returned error is
+// nil or a join of *ConfigError values.
+func (b Lifecycle[T, R]) Validate() error {
+	var errs []error
+
+	addErr := func(field string, err error) {
+		errs = append(errs, &ConfigError{Field: field, VariantIndex: -1, Err: err})
+	}
+
+	if b.Arrange == nil && b.Describe == nil {
+		if b.When == "" {
+			addErr("When", ErrEmptyWhen)
+		}
+		if b.Then == "" {
+			addErr("Then", ErrEmptyThen)
+		}
+	}
+
+	if b.Arrange == nil {
+		if b.Act == nil {
+			addErr("Act", ErrNilAct)
+		}
+		if b.Assert == nil {
+			addErr("Assert", ErrNilAssert)
+		}
+	}
+
+	for i, id := range b.DependsOn {
+		if id == "" {
+			errs = append(errs, &ConfigError{Field: "DependsOn[" + strconv.Itoa(i) + "]", VariantIndex: -1, Err: ErrEmptyDependsOn})
+		}
+	}
+
+	for i, f := range b.DefaultTC {
+		if f == nil {
+			errs = append(errs, &ConfigError{Field: "DefaultTC[" + strconv.Itoa(i) + "]", VariantIndex: -1, Err: ErrNilDefaultTC})
+		}
+	}
+
+	for i, f := range b.Invariants {
+		if f == nil {
+			errs = append(errs, &ConfigError{Field: "Invariants[" + strconv.Itoa(i) + "]", VariantIndex: -1, Err: ErrNilInvariant})
+		}
+	}
+
+	for i, f := range b.NormalizeResult {
+		if f == nil {
+			errs = append(errs, &ConfigError{Field: "NormalizeResult[" + strconv.Itoa(i) + "]", VariantIndex: -1, Err: ErrNilNormalizer})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+package tbdd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigError(t *testing.T) {
+	t.Parallel()
+
+	{
+		err := &ConfigError{Field: "When", VariantIndex: -1, Err: ErrEmptyWhen}
+
+		if exp := "tbdd: invalid When: When string of BDD test must not be empty"; err.Error() != exp {
+			t.Errorf("expected '%s' but got '%s'", exp, err.Error())
+		}
+
+		if !errors.Is(err, ErrEmptyWhen) {
+			t.Error("expected ConfigError to unwrap to ErrEmptyWhen")
+		}
+	}
+
+	{
+		err := &ConfigError{Field: "Kind", Prefix: "0/", VariantIndex: 2, Err: ErrEmptyVariantKind}
+
+		if exp := `tbdd: invalid Kind of variant 2 (prefix = "0/"): test case variant has no Kind detail`; err.Error() != exp {
+			t.Errorf("expected '%s' but got '%s'", exp, err.Error())
+		}
+	}
+}
+
+func TestLifecycle_Validate(t *testing.T) {
+	t.Parallel()
+
+	{
+		err := Lifecycle[mTC, mTCR]{}.Validate()
+
+		for _, exp := range []error{ErrEmptyWhen, ErrEmptyThen, ErrNilAct, ErrNilAssert} {
+			if !errors.Is(err, exp) {
+				t.Errorf("expected Validate error to contain '%v'", exp)
+			}
+		}
+
+		var cfgErr *ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != "When" {
+			t.Error("expected first ConfigError to describe the When field")
+		}
+	}
+
+	{
+		err := Lifecycle[mTC, mTCR]{
+			Describe: func(*testing.T, Describe[mTC]) DescribeResponse {
+				return DescribeResponse{"w", "t"}
+			},
+		}.Validate()
+
+		if errors.Is(err, ErrEmptyWhen) || errors.Is(err, ErrEmptyThen) {
+			t.Error("expected Describe to satisfy When and Then requirements")
+		}
+
+		if !errors.Is(err, ErrNilAct) || !errors.Is(err, ErrNilAssert) {
+			t.Error("expected Act and Assert to still be required")
+		}
+	}
+
+	{
+		err := Lifecycle[mTC, mTCR]{
+			Arrange: func(*testing.T, Arrange[mTC, mTCR]) (string, func(*testing.T)) {
+				return "g", func(*testing.T) {}
+			},
+		}.Validate()
+
+		if err != nil {
+			t.Errorf("expected nil error but got '%v'", err)
+		}
+	}
+
+	{
+		err := WT(
+			mTC{},
+			"w", func(*testing.T, mTC) mTCR { return mTCR{} },
+			"t", func(*testing.T, mTC, mTCR) {},
+		).Validate()
+
+		if err != nil {
+			t.Errorf("expected nil error but got '%v'", err)
+		}
+	}
+}
+
+func TestHooks_ConfigError(t *testing.T) {
+	t.Parallel()
+
+	mt := &mT{}
+
+	var errs []*ConfigError
+	b := Lifecycle[mTC, mTCR]{
+		hooks: Hooks[mTC, mTCR]{
+			ConfigError: func(_ *testing.T, err *ConfigError) {
+				errs = append(errs, err)
+			},
+		},
+		getT: nilGetT,
+	}
+
+	f := ((lifecycle[mTC, mTCR])(b)).newI(mt, 3)
+	f(mt)
+
+	exp := []error{ErrEmptyWhen, ErrEmptyThen, ErrNilAct, ErrNilAssert}
+	if len(errs) != len(exp) {
+		t.Fatalf("expected %d config errors but got %d", len(exp), len(errs))
+	}
+
+	for i, v := range exp {
+		if !errors.Is(errs[i], v) {
+			t.Errorf("expected config error %d to be '%v' but got '%v'", i, v, errs[i])
+		}
+
+		if errs[i].Prefix != "3/" {
+			t.Errorf("expected config error %d to have prefix '3/' but got '%s'", i, errs[i].Prefix)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package tbdd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+)
+
+// ExampleFile collects Example functions from lifecycles configured with
+// WithExample and keeps them in a generated Go source file, so behaviors
+// double as godoc examples without drifting from their tests.
+type ExampleFile struct {
+	path, pkg string
+
+	mu       sync.Mutex
+	examples map[string]string
+	// conflicts lists the names recorded for examples of differing source
+	conflicts []string
+}
+
+// NewExampleFile returns an ExampleFile for the _test.go file at path in the
+// package named pkg.
+//
+// The file must belong to the package which defines the test case types and
+// the functions named by WithExample; qualifiers of that package are dropped
+// from the generated test case literals.
+func NewExampleFile(path, pkg string) *ExampleFile {
+	return &ExampleFile{path: path, pkg: pkg, examples: map[string]string{}}
+}
+
+// WithExample records an Example function in f for every scenario of the
+// Lifecycle once its Act completes. The function prints the result of
+// calling the function named call with the scenario's test case and expects
+// the output the Act produced, formatted by fmt.Println:
+//
+//	func ExampleLogin() {
+//		fmt.Println(login(Creds{User: "ann"}))
+//		// Output: welcome ann
+//	}
+//
+// The basis test case becomes Example<name>; variants become
+// Example<name>_<kind> with the Kind reduced to the lowercase letters and
+// digits Go allows in example suffixes, prefixed with "kind" unless it then
+// starts with a letter. Kinds which reduce to the same suffix, such as
+// "dry run" and "Dry-Run", fail Source unless their examples are identical.
+// Test cases are rendered with the %#v verb, so they should be made of
+// values with Go syntax representations.
+func WithExample[T, R any](f *ExampleFile, name, call string) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.example = func(kind string, tc T, r R) {
+			f.record(exampleName(name, kind), call, tc, r)
+		}
+	}
+}
+
+func exampleName(name, kind string) string {
+	name = "Example" + name
+	if kind == "" {
+		return name
+	}
+
+	var sb strings.Builder
+	for _, r := range kind {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	// go vet requires example suffixes to start with a lowercase letter
+	suffix := sb.String()
+	if suffix == "" || !unicode.IsLetter(rune(suffix[0])) {
+		suffix = "kind" + suffix
+	}
+
+	return name + "_" + suffix
+}
+
+// unqualified returns the Go syntax lit with the qualifiers of the package
+// pkg removed from its selector expressions, leaving string literals and
+// other packages alone. lit is returned unchanged when it is not a valid
+// expression, so the generated source fails to format instead.
+func unqualified(lit, pkg string) string {
+	fset := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fset, "", lit, 0)
+	if err != nil {
+		return lit
+	}
+
+	// the byte ranges of the qualifiers, in source order
+	var cuts [][2]int
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == pkg {
+				cuts = append(cuts, [2]int{fset.Position(id.Pos()).Offset, fset.Position(sel.Sel.Pos()).Offset})
+			}
+		}
+		return true
+	})
+
+	var sb strings.Builder
+	var last int
+	for _, c := range cuts {
+		sb.WriteString(lit[last:c[0]])
+		last = c[1]
+	}
+	sb.WriteString(lit[last:])
+
+	return sb.String()
+}
+
+func (f *ExampleFile) record(name, call string, tc, r any) {
+	lit := unqualified(fmt.Sprintf("%#v", tc), strings.TrimSuffix(f.pkg, "_test"))
+
+	var sb strings.Builder
+	sb.WriteString("func " + name + "() {\n")
+	sb.WriteString("\tfmt.Println(" + call + "(" + lit + "))\n")
+	sb.WriteString("\t// Output:\n")
+	for line := range strings.SplitSeq(fmt.Sprint(r), "\n") {
+		sb.WriteString(strings.TrimRight("\t// "+line, " \t") + "\n")
+	}
+	sb.WriteString("}\n")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// scenarios which run again, such as under -count, record the same example
+	if src, ok := f.examples[name]; ok && src != sb.String() && !slices.Contains(f.conflicts, name) {
+		f.conflicts = append(f.conflicts, name)
+	}
+
+	f.examples[name] = sb.String()
+}
+
+// Source returns the generated Go source of the file, or an error when
+// differing examples were recorded with the same name.
+func (f *ExampleFile) Source() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.conflicts) > 0 {
+		return nil, errors.New("differing examples were recorded as " + strings.Join(f.conflicts, ", ") + "; give their scenarios distinct example names or Kinds")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by tbdd from behavior tests; DO NOT EDIT.\n\n")
+	b.WriteString("package " + f.pkg + "\n\n")
+	b.WriteString("import \"fmt\"\n")
+
+	for _, name := range slices.Sorted(maps.Keys(f.examples)) {
+		b.WriteString("\n" + f.examples[name])
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// Check fails the test unless the file at the path of f holds the generated
+// source, which it should be called to verify once every lifecycle
+// contributing examples has run.
+//
+// When the -tbdd.update-golden flag is set the file is (re)written instead.
+func (f *ExampleFile) Check(t *testing.T) {
+	t.Helper()
+
+	f.check(t)
+}
+
+func (f *ExampleFile) check(t goldenT) {
+	t.Helper()
+
+	src, err := f.Source()
+	if err != nil {
+		t.Fatalf("failed to generate examples for %s: %v", f.path, err)
+		return
+	}
+
+	if config.updateGolden {
+		if err := os.WriteFile(f.path, src, 0o644); err != nil {
+			t.Fatalf("failed to update examples file %s: %v", f.path, err)
+			return
+		}
+
+		t.Logf("updated examples file %s", f.path)
+		RecordUpdate("examples", f.path)
+		return
+	}
+
+	if b, err := os.ReadFile(f.path); err != nil || !bytes.Equal(b, src) {
+		t.Fatalf("examples file %s is out of date; run with -tbdd.update-golden to regenerate it", f.path)
+	}
+}
@@ -0,0 +1,164 @@
+package tbdd
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type greeting struct {
+	Name string
+}
+
+func greet(tc greeting) string {
+	return "hello " + tc.Name + "\nwelcome  "
+}
+
+func TestExampleFile(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	path := filepath.Join(t.TempDir(), "example_test.go")
+	examples := NewExampleFile(path, "tbdd_test")
+
+	b := WT(
+		greeting{"ann"},
+		"a visitor is greeted", func(_ *testing.T, tc greeting) string { return greet(tc) },
+		"they are welcomed", func(*testing.T, greeting, string) {},
+	).With(
+		WithExample[greeting, string](examples, "Greet", "greet"),
+		WithVariants[greeting, string](func(*testing.T, greeting) iter.Seq[TestVariant[greeting]] {
+			return func(yield func(TestVariant[greeting]) bool) {
+				yield(TestVariant[greeting]{Kind: "Returning Visitor-2", TC: greeting{"bob"}})
+			}
+		}),
+	)
+
+	f := b.New(t)
+	f(t)
+
+	exp := `// Code generated by tbdd from behavior tests; DO NOT EDIT.
+
+package tbdd_test
+
+import "fmt"
+
+func ExampleGreet() {
+	fmt.Println(greet(greeting{Name: "ann"}))
+	// Output:
+	// hello ann
+	// welcome
+}
+
+func ExampleGreet_returningvisitor2() {
+	fmt.Println(greet(greeting{Name: "bob"}))
+	// Output:
+	// hello bob
+	// welcome
+}
+`
+	if src, err := examples.Source(); err != nil || string(src) != exp {
+		t.Fatalf("expected source:\n%s\nbut got:\n%s\n%v", exp, src, err)
+	}
+
+	//
+	// a missing file is out of date
+	//
+
+	mt := &mGoldenT{}
+	examples.check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "examples file %s is out of date; run with -tbdd.update-golden to regenerate it" {
+		t.Errorf("expected the examples file to be out of date but got %v", mt.fatalfCalls)
+	}
+
+	//
+	// updating writes the file, after which it is up to date
+	//
+
+	config.updateGolden = true
+
+	mt = &mGoldenT{}
+	examples.check(mt)
+	if len(mt.fatalfCalls) != 0 || len(mt.logs) != 1 || mt.logs[0] != "updated examples file "+path {
+		t.Errorf("expected the examples file to be updated but got %v %v", mt.fatalfCalls, mt.logs)
+	}
+
+	config.updateGolden = false
+
+	examples.Check(t)
+
+	//
+	// failures
+	//
+
+	config.updateGolden = true
+
+	mt = &mGoldenT{}
+	NewExampleFile(t.TempDir(), "x").check(mt)
+	if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "failed to update examples file %s: %v" {
+		t.Errorf("expected the update to fail but got %v", mt.fatalfCalls)
+	}
+
+	invalid := NewExampleFile(path, "x")
+	invalid.record("ExampleBroken", "(", greeting{}, "")
+
+	mt = &mGoldenT{}
+	invalid.check(mt)
+	if len(mt.fatalfCalls) != 1 || !strings.HasPrefix(fmt.Sprintf(mt.fatalfCalls[0].format, mt.fatalfCalls[0].args...), "failed to generate examples for "+path+": ") {
+		t.Errorf("expected the generation to fail but got %v", mt.fatalfCalls)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+
+	//
+	// Kinds reducing to the same name conflict unless their examples match
+	//
+
+	conflicting := NewExampleFile(path, "tbdd_test")
+	conflicting.record(exampleName("Greet", "dry run"), "greet", greeting{"ann"}, "hello ann")
+	conflicting.record(exampleName("Greet", "Dry-Run"), "greet", greeting{"ann"}, "hello ann")
+	if _, err := conflicting.Source(); err != nil {
+		t.Errorf("expected identical examples to not conflict but got %v", err)
+	}
+
+	conflicting.record(exampleName("Greet", "DRY RUN"), "greet", greeting{"bob"}, "hello bob")
+	if _, err := conflicting.Source(); err == nil || err.Error() != "differing examples were recorded as ExampleGreet_dryrun; give their scenarios distinct example names or Kinds" {
+		t.Errorf("expected the examples to conflict but got %v", err)
+	}
+}
+
+func Test_exampleName(t *testing.T) {
+	t.Parallel()
+
+	for kind, exp := range map[string]string{
+		"":                    "ExampleGreet",
+		"Returning Visitor-2": "ExampleGreet_returningvisitor2",
+		"2FA":                 "ExampleGreet_kind2fa",
+		"élan":                "ExampleGreet_lan",
+		"!!":                  "ExampleGreet_kind",
+	} {
+		Equal(t, exampleName("Greet", kind), exp)
+	}
+}
+
+func Test_unqualified(t *testing.T) {
+	t.Parallel()
+
+	for lit, exp := range map[string]string{
+		`tbdd.greeting{Name:"tbdd.x"}`:               `greeting{Name:"tbdd.x"}`,
+		`[]tbdd.greeting{tbdd.greeting{Name:"ann"}}`: `[]greeting{greeting{Name:"ann"}}`,
+		`map[string]time.Duration{"tbdd.":1}`:        `map[string]time.Duration{"tbdd.":1}`,
+		`othertbdd.greeting{}`:                       `othertbdd.greeting{}`,
+		`(*tbdd.greeting)(0xc000010000)`:             `(*greeting)(0xc000010000)`,
+		`tbdd.greeting{Name:`:                        `tbdd.greeting{Name:`,
+	} {
+		Equal(t, unqualified(lit, "tbdd"), exp)
+	}
+}
@@ -0,0 +1,316 @@
+package tbdd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Expectation is a fluent assertion about a value, usually an Act result or
+// a value nested within it.
+//
+// Every failed assertion fails the test via t.Fatalf with the path of the
+// value being checked.
+type Expectation struct {
+	t    assertT
+	v    reflect.Value
+	path string
+	// failed stops further checks once an assertion or navigation failed.
+	failed bool
+}
+
+// Expect starts a fluent assertion about v:
+//
+//	tbdd.Expect(t, r).Field("User.Email").Equal("x@y.z")
+func Expect(t *testing.T, v any) *Expectation {
+	t.Helper()
+
+	return expect(linkedT{T: t}, v)
+}
+
+func expect(t assertT, v any) *Expectation {
+	return &Expectation{t: t, v: reflect.ValueOf(v), path: "value"}
+}
+
+// Field returns an Expectation about the value found by following path
+// from the current value.
+//
+// A path is a sequence of struct field names separated by dots, each of
+// which may be followed by any number of "[index]" selectors for slices,
+// arrays, and maps, such as "Orders[0].Lines[2].SKU" or `Tags["env"]`. Map
+// keys may be quoted; unquoted keys are parsed according to the map's key
+// kind. Pointers and interfaces are followed automatically.
+//
+// A path which cannot be followed fails the test.
+func (e *Expectation) Field(path string) *Expectation {
+	e.t.Helper()
+
+	next := &Expectation{t: e.t, path: e.path, failed: e.failed}
+	if e.failed {
+		return next
+	}
+
+	if strings.HasPrefix(path, "[") {
+		next.path += path
+	} else {
+		next.path += "." + path
+	}
+
+	v, err := navigate(e.v, path)
+	if err != nil {
+		next.failed = true
+		e.t.Fatalf("cannot navigate to %s: %v", next.path, err)
+		return next
+	}
+
+	next.v = v
+	return next
+}
+
+// Equal asserts the value deeply equals want, as reported by reflect.DeepEqual.
+func (e *Expectation) Equal(want any) *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.value(); ok && !reflect.DeepEqual(got, want) {
+		e.fail("expected %s to equal %#v but got %#v", e.path, want, got)
+	}
+
+	return e
+}
+
+// NotEqual asserts the value does not deeply equal other.
+func (e *Expectation) NotEqual(other any) *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.value(); ok && reflect.DeepEqual(got, other) {
+		e.fail("expected %s to not equal %#v", e.path, other)
+	}
+
+	return e
+}
+
+// Zero asserts the value is the zero value of its type, or nil.
+func (e *Expectation) Zero() *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.value(); ok && e.v.IsValid() && !e.v.IsZero() {
+		e.fail("expected %s to be zero but got %#v", e.path, got)
+	}
+
+	return e
+}
+
+// NotZero asserts the value is not the zero value of its type.
+func (e *Expectation) NotZero() *Expectation {
+	e.t.Helper()
+
+	if _, ok := e.value(); ok && (!e.v.IsValid() || e.v.IsZero()) {
+		e.fail("expected %s to be non-zero", e.path)
+	}
+
+	return e
+}
+
+// Len asserts the value is a string, slice, array, map, or channel of length n.
+func (e *Expectation) Len(n int) *Expectation {
+	e.t.Helper()
+
+	got, ok := e.value()
+	if !ok {
+		return e
+	}
+
+	switch e.v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		if l := e.v.Len(); l != n {
+			e.fail("expected %s to have length %d but got %d: %#v", e.path, n, l, got)
+		}
+	default:
+		e.fail("expected %s to have a length but it is a %T", e.path, got)
+	}
+
+	return e
+}
+
+// Satisfies asserts check returns a nil error for the value.
+func (e *Expectation) Satisfies(check func(any) error) *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.value(); ok {
+		if err := check(got); err != nil {
+			e.fail("expected %s to satisfy check but got %#v: %v", e.path, got, err)
+		}
+	}
+
+	return e
+}
+
+// value returns the current value as an interface and whether assertions
+// should still be evaluated.
+func (e *Expectation) value() (any, bool) {
+	if e.failed {
+		return nil, false
+	}
+
+	if !e.v.IsValid() {
+		return nil, true
+	}
+
+	if !e.v.CanInterface() {
+		e.fail("cannot inspect %s: value is unexported", e.path)
+		return nil, false
+	}
+
+	return e.v.Interface(), true
+}
+
+func (e *Expectation) fail(format string, args ...any) {
+	e.t.Helper()
+
+	e.failed = true
+	e.t.Fatalf(format, args...)
+}
+
+var errNilValue = errors.New("nil value")
+
+// navigate follows path from v as described by Expectation.Field.
+func navigate(v reflect.Value, path string) (reflect.Value, error) {
+	for path != "" {
+		v = indirect(v)
+
+		if path[0] == '[' {
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return reflect.Value{}, errors.New("unterminated index in path")
+			}
+
+			var err error
+			v, err = index(v, path[1:end])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			path = strings.TrimPrefix(path[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(path, ".[")
+		if end < 0 {
+			end = len(path)
+		}
+
+		name := path[:end]
+		if name == "" {
+			return reflect.Value{}, errors.New("empty field name in path")
+		}
+
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("field %s: %w", name, errNilValue)
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("field %s: %s is not a struct", name, v.Type())
+		}
+
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			return reflect.Value{}, fmt.Errorf("field %s: no such field in %s", name, v.Type())
+		}
+
+		v = f
+		path = strings.TrimPrefix(path[end:], ".")
+	}
+
+	return v, nil
+}
+
+// indirect follows pointers and interfaces until reaching a concrete value,
+// returning the invalid Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func index(v reflect.Value, sel string) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("index [%s]: %w", sel, errNilValue)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, err := strconv.Atoi(sel)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("index [%s]: not an integer", sel)
+		}
+
+		if i < 0 || i >= v.Len() {
+			return reflect.Value{}, fmt.Errorf("index [%d]: out of range for length %d", i, v.Len())
+		}
+
+		return v.Index(i), nil
+	case reflect.Map:
+		k, err := mapKey(v.Type().Key(), sel)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key [%s]: %w", sel, err)
+		}
+
+		e := v.MapIndex(k)
+		if !e.IsValid() {
+			return reflect.Value{}, fmt.Errorf("key [%s]: not present", sel)
+		}
+
+		return e, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("index [%s]: %s cannot be indexed", sel, v.Type())
+}
+
+// mapKey converts a path selector into a key of type kt.
+func mapKey(kt reflect.Type, sel string) (reflect.Value, error) {
+	if s, err := strconv.Unquote(sel); err == nil {
+		sel = s
+	}
+
+	k := reflect.New(kt).Elem()
+	invalid := fmt.Errorf("not a valid %s", kt)
+
+	switch kt.Kind() {
+	case reflect.String:
+		k.SetString(sel)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(sel, 10, 64)
+		if err != nil || k.OverflowInt(n) {
+			return reflect.Value{}, invalid
+		}
+
+		k.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(sel, 10, 64)
+		if err != nil || k.OverflowUint(n) {
+			return reflect.Value{}, invalid
+		}
+
+		k.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(sel)
+		if err != nil {
+			return reflect.Value{}, invalid
+		}
+
+		k.SetBool(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported key type %s", kt)
+	}
+
+	return k, nil
+}
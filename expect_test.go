@@ -0,0 +1,118 @@
+package tbdd
+
+import (
+	"errors"
+	"testing"
+)
+
+type expectUser struct {
+	Email string
+	Tags  map[string]string
+	Nums  map[int8]bool
+	Flags map[bool]uint8
+	IDs   map[uint16]string
+	Keys  map[struct{}]int
+	Prev  *expectUser
+	any   int
+}
+
+type expectResult struct {
+	User   *expectUser
+	Orders []struct{ SKU string }
+	Extra  any
+	Array  [2]int
+}
+
+func newExpectResult() expectResult {
+	return expectResult{
+		User: &expectUser{
+			Email: "x@y.z",
+			Tags:  map[string]string{"env": "prod"},
+			Nums:  map[int8]bool{-3: true},
+			Flags: map[bool]uint8{true: 1},
+			IDs:   map[uint16]string{7: "seven"},
+		},
+		Orders: []struct{ SKU string }{{"a"}, {"b"}},
+		Extra:  &expectUser{Email: "extra"},
+		Array:  [2]int{1, 2},
+	}
+}
+
+func TestExpect(t *testing.T) {
+	t.Parallel()
+
+	r := newExpectResult()
+
+	Expect(t, r).Field("User.Email").Equal("x@y.z").NotEqual("other").NotZero()
+	Expect(t, r).Field(`User.Tags["env"]`).Equal("prod")
+	Expect(t, r).Field("User.Tags[env]").Equal("prod")
+	Expect(t, r).Field("User.Nums[-3]").Equal(true)
+	Expect(t, r).Field("User.Flags[true]").Equal(uint8(1))
+	Expect(t, r).Field("User.IDs[7]").Equal("seven")
+	Expect(t, r).Field("Orders[1].SKU").Equal("b")
+	Expect(t, r).Field("Orders").Len(2).Field("[0].SKU").Equal("a")
+	Expect(t, r).Field("Extra.Email").Equal("extra")
+	Expect(t, r).Field("Array[1]").Equal(2)
+	Expect(t, r).Field("User.Prev").Zero()
+	Expect(t, r).Field("User.Email[0]").Equal(byte('x'))
+	Expect(t, nil).Zero().Equal(nil)
+	Expect(t, 0).Zero()
+	Expect(t, r).Satisfies(func(v any) error {
+		if v.(expectResult).User == nil {
+			return errors.New("no user")
+		}
+
+		return nil
+	})
+}
+
+func TestExpect_failures(t *testing.T) {
+	t.Parallel()
+
+	r := newExpectResult()
+
+	for _, v := range []struct {
+		f   func(*Expectation)
+		exp string
+	}{
+		{func(e *Expectation) { e.Field("User.Email").Equal("other") }, "expected %s to equal %#v but got %#v"},
+		{func(e *Expectation) { e.Field("User.Email").NotEqual("x@y.z") }, "expected %s to not equal %#v"},
+		{func(e *Expectation) { e.Field("User.Email").Zero() }, "expected %s to be zero but got %#v"},
+		{func(e *Expectation) { e.Field("User.Prev").NotZero() }, "expected %s to be non-zero"},
+		{func(e *Expectation) { e.Field("Orders").Len(3) }, "expected %s to have length %d but got %d: %#v"},
+		{func(e *Expectation) { e.Field("User").Len(1) }, "expected %s to have a length but it is a %T"},
+		{func(e *Expectation) { e.Field("User.any").Equal(0) }, "cannot inspect %s: value is unexported"},
+		{func(e *Expectation) { e.Satisfies(func(any) error { return errors.New("no") }) }, "expected %s to satisfy check but got %#v: %v"},
+		{func(e *Expectation) { e.Field("Orders[").Equal(nil) }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User..Email") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Prev.Email") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Prev[0]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("Orders.SKU") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("Missing") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("Orders[x]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("Orders[2]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Tags[missing]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Nums[1000]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.IDs[-1]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Flags[maybe]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User.Keys[x]") }, "cannot navigate to %s: %v"},
+		{func(e *Expectation) { e.Field("User[0]") }, "cannot navigate to %s: %v"},
+	} {
+		mt := &mT{}
+
+		e := expect(mt, r)
+		v.f(e)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+
+	// a failed expectation stops evaluating
+	mt := &mT{}
+	expect(mt, r).Field("Missing").Field("Email").Equal("x").Len(1).NotZero()
+
+	if len(mt.fatalfCalls) != 1 {
+		t.Errorf("expected one fatalf call but got %v", mt.fatalfCalls)
+	}
+}
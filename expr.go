@@ -0,0 +1,189 @@
+package tbdd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// True fails the test, continuing it like t.Error, unless ok is true. The
+// failure is labeled with the source of the expression passed as ok, so a
+// bare condition is as easy to diagnose as one with a hand-written message:
+//
+//	tbdd.True(t, got.Status == want.Status)
+//
+// fails with "expected got.Status == want.Status to be true", linked to the
+// line which called True.
+//
+// The expression is read at run time from the source file of the caller,
+// which is parsed when the first assertion within it fails and cached for
+// the rest of the process, so passing assertions cost nothing. A test binary run where its sources are not
+// present, such as one built with go test -c and copied to another machine
+// or built with -trimpath, labels the failure "the condition" instead.
+func True(t *testing.T, ok bool) {
+	t.Helper()
+
+	isTrue(t, "True", ok)
+}
+
+// Equal fails the test, continuing it like t.Error, unless got deeply equals
+// want, as reported by reflect.DeepEqual. Like True, the failure is labeled
+// with the source of both expressions:
+//
+//	tbdd.Equal(t, got.Status, want.Status)
+//
+// fails with "expected got.Status to equal want.Status: got 3, want 4".
+func Equal[V any](t *testing.T, got, want V) {
+	t.Helper()
+
+	isEqual(t, "Equal", got, want)
+}
+
+// errorT is the subset of *testing.T that the labeled assertions depend on.
+type errorT interface {
+	Helper()
+	Error(args ...any)
+}
+
+// isTrue fails t unless ok, labeling the failure with the argument of the
+// call to fn which called it.
+func isTrue(t errorT, fn string, ok bool) {
+	t.Helper()
+
+	if ok {
+		return
+	}
+
+	f, _ := callerOutside()
+	labels := callArgs(f, fn, "the condition")
+
+	t.Error(link(frameSource(f)) + "expected " + labels[0] + " to be true")
+}
+
+// isEqual fails t unless got deeply equals want, labeling the failure with
+// the arguments of the call to fn which called it.
+func isEqual(t errorT, fn string, got, want any) {
+	t.Helper()
+
+	if reflect.DeepEqual(got, want) {
+		return
+	}
+
+	f, _ := callerOutside()
+	labels := callArgs(f, fn, "the value", "the expected value")
+
+	t.Error(link(frameSource(f)) + fmt.Sprintf("expected %s to equal %s: got %#v, want %#v", labels[0], labels[1], got, want))
+}
+
+// readSource reads the source files which label assertions.
+var readSource = os.ReadFile
+
+// parsed holds the source files parsed to label assertions, by path; the
+// files which cannot be read or parsed are held as nil.
+var parsed struct {
+	mu sync.Mutex
+	m  map[string]*sourceFile
+}
+
+type sourceFile struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+}
+
+// callArgs returns the source of the last arguments of the innermost call to
+// fn spanning the line of f, one for each of the fallbacks, with runs of
+// whitespace collapsed. It returns fallbacks when there is no such call
+// with more arguments than fallbacks, the first being t.
+func callArgs(f runtime.Frame, fn string, fallbacks ...string) []string {
+	sf := parseSource(f.File)
+	if sf == nil {
+		return fallbacks
+	}
+
+	var call *ast.CallExpr
+	ast.Inspect(sf.file, func(n ast.Node) bool {
+		c, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		if sf.fset.Position(c.Pos()).Line > f.Line || sf.fset.Position(c.End()).Line < f.Line {
+			// calls not spanning the line cannot contain one which does
+			return false
+		}
+
+		if calleeName(c.Fun) == fn && len(c.Args) > len(fallbacks) {
+			call = c
+		}
+
+		return true
+	})
+
+	if call == nil {
+		return fallbacks
+	}
+
+	labels := make([]string, len(fallbacks))
+	for i, arg := range call.Args[len(call.Args)-len(fallbacks):] {
+		start, end := sf.fset.Position(arg.Pos()).Offset, sf.fset.Position(arg.End()).Offset
+		labels[i] = strings.Join(strings.Fields(string(sf.src[start:end])), " ")
+	}
+
+	return labels
+}
+
+// calleeName returns the name of the function called by a call of fun, such
+// as "True" for tbdd.True or "Equal" for tbdd.Equal[int].
+func calleeName(fun ast.Expr) string {
+	switch e := fun.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return calleeName(e.X)
+	case *ast.IndexListExpr:
+		return calleeName(e.X)
+	}
+
+	return ""
+}
+
+// parseSource returns the parsed source file at path, or nil when it cannot
+// be read or parsed.
+func parseSource(path string) *sourceFile {
+	if path == "" {
+		return nil
+	}
+
+	parsed.mu.Lock()
+	defer parsed.mu.Unlock()
+
+	if sf, ok := parsed.m[path]; ok {
+		return sf
+	}
+
+	if parsed.m == nil {
+		parsed.m = map[string]*sourceFile{}
+	}
+
+	var sf *sourceFile
+	if src, err := readSource(path); err == nil {
+		fset := token.NewFileSet()
+		if file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution); err == nil {
+			sf = &sourceFile{fset, file, src}
+		}
+	}
+
+	parsed.m[path] = sf
+
+	return sf
+}
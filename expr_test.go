@@ -0,0 +1,138 @@
+package tbdd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func Test_isTrue(t *testing.T) {
+	t.Parallel()
+
+	got, want := struct{ Status int }{3}, struct{ Status int }{4}
+
+	mt := &mT{}
+	isTrue(mt, "isTrue", got.Status == 3)
+
+	if mt.Failed() {
+		t.Fatalf("expected a true condition to pass but got %v", mt.errorCalls)
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	isTrue(mt, "isTrue", got.Status ==
+		want.Status)
+
+	if exp := link(file+":"+strconv.Itoa(line+1)) + "expected got.Status == want.Status to be true"; len(mt.errorCalls) != 1 || mt.errorCalls[0][0] != exp {
+		t.Errorf("expected the failure '%s' but got %v", exp, mt.errorCalls)
+	}
+
+	mt = &mT{}
+	isEqual(mt, "isEqual", got.Status, 3)
+
+	if mt.Failed() {
+		t.Fatalf("expected equal values to pass but got %v", mt.errorCalls)
+	}
+
+	_, _, line, _ = runtime.Caller(0)
+	isEqual(mt, "isEqual", got.Status, want.Status)
+
+	if exp := link(file+":"+strconv.Itoa(line+1)) + "expected got.Status to equal want.Status: got 3, want 4"; len(mt.errorCalls) != 1 || mt.errorCalls[0][0] != exp {
+		t.Errorf("expected the failure '%s' but got %v", exp, mt.errorCalls)
+	}
+
+	// a call which cannot be found falls back to generic labels
+	mt = &mT{}
+	_, _, line, _ = runtime.Caller(0)
+	isTrue(mt, "True", false)
+
+	if exp := link(file+":"+strconv.Itoa(line+1)) + "expected the condition to be true"; len(mt.errorCalls) != 1 || mt.errorCalls[0][0] != exp {
+		t.Errorf("expected the failure '%s' but got %v", exp, mt.errorCalls)
+	}
+}
+
+func Test_isTrue_withoutSources(t *testing.T) {
+	origRead, origParsed := readSource, parsed.m
+	defer func() {
+		readSource, parsed.m = origRead, origParsed
+	}()
+
+	readSource = func(string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+	parsed.m = nil
+
+	mt := &mT{}
+	_, file, line, _ := runtime.Caller(0)
+	isTrue(mt, "isTrue", 1 > 2)
+	isEqual(mt, "isEqual", 3, 4)
+
+	exp := []string{
+		link(file+":"+strconv.Itoa(line+1)) + "expected the condition to be true",
+		link(file+":"+strconv.Itoa(line+2)) + "expected the value to equal the expected value: got 3, want 4",
+	}
+	if len(mt.errorCalls) != len(exp) || mt.errorCalls[0][0] != exp[0] || mt.errorCalls[1][0] != exp[1] {
+		t.Errorf("expected the failures %q but got %v", exp, mt.errorCalls)
+	}
+}
+
+func TestTrue(t *testing.T) {
+	t.Parallel()
+
+	True(t, true)
+	Equal(t, []int{1}, []int{1})
+	Equal[any](t, nil, nil)
+}
+
+func Test_callArgs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "a_test.go")
+	src := `package a
+
+func f() {
+	tbdd.Equal[int](t, a,
+		b)
+	tbdd.True(t, x(y(z) > 1))
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		line int
+		fn   string
+		exp  []string
+	}{
+		{4, "Equal", []string{"a", "b"}},
+		{5, "Equal", []string{"a", "b"}},
+		{6, "True", []string{"x(y(z) > 1)"}},
+		{6, "Equal", []string{"-", "-"}},
+		{7, "True", []string{"-"}},
+	} {
+		fallbacks := make([]string, len(tc.exp))
+		for i := range fallbacks {
+			fallbacks[i] = "-"
+		}
+
+		labels := callArgs(runtime.Frame{File: path, Line: tc.line}, tc.fn, fallbacks...)
+		if len(labels) != len(tc.exp) || labels[0] != tc.exp[0] || labels[len(labels)-1] != tc.exp[len(tc.exp)-1] {
+			t.Errorf("expected the labels %q of %s on line %d but got %q", tc.exp, tc.fn, tc.line, labels)
+		}
+	}
+
+	// files which cannot be read or parsed are not labeled
+	broken := filepath.Join(t.TempDir(), "b_test.go")
+	if err := os.WriteFile(broken, []byte(src+"func ("), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{"", broken, filepath.Join(t.TempDir(), "missing_test.go")} {
+		if labels := callArgs(runtime.Frame{File: file, Line: 6}, "True", "-"); labels[0] != "-" {
+			t.Errorf("expected the fallback label for '%s' but got %q", file, labels)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tbdd
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// leakT is the subset of *testing.T that descriptor leak checks depend on.
+type leakT interface {
+	Cleanup(func())
+	Errorf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// checkFDLeaks records the descriptors listed by openFDs and registers a
+// cleanup on t which fails it with every descriptor open once t completes
+// that was not open before.
+//
+// The check is only logged as unavailable when the descriptors cannot be
+// listed, such as on platforms without support.
+func checkFDLeaks(t leakT, openFDs func() (map[int]string, error)) {
+	before, err := openFDs()
+	if err != nil {
+		t.Logf("tbdd: descriptor leak check unavailable: %v", err)
+		return
+	}
+
+	t.Cleanup(func() {
+		after, err := openFDs()
+		if err != nil {
+			t.Logf("tbdd: descriptor leak check unavailable: %v", err)
+			return
+		}
+
+		if leaked := leakedFDs(before, after); len(leaked) > 0 {
+			t.Errorf("scenario leaked %d file descriptors:\n\t%s", len(leaked), strings.Join(leaked, "\n\t"))
+		}
+	})
+}
+
+// leakedFDs describes every descriptor of after which is not in before, or
+// which now refers to something else, in ascending order.
+func leakedFDs(before, after map[int]string) []string {
+	var fds []int
+	for fd, desc := range after {
+		if prev, ok := before[fd]; !ok || prev != desc {
+			fds = append(fds, fd)
+		}
+	}
+
+	slices.Sort(fds)
+
+	leaked := make([]string, len(fds))
+	for i, fd := range fds {
+		leaked[i] = "+ " + strconv.Itoa(fd) + " " + after[fd]
+	}
+
+	return leaked
+}
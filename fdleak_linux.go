@@ -0,0 +1,40 @@
+package tbdd
+
+import (
+	"os"
+	"strconv"
+)
+
+// openFDs returns the targets of the open descriptors of the process, such
+// as file paths and "socket:[inode]", keyed by descriptor.
+//
+// The epoll and eventfd descriptors created on demand by the runtime's
+// network poller are excluded.
+func openFDs() (map[int]string, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make(map[int]string, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+
+		// the descriptor used to read the directory is already closed
+		target, err := os.Readlink("/proc/self/fd/" + e.Name())
+		if err != nil {
+			continue
+		}
+
+		if target == "anon_inode:[eventpoll]" || target == "anon_inode:[eventfd]" {
+			continue
+		}
+
+		fds[fd] = target
+	}
+
+	return fds, nil
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package tbdd
+
+import (
+	"errors"
+	"runtime"
+)
+
+// openFDs reports that descriptors cannot be listed on this platform.
+func openFDs() (map[int]string, error) {
+	return nil, errors.New("not supported on " + runtime.GOOS)
+}
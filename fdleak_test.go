@@ -0,0 +1,188 @@
+package tbdd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_fdLeakCheck(t *testing.T) {
+	if os.Getenv("TBDD_FDLEAK_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"a file is left open", func(t *testing.T, _ struct{}) {
+				if _, err := os.Create(filepath.Join(t.TempDir(), "leaked.txt")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			"it is reported", func(*testing.T, struct{}) {},
+		).With(WithFDLeakCheck[struct{}, struct{}]()).New(t)
+		f(t)
+		return
+	}
+
+	// descriptors closed by cleanups are not leaks
+	open := func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "closed.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			f.Close()
+		})
+	}
+
+	f := WTN(
+		struct{}{},
+		"a file is closed by a cleanup", func(t *testing.T, _ struct{}) {
+			open(t)
+		},
+		"no leak is reported", func(*testing.T, struct{}) {},
+	).With(WithFDLeakCheck[struct{}, struct{}]()).New(t)
+	f(t)
+
+	f = GWTN(
+		struct{}{},
+		"a file opened by the given phase", func(t *testing.T, _ *struct{}) {
+			open(t)
+		},
+		"the behavior runs", func(*testing.T, struct{}) {},
+		"no leak is reported", func(*testing.T, struct{}) {},
+	).With(WithFDLeakCheck[struct{}, struct{}]()).New(t)
+	f(t)
+
+	//
+	// leaked descriptors fail the scenario
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_FDLEAK_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+
+	if _, err := openFDs(); err != nil {
+		if !strings.Contains(string(out), "tbdd: descriptor leak check unavailable: ") {
+			t.Errorf("expected the check to be reported as unavailable:\n%s", out)
+		}
+		return
+	}
+
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_a_file_is_left_open ",
+		"scenario leaked 1 file descriptors:\n",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+// mLeakT records the interactions of a descriptor leak check.
+type mLeakT struct {
+	cleanups []func()
+	errors   []string
+	logs     []string
+}
+
+func (m *mLeakT) Cleanup(f func()) {
+	m.cleanups = append(m.cleanups, f)
+}
+
+func (m *mLeakT) Errorf(format string, _ ...any) {
+	m.errors = append(m.errors, format)
+}
+
+func (m *mLeakT) Logf(format string, _ ...any) {
+	m.logs = append(m.logs, format)
+}
+
+func Test_checkFDLeaks(t *testing.T) {
+	t.Parallel()
+
+	list := func(snapshots ...map[int]string) func() (map[int]string, error) {
+		return func() (map[int]string, error) {
+			if len(snapshots) == 0 {
+				return nil, errors.New("unsupported")
+			}
+
+			fds := snapshots[0]
+			snapshots = snapshots[1:]
+
+			return fds, nil
+		}
+	}
+
+	mt := &mLeakT{}
+	checkFDLeaks(mt, list())
+	if len(mt.cleanups) != 0 || !reflect.DeepEqual(mt.logs, []string{"tbdd: descriptor leak check unavailable: %v"}) {
+		t.Errorf("expected only the unavailable check to be logged: %+v", mt)
+	}
+
+	mt = &mLeakT{}
+	checkFDLeaks(mt, list(map[int]string{0: "/dev/null"}))
+	mt.cleanups[0]()
+	if len(mt.errors) != 0 || !reflect.DeepEqual(mt.logs, []string{"tbdd: descriptor leak check unavailable: %v"}) {
+		t.Errorf("expected only the unavailable check to be logged: %+v", mt)
+	}
+
+	mt = &mLeakT{}
+	checkFDLeaks(mt, list(map[int]string{0: "/dev/null"}, map[int]string{0: "/dev/null"}))
+	mt.cleanups[0]()
+	if len(mt.errors) != 0 || len(mt.logs) != 0 {
+		t.Errorf("expected no leak to be reported: %+v", mt)
+	}
+
+	mt = &mLeakT{}
+	checkFDLeaks(mt, list(map[int]string{0: "/dev/null"}, map[int]string{0: "/dev/null", 3: "socket:[1]"}))
+	mt.cleanups[0]()
+	if !reflect.DeepEqual(mt.errors, []string{"scenario leaked %d file descriptors:\n\t%s"}) {
+		t.Errorf("expected the leak to be reported: %+v", mt)
+	}
+}
+
+func Test_leakedFDs(t *testing.T) {
+	t.Parallel()
+
+	before := map[int]string{0: "/dev/null", 3: "/tmp/a", 4: "/tmp/b"}
+	after := map[int]string{0: "/dev/null", 4: "/tmp/c", 7: "socket:[42]", 5: "pipe:[9]"}
+
+	exp := []string{"+ 4 /tmp/c", "+ 5 pipe:[9]", "+ 7 socket:[42]"}
+	if got := leakedFDs(before, after); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %q but got %q", exp, got)
+	}
+
+	if got := leakedFDs(after, before); len(got) != 2 {
+		t.Errorf("expected only the descriptors which changed or were opened but got %q", got)
+	}
+}
+
+func Test_openFDs(t *testing.T) {
+	t.Parallel()
+
+	if _, err := openFDs(); err != nil {
+		t.Skipf("descriptors cannot be listed: %v", err)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "open.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	fds, err := openFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := fds[int(f.Fd())]; !ok {
+		t.Errorf("expected descriptor %d of the open file to be listed: %v", f.Fd(), fds)
+	}
+}
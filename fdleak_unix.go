@@ -0,0 +1,58 @@
+//go:build unix && !linux
+
+package tbdd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// openFDs returns a description of each open descriptor of the process,
+// such as "socket (inode 42)", keyed by descriptor.
+//
+// Descriptors without a file type, such as the kqueue of the runtime's
+// network poller, are excluded.
+func openFDs() (map[int]string, error) {
+	entries, err := os.ReadDir("/dev/fd")
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make(map[int]string, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+
+		// the descriptor used to read the directory is already closed
+		var st syscall.Stat_t
+		if err := syscall.Fstat(fd, &st); err != nil {
+			continue
+		}
+
+		var kind string
+		switch st.Mode & syscall.S_IFMT {
+		case syscall.S_IFSOCK:
+			kind = "socket"
+		case syscall.S_IFIFO:
+			kind = "pipe"
+		case syscall.S_IFREG:
+			kind = "file"
+		case syscall.S_IFDIR:
+			kind = "directory"
+		case syscall.S_IFCHR:
+			kind = "device"
+		case 0:
+			continue
+		default:
+			kind = "descriptor"
+		}
+
+		fds[fd] = fmt.Sprintf("%s (inode %d)", kind, st.Ino)
+	}
+
+	return fds, nil
+}
@@ -0,0 +1,255 @@
+package tbdd
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Flag is a boolean feature flag of a test case of type T.
+type Flag[T any] struct {
+	// Name identifies the flag within variant Kinds.
+	Name string
+	// Set enables or disables the flag of a test case.
+	Set func(tc *T, enabled bool)
+}
+
+// FlagCombinations selects which combinations of flags FlagVariants yields.
+type FlagCombinations uint8
+
+const (
+	// AllFlagCombinations yields every combination of the flags: 2^n variants
+	// for n flags, generated one at a time so MaxVariants bounds the work.
+	AllFlagCombinations FlagCombinations = iota + 1
+	// PairwiseFlagCombinations yields a small set of combinations in which
+	// every pair of flags appears with each of its four pairs of values at
+	// least once, which finds most interaction bugs with far fewer scenarios.
+	PairwiseFlagCombinations
+)
+
+func (c FlagCombinations) String() string {
+	switch c {
+	case AllFlagCombinations:
+		return "all"
+	case PairwiseFlagCombinations:
+		return "pairwise"
+	}
+
+	return "FlagCombinations(" + strconv.Itoa(int(c)) + ")"
+}
+
+// FlagVariants returns a Variants function yielding a variant of the basis
+// test case for each combination of flags selected by c. Every flag of a
+// variant is set, whether enabled or disabled, and its Kind names the
+// enabled flags in declaration order, such as "flags: beta, dark-mode", or
+// is "flags: none".
+//
+// Set is called on a copy of the basis test case, before it is cloned with
+// CloneTC, so flags must not be stored in memory shared with the basis.
+//
+// FlagVariants panics if c is unknown, if c is AllFlagCombinations and there
+// are 63 or more flags, whose combinations cannot be counted, or if a flag
+// has an empty or duplicate Name or a nil Set function.
+func FlagVariants[T any](c FlagCombinations, flags ...Flag[T]) func(*testing.T, T) iter.Seq[TestVariant[T]] {
+	validateFlags("tbdd.FlagVariants", flags)
+
+	var combinations iter.Seq[[]bool]
+	switch c {
+	case AllFlagCombinations:
+		if len(flags) >= maxAllFlags {
+			panic("tbdd.FlagVariants: all combinations of " + strconv.Itoa(len(flags)) + " flags cannot be enumerated; use at most " +
+				strconv.Itoa(maxAllFlags-1) + " flags or PairwiseFlagCombinations")
+		}
+
+		combinations = allCombinations(len(flags))
+	case PairwiseFlagCombinations:
+		combinations = slices.Values(pairwiseCombinations(len(flags)))
+	default:
+		panic("tbdd.FlagVariants: unknown " + c.String())
+	}
+
+	return flagVariants(flags, combinations)
+}
+
+// FlagSetVariants is like FlagVariants except it yields one variant for each
+// of the explicit sets, which list the names of the flags to enable; every
+// other flag is disabled.
+//
+// FlagSetVariants panics if a set names an unknown flag, or a flag has an
+// empty or duplicate Name or a nil Set function.
+func FlagSetVariants[T any](flags []Flag[T], sets ...[]string) func(*testing.T, T) iter.Seq[TestVariant[T]] {
+	index := validateFlags("tbdd.FlagSetVariants", flags)
+
+	combinations := make([][]bool, len(sets))
+	for i, set := range sets {
+		combinations[i] = make([]bool, len(flags))
+		for _, name := range set {
+			j, ok := index[name]
+			if !ok {
+				panic("tbdd.FlagSetVariants: set " + strconv.Itoa(i) + " names unknown flag " + strconv.Quote(name))
+			}
+
+			combinations[i][j] = true
+		}
+	}
+
+	return flagVariants(flags, slices.Values(combinations))
+}
+
+// validateFlags panics on behalf of fn if flags are misconfigured and
+// returns the index of every flag by name.
+func validateFlags[T any](fn string, flags []Flag[T]) map[string]int {
+	index := make(map[string]int, len(flags))
+	for i, f := range flags {
+		prefix := fn + ": flag " + strconv.Itoa(i)
+		switch _, dup := index[f.Name]; {
+		case f.Name == "":
+			panic(prefix + " must have a non-empty Name")
+		case dup:
+			panic(prefix + " has the duplicate Name " + strconv.Quote(f.Name))
+		case f.Set == nil:
+			panic(prefix + " must have a non-nil Set function")
+		}
+
+		index[f.Name] = i
+	}
+
+	return index
+}
+
+func flagVariants[T any](flags []Flag[T], combinations iter.Seq[[]bool]) func(*testing.T, T) iter.Seq[TestVariant[T]] {
+	return func(_ *testing.T, basis T) iter.Seq[TestVariant[T]] {
+		return func(yield func(TestVariant[T]) bool) {
+			for enabled := range combinations {
+				tc := basis
+
+				var names []string
+				for i, f := range flags {
+					f.Set(&tc, enabled[i])
+					if enabled[i] {
+						names = append(names, f.Name)
+					}
+				}
+
+				kind := "flags: none"
+				if len(names) > 0 {
+					kind = "flags: " + strings.Join(names, ", ")
+				}
+
+				if !yield(TestVariant[T]{Kind: kind, TC: tc}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// maxAllFlags is the smallest number of flags whose combinations cannot be
+// counted by an int64.
+const maxAllFlags = 63
+
+// allCombinations returns every combination of n boolean values, where n is
+// less than maxAllFlags, generated one at a time counting up from all false
+// with the first value as the least significant bit.
+func allCombinations(n int) iter.Seq[[]bool] {
+	return func(yield func([]bool) bool) {
+		for i := range int64(1) << n {
+			combination := make([]bool, n)
+			for j := range n {
+				combination[j] = i&(1<<j) != 0
+			}
+
+			if !yield(combination) {
+				return
+			}
+		}
+	}
+}
+
+// pairwiseCombinations returns combinations of n boolean values covering
+// every pair of values of every two positions, built greedily: each
+// combination starts from the first uncovered pair and assigns each
+// remaining position the value covering the most uncovered pairs.
+func pairwiseCombinations(n int) [][]bool {
+	if n < 2 {
+		return slices.Collect(allCombinations(n))
+	}
+
+	// uncovered[i][j][a][b] is true while positions i < j have not yet held
+	// the values a and b together
+	type pair = [2][2]bool
+	uncovered := make([][]pair, n)
+	remaining := 0
+	for i := range n {
+		uncovered[i] = make([]pair, n)
+		for j := i + 1; j < n; j++ {
+			uncovered[i][j] = pair{{true, true}, {true, true}}
+			remaining += 4
+		}
+	}
+
+	b2i := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	var combinations [][]bool
+	for remaining > 0 {
+		row := make([]bool, n)
+		assigned := make([]bool, n)
+
+	seed:
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				for a := range 2 {
+					for b := range 2 {
+						if uncovered[i][j][a][b] {
+							row[i], row[j] = a == 1, b == 1
+							assigned[i], assigned[j] = true, true
+							break seed
+						}
+					}
+				}
+			}
+		}
+
+		for k := range n {
+			if assigned[k] {
+				continue
+			}
+
+			var gain [2]int
+			for v := range 2 {
+				for i := range n {
+					if !assigned[i] {
+						continue
+					}
+
+					if i < k && uncovered[i][k][b2i(row[i])][v] || k < i && uncovered[k][i][v][b2i(row[i])] {
+						gain[v]++
+					}
+				}
+			}
+
+			row[k] = gain[1] > gain[0]
+			assigned[k] = true
+		}
+
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				if p := &uncovered[i][j][b2i(row[i])][b2i(row[j])]; *p {
+					*p = false
+					remaining--
+				}
+			}
+		}
+
+		combinations = append(combinations, row)
+	}
+
+	return combinations
+}
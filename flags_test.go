@@ -0,0 +1,170 @@
+package tbdd
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type flagsTC struct {
+	beta, dark, fast bool
+}
+
+var testFlags = []Flag[flagsTC]{
+	{"beta", func(tc *flagsTC, v bool) { tc.beta = v }},
+	{"dark-mode", func(tc *flagsTC, v bool) { tc.dark = v }},
+	{"fast", func(tc *flagsTC, v bool) { tc.fast = v }},
+}
+
+func flagKinds(t *testing.T, f func(*testing.T, flagsTC) iter.Seq[TestVariant[flagsTC]]) ([]string, []flagsTC) {
+	var kinds []string
+	var tcs []flagsTC
+	for v := range f(t, flagsTC{fast: true}) {
+		kinds = append(kinds, v.Kind)
+		tcs = append(tcs, v.TC)
+	}
+
+	return kinds, tcs
+}
+
+func TestFlagVariants(t *testing.T) {
+	var kinds []string
+	f := GWTN(
+		flagsTC{},
+		"some flags", func(*testing.T, *flagsTC) {},
+		"the behavior runs", func(t *testing.T, tc flagsTC) {
+			kinds = append(kinds, t.Name())
+		},
+		"it passes", func(*testing.T, flagsTC) {},
+	).With(WithVariants[flagsTC, struct{}](FlagVariants(AllFlagCombinations, testFlags[:2]...))).New(t)
+	f(t)
+
+	if len(kinds) != 5 {
+		t.Errorf("expected the basis and four variants to run but got %v", kinds)
+	}
+
+	all, tcs := flagKinds(t, FlagVariants(AllFlagCombinations, testFlags...))
+	expAll := []string{
+		"flags: none",
+		"flags: beta",
+		"flags: dark-mode",
+		"flags: beta, dark-mode",
+		"flags: fast",
+		"flags: beta, fast",
+		"flags: dark-mode, fast",
+		"flags: beta, dark-mode, fast",
+	}
+	if !slices.Equal(all, expAll) {
+		t.Errorf("expected kinds %q but got %q", expAll, all)
+	}
+
+	if tcs[0] != (flagsTC{}) || tcs[7] != (flagsTC{true, true, true}) {
+		t.Errorf("expected every flag to be set but got %+v", tcs)
+	}
+
+	sets, _ := flagKinds(t, FlagSetVariants(testFlags, nil, []string{"fast", "beta"}))
+	if expSets := []string{"flags: none", "flags: beta, fast"}; !slices.Equal(sets, expSets) {
+		t.Errorf("expected kinds %q but got %q", expSets, sets)
+	}
+
+	// iteration stops when asked to
+	for range FlagVariants(PairwiseFlagCombinations, testFlags...)(t, flagsTC{}) {
+		break
+	}
+}
+
+func Test_pairwiseCombinations(t *testing.T) {
+	t.Parallel()
+
+	for n := range 12 {
+		combinations := pairwiseCombinations(n)
+
+		if n < 2 {
+			if len(combinations) != 1<<n {
+				t.Errorf("n = %d: expected every combination but got %v", n, combinations)
+			}
+			continue
+		}
+
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				for _, want := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+					if !slices.ContainsFunc(combinations, func(c []bool) bool {
+						return c[i] == want[0] && c[j] == want[1]
+					}) {
+						t.Errorf("n = %d: flags %d and %d never hold %v", n, i, j, want)
+					}
+				}
+			}
+		}
+
+		if n >= 4 && len(combinations) >= 1<<n {
+			t.Errorf("n = %d: expected fewer than every combination but got %d", n, len(combinations))
+		}
+	}
+
+	if n := len(pairwiseCombinations(10)); n > 12 {
+		t.Errorf("expected at most 12 combinations of 10 flags but got %d", n)
+	}
+}
+
+// manyFlags returns n flags which set nothing.
+func manyFlags(n int) []Flag[flagsTC] {
+	flags := make([]Flag[flagsTC], n)
+	for i := range flags {
+		flags[i] = Flag[flagsTC]{"f" + strconv.Itoa(i), func(*flagsTC, bool) {}}
+	}
+
+	return flags
+}
+
+func TestFlagVariants_lazy(t *testing.T) {
+	t.Parallel()
+
+	// the combinations of many flags are generated as they are consumed
+	var kinds []string
+	for v := range FlagVariants(AllFlagCombinations, manyFlags(62)...)(t, flagsTC{}) {
+		kinds = append(kinds, v.Kind)
+		if len(kinds) == 3 {
+			break
+		}
+	}
+
+	if exp := []string{"flags: none", "flags: f0", "flags: f1"}; !slices.Equal(kinds, exp) {
+		t.Errorf("expected the kinds %q but got %q", exp, kinds)
+	}
+}
+
+func TestFlagVariants_panics(t *testing.T) {
+	set := func(*flagsTC, bool) {}
+
+	tests := []struct {
+		name string
+		f    func()
+		exp  string
+	}{
+		{"unknown combinations", func() { FlagVariants[flagsTC](0) }, "tbdd.FlagVariants: unknown FlagCombinations(0)"},
+		{"empty name", func() { FlagVariants(AllFlagCombinations, Flag[flagsTC]{Set: set}) }, "tbdd.FlagVariants: flag 0 must have a non-empty Name"},
+		{"duplicate name", func() { FlagVariants(AllFlagCombinations, Flag[flagsTC]{"a", set}, Flag[flagsTC]{"a", set}) }, `tbdd.FlagVariants: flag 1 has the duplicate Name "a"`},
+		{"nil set", func() { FlagVariants(AllFlagCombinations, Flag[flagsTC]{Name: "a"}) }, "tbdd.FlagVariants: flag 0 must have a non-nil Set function"},
+		{"unknown flag", func() { FlagSetVariants(testFlags, []string{"beta"}, []string{"nope"}) }, `tbdd.FlagSetVariants: set 1 names unknown flag "nope"`},
+		{"too many flags", func() { FlagVariants(AllFlagCombinations, manyFlags(63)...) }, "tbdd.FlagVariants: all combinations of 63 flags cannot be enumerated; use at most 62 flags or PairwiseFlagCombinations"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			tc.f()
+		})
+	}
+
+	if s := PairwiseFlagCombinations.String(); s != "pairwise" {
+		t.Errorf("unexpected String: %s", s)
+	}
+}
@@ -0,0 +1,96 @@
+package tbdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Golden fails the test unless got, encoded as JSON with masks applied,
+// is equivalent to the JSON document in the golden file at path. Strings,
+// byte slices, and json.RawMessage values are treated as JSON documents;
+// any other value is encoded with encoding/json.
+//
+// When the -tbdd.update-golden flag is set the golden file is (re)written
+// with the masked document instead.
+func Golden(t *testing.T, path string, got any, masks ...Mask) {
+	t.Helper()
+
+	golden(linkedT{T: t}, path, got, masks)
+}
+
+// Golden compares the Result of the scenario against the golden file at
+// path as Golden does, applying the masks of the Lifecycle.
+func (a Assert[T, R]) Golden(t *testing.T, path string) {
+	t.Helper()
+
+	golden(linkedT{T: t}, path, a.Result, a.Artifacts.Masks())
+}
+
+// goldenT is the subset of *testing.T that golden comparisons depend on.
+type goldenT interface {
+	assertT
+	Logf(format string, args ...any)
+}
+
+func golden(t goldenT, path string, got any, masks []Mask) {
+	t.Helper()
+
+	b, err := maskAny(got, masks)
+	if err != nil {
+		t.Fatalf("failed to mask value for golden file %s: %v", path, err)
+		return
+	}
+
+	if config.updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+			return
+		}
+
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", path, err)
+			return
+		}
+
+		t.Logf("updated golden file %s", path)
+		RecordUpdate("golden", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v; run with -tbdd.update-golden to create it", path, err)
+		return
+	}
+
+	w, err := decodeJSON(want)
+	if err != nil {
+		t.Fatalf("golden file %s is invalid JSON: %v", path, err)
+		return
+	}
+
+	// masked documents are always valid
+	g, _ := decodeJSON(b)
+
+	if p, ok := jsonDiff("$", w, g); !ok {
+		t.Fatalf("value does not match golden file %s at %s:\nexpected:\n%s\nactual:\n%s", path, p, bytes.TrimSpace(want), b)
+	}
+}
+
+// maskAny returns v as indented JSON with masks applied, treating strings,
+// byte slices, and json.RawMessage values as JSON documents.
+func maskAny(v any, masks []Mask) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return MaskJSON([]byte(v), masks...)
+	case []byte:
+		return MaskJSON(v, masks...)
+	case json.RawMessage:
+		return MaskJSON(v, masks...)
+	}
+
+	return MaskValue(v, masks...)
+}
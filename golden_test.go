@@ -0,0 +1,141 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var _ goldenT = (*testing.T)(nil)
+
+type mGoldenT struct {
+	mT
+	logs []string
+}
+
+func (t *mGoldenT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+type goldenResult struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func TestGolden(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	path := filepath.Join(t.TempDir(), "testdata", "result.golden")
+
+	//
+	// updating writes the masked value
+	//
+
+	config.updateGolden = true
+
+	mt := &mGoldenT{}
+	golden(mt, path, goldenResult{"generated", 3}, []Mask{{Path: "ID"}})
+	if len(mt.fatalfCalls) != 0 || len(mt.logs) != 1 || mt.logs[0] != "updated golden file "+path {
+		t.Fatalf("expected the golden file to be updated but got %v %v", mt.fatalfCalls, mt.logs)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil || string(b) != "{\n  \"id\": \"<masked>\",\n  \"total\": 3\n}\n" {
+		t.Fatalf("expected the masked value to be written but got %q %v", b, err)
+	}
+
+	//
+	// comparisons apply the masks to the new value
+	//
+
+	config.updateGolden = false
+
+	Golden(t, path, goldenResult{"other", 3}, Mask{Path: "ID"})
+	Golden(t, path, `{"total": 3, "id": "x"}`, Mask{Path: "$.id"})
+	Golden(t, path, []byte(`{"total": 3, "id": "<masked>"}`))
+	Golden(t, path, json.RawMessage(`{"total": 3, "id": "<masked>"}`))
+
+	f := WT(
+		goldenResult{"run-specific", 3},
+		"the result is produced", func(_ *testing.T, tc goldenResult) goldenResult { return tc },
+		"it matches the golden file", func(*testing.T, goldenResult, goldenResult) {},
+	).With(
+		WithMasks[goldenResult, goldenResult](Mask{Path: "$.id"}),
+		WithAssert(func(t *testing.T, a Assert[goldenResult, goldenResult]) {
+			a.Golden(t, path)
+
+			a.Artifacts.WriteJSON(t, "result.json", a.Result)
+
+			b, err := os.ReadFile(filepath.Join(a.Artifacts.Dir(t), "result.json"))
+			if err != nil || !strings.Contains(string(b), `"id": "<masked>"`) {
+				t.Errorf("expected the artifact to be masked but got %q %v", b, err)
+			}
+		}),
+	).New(t)
+	f(t)
+
+	// a zero Assert has no masks
+	Assert[goldenResult, goldenResult]{Result: goldenResult{"<masked>", 3}}.Golden(t, path)
+}
+
+func TestGolden_failures(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "result.golden")
+	if err := os.WriteFile(path, []byte(`{"id": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.golden")
+	if err := os.WriteFile(invalid, []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	big := filepath.Join(dir, "big.golden")
+	if err := os.WriteFile(big, []byte(`{"id": 9007199254740993}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := filepath.Join(dir, "missing.golden")
+
+	for _, v := range []struct {
+		update bool
+		path   string
+		got    any
+		masks  []Mask
+		exp    string
+	}{
+		{false, path, `{`, nil, "failed to mask value for golden file " + path + ": unexpected EOF"},
+		{false, missing, `{}`, nil, "failed to read golden file " + missing + ": open " + missing + ": no such file or directory; run with -tbdd.update-golden to create it"},
+		{false, invalid, `{}`, nil, "golden file " + invalid + " is invalid JSON: unexpected end of JSON input"},
+		{false, path, `{"id": 2}`, nil, "value does not match golden file " + path + " at $.id:\nexpected:\n{\"id\": 1}\nactual:\n{\n  \"id\": 2\n}"},
+		{false, big, `{"id": 9007199254740992}`, nil, "value does not match golden file " + big + " at $.id:\nexpected:\n{\"id\": 9007199254740993}\nactual:\n{\n  \"id\": 9007199254740992\n}"},
+		{true, filepath.Join(path, "x"), `{}`, nil, "failed to create golden directory: mkdir " + path + ": not a directory"},
+		{true, dir, `{}`, nil, "failed to update golden file " + dir + ": open " + dir + ": is a directory"},
+	} {
+		config.updateGolden = v.update
+
+		mt := &mGoldenT{}
+		golden(mt, v.path, v.got, v.masks)
+
+		if len(mt.fatalfCalls) != 1 {
+			t.Errorf("expected one fatalf call with message %q but got %v", v.exp, mt.fatalfCalls)
+			continue
+		}
+
+		if msg := fmt.Sprintf(mt.fatalfCalls[0].format, mt.fatalfCalls[0].args...); msg != v.exp {
+			t.Errorf("expected fatalf message %q but got %q", v.exp, msg)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package tbdd
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// Scenario is anything that can construct a test function, such as a
+// Lifecycle or a Group.
+type Scenario interface {
+	New(*testing.T) func(*testing.T)
+}
+
+// Group composes several scenarios into one. Groups are scenarios
+// themselves, so they can be nested.
+//
+// Each member scenario runs as its own subtest named after its index within
+// the group; the group itself is rendered under whatever subtest its test
+// function is passed to.
+type Group struct {
+	scenarios  []Scenario
+	sequential bool
+	parallel   bool
+	shuffle    bool
+	seed       uint64
+}
+
+// Sequence returns a Group whose scenarios run in the declared order.
+//
+// Scenarios in a sequence usually share the outcomes of earlier scenarios
+// through the values they capture or reference from their test cases. For
+// that reason, once a scenario fails every remaining scenario of the
+// sequence is skipped rather than run against an unexpected state.
+func Sequence(scenarios ...Scenario) Group {
+	return Group{scenarios: scenarios, sequential: true}
+}
+
+// Independent returns a Group whose scenarios do not depend on each other.
+// Every scenario runs regardless of the outcome of the others and the group
+// may be configured to run them in parallel or in shuffled order.
+//
+// Within any group, a Lifecycle whose DependsOn names the scenarios of
+// another member runs after that member, even when declared before it.
+// Under a -tbdd.budget, members otherwise run by descending Priority so the
+// most important run before the budget is spent. Members of a Parallel group
+// are not ordered.
+func Independent(scenarios ...Scenario) Group {
+	return Group{scenarios: scenarios}
+}
+
+// Parallel returns a copy of the group whose scenarios each call t.Parallel
+// before running.
+//
+// Parallel panics if the group is a Sequence.
+func (g Group) Parallel() Group {
+	if g.sequential {
+		panic("tbdd.Group: a Sequence cannot run in parallel")
+	}
+
+	g.parallel = true
+	return g
+}
+
+// Shuffle returns a copy of the group whose scenarios run in an order
+// determined by seed. The same seed always produces the same order.
+//
+// Shuffle panics if the group is a Sequence.
+func (g Group) Shuffle(seed uint64) Group {
+	if g.sequential {
+		panic("tbdd.Group: a Sequence cannot be shuffled")
+	}
+
+	g.shuffle = true
+	g.seed = seed
+	return g
+}
+
+// New takes a *testing.T to construct sub-tests for every scenario of the
+// group.
+func (g Group) New(t *testing.T) func(*testing.T) {
+	t.Helper()
+
+	fs := make([]func(*testing.T), len(g.scenarios))
+	for i, s := range g.scenarios {
+		fs[i] = s.New(t)
+	}
+
+	return func(t *testing.T) {
+		t.Helper()
+
+		g.run(t, fs)
+	}
+}
+
+// groupT is the subset of *testing.T a Group depends on.
+type groupT interface {
+	Helper()
+	Run(string, func(*testing.T)) bool
+}
+
+func (g Group) run(t groupT, fs []func(*testing.T)) {
+	t.Helper()
+
+	order := make([]int, len(fs))
+	for i := range order {
+		order[i] = i
+	}
+
+	if g.shuffle {
+		r := rand.New(rand.NewPCG(g.seed, g.seed))
+		r.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	var failedIndex = -1
+	for len(order) > 0 {
+		i := g.next(order)
+		order = slices.DeleteFunc(order, func(j int) bool { return j == i })
+
+		f := fs[i]
+
+		if failedIndex >= 0 {
+			t.Run(strconv.Itoa(i), func(t *testing.T) {
+				nillableT{t, nil}.Skip("not run: scenario " + strconv.Itoa(failedIndex) + " of the sequence failed")
+			})
+			continue
+		}
+
+		ok := t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if g.parallel {
+				nillableT{t, nil}.Parallel()
+			}
+			if g.shuffle && t != nil {
+				t.Cleanup(func() {
+					if t.Failed() {
+						t.Logf("tbdd: ran in an order shuffled with seed %d; pass the same seed to Shuffle to reproduce it", g.seed)
+					}
+				})
+			}
+
+			f(t)
+		})
+
+		if !ok && g.sequential {
+			failedIndex = i
+		}
+	}
+}
+
+// next returns the first index of order whose scenario's dependencies have
+// all been recorded, so scenarios run after the scenarios of the group they
+// depend on, or the first index when there is none. Under a -tbdd.budget it
+// returns the first such index of the highest Priority instead, so the most
+// important scenarios run before the budget is spent.
+func (g Group) next(order []int) int {
+	next := -1
+	for _, i := range order {
+		if i >= len(g.scenarios) {
+			return i
+		}
+
+		if d, ok := g.scenarios[i].(dependent); ok && !recorded(d.dependencies()) {
+			continue
+		}
+
+		if config.deadline.IsZero() {
+			return i
+		}
+
+		if next < 0 || priorityOf(g.scenarios[i]) > priorityOf(g.scenarios[next]) {
+			next = i
+		}
+	}
+
+	if next < 0 {
+		return order[0]
+	}
+
+	return next
+}
@@ -0,0 +1,156 @@
+package tbdd
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var _ Scenario = Lifecycle[struct{}, struct{}]{}
+var _ Scenario = Group{}
+var _ groupT = (*testing.T)(nil)
+
+func TestSequence(t *testing.T) {
+	type TC struct {
+		log *[]string
+	}
+
+	var log []string
+	step := func(name string) Lifecycle[TC, struct{}] {
+		return WTN(
+			TC{&log},
+			name, func(_ *testing.T, tc TC) {
+				*tc.log = append(*tc.log, name)
+			},
+			"it is recorded", func(t *testing.T, tc TC) {
+				if (*tc.log)[len(*tc.log)-1] != name {
+					t.Error("unexpected step order")
+				}
+			},
+		)
+	}
+
+	g := Sequence(
+		step("a"),
+		step("b"),
+		Independent(step("c"), step("d")),
+	)
+
+	t.Run("flow", g.New(t))
+
+	if exp := "a b c d"; strings.Join(log, " ") != exp {
+		t.Errorf("expected log '%s' but got '%s'", exp, strings.Join(log, " "))
+	}
+}
+
+func TestSequence_stopsAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	mt := &mT{}
+
+	var calls []int
+	fs := []func(*testing.T){
+		func(*testing.T) { calls = append(calls, 0) },
+		func(*testing.T) {
+			calls = append(calls, 1)
+			mt.Error("boom")
+		},
+		func(*testing.T) { calls = append(calls, 2) },
+	}
+
+	Sequence().run(mt, fs)
+
+	if !slices.Equal(calls, []int{0, 1}) {
+		t.Errorf("expected only the first two scenarios to run but got %v", calls)
+	}
+
+	if !slices.Equal(mt.runCalls, []string{"0", "1", "2"}) {
+		t.Errorf("expected every scenario to be reported but got %v", mt.runCalls)
+	}
+}
+
+func TestIndependent(t *testing.T) {
+	t.Parallel()
+
+	{
+		mt := &mT{}
+
+		var calls []int
+		fs := []func(*testing.T){
+			func(*testing.T) {
+				calls = append(calls, 0)
+				mt.Error("boom")
+			},
+			func(*testing.T) { calls = append(calls, 1) },
+		}
+
+		Independent().Parallel().run(mt, fs)
+
+		if !slices.Equal(calls, []int{0, 1}) {
+			t.Errorf("expected every scenario to run but got %v", calls)
+		}
+	}
+
+	{
+		order := func(seed uint64) []string {
+			mt := &mT{}
+			fs := make([]func(*testing.T), 10)
+			for i := range fs {
+				fs[i] = func(*testing.T) {}
+			}
+
+			Independent().Shuffle(seed).run(mt, fs)
+			return mt.runCalls
+		}
+
+		a, b := order(42), order(42)
+		if !slices.Equal(a, b) {
+			t.Errorf("expected the same seed to produce the same order: %v != %v", a, b)
+		}
+
+		if slices.Equal(a, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}) {
+			t.Errorf("expected a shuffled order but got %v", a)
+		}
+	}
+
+	{
+		var ran atomic.Int32
+		lc := WTN(
+			struct{}{},
+			"w", func(*testing.T, struct{}) { ran.Add(1) },
+			"t", func(*testing.T, struct{}) {},
+		)
+
+		t.Run("parallel", Independent(lc, lc).Parallel().Shuffle(1).New(t))
+
+		if n := ran.Load(); n != 2 {
+			t.Errorf("expected 2 runs but got %d", n)
+		}
+	}
+}
+
+func TestGroup_panics(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { Sequence().Parallel() }, "tbdd.Group: a Sequence cannot run in parallel"},
+		{func() { Sequence().Shuffle(1) }, "tbdd.Group: a Sequence cannot be shuffled"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tbdd
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+// Append returns hooks which call every hook of h followed by the hook of
+// other for the same event. Events only one side defines keep that hook.
+//
+// Append makes it possible to compose hooks from shared helpers:
+//
+//	hooks := leakCheckHooks().Append(timingHooks())
+func (h Hooks[T, R]) Append(other Hooks[T, R]) Hooks[T, R] {
+	return Hooks[T, R]{
+		AfterArrange: chainHook(h.AfterArrange, other.AfterArrange),
+		AfterGiven:   chainHook(h.AfterGiven, other.AfterGiven),
+		AfterAct:     chainHook(h.AfterAct, other.AfterAct),
+		AfterAssert:  chainHook(h.AfterAssert, other.AfterAssert),
+		AfterSkip:    chainHook(h.AfterSkip, other.AfterSkip),
+		ConfigError:  chainHook(h.ConfigError, other.ConfigError),
+	}
+}
+
+// Prepend returns hooks which call every hook of other followed by the hook
+// of h for the same event. It is equivalent to other.Append(h).
+func (h Hooks[T, R]) Prepend(other Hooks[T, R]) Hooks[T, R] {
+	return other.Append(h)
+}
+
+// WithAppendedHooks appends hooks to those already configured, so they run
+// after them.
+func WithAppendedHooks[T, R any](hooks Hooks[T, R]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.hooks = b.hooks.Append(hooks)
+	}
+}
+
+// WithPrependedHooks prepends hooks to those already configured, so they run
+// before them.
+func WithPrependedHooks[T, R any](hooks Hooks[T, R]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.hooks = b.hooks.Prepend(hooks)
+	}
+}
+
+// HookPriority orders groups of hooks registered with WithPrioritizedHooks.
+// Lower priorities run first and groups of equal priority run in the order
+// they were registered.
+type HookPriority int
+
+const (
+	// HookPriorityFirst is intended for hooks which must observe an event
+	// before any other hook, such as timers.
+	HookPriorityFirst HookPriority = -1000
+	// HookPriorityDefault is the priority of the hooks set by WithHooks or by
+	// an Arrange function. They run before other groups of this priority.
+	HookPriorityDefault HookPriority = 0
+	// HookPriorityLast is intended for hooks which must observe an event
+	// after every other hook, such as leak detection.
+	HookPriorityLast HookPriority = 1000
+)
+
+type hookLayer[T, R any] struct {
+	priority HookPriority
+	hooks    Hooks[T, R]
+}
+
+// WithPrioritizedHooks registers an additional group of hooks which runs in
+// priority order relative to the lifecycle's other hooks, regardless of the
+// order in which the groups were composed. WithHooks does not replace groups
+// registered this way.
+func WithPrioritizedHooks[T, R any](priority HookPriority, hooks Hooks[T, R]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.hookLayers = append(slices.Clip(b.hookLayers), hookLayer[T, R]{priority, hooks})
+	}
+}
+
+// mergedHooks returns the lifecycle hooks combined with every prioritized
+// group of hooks.
+func (b *phases[T, R]) mergedHooks() Hooks[T, R] {
+	if len(b.hookLayers) == 0 {
+		return b.hooks
+	}
+
+	layers := append([]hookLayer[T, R]{{HookPriorityDefault, b.hooks}}, b.hookLayers...)
+	slices.SortStableFunc(layers, func(a, b hookLayer[T, R]) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+
+	var h Hooks[T, R]
+	for _, l := range layers {
+		h = h.Append(l.hooks)
+	}
+
+	return h
+}
+
+// mergeHooks folds the prioritized groups of hooks into b.hooks.
+func (b *phases[T, R]) mergeHooks() {
+	b.hooks = b.mergedHooks()
+	b.hookLayers = nil
+}
+
+// chainHook returns a hook which calls first then second, skipping whichever
+// is nil.
+func chainHook[P any](first, second func(*testing.T, P)) func(*testing.T, P) {
+	if first == nil {
+		return second
+	}
+
+	if second == nil {
+		return first
+	}
+
+	return func(t *testing.T, p P) {
+		first(t, p)
+		second(t, p)
+	}
+}
@@ -0,0 +1,142 @@
+package tbdd
+
+import (
+	"iter"
+	"strings"
+	"testing"
+)
+
+func TestHooks_Append(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	labeled := func(label string) Hooks[struct{}, struct{}] {
+		record := func(event string) {
+			calls = append(calls, label+"."+event)
+		}
+
+		return Hooks[struct{}, struct{}]{
+			AfterArrange: func(*testing.T, AfterArrange[struct{}]) { record("arrange") },
+			AfterGiven:   func(*testing.T, AfterGiven[struct{}]) { record("given") },
+			AfterAct:     func(*testing.T, AfterAct[struct{}, struct{}]) { record("act") },
+			AfterAssert:  func(*testing.T, AfterAssert[struct{}, struct{}]) { record("assert") },
+			AfterSkip:    func(*testing.T, AfterSkip[struct{}]) { record("skip") },
+			ConfigError:  func(*testing.T, *ConfigError) { record("config") },
+		}
+	}
+
+	h := labeled("b").Append(labeled("c")).Prepend(labeled("a")).Append(Hooks[struct{}, struct{}]{})
+	h.AfterArrange(nil, AfterArrange[struct{}]{})
+	h.AfterGiven(nil, AfterGiven[struct{}]{})
+	h.AfterAct(nil, AfterAct[struct{}, struct{}]{})
+	h.AfterAssert(nil, AfterAssert[struct{}, struct{}]{})
+	h.AfterSkip(nil, AfterSkip[struct{}]{})
+	h.ConfigError(nil, nil)
+
+	exp := "a.arrange b.arrange c.arrange a.given b.given c.given a.act b.act c.act a.assert b.assert c.assert a.skip b.skip c.skip a.config b.config c.config"
+	if s := strings.Join(calls, " "); s != exp {
+		t.Errorf("expected calls '%s' but got '%s'", exp, s)
+	}
+
+	if h := (Hooks[struct{}, struct{}]{}).Append(Hooks[struct{}, struct{}]{}); h.AfterAct != nil {
+		t.Error("expected appending empty hooks to leave events unset")
+	}
+}
+
+func TestWithAppendedHooks(t *testing.T) {
+	var calls []string
+	afterAct := func(label string) Hooks[struct{}, struct{}] {
+		return Hooks[struct{}, struct{}]{
+			AfterAct: func(*testing.T, AfterAct[struct{}, struct{}]) {
+				calls = append(calls, label)
+			},
+		}
+	}
+
+	f := WTN(
+		struct{}{},
+		"it acts", func(*testing.T, struct{}) {},
+		"hooks run in order", func(*testing.T, struct{}) {},
+	).With(
+		WithHooks(afterAct("lifecycle")),
+		WithAppendedHooks(afterAct("appended")),
+		WithPrependedHooks(afterAct("prepended")),
+	).New(t)
+	f(t)
+
+	if exp := "prepended lifecycle appended"; strings.Join(calls, " ") != exp {
+		t.Errorf("expected hook order '%s' but got '%s'", exp, strings.Join(calls, " "))
+	}
+}
+
+func TestWithPrioritizedHooks(t *testing.T) {
+	var calls []string
+	afterAct := func(label string) Hooks[struct{}, struct{}] {
+		return Hooks[struct{}, struct{}]{
+			AfterAct: func(*testing.T, AfterAct[struct{}, struct{}]) {
+				calls = append(calls, label)
+			},
+		}
+	}
+
+	opts := Options(
+		WithPrioritizedHooks(HookPriorityLast, afterAct("last")),
+		WithPrioritizedHooks(HookPriorityDefault, afterAct("default")),
+		WithHooks(afterAct("lifecycle")),
+		WithPrioritizedHooks(HookPriorityFirst, afterAct("first")),
+	)
+
+	for _, b := range []Lifecycle[struct{}, struct{}]{
+		GWTN(
+			struct{}{},
+			"arranged hooks", func(*testing.T, *struct{}) {},
+			"it acts", func(*testing.T, struct{}) {},
+			"hooks run by priority", func(*testing.T, struct{}) {},
+		),
+		WTN(
+			struct{}{},
+			"it acts", func(*testing.T, struct{}) {},
+			"hooks run by priority", func(*testing.T, struct{}) {},
+		),
+	} {
+		calls = nil
+
+		f := b.With(opts).New(t)
+		f(t)
+
+		if exp := "first lifecycle default last"; strings.Join(calls, " ") != exp {
+			t.Errorf("expected hook order '%s' but got '%s'", exp, strings.Join(calls, " "))
+		}
+	}
+
+	//
+	// prioritized hooks observe variant configuration errors
+	//
+
+	var configErrors int
+	b := WTN(
+		struct{}{},
+		"it acts", func(*testing.T, struct{}) {},
+		"it asserts", func(*testing.T, struct{}) {},
+	).With(
+		WithPrioritizedHooks(HookPriorityLast, Hooks[struct{}, struct{}]{
+			ConfigError: func(_ *testing.T, err *ConfigError) {
+				configErrors++
+			},
+		}),
+		WithVariants[struct{}, struct{}](func(*testing.T, struct{}) iter.Seq[TestVariant[struct{}]] {
+			return func(yield func(TestVariant[struct{}]) bool) {
+				yield(TestVariant[struct{}]{})
+			}
+		}),
+	)
+	b.getT = nilGetT
+
+	mt := &mT{}
+	f := (lifecycle[struct{}, struct{}])(b).new(mt)
+	f(mt)
+
+	if configErrors != 1 || len(mt.fatalfCalls) != 1 {
+		t.Errorf("expected one config error and fatal call but got %d and %d", configErrors, len(mt.fatalfCalls))
+	}
+}
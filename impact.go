@@ -0,0 +1,215 @@
+package tbdd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+// impactMap is the file written by -tbdd.impact-record and read by
+// -tbdd.impact, mapping scenarios to the packages they exercise.
+type impactMap struct {
+	// Modules holds the version of every dependency module the map was
+	// recorded with, by module path.
+	Modules map[string]string `json:",omitempty"`
+	// Scenarios holds the packages each scenario exercised, by ID.
+	Scenarios map[string]impactEntry
+}
+
+type impactEntry struct {
+	Test     string
+	Packages []string
+}
+
+// impactSelection holds the scenarios selected by the -tbdd.impact and
+// -tbdd.changed flags.
+type impactSelection struct {
+	m impactMap
+	// changed holds the changed packages as import paths or patterns ending
+	// in "/...".
+	changed []string
+}
+
+// selects reports whether the scenario with the given ID exercises a changed
+// package. Scenarios missing from the map, such as new ones, are selected. A
+// nil selection selects every scenario.
+func (s *impactSelection) selects(id string) bool {
+	if s == nil {
+		return true
+	}
+
+	e, ok := s.m.Scenarios[id]
+	if !ok {
+		return true
+	}
+
+	for _, pkg := range e.Packages {
+		for _, pattern := range s.changed {
+			if matchPackage(pattern, pkg) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchPackage reports whether pkg is the package pattern names, or is below
+// it when pattern ends in "/...".
+func matchPackage(pattern, pkg string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+
+	return pkg == pattern
+}
+
+// loadImpact reads the impact map at file and returns the selection of the
+// scenarios exercising the changed packages or the dependency modules whose
+// versions differ from those the map was recorded with.
+func loadImpact(file string, changed []string) (*impactSelection, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var m impactMap
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.New(file + ": " + err.Error())
+	}
+
+	for mod, version := range moduleVersions() {
+		if m.Modules[mod] != version {
+			changed = append(changed, mod+"/...")
+		}
+	}
+
+	return &impactSelection{m, changed}, nil
+}
+
+// moduleVersions returns the version of every dependency module of the
+// running binary, by module path, as recorded in its build information.
+func moduleVersions() map[string]string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	versions := map[string]string{}
+	for _, d := range bi.Deps {
+		version := d.Version
+		if r := d.Replace; r != nil {
+			// packages keep the import path of the module they replace
+			version = "=> " + r.Path + " " + r.Version
+		}
+
+		versions[d.Path] = version
+	}
+
+	return versions
+}
+
+// recordImpact merges the packages covered by the coverage profile at
+// profile into the impact map at file, under the ID of each of rs, so every
+// scenario run by the process is mapped to every package the process
+// exercised; running one test per process maps scenarios to the packages
+// of their own test.
+func recordImpact(file, profile string, rs []ScenarioResult) error {
+	f, err := os.Open(profile)
+	if err != nil {
+		return errors.New("reading the coverage profile: " + err.Error())
+	}
+	defer f.Close()
+
+	pkgs, err := coveredPackages(f)
+	if err != nil {
+		return errors.New("reading the coverage profile: " + err.Error())
+	}
+
+	var m impactMap
+	if b, err := os.ReadFile(file); err == nil {
+		if err := json.Unmarshal(b, &m); err != nil {
+			return errors.New(file + ": " + err.Error())
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	m.Modules = moduleVersions()
+	if m.Scenarios == nil {
+		m.Scenarios = map[string]impactEntry{}
+	}
+
+	for _, r := range rs {
+		if r.ID != "" {
+			m.Scenarios[r.ID] = impactEntry{r.Test, pkgs}
+		}
+	}
+
+	// a map holds no values which can fail to encode
+	b, _ := json.MarshalIndent(m, "", "  ")
+
+	return os.WriteFile(file, append(b, '\n'), 0o644)
+}
+
+// coveredPackages returns the sorted import paths of the packages with a
+// statement executed at least once in the text coverage profile read from r,
+// as written by -coverprofile.
+func coveredPackages(r io.Reader) ([]string, error) {
+	var pkgs []string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		// blocks are written as "import/path/file.go:1.2,3.4 statements count"
+		line := s.Text()
+		if strings.HasPrefix(line, "mode: ") {
+			continue
+		}
+
+		file, _, ok := strings.Cut(line, ":")
+		i := strings.LastIndexByte(line, ' ')
+		if !ok || i < 0 {
+			return nil, errors.New("invalid line " + line)
+		}
+
+		if line[i+1:] == "0" {
+			continue
+		}
+
+		if pkg := path.Dir(file); !slices.Contains(pkgs, pkg) {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(pkgs)
+
+	return pkgs, nil
+}
+
+// coverProfile returns the path of the coverage profile the testing package
+// writes for the flags of fs, or empty when it writes none.
+func coverProfile(fs *flag.FlagSet) string {
+	f := fs.Lookup("test.coverprofile")
+	if f == nil || f.Value.String() == "" {
+		return ""
+	}
+
+	profile := f.Value.String()
+	if d := fs.Lookup("test.outputdir"); d != nil && d.Value.String() != "" && !filepath.IsAbs(profile) {
+		profile = filepath.Join(d.Value.String(), profile)
+	}
+
+	return profile
+}
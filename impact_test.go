@@ -0,0 +1,112 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_coveredPackages(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := coveredPackages(strings.NewReader(`mode: atomic
+example.com/m/b/b.go:3.14,3.27 1 4
+example.com/m/a/a.go:3.14,3.27 1 1
+example.com/m/a/x.go:3.14,3.27 1 2
+example.com/m/c/c.go:3.14,3.27 1 0
+`))
+
+	True(t, err == nil)
+	Equal(t, pkgs, []string{"example.com/m/a", "example.com/m/b"})
+
+	_, err = coveredPackages(strings.NewReader("mode: set\nnonsense\n"))
+	True(t, err != nil && err.Error() == "invalid line nonsense")
+}
+
+func Test_matchPackage(t *testing.T) {
+	t.Parallel()
+
+	True(t, matchPackage("example.com/m/a", "example.com/m/a"))
+	True(t, !matchPackage("example.com/m/a", "example.com/m/a/b"))
+	True(t, matchPackage("example.com/m/...", "example.com/m"))
+	True(t, matchPackage("example.com/m/...", "example.com/m/a/b"))
+	True(t, !matchPackage("example.com/m/...", "example.com/mod"))
+}
+
+func Test_recordImpact(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	profile := filepath.Join(dir, "cover.out")
+	file := filepath.Join(dir, "impact.json")
+
+	if err := os.WriteFile(profile, []byte("mode: atomic\nexample.com/m/a/a.go:3.14,3.27 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"Scenarios": {"kept": {"Test": "TestB", "Packages": ["example.com/m/b"]}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := recordImpact(file, profile, []ScenarioResult{{ID: "0123456789ab", Test: "TestA/when_a_runs"}, {Test: "unidentified"}})
+	True(t, err == nil)
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m impactMap
+	True(t, json.Unmarshal(b, &m) == nil)
+	Equal(t, m.Scenarios, map[string]impactEntry{
+		"kept":         {"TestB", []string{"example.com/m/b"}},
+		"0123456789ab": {"TestA/when_a_runs", []string{"example.com/m/a"}},
+	})
+
+	err = recordImpact(file, filepath.Join(dir, "missing.out"), nil)
+	True(t, err != nil && strings.HasPrefix(err.Error(), "reading the coverage profile: "))
+}
+
+func TestLifecycle_impact(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	file := filepath.Join(t.TempDir(), "impact.json")
+
+	m := impactMap{Modules: moduleVersions(), Scenarios: map[string]impactEntry{
+		scenarioID(t.Name(), "", "when a runs then it passes"):         {"", []string{"example.com/m/a"}},
+		scenarioID(t.Name(), "", "given b when b runs then it passes"): {"", []string{"example.com/m/b"}},
+	}}
+	b, _ := json.Marshal(m)
+	if err := os.WriteFile(file, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sel, err := loadImpact(file, []string{"example.com/m/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.impact = sel
+
+	var ran []string
+	act := func(name string) func(*testing.T, int) {
+		return func(*testing.T, int) {
+			ran = append(ran, name)
+		}
+	}
+	pass := func(*testing.T, int) {}
+
+	WTN(0, "a runs", act("a"), "it passes", pass).New(t)(t)
+	GWTN(0, "b", func(*testing.T, *int) {}, "b runs", act("b"), "it passes", pass).New(t)(t)
+	WTN(0, "a new scenario runs", act("new"), "it passes", pass).New(t)(t)
+
+	Equal(t, ran, []string{"b", "new"})
+	Equal(t, WTN(0, "a runs", act("a"), "it passes", pass).Plan(t).Basis.Skip, "excluded by -tbdd.changed")
+
+	_, err = loadImpact(filepath.Join(t.TempDir(), "missing.json"), nil)
+	True(t, err != nil)
+}
@@ -0,0 +1,166 @@
+package tbdd
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// kindOf returns the Kind of v, or when it has none and infer is set, the
+// Kind inferred from how its TC differs from basis.
+func kindOf[T any](infer bool, basis T, v TestVariant[T]) string {
+	if v.Kind != "" || !infer {
+		return v.Kind
+	}
+
+	return inferKind(basis, v.TC)
+}
+
+// inferKind returns the fields of tc which differ from those of basis with
+// their values in tc, in field order, such as "Amount=0,Currency=EUR", or
+// empty when there are none. Fields of nested structs, and of structs their
+// pointers point to, are named by their path, such as "Address.City=Paris",
+// unless the struct is a fmt.Stringer such as time.Time, which is compared
+// and written as a whole. Other pointers are written as what they point to,
+// and fields which cannot be written, such as funcs and channels, are left
+// out, so a variant which differs from the basis only in them still needs a
+// Kind. A test case which is not a struct is written as a whole when it
+// differs.
+func inferKind[T any](basis, tc T) string {
+	var diffs []string
+	diffFields(&diffs, "", reflect.ValueOf(&basis).Elem(), reflect.ValueOf(&tc).Elem())
+
+	return strings.Join(diffs, ",")
+}
+
+var stringerType = reflect.TypeFor[fmt.Stringer]()
+
+func diffFields(diffs *[]string, path string, a, b reflect.Value) {
+	if a.Kind() == reflect.Pointer && !a.Type().Implements(stringerType) && !a.IsNil() && !b.IsNil() && a.Type().Elem().Kind() == reflect.Struct {
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if a.Kind() == reflect.Struct && !a.Type().Implements(stringerType) {
+		for i := range a.NumField() {
+			f := a.Type().Field(i)
+			if f.Name == "_" {
+				continue
+			}
+
+			name := f.Name
+			if path != "" {
+				name = path + "." + name
+			}
+
+			diffFields(diffs, name, a.Field(i), b.Field(i))
+		}
+		return
+	}
+
+	// values are compared as they are written so the Kind changes exactly
+	// when the test case does in a way it can show
+	as, ok := kindText(a, nil)
+	if !ok {
+		return
+	}
+
+	bs, ok := kindText(b, nil)
+	if !ok || as == bs {
+		return
+	}
+
+	if path == "" {
+		*diffs = append(*diffs, bs)
+		return
+	}
+
+	*diffs = append(*diffs, path+"="+bs)
+}
+
+// kindText returns v written as fmt.Sprint writes it, except that pointers
+// are written as what they point to, and whether v can be written at all,
+// which it cannot when it is or holds a func, a channel, an unsafe.Pointer,
+// or a pointer to itself. seen holds the pointers being written.
+func kindText(v reflect.Value, seen map[uintptr]bool) (string, bool) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return "nil", true
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return "", false
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+				break
+			}
+
+			return "nil", true
+		}
+	}
+
+	if v.CanInterface() && v.Type().Implements(stringerType) {
+		return fmt.Sprint(v.Interface()), true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if seen[v.Pointer()] {
+			return "", false
+		}
+
+		if seen == nil {
+			seen = map[uintptr]bool{}
+		}
+
+		seen[v.Pointer()] = true
+		defer delete(seen, v.Pointer())
+
+		return kindText(v.Elem(), seen)
+	case reflect.Interface:
+		return kindText(v.Elem(), seen)
+	case reflect.Struct:
+		parts := make([]string, v.NumField())
+		for i := range parts {
+			s, ok := kindText(v.Field(i), seen)
+			if !ok {
+				return "", false
+			}
+
+			parts[i] = s
+		}
+
+		return "{" + strings.Join(parts, " ") + "}", true
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			s, ok := kindText(v.Index(i), seen)
+			if !ok {
+				return "", false
+			}
+
+			parts[i] = s
+		}
+
+		return "[" + strings.Join(parts, " ") + "]", true
+	case reflect.Map:
+		parts := make([]string, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			k, ok := kindText(it.Key(), seen)
+			if !ok {
+				return "", false
+			}
+
+			e, ok := kindText(it.Value(), seen)
+			if !ok {
+				return "", false
+			}
+
+			parts = append(parts, k+":"+e)
+		}
+		slices.Sort(parts)
+
+		return "map[" + strings.Join(parts, " ") + "]", true
+	}
+
+	return fmt.Sprint(v), true
+}
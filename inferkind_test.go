@@ -0,0 +1,151 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_inferKind(t *testing.T) {
+	t.Parallel()
+
+	type payment struct {
+		Amount   int
+		Currency string
+	}
+
+	basis := payment{Amount: 100, Currency: "USD"}
+
+	var ran []string
+	b := WTN(basis, "it is charged", func(t *testing.T, _ payment) {
+		ran = append(ran, t.Name()[strings.IndexByte(t.Name(), '/')+1:])
+	}, "it succeeds", func(*testing.T, payment) {}).With(
+		WithInferKind[payment, struct{}](),
+		WithVariants[payment, struct{}](func(_ *testing.T, tc payment) iter.Seq[TestVariant[payment]] {
+			return func(yield func(TestVariant[payment]) bool) {
+				_ = yield(TestVariant[payment]{TC: payment{Amount: 0, Currency: "EUR"}}) &&
+					yield(TestVariant[payment]{TC: payment{Amount: 100, Currency: "GBP"}}) &&
+					yield(TestVariant[payment]{Kind: "refund", TC: payment{Amount: -100, Currency: "USD"}})
+			}
+		}),
+	)
+
+	b.New(t)(t)
+
+	Equal(t, ran, []string{
+		"when_it_is_charged",
+		"Amount=0,Currency=EUR/when_it_is_charged",
+		"Currency=GBP/when_it_is_charged",
+		"refund/when_it_is_charged",
+	})
+
+	p := b.Plan(t)
+
+	var kinds []string
+	for _, s := range p.Variants {
+		kinds = append(kinds, s.Kind)
+	}
+
+	Equal(t, kinds, []string{"Amount=0,Currency=EUR", "Currency=GBP", "refund"})
+
+	// a variant differing only in what cannot be written still needs a Kind
+	type retrying struct {
+		Retry func() bool
+	}
+
+	p = WTN(retrying{}, "it is retried", func(*testing.T, retrying) {}, "it succeeds", func(*testing.T, retrying) {}).With(
+		WithInferKind[retrying, struct{}](),
+		WithVariants[retrying, struct{}](func(*testing.T, retrying) iter.Seq[TestVariant[retrying]] {
+			return func(yield func(TestVariant[retrying]) bool) {
+				yield(TestVariant[retrying]{TC: retrying{Retry: func() bool { return false }}})
+			}
+		}),
+	).Plan(t)
+
+	if len(p.Variants) != 1 || !errors.Is(p.Variants[0].Err, ErrEmptyVariantKind) {
+		t.Errorf("expected the variant to fail for lacking a Kind but got %+v", p.Variants)
+	}
+}
+
+func Test_inferKind(t *testing.T) {
+	t.Parallel()
+
+	type address struct {
+		City string
+	}
+
+	type order struct {
+		id      int
+		Address address
+		Placed  time.Time
+		Tags    []string
+	}
+
+	placed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	basis := order{id: 1, Address: address{"Oslo"}, Placed: placed, Tags: []string{"a"}}
+
+	for _, c := range []struct {
+		name string
+		tc   order
+		exp  string
+	}{
+		{"unchanged", basis, ""},
+		{"unexported", order{id: 2, Address: address{"Oslo"}, Placed: placed, Tags: []string{"a"}}, "id=2"},
+		{"nested", order{id: 1, Address: address{"Paris"}, Placed: placed, Tags: []string{"a"}}, "Address.City=Paris"},
+		{"stringer", order{id: 1, Address: address{"Oslo"}, Tags: []string{"a"}}, "Placed=0001-01-01 00:00:00 +0000 UTC"},
+		{"slice", order{id: 1, Address: address{"Oslo"}, Placed: placed}, "Tags=[]"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			Equal(t, inferKind(basis, c.tc), c.exp)
+		})
+	}
+
+	Equal(t, inferKind(1, 2), "2")
+	Equal(t, inferKind(1, 1), "")
+
+	//
+	// pointers are followed and what cannot be written is left out
+	//
+
+	type request struct {
+		Limit   *int
+		Address *address
+		IDs     []*int
+		Retry   func() bool
+		Done    chan struct{}
+	}
+
+	one, two := 1, 2
+	// a distinct pointer to the same value
+	same := 1
+	basisReq := request{Limit: &one, Address: &address{"Oslo"}, IDs: []*int{&one}, Retry: func() bool { return true }}
+
+	for _, c := range []struct {
+		name string
+		tc   request
+		exp  string
+	}{
+		{"same values", request{Limit: &same, Address: &address{"Oslo"}, IDs: []*int{&same}}, ""},
+		{"pointer", request{Limit: &two, Address: &address{"Oslo"}, IDs: []*int{&one}}, "Limit=2"},
+		{"nil pointer", request{Address: &address{"Oslo"}, IDs: []*int{&one}}, "Limit=nil"},
+		{"pointer to struct", request{Limit: &one, Address: &address{"Paris"}, IDs: []*int{&one}}, "Address.City=Paris"},
+		{"nil pointer to struct", request{Limit: &one, IDs: []*int{&one}}, "Address=nil"},
+		{"pointers in a slice", request{Limit: &one, Address: &address{"Oslo"}, IDs: []*int{&one, &two}}, "IDs=[1 2]"},
+		{"func and chan", request{Limit: &one, Address: &address{"Oslo"}, IDs: []*int{&one}, Done: make(chan struct{})}, ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			Equal(t, inferKind(basisReq, c.tc), c.exp)
+		})
+	}
+
+	type node struct {
+		Name string
+		Next *node
+	}
+
+	loop := &node{Name: "a"}
+	loop.Next = loop
+	Equal(t, inferKind(node{Name: "a"}, node{Name: "b", Next: loop}), "Name=b")
+}
@@ -0,0 +1,30 @@
+package tbdd
+
+import (
+	"strconv"
+	"testing"
+)
+
+// checkInvariants runs every invariant of the plan in its own subtest of t,
+// named after phase, except those an earlier phase of the scenario sr broke,
+// and records in sr the invariants broken by this phase.
+func (p *plan[T, R]) checkInvariants(t *testing.T, phase string, tc func() *T, sr *scenario) {
+	for i, f := range p.invs {
+		if f == nil || i < len(sr.broken) && sr.broken[i] {
+			continue
+		}
+
+		name := "invariant " + strconv.Itoa(i) + " after " + phase
+		if p.run(nillableT{t, p.runHook}, name, func(t *testing.T) {
+			f(t, *tc())
+		}) {
+			continue
+		}
+
+		if sr.broken == nil {
+			sr.broken = make([]bool, len(p.invs))
+		}
+		sr.broken[i] = true
+		sr.Invariants = append(sr.Invariants, name)
+	}
+}
@@ -0,0 +1,127 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_invariants(t *testing.T) {
+	type ledger struct {
+		balance *int
+	}
+
+	balanced := func(t *testing.T, tc ledger) {
+		if tc.balance != nil && *tc.balance < 0 {
+			t.Errorf("the balance is negative: %d", *tc.balance)
+		}
+	}
+
+	var checked []ledger
+	observe := func(_ *testing.T, tc ledger) {
+		checked = append(checked, tc)
+	}
+
+	open := func(_ *testing.T, tc *ledger) {
+		tc.balance = new(int)
+	}
+
+	if os.Getenv("TBDD_INVARIANTS_HELPER") == "1" {
+		GWTN(ledger{}, "an open ledger", open, "more is withdrawn than deposited", func(_ *testing.T, tc ledger) {
+			*tc.balance--
+		}, "it is rejected", func(*testing.T, ledger) {}).With(WithInvariants[ledger, struct{}](balanced, observe)).New(t)(t)
+		return
+	}
+
+	// every invariant is checked after every phase of a passing scenario
+	GWTN(ledger{}, "an open ledger", open, "nothing happens", func(*testing.T, ledger) {}, "it balances", func(*testing.T, ledger) {}).With(WithInvariants[ledger, struct{}](balanced, nil, observe)).New(t)(t)
+
+	if len(checked) != 4 || checked[0].balance != nil || checked[1].balance == nil {
+		t.Errorf("expected the invariants to be checked after arrange, given, act, and assert but got %v", checked)
+	}
+
+	// a scenario without a given phase only checks after act and assert
+	checked = nil
+	WTN(ledger{}, "nothing happens", func(*testing.T, ledger) {}, "it balances", func(*testing.T, ledger) {}).With(WithInvariants[ledger, struct{}](observe)).New(t)(t)
+
+	if len(checked) != 2 {
+		t.Errorf("expected the invariants to be checked after act and assert but got %v", checked)
+	}
+
+	// options append their invariants to those of earlier options
+	checked = nil
+	var appended int
+	WTN(ledger{}, "nothing happens", func(*testing.T, ledger) {}, "it balances", func(*testing.T, ledger) {}).With(
+		WithInvariants[ledger, struct{}](observe),
+		WithInvariants[ledger, struct{}](func(*testing.T, ledger) { appended++ }),
+	).New(t)(t)
+
+	if len(checked) != 2 || appended != 2 {
+		t.Errorf("expected both options' invariants to be checked after act and assert but got %d and %d checks", len(checked), appended)
+	}
+
+	//
+	// a broken invariant fails the subtest of the phase which broke it
+	//
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_INVARIANTS_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	prefix := t.Name() + "/given_an_open_ledger/"
+	for _, exp := range []string{
+		"--- PASS: " + prefix + "invariant_0_after_given ",
+		"--- FAIL: " + prefix + "when_more_is_withdrawn_than_deposited/invariant_0_after_act ",
+		"the balance is negative: -1",
+		"--- PASS: " + prefix + "when_more_is_withdrawn_than_deposited/then_it_is_rejected/invariant_1_after_assert ",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	if s := "invariant_0_after_assert"; strings.Contains(string(out), s) {
+		t.Errorf("expected the broken invariant not to be checked again:\n%s", out)
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ Invariants []string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Results) != 1 || !slices.Equal(r.Results[0].Invariants, []string{"invariant 0 after act"}) {
+		t.Errorf("expected the broken invariant to be recorded but got %+v", r.Results)
+	}
+}
+
+func TestLifecycle_Validate_invariants(t *testing.T) {
+	t.Parallel()
+
+	b := WTN(0, "w", func(*testing.T, int) {}, "t", func(*testing.T, int) {})
+	b.Invariants = []func(*testing.T, int){func(*testing.T, int) {}, nil}
+
+	err := b.Validate()
+
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Field != "Invariants[1]" || !errors.Is(err, ErrNilInvariant) {
+		t.Errorf("expected a nil invariant error but got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package tbdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// JSONText is the set of types holding raw JSON accepted by the JSON
+// assertions.
+type JSONText interface {
+	~string | ~[]byte
+}
+
+// JSONEq fails the test unless want and got hold equivalent JSON documents,
+// ignoring whitespace and object key order. Numbers are compared by their
+// exact values, so 1 equals 1.0 while integers beyond the precision of a
+// float64 differ. The failure identifies the first differing location and
+// shows both documents normalized.
+func JSONEq[J JSONText](t *testing.T, want, got J) {
+	t.Helper()
+
+	jsonEq(linkedT{T: t}, []byte(want), []byte(got))
+}
+
+// JSONPath decodes body and returns the value found at path, failing the
+// test if body is not valid JSON or the path does not exist.
+//
+// Paths start with "$" for the document root followed by any number of
+// ".name" or `["name"]` object member selectors and "[index]" array
+// selectors, such as "$.items[0].id". Values are returned as decoded by
+// encoding/json into an any, except numbers are json.Number so large
+// integers such as IDs keep every digit: objects are map[string]any, arrays
+// are []any, and numbers are json.Number.
+func JSONPath[J JSONText](t *testing.T, body J, path string) any {
+	t.Helper()
+
+	return jsonPath(linkedT{T: t}, []byte(body), path)
+}
+
+func jsonEq(t assertT, want, got []byte) {
+	t.Helper()
+
+	w, err := decodeJSON(want)
+	if err != nil {
+		t.Fatalf("expected JSON is invalid: %v", err)
+		return
+	}
+
+	g, err := decodeJSON(got)
+	if err != nil {
+		t.Fatalf("actual JSON is invalid: %v; JSON: %s", err, got)
+		return
+	}
+
+	if path, ok := jsonDiff("$", w, g); !ok {
+		t.Fatalf("JSON documents differ at %s:\nexpected:\n%s\nactual:\n%s", path, jsonIndent(w), jsonIndent(g))
+	}
+}
+
+// decodeJSON decodes the JSON document b like json.Unmarshal into an any,
+// except numbers are decoded as json.Number.
+func decodeJSON(b []byte) (any, error) {
+	var v any
+	if !json.Valid(b) {
+		// report the syntax error as json.Unmarshal does
+		return nil, json.Unmarshal(b, &v)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	err := d.Decode(&v)
+	return v, err
+}
+
+// jsonDiff returns the path of the first difference between decoded JSON
+// values a and b, and false, or true when they are equal.
+func jsonDiff(path string, a, b any) (string, bool) {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			return path, false
+		}
+
+		keys := make([]string, 0, len(a)+len(b))
+		for k := range a {
+			keys = append(keys, k)
+		}
+		for k := range b {
+			if _, ok := a[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			av, aok := a[k]
+			bv, bok := b[k]
+			p := path + jsonMemberSelector(k)
+			if aok != bok {
+				return p, false
+			}
+
+			if p, ok := jsonDiff(p, av, bv); !ok {
+				return p, false
+			}
+		}
+
+		return "", true
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			return path, false
+		}
+
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if p, ok := jsonDiff(path+"["+strconv.Itoa(i)+"]", a[i], b[i]); !ok {
+				return p, false
+			}
+		}
+
+		if len(a) != len(b) {
+			return path + ".length", false
+		}
+
+		return "", true
+	case json.Number:
+		b, ok := b.(json.Number)
+		return path, ok && jsonNumbersEqual(a, b)
+	}
+
+	return path, reflect.DeepEqual(a, b)
+}
+
+// jsonNumbersEqual reports whether the valid JSON numbers a and b have the
+// same value.
+func jsonNumbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	x, xok := new(big.Rat).SetString(string(a))
+	y, yok := new(big.Rat).SetString(string(b))
+	return xok && yok && x.Cmp(y) == 0
+}
+
+func jsonMemberSelector(k string) string {
+	for i, r := range k {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return "[" + strconv.Quote(k) + "]"
+		}
+	}
+
+	if k == "" {
+		return `[""]`
+	}
+
+	return "." + k
+}
+
+func jsonIndent(v any) string {
+	// values decoded from JSON always encode
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return string(b)
+}
+
+func jsonPath(t assertT, body []byte, path string) any {
+	t.Helper()
+
+	v, err := decodeJSON(body)
+	if err != nil {
+		t.Fatalf("invalid JSON: %v; JSON: %s", err, body)
+		return nil
+	}
+
+	v, err = followJSONPath(v, path)
+	if err != nil {
+		t.Fatalf("JSON path %s: %v; JSON: %s", path, err, body)
+		return nil
+	}
+
+	return v
+}
+
+func followJSONPath(v any, path string) (any, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, errors.New(`path must start with "$"`)
+	}
+
+	at := "$"
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, errors.New("empty member name after " + at)
+			}
+
+			var err error
+			if v, err = jsonMember(v, at, name); err != nil {
+				return nil, err
+			}
+
+			at += "." + name
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.New("unterminated selector after " + at)
+			}
+
+			sel := rest[1:end]
+			if name, err := strconv.Unquote(sel); err == nil {
+				if v, err = jsonMember(v, at, name); err != nil {
+					return nil, err
+				}
+			} else {
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, fmt.Errorf("invalid selector [%s] after %s", sel, at)
+				}
+
+				a, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("%s is not an array", at)
+				}
+
+				if i < 0 || i >= len(a) {
+					return nil, fmt.Errorf("index %d out of range for %s of length %d", i, at, len(a))
+				}
+
+				v = a[i]
+			}
+
+			at += rest[:end+1]
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q after %s", rest[0], at)
+		}
+	}
+
+	return v, nil
+}
+
+func jsonMember(v any, at, name string) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", at)
+	}
+
+	mv, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("%s has no member %q", at, name)
+	}
+
+	return mv, nil
+}
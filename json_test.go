@@ -0,0 +1,147 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestJSONEq(t *testing.T) {
+	t.Parallel()
+
+	JSONEq(t, `{"a": 1, "b": [true, null]}`, "{\n\"b\":[true,null],\"a\":1.0}")
+	JSONEq(t, []byte(`"x"`), []byte(` "x" `))
+	JSONEq(t, `[9007199254740993, 1e2, 0.10]`, `[9007199254740993, 100, 0.1]`)
+}
+
+func TestJSONPath(t *testing.T) {
+	t.Parallel()
+
+	body := `{"items": [{"id": 7, "tags": {"a b": "c"}}], "ok": true, "big": 9007199254740993}`
+
+	for _, v := range []struct {
+		path string
+		exp  any
+	}{
+		{"$", map[string]any{"big": json.Number("9007199254740993"), "items": []any{map[string]any{"id": json.Number("7"), "tags": map[string]any{"a b": "c"}}}, "ok": true}},
+		{"$.ok", true},
+		{"$.items[0].id", json.Number("7")},
+		{`$["items"][0].tags["a b"]`, "c"},
+		{"$.big", json.Number("9007199254740993")},
+	} {
+		if got := JSONPath(t, body, v.path); !reflect.DeepEqual(got, v.exp) {
+			t.Errorf("expected %s to be %#v but got %#v", v.path, v.exp, got)
+		}
+	}
+}
+
+func TestJSON_failures(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		f   func(assertT)
+		exp string
+	}{
+		{
+			func(t assertT) { jsonEq(t, []byte(`{`), []byte(`{}`)) },
+			"expected JSON is invalid: unexpected end of JSON input",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{}`), []byte(`[`)) },
+			"actual JSON is invalid: unexpected end of JSON input; JSON: [",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{"a":{"b":1}}`), []byte(`{"a":{"b":2}}`)) },
+			"JSON documents differ at $.a.b:\nexpected:\n{\n  \"a\": {\n    \"b\": 1\n  }\n}\nactual:\n{\n  \"a\": {\n    \"b\": 2\n  }\n}",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{"a":1}`), []byte(`{"a":1,"b c":2}`)) },
+			"JSON documents differ at $[\"b c\"]:\nexpected:\n{\n  \"a\": 1\n}\nactual:\n{\n  \"a\": 1,\n  \"b c\": 2\n}",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{"":[1]}`), []byte(`{"":[1,2]}`)) },
+			"JSON documents differ at $[\"\"].length:\nexpected:\n{\n  \"\": [\n    1\n  ]\n}\nactual:\n{\n  \"\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`[{"a1":1}]`), []byte(`[{"a1":2}]`)) },
+			"JSON documents differ at $[0].a1:\nexpected:\n[\n  {\n    \"a1\": 1\n  }\n]\nactual:\n[\n  {\n    \"a1\": 2\n  }\n]",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{"id":9007199254740993}`), []byte(`{"id":9007199254740992}`)) },
+			"JSON documents differ at $.id:\nexpected:\n{\n  \"id\": 9007199254740993\n}\nactual:\n{\n  \"id\": 9007199254740992\n}",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`[1]`), []byte(`["1"]`)) },
+			"JSON documents differ at $[0]:\nexpected:\n[\n  1\n]\nactual:\n[\n  \"1\"\n]",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{} x`), []byte(`{}`)) },
+			"expected JSON is invalid: invalid character 'x' after top-level value",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`{}`), []byte(`[]`)) },
+			"JSON documents differ at $:\nexpected:\n{}\nactual:\n[]",
+		},
+		{
+			func(t assertT) { jsonEq(t, []byte(`[]`), []byte(`{}`)) },
+			"JSON documents differ at $:\nexpected:\n[]\nactual:\n{}",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{`), "$") },
+			"invalid JSON: unexpected end of JSON input; JSON: {",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{}`), "a") },
+			`JSON path a: path must start with "$"; JSON: {}`,
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{}`), "$.") },
+			"JSON path $.: empty member name after $; JSON: {}",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{}`), "$.a") },
+			`JSON path $.a: $ has no member "a"; JSON: {}`,
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`[]`), "$.a") },
+			"JSON path $.a: $ is not an object; JSON: []",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`[]`), `$["a"]`) },
+			`JSON path $["a"]: $ is not an object; JSON: []`,
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`[]`), "$[0") },
+			"JSON path $[0: unterminated selector after $; JSON: []",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`[]`), "$[x]") },
+			"JSON path $[x]: invalid selector [x] after $; JSON: []",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{}`), "$[0]") },
+			"JSON path $[0]: $ is not an array; JSON: {}",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`{"a":[1]}`), "$.a[1]") },
+			"JSON path $.a[1]: index 1 out of range for $.a of length 1; JSON: {\"a\":[1]}",
+		},
+		{
+			func(t assertT) { jsonPath(t, []byte(`[]`), "$x") },
+			"JSON path $x: unexpected 'x' after $; JSON: []",
+		},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 {
+			t.Errorf("expected one fatalf call with message %q but got %v", v.exp, mt.fatalfCalls)
+			continue
+		}
+
+		if msg := fmt.Sprintf(mt.fatalfCalls[0].format, mt.fatalfCalls[0].args...); msg != v.exp {
+			t.Errorf("expected fatalf message %q but got %q", v.exp, msg)
+		}
+	}
+}
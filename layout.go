@@ -0,0 +1,36 @@
+package tbdd
+
+// SubtestLayout controls how the phases of a Lifecycle map onto subtests.
+type SubtestLayout uint8
+
+const (
+	// LayoutNested runs the given, when, and then phases as three nested
+	// subtests. It is the default.
+	LayoutNested SubtestLayout = iota
+
+	// LayoutFlat runs every phase within a single subtest named
+	// "given X/when Y/then Z", for tooling which struggles with deeply
+	// nested subtests.
+	//
+	// The subtest is named before the given phase runs, so descriptions
+	// changed by AfterGiven or Describe are validated and reported but do
+	// not rename it.
+	LayoutFlat
+
+	// LayoutMerged keeps the given subtest but runs Act and Assert within a
+	// single "when Y/then Z" subtest, so scenarios without a given phase
+	// need only one subtest per variant. It cuts the subtest creation
+	// overhead of very large variant matrices.
+	LayoutMerged
+
+	// LayoutID runs every phase within a single subtest named by the
+	// scenario's ScenarioResult.ID, such as "0ca62d4bb599", and logs the
+	// scenario sentence within it instead. The short, stable, slash free
+	// names suit go test -json aggregators which mangle descriptive names.
+	//
+	// Table test indexes and variant kinds are part of the ID rather than
+	// subtest name prefixes. Like LayoutFlat, scenarios with a given phase
+	// are named before it runs, so their ID reflects the descriptions as
+	// Arrange left them.
+	LayoutID
+)
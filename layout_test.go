@@ -0,0 +1,147 @@
+package tbdd
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLayoutFlat(t *testing.T) {
+	type TC struct {
+		names *[]string
+	}
+
+	record := func(t *testing.T, tc TC) {
+		*tc.names = append(*tc.names, t.Name())
+	}
+
+	var names []string
+	variants := WithVariants[TC, struct{}](func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+		return func(yield func(TestVariant[TC]) bool) {
+			yield(TestVariant[TC]{Kind: "variant", TC: tc})
+		}
+	})
+
+	for _, b := range []Lifecycle[TC, struct{}]{
+		GWTN(
+			TC{&names},
+			"a context", func(*testing.T, *TC) {},
+			"it acts", record,
+			"it asserts", record,
+		).With(WithLayout[TC, struct{}](LayoutFlat), variants),
+		WTN(
+			TC{&names},
+			"it acts", record,
+			"it asserts", record,
+		).With(WithLayout[TC, struct{}](LayoutFlat), variants),
+	} {
+		f := b.New(t)
+		f(t)
+	}
+
+	exp := []string{
+		"given_a_context/when_it_acts/then_it_asserts",
+		"given_a_context/when_it_acts/then_it_asserts",
+		"variant/given_a_context/when_it_acts/then_it_asserts",
+		"variant/given_a_context/when_it_acts/then_it_asserts",
+		"when_it_acts/then_it_asserts",
+		"when_it_acts/then_it_asserts",
+		"variant/when_it_acts/then_it_asserts",
+		"variant/when_it_acts/then_it_asserts",
+	}
+
+	if len(names) != len(exp) {
+		t.Fatalf("expected %d phases to run but got %d: %v", len(exp), len(names), names)
+	}
+
+	for i, name := range names {
+		if name != t.Name()+"/"+exp[i] {
+			t.Errorf("phase %d: expected test name '%s/%s' but got '%s'", i, t.Name(), exp[i], name)
+		}
+	}
+}
+
+func TestLayoutMerged(t *testing.T) {
+	var names []string
+	record := func(t *testing.T, _ struct{}) {
+		names = append(names, t.Name())
+	}
+
+	f := GWTN(
+		struct{}{},
+		"a context", func(t *testing.T, _ *struct{}) {
+			names = append(names, t.Name())
+		},
+		"it acts", record,
+		"it asserts", record,
+	).With(WithLayout[struct{}, struct{}](LayoutMerged)).New(t)
+	f(t)
+
+	given := t.Name() + "/given_a_context"
+	exp := given + "/when_it_acts/then_it_asserts"
+	if len(names) != 3 || names[0] != given || names[1] != exp || names[2] != exp {
+		t.Errorf("expected Given to run in '%s' and Act and Assert to both run in '%s' but got %v", given, exp, names)
+	}
+}
+
+func TestLayoutID(t *testing.T) {
+	var names []string
+	record := func(t *testing.T, _ string) {
+		names = append(names, t.Name())
+	}
+
+	variants := WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+		return func(yield func(TestVariant[string]) bool) {
+			yield(TestVariant[string]{Kind: "admin", TC: "admin"})
+		}
+	})
+
+	gwt := GWTN(
+		"basis",
+		"a user", func(t *testing.T, _ *string) {
+			names = append(names, t.Name())
+		},
+		"they log in", record,
+		"they see their dashboard", record,
+	).With(WithLayout[string, struct{}](LayoutID), variants)
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	f := gwt.NewI(t, 3)
+	f(t)
+
+	wt := WTN(
+		"basis",
+		"the service starts", record,
+		"it is healthy", record,
+	).With(WithLayout[string, struct{}](LayoutID))
+
+	f = wt.New(t)
+	f(t)
+
+	gwtID := scenarioID(t.Name(), "3/", "given a user when they log in then they see their dashboard")
+	adminID := scenarioID(t.Name(), "3/", "admin: given a user when they log in then they see their dashboard")
+	wtID := scenarioID(t.Name(), "", "when the service starts then it is healthy")
+
+	var exp []string
+	for _, id := range []string{gwtID, gwtID, gwtID, adminID, adminID, adminID, wtID, wtID} {
+		exp = append(exp, t.Name()+"/"+id)
+	}
+
+	if !slices.Equal(names, exp) {
+		t.Errorf("expected every phase to run in a subtest named by the scenario ID %v but got %v", exp, names)
+	}
+
+	var ids []string
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	if exp := []string{gwtID, adminID, wtID}; !slices.Equal(ids, exp) {
+		t.Errorf("expected results with IDs %v but got %v", exp, ids)
+	}
+}
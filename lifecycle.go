@@ -20,9 +20,12 @@
 package tbdd
 
 import (
+	"context"
 	"iter"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 // Lifecycle describes an execution process with a specific order to it.
@@ -49,7 +52,7 @@ import (
 //
 // - AfterAssert (hook)
 //
-// - Variants
+// - Variants, then Variants2
 type Lifecycle[T, R any] struct {
 	Given, When, Then string
 	hooks             Hooks[T, R]
@@ -57,14 +60,56 @@ type Lifecycle[T, R any] struct {
 
 	// CloneTC optionally specifies how to clone the Test Case type rather than using interface detection magic
 	// which can be prone to receiver based semantic matching issues.
+	//
+	// A panic of CloneTC fails the scenario being cloned as misconfigured, with the panic
+	// value and stack, rather than crashing the test binary.
+	//
+	// It is only called for the scenarios which run, once the selection flags, SkipTC,
+	// SkipUntil, DependsOn, and -tbdd.budget have let them. Scenarios need their clone
+	// sooner when Describe sees DefaultTC, when an Arrange function not made by GWT or an
+	// AfterArrange or AfterSkip hook receives the test case, and under SharedStateCheck.
 	CloneTC func(T) T
 
 	// Variants allows for the construction of more test cases from a basis test case.
 	// The T passed in is a copy of Lifecycle.TC taken before the basis test runs.
 	// The resulting TestVariant.TC values will each be cloned with CloneTC (if non-nil)
 	// before being executed, so they can mutate TC without affecting each other.
+	//
+	// Cloning is deferred until just before the first phase which could mutate
+	// the TC, so skipped variants and those excluded by -tbdd.filter before
+	// reaching such a phase are never cloned.
+	//
+	// A panic of the iterator, rather than of a variant it yielded, ends the iteration and
+	// fails a tbdd-config-error subtest of the index of the variant it failed to produce,
+	// with the panic value and stack, while the test continues with Variants2.
 	Variants func(*testing.T, T) iter.Seq[TestVariant[T]]
 
+	// Variants2 is like Variants but suits generators which can fail part way, such as
+	// those reading external data. Each non-nil error yielded fails the test with the
+	// index of the offending variant, while iteration continues with the next one. A panic
+	// ends the iteration as described for Variants.
+	//
+	// Variants2 runs after Variants when both are set, with variant indexes continuing
+	// from those of Variants.
+	Variants2 func(*testing.T, T) iter.Seq2[TestVariant[T], error]
+
+	// InferKind, when set, gives each variant yielded without a Kind one inferred from the
+	// fields of its TC which differ from those of the basis TC, with their values, such as
+	// "Amount=0,Currency=EUR", so the Kind cannot drift from the data it describes.
+	// Pointers are written as what they point to, and fields which cannot be written,
+	// such as funcs and channels, are left out. A variant whose TC does not otherwise
+	// differ from the basis still fails for lacking a Kind.
+	InferKind bool
+
+	// DefaultTC is applied in order to the test case of every scenario, the basis and each
+	// variant, once CloneTC has copied it and before Describe and Arrange, so fields left at
+	// their zero value can be filled with sensible defaults and a variant need only set the
+	// fields it is about. Defaults writing through pointers, maps, or slices therefore only
+	// change the scenario's own clone. Variants receive the basis test case as it was before
+	// DefaultTC, and Plan applies it to the test cases it passes to Describe. Nil functions
+	// are reported by Validate and otherwise ignored.
+	DefaultTC []func(*T)
+
 	// Arrange, when non-nil, sets hooks, test case defaults, and initial descriptions then returns a
 	// "given" description string and a function that sets up any context the test case requires. It will
 	// be called shortly after being returned to set up the "given" context for the test case. The returned
@@ -77,14 +122,180 @@ type Lifecycle[T, R any] struct {
 	// Describe makes sure given (if applicable), when, and then descriptions are set
 	Describe func(*testing.T, Describe[T]) DescribeResponse
 
-	// Act exercises the component under test and stores results
+	// Require, when non-nil, validates the external preconditions of the behavior, such as a
+	// reachable service or an applied migration, once the given phase has run and before Act.
+	// A non-nil error ends the scenario as RequirePolicy selects, skipping it by default, and
+	// is recorded as its ScenarioResult.Precondition so that an environment which is not ready
+	// can be told apart from a broken behavior.
+	Require       func(*testing.T, T) error
+	RequirePolicy RequirePolicy
+
+	// Act exercises the component under test and stores results.
+	//
+	// Goroutines started by Act should report failures through RecorderFor(t)
+	// rather than t.
 	Act func(*testing.T, T) R
 
 	// Assert: validate results + side-effects
 	Assert func(*testing.T, Assert[T, R])
 
-	getT    func(testingT) *testing.T
-	runHook func(string)
+	// Layout controls how the phases map onto subtests; the zero value is LayoutNested.
+	Layout SubtestLayout
+
+	// Trace wraps every scenario in a runtime/trace task named after its outermost subtest,
+	// with its given, act, and assert phases in regions of those names, so the output of
+	// go test -trace can be correlated with the phase boundaries.
+	Trace bool
+
+	// MemStats measures the change in runtime.MemStats across the Act of every scenario,
+	// reporting it in AfterAct.Mem. Reading the statistics briefly stops the world, so it is
+	// opt-in.
+	MemStats bool
+
+	// FDLeakCheck fails every scenario which, once its subtests and their cleanups complete,
+	// holds open file descriptors, such as files and sockets, that were not open when its
+	// outermost subtest started. Descriptors opened concurrently by parallel tests cannot be
+	// told apart from leaks, so it should only be set for tests which do not run in parallel.
+	FDLeakCheck bool
+
+	// Watchdog, when positive, is how long the outermost subtest of a scenario may take,
+	// including its cleanups. A scenario which exceeds it ends the test binary with the
+	// stack of every goroutine written to stderr, so a hung Act is reported with the
+	// scenario it belongs to long before the -test.timeout deadline.
+	Watchdog time.Duration
+
+	// SlowPhase, when positive, is how long the arrange, given, act, or assert phase of a
+	// scenario may take before a Warning is recorded, naming the phase and its duration,
+	// and the phase is added to the SlowPhases of the scenario's result. Slow phases do not
+	// fail the scenario.
+	SlowPhase time.Duration
+
+	// WarmupRuns is the number of times Act is called, with its results discarded, before
+	// the call whose result is asserted, so pools, caches, and lazily initialized state are
+	// filled before profiling, memory statistics, and baselines measure it. Each warmup
+	// receives its own clone of the test case when CloneTC is set and runs within its own
+	// bubble when Synctest is set; a warmup failure fails the scenario.
+	WarmupRuns int
+
+	// Invariants are checked after the arrange, given, act, and assert phases of every
+	// scenario, each in a subtest named after its index and the phase, such as
+	// "invariant 0 after act", so system-wide properties need not be repeated in every
+	// Assert. An invariant broken by a phase is not checked again after later phases, so
+	// its failure names the phase which broke it, and ScenarioResult.Invariants records
+	// it. Nil invariants are reported by Validate and otherwise ignored.
+	Invariants []func(*testing.T, T)
+
+	// NormalizeResult is applied in order to the result of every Act once it returns, before
+	// the AfterAct hook, examples, and Assert see it, so every Then and golden comparison works
+	// on the same canonical form, such as with slices sorted, timestamps zeroed, and maps
+	// canonicalized. Results of warmup runs are discarded without being normalized. Nil
+	// functions are reported by Validate and otherwise ignored.
+	NormalizeResult []func(*R)
+
+	// MaxVariants, when positive, is the most variants Variants and Variants2 may yield
+	// together. The first variant beyond it ends the iteration and fails a tbdd-config-error
+	// subtest naming the limit and the Kinds of the first few variants, so a generator bug
+	// yielding a great many variants fails fast rather than running them all.
+	MaxVariants int
+
+	// SharedStateCheck fails the test when the test cases of its basis scenario and
+	// variants, once cloned by CloneTC, share mutable memory: the targets of pointers and
+	// the contents of maps, slices, and channels. Scenarios which call t.Parallel race on
+	// such memory, which usually means CloneTC is missing or shallow. Struct fields tagged
+	// `tbdd:"shared"` hold state shared intentionally and are not inspected. Only the
+	// first 1000 scenarios are checked, as each is compared with every earlier one; a
+	// warning names the first scenario left unchecked.
+	SharedStateCheck bool
+
+	// ReadOnlyTC fails the scenario as misconfigured when its Describe or Assert phase
+	// mutates memory reachable from the test case they receive, which is documented as
+	// read-only during them: the targets of pointers and the contents of maps and slices.
+	// Such a mutation otherwise leaks silently into the later phases and, when CloneTC is
+	// missing or shallow, into the other variants. The failure names the path of the first
+	// mutated value, such as TC.Items[0].Price. Struct fields tagged `tbdd:"shared"` hold
+	// state shared intentionally and are not inspected.
+	ReadOnlyTC bool
+
+	// ParallelSafe allows the given and act phases of its scenarios to call t.Parallel. Such
+	// a call pauses the scenario until its parent test returns, after any teardown the parent
+	// deferred and any fixtures it shares are released, so unless it is set the scenario fails
+	// explaining the misuse.
+	ParallelSafe bool
+
+	// Synctest runs the Act and Assert functions of every scenario within their own
+	// testing/synctest bubbles, so timers and sleeps complete instantly and deterministically
+	// once every goroutine of the bubble is blocked. Each bubble waits for the goroutines
+	// started within it to exit, and any failure within a bubble ends its subtest.
+	//
+	// Subtests cannot run within a bubble, so ActRuns, ActRunsParallel, BothOrders,
+	// ThenTable, ParallelThenTable, and ThenRoute fail the scenario with a
+	// tbdd-config-error when they are called under Synctest. Invariants, which are
+	// checked outside the bubbles, are unaffected.
+	Synctest bool
+
+	// Owner, Ticket, and Severity identify who is responsible for the behavior, where it is
+	// tracked, and how much its failure matters. They are convenience fields for the
+	// MetaOwner, MetaTicket, and MetaSeverity keys of Meta and take precedence over them.
+	Owner, Ticket, Severity string
+
+	// Meta holds arbitrary metadata about the behavior. It is recorded in the ScenarioResult
+	// of every scenario, and so every Report, and logged when a scenario fails.
+	Meta map[string]string
+
+	// Source is the "file:line" of the code which defined the behavior, set by GWT, New, and
+	// the functions built on them to the line which called them, or when empty, to the line
+	// which calls Lifecycle.New to run it. It is recorded as the ScenarioResult.Source of
+	// every scenario, emitted as its tbdd.source attribute, and logged when a scenario fails,
+	// so a failure leads straight to its definition. Lifecycles generated from data files may
+	// set it to the location of their data instead.
+	Source string
+
+	// SkipUntil, when non-zero, skips every scenario with SkipUntilReason until that time,
+	// after which the scenarios fail with "skip expired" so that temporary skips are revisited
+	// rather than living forever.
+	SkipUntil       time.Time
+	SkipUntilReason string
+
+	// DependsOn lists the IDs of scenarios which must pass before the scenarios of the
+	// Lifecycle run, as recorded in their ScenarioResult.ID and tbdd.id attribute. A scenario
+	// whose prerequisite failed, was skipped, or has not run yet in the process is skipped
+	// with a reason naming the prerequisite. A Group runs the scenarios it holds after those
+	// of the group they depend on; other prerequisites must be run first, such as by a test
+	// declared earlier in its file.
+	DependsOn []string
+
+	// Priority ranks the scenarios of the Lifecycle for the -tbdd.min-priority flag, which
+	// excludes those below it from the run, and orders the members of a Group under a
+	// -tbdd.budget so the most important run first. It does not reorder tests or the
+	// variants of a Lifecycle, which run in the order they are declared. It is
+	// PriorityNormal when unset, and recorded as the ScenarioResult.Priority of every
+	// scenario.
+	Priority Priority
+
+	// Seed is the base of the seeds of its scenarios, from which every stochastic input of a
+	// scenario should be derived so that it can be reproduced. The basis scenario receives
+	// Seed itself and every variant a seed derived deterministically from Seed and its Kind.
+	// The seed of a scenario is the Seed of every phase struct it passes and is recorded as
+	// its ScenarioResult.Seed. When zero, the run seed returned by the Seed function is the
+	// base, so passing the same -tbdd.seed reproduces the scenario seeds.
+	Seed int64
+
+	// Masks replace volatile values with placeholders before Assert.Golden
+	// comparisons and before Artifacts.WriteJSON persists values.
+	Masks []Mask
+
+	// TCCodec encodes and decodes test cases for every feature which persists or loads
+	// them; JSONCodec is used when it is nil. When the -tbdd.artifacts flag is set the test
+	// case of every failing scenario is persisted to its Artifacts directory with it.
+	TCCodec TCCodec[T]
+
+	getT        func(TestingT) *testing.T
+	runHook     func(string)
+	runObserver func(string)
+	hookLayers  []hookLayer[T, R]
+	example     func(kind string, tc T, r R)
+	actProfiler *actProfiler
+	baseline    func(t *testing.T, elapsed time.Duration, mem *MemDelta)
 }
 
 // NewI takes a *testing.T and an index in a table driven test to construct
@@ -108,11 +319,39 @@ func (b Lifecycle[T, R]) New(t *testing.T) func(*testing.T) {
 	}
 }
 
+// NewTI is like NewI but accepts any TestingT implementation.
+//
+// Unless WithTestingTAdapter is used, t and the values passed to the returned
+// function must be *testing.T values.
+func (b Lifecycle[T, R]) NewTI(t TestingT, tableTestIndex int) func(TestingT) {
+	t.Helper()
+
+	return (lifecycle[T, R])(b).newI(t, tableTestIndex)
+}
+
+// NewT is like New but accepts any TestingT implementation.
+//
+// Unless WithTestingTAdapter is used, t and the values passed to the returned
+// function must be *testing.T values.
+func (b Lifecycle[T, R]) NewT(t TestingT) func(TestingT) {
+	t.Helper()
+
+	return (lifecycle[T, R])(b).new(t)
+}
+
 type Hooks[T, R any] struct {
 	AfterArrange func(*testing.T, AfterArrange[T])
 	AfterGiven   func(*testing.T, AfterGiven[T])
 	AfterAct     func(*testing.T, AfterAct[T, R])
 	AfterAssert  func(*testing.T, AfterAssert[T, R])
+
+	// AfterSkip is called when the given or when phase of a scenario is skipped, just before
+	// the skipped subtest ends.
+	AfterSkip func(*testing.T, AfterSkip[T])
+
+	// ConfigError is called for each misconfiguration detected while running the lifecycle,
+	// just before the test is failed.
+	ConfigError func(*testing.T, *ConfigError)
 }
 
 // Arrange contains the mutable configuration of the rest of the test execution plan.
@@ -133,6 +372,8 @@ type Arrange[T, R any] struct {
 	// Assert can be altered by the Arrange func if desired.
 	// This is a pointer to the lifecycle's Assert function so Arrange can replace it.
 	Assert *(func(*testing.T, Assert[T, R]))
+	// Require can be altered by the Arrange func if desired.
+	Require *(func(*testing.T, T) error)
 	// Given is provided for seeding the first return argument context if desired.
 	Given string
 	// When can be altered by Arrange func if desired.
@@ -141,6 +382,10 @@ type Arrange[T, R any] struct {
 	// Then can be altered by Arrange func if desired.
 	// It must be non-empty by the end of the Describe phase which comes after Arrange.
 	Then *string
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // AfterArrange describes the configuration of a test case arrangement for
@@ -154,6 +399,12 @@ type AfterArrange[T any] struct {
 	NilGivenFunc bool
 	// EmptyGivenString is true if the effective Given description of a BDD lifecycle is empty.
 	EmptyGivenString bool
+	// Bag is shared by every hook of the scenario.
+	Bag *Bag
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // AfterGiven describes the configuration of a test case for
@@ -168,6 +419,12 @@ type AfterGiven[T any] struct {
 	// Then can be altered by AfterGiven func if desired.
 	Then     *string
 	GivenRan bool
+	// Bag is shared by every hook of the scenario.
+	Bag *Bag
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // Describe contains the configuration of a test case and its Given, When, and then context
@@ -182,6 +439,8 @@ type Describe[T any] struct {
 	When string
 	// Then is the initial value of then which can be referenced and loaded into the returned DescribeResponse struct as desired.
 	Then string
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // DescribeResponse contains the definition of when + then for a BDD test case
@@ -200,6 +459,15 @@ type AfterAct[T, R any] struct {
 	TC *T
 	// Result can be altered by AfterAct func if desired.
 	Result *R
+	// Bag is shared by every hook of the scenario.
+	Bag *Bag
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Mem is the change in memory statistics across Act when the Lifecycle sets
+	// MemStats, otherwise nil.
+	Mem *MemDelta
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // Assert describes the configuration of a test case and its result for analysis.
@@ -208,6 +476,10 @@ type Assert[T, R any] struct {
 	TC T
 	// R and its internals are intended to be immutable during Assert phase.
 	Result R
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // AfterAssert describes the configuration of a test case and its result for
@@ -217,6 +489,31 @@ type AfterAssert[T, R any] struct {
 	TC *T
 	// Result can be altered by AfterAssert func if desired.
 	Result *R
+	// Bag is shared by every hook of the scenario.
+	Bag *Bag
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
+}
+
+// AfterSkip describes a skipped scenario for post-skip hook use.
+type AfterSkip[T any] struct {
+	TC *T
+	// Phase is "given", "require", or "when" depending on which phase skipped: the given or
+	// when phase by calling Skip, SkipNow, or Skipf, or the require phase by reporting an
+	// unmet precondition under RequireSkip.
+	Phase string
+	// Reason is the message passed to tbdd.Skip or tbdd.Skipf, or empty when the
+	// phase skipped by other means. The message of a plain t.Skip or t.Skipf is not
+	// recorded, as the testing package does not expose it.
+	Reason string
+	// Bag is shared by every hook of the scenario.
+	Bag *Bag
+	// Artifacts is the artifact directory of the scenario.
+	Artifacts *Artifacts
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64
 }
 
 // TestVariant describes a new test case created from some basis case.
@@ -226,223 +523,1013 @@ type TestVariant[T any] struct {
 	Kind        string
 	SkipTC      bool
 	SkipCloneTC bool
+
+	// Priority, when set, replaces the Lifecycle.Priority of the variant, such as to run
+	// exhaustive variants of a smoke scenario only nightly.
+	Priority Priority
+
+	// Timeout, when positive, is how long the Act phase of the variant may take, for
+	// generated edge cases which legitimately take longer than the rest. An Act which
+	// exceeds it ends the test binary with the stack of every goroutine written to stderr,
+	// naming the variant, like Lifecycle.Watchdog does. The Watchdog of the variant's
+	// scenario, if any, is extended by Timeout so it does not end the variant first.
+	Timeout time.Duration
 }
 
-// testingT is a simplified version of the functions the *testing.T type implements.
+// TestingT is a simplified version of the functions the *testing.T type implements.
 //
 // In normal use the caller should always be comfortable using a standard non-nil
-// *testing.T value which will always satisfy the interface testingT.
-type testingT interface {
+// *testing.T value which will always satisfy the interface TestingT. Frameworks
+// built on tbdd may pass their own implementation to NewT or NewTI along with
+// WithTestingTAdapter.
+type TestingT interface {
 	Helper()
 	Run(string, func(*testing.T)) bool
 	Fatalf(format string, args ...any)
 	Error(args ...any)
 }
 
+// runT is the subset of TestingT needed to start a subtest.
+type runT interface {
+	Run(string, func(*testing.T)) bool
+}
+
 type lifecycle[T, R any] Lifecycle[T, R]
 
-func (b lifecycle[T, R]) afterArrange(t *testing.T, tc *T, arrangeRan, nilGivenFunc, emptyGivenString bool) {
+// plan is the execution plan of a Lifecycle, built once by newI and shared
+// by the basis test case and every variant so that large Lifecycle values
+// are not copied for each of them.
+type plan[T, R any] struct {
+	// phases is the initial configuration copied by each scenario.
+	phases    phases[T, R]
+	tc        T
+	cloneTC   func(T) T
+	variants  func(*testing.T, T) iter.Seq[TestVariant[T]]
+	variants2 func(*testing.T, T) iter.Seq2[TestVariant[T], error]
+	defaults  []func(*T)
+	inferKind bool
+	layout    SubtestLayout
+	synctest  bool
+	trace     bool
+	memStats  bool
+	warmups   int
+	maxVars   int
+	invs      []func(*testing.T, T)
+	normalize []func(*R)
+	fdLeaks   bool
+	aliasing  bool
+	readOnly  bool
+	lazyGiven bool
+	parSafe   bool
+	reqPolicy RequirePolicy
+	seed      int64
+	codec     TCCodec[T]
+	watchdog  time.Duration
+	slowPhase time.Duration
+	masks     []Mask
+	meta      map[string]string
+	example   func(kind string, tc T, r R)
+	profiler  *actProfiler
+	baseline  func(t *testing.T, elapsed time.Duration, mem *MemDelta)
+	source    string
+
+	skipUntil       time.Time
+	skipUntilReason string
+	dependsOn       []string
+	priority        Priority
+
+	// indexPrefix is the subtest name prefix derived from the table test
+	// index, such as "3/", or empty when there is no index.
+	indexPrefix string
+	// whenName and thenName are the names of the when and then subtests of
+	// the scenarios whose descriptions Arrange leaves unchanged.
+	whenName, thenName string
+
+	// getT converts a TestingT to *testing.T
+	//
+	// under a self-test context it will return nil
+	getT func(TestingT) *testing.T
+
+	// runHook is an internal function reference supporting self-test contexts
+	//
+	// It is used to track run calls.
+	runHook     func(string)
+	runObserver func(string)
+}
+
+// phases holds the parts of a Lifecycle which Arrange and Describe may
+// change. Every scenario works on its own copy.
+type phases[T, R any] struct {
+	Given, When, Then string
+	hooks             Hooks[T, R]
+	hookLayers        []hookLayer[T, R]
+	arrange           func(*testing.T, Arrange[T, R]) (string, func(*testing.T))
+	describe          func(*testing.T, Describe[T]) DescribeResponse
+	require           func(*testing.T, T) error
+	act               func(*testing.T, T) R
+	assert            func(*testing.T, Assert[T, R])
+}
+
+func (b *phases[T, R]) afterArrange(t *testing.T, tc func() *T, bag *Bag, art *Artifacts, seed int64, arrangeRan, nilGivenFunc, emptyGivenString bool) {
 	if f := b.hooks.AfterArrange; f != nil {
-		f(t, AfterArrange[T]{tc, arrangeRan, nilGivenFunc, emptyGivenString})
+		f(t, AfterArrange[T]{tc(), arrangeRan, nilGivenFunc, emptyGivenString, bag, art, seed})
+	}
+}
+
+// afterSkip records a skip of phase and calls the AfterSkip hook when t has been skipped,
+// unless a phase nested within it already did.
+func (b *phases[T, R]) afterSkip(t *testing.T, tc func() *T, bag *Bag, art *Artifacts, phase string, sr *scenario) {
+	if !(nillableT{t, nil}).Skipped() || sr.SkipPhase != "" {
+		return
+	}
+
+	sr.SkipPhase = phase
+	sr.SkipReason = skipReason(t)
+
+	if f := b.hooks.AfterSkip; f != nil {
+		f(t, AfterSkip[T]{tc(), phase, sr.SkipReason, bag, art, sr.Seed})
+	}
+}
+
+func (b *phases[T, R]) configError(t *testing.T, field, prefix string, variantIndex int, err error) {
+	if f := b.mergedHooks().ConfigError; f != nil {
+		f(t, &ConfigError{field, prefix, variantIndex, err})
 	}
 }
 
-func (b lifecycle[T, R]) newI(t testingT, tableTestIndex int) func(testingT) {
+// configErrorSubtest names the subtests which report misconfigurations.
+const configErrorSubtest = "tbdd-config-error"
+
+// configFailure calls report within a subtest named prefix followed by
+// configErrorSubtest, so a misconfigured scenario or variant fails on its own
+// rather than ending the test running the lifecycle and obscuring the results
+// of other table entries. The result of sr is recorded when it is non-nil.
+//
+// report receives the subtest, which prefixes its failures with a link to
+// the definition of the Lifecycle, or t itself in self-test contexts where
+// the subtest is nil.
+func (p *plan[T, R]) configFailure(t TestingT, prefix string, sr *scenario, report func(TestingT)) {
 	t.Helper()
 
-	// getT converts a testingT to *testing.T
-	//
-	// under a self-test context it will return nil
-	getT := b.getT
-	if getT == nil {
-		getT = defaultGetT
+	p.run(t, prefix+configErrorSubtest, func(st *testing.T) {
+		if st == nil {
+			report(t)
+			return
+		}
+
+		st.Helper()
+
+		if sr != nil {
+			recordResult(st, sr)
+		}
+
+		report(linkedT{st, p.source})
+	})
+}
+
+// variantConfigFailure reports a misconfiguration of the variant at index i
+// with report, as configFailure does, recording a failed result of kind.
+func (p *plan[T, R]) variantConfigFailure(t TestingT, i int, kind string, report func(TestingT)) {
+	t.Helper()
+
+	sr := &scenario{class: ClassFailedConfig}
+	sr.Kind = kind
+	sr.Priority = p.priority
+	sr.Meta = p.meta
+	sr.Source = p.source
+
+	p.configFailure(t, p.indexPrefix+"variant "+strconv.Itoa(i)+"/", sr, report)
+}
+
+// run starts a subtest after notifying any run observer.
+func (p *plan[T, R]) run(t runT, name string, f func(*testing.T)) bool {
+	if f := p.runObserver; f != nil {
+		f(name)
 	}
 
-	// runHook is an internal function reference supporting self-test contexts
-	//
-	// It is used to track run calls.
-	runHook := b.runHook
+	return t.Run(name, f)
+}
 
-	f := func(t testingT, tc T, prefix string) func(testingT) {
-		t.Helper()
+func (b lifecycle[T, R]) newI(t TestingT, tableTestIndex int) func(TestingT) {
+	t.Helper()
+
+	p := &plan[T, R]{
+		phases: phases[T, R]{
+			Given:      b.Given,
+			When:       b.When,
+			Then:       b.Then,
+			hooks:      b.hooks,
+			hookLayers: b.hookLayers,
+			arrange:    b.Arrange,
+			describe:   b.Describe,
+			require:    b.Require,
+			act:        b.Act,
+			assert:     b.Assert,
+		},
+		tc:          b.TC,
+		cloneTC:     b.CloneTC,
+		variants:    b.Variants,
+		variants2:   b.Variants2,
+		defaults:    b.DefaultTC,
+		inferKind:   b.InferKind,
+		lazyGiven:   b.Arrange == nil || arrangesLazily(b.Arrange),
+		layout:      b.Layout,
+		synctest:    b.Synctest,
+		trace:       b.Trace,
+		memStats:    b.MemStats,
+		warmups:     b.WarmupRuns,
+		maxVars:     b.MaxVariants,
+		invs:        b.Invariants,
+		normalize:   b.NormalizeResult,
+		fdLeaks:     b.FDLeakCheck,
+		aliasing:    b.SharedStateCheck,
+		readOnly:    b.ReadOnlyTC,
+		parSafe:     b.ParallelSafe,
+		reqPolicy:   b.RequirePolicy,
+		seed:        b.Seed,
+		codec:       b.tcCodec(),
+		watchdog:    b.Watchdog,
+		slowPhase:   b.SlowPhase,
+		masks:       b.Masks,
+		meta:        metadata(b.Owner, b.Ticket, b.Severity, b.Meta),
+		example:     b.example,
+		profiler:    b.actProfiler,
+		baseline:    b.baseline,
+		source:      b.Source,
+		getT:        b.getT,
+		runHook:     b.runHook,
+		runObserver: b.runObserver,
+
+		skipUntil:       b.SkipUntil,
+		skipUntilReason: b.SkipUntilReason,
+		dependsOn:       b.DependsOn,
+		priority:        b.Priority.orNormal(),
+	}
+	if p.getT == nil {
+		p.getT = defaultGetT
+	}
+	if p.source == "" {
+		// a Lifecycle literal is located by the code running it
+		p.source = definedAt()
+	}
+	if tableTestIndex >= 0 {
+		p.indexPrefix = strconv.Itoa(tableTestIndex) + "/"
+	}
+	p.whenName, p.thenName = "when "+b.When, "then "+b.Then
+
+	return p.exec
+}
+
+func (b lifecycle[T, R]) new(t TestingT) func(TestingT) {
+	t.Helper()
+
+	return b.newI(t, -1)
+}
+
+// applyDefaults applies every non-nil function of defaults to tc, in order.
+func applyDefaults[T any](defaults []func(*T), tc *T) {
+	for _, f := range defaults {
+		if f != nil {
+			f(tc)
+		}
+	}
+}
 
-		b := b
+// scenario returns the test function of one scenario: the basis test case
+// when kind is empty, otherwise the variant of that Kind.
+//
+// When clone is non-nil it is applied to tc just before the first phase
+// which could mutate it, followed by the DefaultTC of the plan, so scenarios
+// which never get that far, such as those excluded by -tbdd.filter or
+// skipped by SkipUntil, are never cloned. When shared is non-nil the test
+// case is instead cloned immediately and fingerprinted in shared.
+func (p *plan[T, R]) scenario(t TestingT, tc T, clone func(T) T, index int, kind string, priority Priority, timeout time.Duration, shared *sharedState) func(TestingT) {
+	t.Helper()
+
+	// warmups clone the test case like the measured Act does
+	warmupClone := clone
 
-		if tableTestIndex >= 0 {
-			s := strconv.Itoa(tableTestIndex)
-			if prefix == "" {
-				prefix = s
-			} else {
-				prefix = s + "/" + prefix
+	sr := &scenario{}
+
+	tcp := func() *T {
+		if !sr.prepared {
+			sr.prepared = true
+			if clone != nil {
+				sr.cloneErr = cloneInto(&tc, clone)
+			}
+			if sr.cloneErr == nil {
+				applyDefaults(p.defaults, &tc)
 			}
 		}
-		if prefix != "" {
-			prefix += "/"
+
+		return &tc
+	}
+
+	getT, runHook := p.getT, p.runHook
+
+	if shared != nil {
+		msgs, unchecked := shared.add(kind, *tcp())
+		for _, msg := range msgs {
+			t.Error(msg)
 		}
 
-		hasGivenPhase := (b.Arrange != nil || b.Given != "")
+		if st := getT(t); unchecked && st != nil {
+			warn(st, Warning{Message: "tbdd: SharedStateCheck compares the test cases of the first " + strconv.Itoa(maxSharedStateScenarios) +
+				" scenarios of a Lifecycle; " + scenarioLabel(kind) + " and those after it are not checked"})
+		}
+	}
 
-		test := func(t testingT) {
-			t.Helper()
+	b := p.phases
+
+	if b.arrange == nil {
+		b.mergeHooks()
+	}
+
+	// names are built with single concatenations of precomputed segments
+	// since large tables create a great many of them
+	prefix := p.indexPrefix
+	if kind != "" {
+		prefix = p.indexPrefix + kind + "/"
+	}
+
+	hasGivenPhase := (b.arrange != nil || b.Given != "")
+
+	watchdog := p.watchdog
+	if watchdog > 0 {
+		watchdog += max(timeout, 0)
+	}
+
+	sr.Kind = kind
+	sr.Priority = priority
+	sr.Meta = p.meta
+	sr.Seed = scenarioSeed(p.seed, kind)
+	sr.Source = p.source
+
+	bag := &Bag{}
+	rec := &Recorder{}
+
+	var testName string
+	if t := getT(t); t != nil {
+		testName = t.Name()
+	}
+	art := newArtifacts(testName, prefix, p.masks)
+	if art.root != "" {
+		art.id = func() string {
+			if sr.ID != "" {
+				return sr.ID
+			}
+
+			return resultID(testName, p.indexPrefix, &ScenarioResult{Given: b.Given, When: b.When, Then: b.Then, Kind: kind})
+		}
+	}
+
+	// traceCtx carries the trace task of the scenario's outermost subtest
+	var traceCtx context.Context
 
-			if f := b.Describe; f != nil {
-				r := f(getT(t), Describe[T]{tc, b.Given, b.When, b.Then})
+	test := func(t TestingT) {
+		t.Helper()
 
-				b.When = r.When
-				b.Then = r.Then
+		if f := b.describe; f != nil {
+			if len(p.defaults) > 0 {
+				// Describe sees the defaults, which only the clone may receive
+				tcp()
 			}
 
-			if b.When == "" {
-				t.Error("When string of BDD test must not be empty")
+			var r DescribeResponse
+			p.readOnlyTC(t, "describe", &tc, sr, nil, func() {
+				r = f(getT(t), Describe[T]{tc, b.Given, b.When, b.Then, sr.Seed})
+			})
+
+			b.When = r.When
+			b.Then = r.Then
+		}
+
+		sr.Given, sr.When, sr.Then = b.Given, b.When, b.Then
+
+		if b.When == "" || b.Then == "" || b.act == nil || b.assert == nil {
+			sr.fail(ClassFailedConfig)
+
+			// a given subtest already holds the scenario and records its result
+			name, rec := prefix, sr
+			if hasGivenPhase {
+				name, rec = "", nil
+			}
+
+			p.configFailure(t, name, rec, func(t TestingT) {
+				t.Helper()
+
+				if b.When == "" {
+					b.configError(getT(t), "When", prefix, -1, ErrEmptyWhen)
+					t.Error(ErrEmptyWhen.Error())
+				}
+				if b.Then == "" {
+					b.configError(getT(t), "Then", prefix, -1, ErrEmptyThen)
+					t.Error(ErrEmptyThen.Error())
+				}
+				if b.act == nil {
+					b.configError(getT(t), "Act", prefix, -1, ErrNilAct)
+					t.Error(ErrNilAct.Error())
+				}
+				if b.assert == nil {
+					b.configError(getT(t), "Assert", prefix, -1, ErrNilAssert)
+					t.Error(ErrNilAssert.Error())
+				}
+
+				t.Fatalf(`when+then not run: BDD test not configured properly (prefix = "%s")`, prefix)
+			})
+			return
+		}
+		if !selected(sr.ScenarioResult) {
+			sr.unselected = true
+			if getT(t) != nil {
+				recordFiltered(testName, sr.ScenarioResult)
 			}
-			if b.Then == "" {
-				t.Error("Then string of BDD test must not be empty")
+			return
+		}
+		if !hasGivenPhase && (belowMinPriority(sr.Priority) || !selectedByID(testName, p.indexPrefix, &sr.ScenarioResult)) {
+			sr.unselected = true
+			return
+		}
+
+		// results and attributes are only recorded for real tests; LayoutID names
+		// subtests after the ID, which the given phase may already have chosen
+		if sr.ID == "" && (p.layout == LayoutID || getT(t) != nil) {
+			sr.ID = resultID(testName, p.indexPrefix, &sr.ScenarioResult)
+		}
+		if t := getT(t); t != nil && hasGivenPhase {
+			emitAttrs(t, &sr.ScenarioResult)
+			if p.layout == LayoutID {
+				t.Log(sr.Scenario())
 			}
-			if b.Act == nil {
-				t.Error("Act function of BDD test is not defined")
+		}
+
+		whenStr, thenStr := p.whenName, p.thenName
+		if b.When != p.phases.When {
+			whenStr = "when " + b.When
+		}
+		if prefix != "" && !hasGivenPhase {
+			whenStr = prefix + whenStr
+		}
+		if b.Then != p.phases.Then {
+			thenStr = "then " + b.Then
+		}
+
+		var result R
+		act := func(t *testing.T) {
+			nillableT{t, runHook}.Helper()
+
+			if !hasGivenPhase {
+				recordResult(t, sr)
+				trackArtifacts(t, art)
+				if p.fdLeaks && t != nil {
+					checkFDLeaks(t, openFDs)
+				}
+				if watchdog > 0 && t != nil {
+					startWatchdog(t, watchdog)
+				}
+				if config.artifacts != "" && t != nil {
+					persistFailingTC(t, art, p.codec, tcp)
+				}
+				emitAttrs(t, &sr.ScenarioResult)
+				if p.trace {
+					traceCtx = startTraceTask(t)
+				}
+				if t != nil && p.layout == LayoutID {
+					t.Log(sr.Scenario())
+				}
 			}
-			if b.Assert == nil {
-				t.Error("Assert function of BDD test is not defined")
+
+			defer b.afterSkip(t, tcp, bag, art, "when", sr)
+
+			if !hasGivenPhase {
+				skipOverBudget(t, sr)
+				skipUntil(t, sr, p.skipUntil, p.skipUntilReason)
+				skipDependent(t, sr, p.dependsOn)
+
+				if tcp(); sr.cloneErr != nil {
+					if t != nil {
+						p.cloneFailure(t, &b, prefix, index, sr)
+					}
+					return
+				}
 			}
-			if b.When == "" || b.Then == "" || b.Act == nil || b.Assert == nil {
-				t.Fatalf(`when+then not run: BDD test not configured properly (prefix = "%s")`, prefix)
+
+			if b.require != nil && !func() bool {
+				nillableT{t, nil}.Helper()
+				defer b.afterSkip(t, tcp, bag, art, "require", sr)
+
+				return require(t, b.require, *tcp(), p.reqPolicy, sr)
+			}() {
 				return
 			}
 
-			whenStr := "when " + b.When
-			if prefix != "" && !hasGivenPhase {
-				whenStr = prefix + whenStr
+			registerRecorder(t, rec)
+
+			if p.warmups > 0 {
+				p.warmUp(t, sr, *tcp(), warmupClone, b.act)
 			}
 
-			t.Run(whenStr, func(t *testing.T) {
-				nt := nillableT{t, runHook}
-				nt.Helper()
+			lint := !p.parSafe && t != nil && !isParallel(t)
+
+			if timeout > 0 && t != nil {
+				stop := startActTimeout(t, kind, timeout)
+				defer stop()
+			}
 
-				result := b.Act(t, tc)
-				if f := b.hooks.AfterAct; f != nil {
-					f(t, AfterAct[T, R]{&tc, &result})
+			var mem *MemDelta
+			func() {
+				if p.slowPhase > 0 {
+					// deferred so a phase which ends the test is still reported as slow
+					defer warnSlowPhase(t, sr, "act", time.Now(), p.slowPhase)
 				}
 
-				nt.Run("then "+b.Then, func(t *testing.T) {
-					nillableT{t, nil}.Helper()
+				if p.synctest || p.profiler != nil || p.trace || p.memStats {
+					start := time.Now()
+					mem = p.instrumentAct(t, sr, traceCtx, art, func(t *testing.T) {
+						result = b.act(t, *tcp())
+					})
+					sr.Mem = mem
 
-					b.Assert(t, Assert[T, R]{tc, result})
-					if f := b.hooks.AfterAssert; f != nil {
-						f(t, AfterAssert[T, R]{&tc, &result})
+					if p.baseline != nil && t != nil && !t.Failed() {
+						p.baseline(t, time.Since(start), mem)
+					}
+				} else {
+					result = b.act(t, *tcp())
+				}
+			}()
+
+			if lint && isParallel(t) {
+				parallelMisuse(t, "act")
+			}
+
+			for _, f := range p.normalize {
+				if f != nil {
+					f(&result)
+				}
+			}
+
+			p.checkInvariants(t, "act", tcp, sr)
+
+			if p.example != nil && !(nillableT{t, nil}).Failed() {
+				p.example(kind, *tcp(), result)
+			}
+			if f := b.hooks.AfterAct; f != nil {
+				f(t, AfterAct[T, R]{tcp(), &result, bag, art, mem, sr.Seed})
+			}
+		}
+		assert := func(t *testing.T) {
+			nillableT{t, nil}.Helper()
+
+			if t != nil {
+				rec.drain(t)
+			}
+
+			// self-tests run the assert phase without a *testing.T to fail
+			var et errorT
+			var cleanup func(func())
+			if t != nil {
+				et, cleanup = t, t.Cleanup
+			}
+
+			func() {
+				if p.slowPhase > 0 {
+					defer warnSlowPhase(t, sr, "assert", time.Now(), p.slowPhase)
+				}
+
+				// parallel subtests of the then phase must finish before the test case
+				// is compared
+				p.readOnlyTC(et, "assert", &tc, sr, cleanup, func() {
+					if p.synctest || p.trace {
+						p.instrumentAssert(t, sr, traceCtx, func(t *testing.T) {
+							b.assert(t, Assert[T, R]{tc, result, art, sr.Seed})
+						})
+					} else {
+						b.assert(t, Assert[T, R]{tc, result, art, sr.Seed})
 					}
 				})
+			}()
+
+			p.checkInvariants(t, "assert", tcp, sr)
+
+			if f := b.hooks.AfterAssert; f != nil {
+				f(t, AfterAssert[T, R]{tcp(), &result, bag, art, sr.Seed})
+			}
+		}
+
+		switch {
+		case (p.layout == LayoutFlat || p.layout == LayoutID) && hasGivenPhase:
+			// already running within the single subtest created by the given phase
+			act(getT(t))
+			assert(getT(t))
+		case p.layout == LayoutFlat, p.layout == LayoutMerged:
+			p.run(t, whenStr+"/"+thenStr, func(t *testing.T) {
+				act(t)
+				assert(t)
+			})
+		case p.layout == LayoutID:
+			p.run(t, sr.ID, func(t *testing.T) {
+				act(t)
+				assert(t)
+			})
+		default:
+			p.run(t, whenStr, func(t *testing.T) {
+				nt := nillableT{t, runHook}
+
+				var actRan bool
+				defer func() {
+					// report a then subtest even when a fatal Act failure ended the when
+					// subtest so that it is clear the assertions were never evaluated
+					if !actRan && nt.Failed() {
+						reason := "not run: Act failed"
+						if sr.Precondition != "" {
+							reason = "not run: precondition not met"
+						}
+
+						p.run(nt, thenStr, func(t *testing.T) {
+							nillableT{t, nil}.Skip(reason)
+						})
+					}
+				}()
+
+				act(t)
+				actRan = true
+
+				p.run(nt, thenStr, assert)
 			})
 		}
+	}
 
-		if hasGivenPhase {
-			next := test
+	if hasGivenPhase {
+		next := test
 
-			test = func(t testingT) {
-				t.Helper()
+		test = func(t TestingT) {
+			t.Helper()
 
-				var arrangeRan bool
-				var given func(*testing.T)
-				if f := b.Arrange; f != nil {
-					arrangeRan = true
-					b.Given, given = f(getT(t), Arrange[T, R]{&tc, &b.hooks, &b.Describe, &b.Act, &b.Assert, b.Given, &b.When, &b.Then})
-					if given == nil {
-						b.afterArrange(getT(t), &tc, arrangeRan, true, b.Given == "")
-						t.Fatalf(`test setup not run: Arrange returned a nil given function (prefix = "%s")`, prefix)
-						return
-					}
+			// an Arrange function may alter the test case, so it receives the clone,
+			// unless it is one of GWT which only hands it to its given function
+			if !p.lazyGiven {
+				if tcp(); sr.cloneErr != nil {
+					p.configFailure(t, prefix, sr, func(t TestingT) {
+						t.Helper()
+
+						p.cloneFailure(t, &b, prefix, index, sr)
+					})
+					return
 				}
+			}
 
-				b.afterArrange(getT(t), &tc, arrangeRan, given == nil, b.Given == "")
+			var arrangeRan bool
+			var given func(*testing.T)
+			if f := b.arrange; f != nil {
+				arrangeRan = true
 
-				if b.Given == "" {
-					t.Fatalf(`test setup not run: Arrange function returned an empty Given string (prefix = "%s")`, prefix)
+				func() {
+					if p.slowPhase > 0 {
+						defer warnSlowPhase(getT(t), sr, "arrange", time.Now(), p.slowPhase)
+					}
+
+					// the pointer of tcp, whose value is cloned in place once the scenario
+					// is known to run
+					b.Given, given = f(getT(t), Arrange[T, R]{&tc, &b.hooks, &b.describe, &b.act, &b.assert, &b.require, b.Given, &b.When, &b.Then, art, sr.Seed})
+				}()
+				b.mergeHooks()
+				if given == nil {
+					sr.fail(ClassFailedConfig)
+					p.configFailure(t, prefix, sr, func(t TestingT) {
+						t.Helper()
+
+						b.configError(getT(t), "Arrange", prefix, -1, ErrNilGivenFunc)
+						b.afterArrange(getT(t), tcp, bag, art, sr.Seed, arrangeRan, true, b.Given == "")
+						t.Fatalf(`test setup not run: Arrange returned a nil given function (prefix = "%s")`, prefix)
+					})
 					return
 				}
+			}
 
-				t.Run(prefix+"given "+b.Given, func(t *testing.T) {
+			b.afterArrange(getT(t), tcp, bag, art, sr.Seed, arrangeRan, given == nil, b.Given == "")
+
+			if b.Given == "" {
+				sr.fail(ClassFailedConfig)
+				p.configFailure(t, prefix, sr, func(t TestingT) {
 					t.Helper()
 
-					var givenRan bool
+					b.configError(getT(t), "Given", prefix, -1, ErrEmptyGiven)
+					t.Fatalf(`test setup not run: Arrange function returned an empty Given string (prefix = "%s")`, prefix)
+				})
+				return
+			}
+
+			// scenarios which are excluded by the flags other than -tbdd.filter skip
+			// their given phase along with the rest
+			if belowMinPriority(sr.Priority) || !selectedByID(testName, p.indexPrefix, &ScenarioResult{Given: b.Given, When: b.When, Then: b.Then, Kind: kind}) {
+				sr.unselected = true
+				return
+			}
+
+			givenStr := prefix + "given " + b.Given
+			switch p.layout {
+			case LayoutFlat:
+				givenStr += "/when " + b.When + "/then " + b.Then
+			case LayoutID:
+				sr.ID = resultID(testName, p.indexPrefix, &ScenarioResult{Given: b.Given, When: b.When, Then: b.Then, Kind: kind})
+				givenStr = sr.ID
+			}
+
+			p.run(t, givenStr, func(t *testing.T) {
+				t.Helper()
+
+				recordResult(t, sr)
+				trackArtifacts(t, art)
+				if p.fdLeaks && t != nil {
+					checkFDLeaks(t, openFDs)
+				}
+				if watchdog > 0 && t != nil {
+					startWatchdog(t, watchdog)
+				}
+				if config.artifacts != "" && t != nil {
+					persistFailingTC(t, art, p.codec, tcp)
+				}
+				if p.trace {
+					traceCtx = startTraceTask(t)
+				}
+
+				var givenRan bool
+				func() {
+					defer b.afterSkip(t, tcp, bag, art, "given", sr)
+					defer func() {
+						if (nillableT{t, nil}).Failed() {
+							sr.fail(ClassFailedArrange)
+						}
+					}()
+
+					skipOverBudget(t, sr)
+					skipUntil(t, sr, p.skipUntil, p.skipUntilReason)
+					skipDependent(t, sr, p.dependsOn)
+
+					if tcp(); sr.cloneErr != nil {
+						if t != nil {
+							p.cloneFailure(t, &b, prefix, index, sr)
+						}
+						return
+					}
+
+					if arrangeRan {
+						p.checkInvariants(t, "arrange", tcp, sr)
+					}
+
 					if given != nil {
 						givenRan = true
-						given(t)
-					}
 
-					if f := b.hooks.AfterGiven; f != nil {
-						f(t, AfterGiven[T]{&tc, &b.Given, &b.When, &b.Then, givenRan})
+						lint := !p.parSafe && t != nil && !isParallel(t)
+
+						func() {
+							if p.slowPhase > 0 {
+								defer warnSlowPhase(t, sr, "given", time.Now(), p.slowPhase)
+							}
+
+							if p.trace {
+								traceRegion(traceCtx, "given", func() {
+									given(t)
+								})
+							} else {
+								given(t)
+							}
+						}()
+
+						if lint && isParallel(t) {
+							parallelMisuse(t, "given")
+						}
+
+						p.checkInvariants(t, "given", tcp, sr)
 					}
+				}()
 
-					next(t)
-				})
-			}
-		} else {
-			b.afterArrange(getT(t), &tc, false, true, true)
+				if f := b.hooks.AfterGiven; f != nil {
+					f(t, AfterGiven[T]{tcp(), &b.Given, &b.When, &b.Then, givenRan, bag, art, sr.Seed})
+				}
 
-			if f := b.hooks.AfterGiven; f != nil {
-				f(getT(t), AfterGiven[T]{&tc, &b.Given, &b.When, &b.Then, false})
-			}
+				next(t)
+			})
 		}
+	} else {
+		b.afterArrange(getT(t), tcp, bag, art, sr.Seed, false, true, true)
 
-		return test
+		if f := b.hooks.AfterGiven; f != nil {
+			f(getT(t), AfterGiven[T]{tcp(), &b.Given, &b.When, &b.Then, false, bag, art, sr.Seed})
+		}
 	}
 
-	return func(t testingT) {
-		t.Helper()
+	return test
+}
 
-		// `tc := b.TC` is required so the basis test works on a copy of the lifecycle's TC value.
-		// The inner `tc := tc` plus optional CloneTC call let the basis test freely mutate its TC
-		// without affecting:
-		//   - the Lifecycle's stored TC, and
-		//   - the value passed to Variants,
-		// except for any shared mutable pointer types when CloneTC is nil or shallow.
-		tc := b.TC
-
-		// run non-variant basis test case
-		{
-			tc := tc // don't delete this line, see above comment block
-			if f := b.CloneTC; f != nil {
-				tc = f(tc)
+// warmUp calls act WarmupRuns times with t and tc, or a clone of it when
+// clone is non-nil, discarding the results. A warmup which fails ends the
+// remaining warmups.
+func (p *plan[T, R]) warmUp(t *testing.T, sr *scenario, tc T, clone func(T) T, act func(*testing.T, T) R) {
+	for range p.warmups {
+		tc := tc
+		if clone != nil {
+			if err := cloneInto(&tc, clone); err != nil {
+				if t != nil {
+					t.Fatalf("%v", &ConfigError{"CloneTC", "", -1, err})
+				}
+				return
 			}
+		}
 
-			f(t, tc, "")(t)
+		if p.synctest {
+			bubble(t, sr, func(t *testing.T) {
+				act(t, tc)
+			})
+		} else {
+			act(t, tc)
 		}
 
-		variants := b.Variants
-		if variants == nil {
+		if (nillableT{t, nil}).Failed() {
 			return
 		}
+	}
+}
 
-		// run test case variations
+// instrumentAct calls act with t, measuring its memory statistics, within a
+// synctest bubble, profiled, and in a trace region of the task in ctx when
+// the plan calls for it. It returns the memory statistics, if measured.
+func (p *plan[T, R]) instrumentAct(t *testing.T, sr *scenario, ctx context.Context, art *Artifacts, act func(*testing.T)) *MemDelta {
+	var mem *MemDelta
+	if p.memStats {
+		mem = &MemDelta{}
+
+		measured := act
+		act = func(t *testing.T) {
+			measureMem(mem, func() {
+				measured(t)
+			})
+		}
+	}
 
-		i := -1
-		for v := range variants(getT(t), tc) {
-			i++
+	f := act
+	if p.synctest {
+		f = func(t *testing.T) {
+			bubble(t, sr, act)
+		}
+	}
 
-			if v.SkipTC {
-				continue
+	g := func() {
+		f(t)
+	}
+	if p.profiler != nil && t != nil {
+		g = func() {
+			p.profiler.profile(t, art, func() {
+				f(t)
+			})
+		}
+	}
+
+	if p.trace {
+		traceRegion(ctx, "act", g)
+	} else {
+		g()
+	}
+
+	return mem
+}
+
+// instrumentAssert calls assert with t, within a synctest bubble and in a
+// trace region of the task in ctx when the plan calls for it.
+func (p *plan[T, R]) instrumentAssert(t *testing.T, sr *scenario, ctx context.Context, assert func(*testing.T)) {
+	g := func() {
+		assert(t)
+	}
+	if p.synctest {
+		g = func() {
+			bubble(t, sr, assert)
+		}
+	}
+
+	if p.trace {
+		traceRegion(ctx, "assert", g)
+		return
+	}
+
+	g()
+}
+
+// exec runs the basis test case followed by every variant.
+func (p *plan[T, R]) exec(t TestingT) {
+	t.Helper()
+
+	getT := p.getT
+
+	// `tc := p.tc` is required so the basis test works on a copy of the lifecycle's TC value.
+	// scenario receives its own copy of tc which it passes through CloneTC, if set, before
+	// any phase can mutate it. This lets the basis test freely mutate its TC without
+	// affecting:
+	//   - the Lifecycle's stored TC, and
+	//   - the value passed to Variants,
+	// except for any shared mutable pointer types when CloneTC is nil or shallow.
+	tc := p.tc
+
+	var shared *sharedState
+	if p.aliasing {
+		shared = &sharedState{}
+	}
+
+	// run non-variant basis test case
+	p.scenario(t, tc, p.cloneTC, -1, "", p.priority, 0, shared)(t)
+
+	// run test case variations
+
+	if t := getT(t); t != nil {
+		variantBases.Store(t, p.seed)
+		defer variantBases.Delete(t)
+	}
+
+	// it tracks the index of the variant being generated across Variants and
+	// Variants2
+	it := variantIter{i: -1}
+	if variants := p.variants; variants != nil {
+		func() {
+			var inBody bool
+			defer p.recoverVariants(t, "Variants", &it, &inBody)
+
+			for v := range variants(getT(t), tc) {
+				it.i++
+				v.Kind = kindOf(p.inferKind, p.tc, v)
+
+				inBody = true
+				if p.overLimit(t, &it, v.Kind) {
+					inBody = false
+					break
+				}
+
+				p.variant(t, it.i, v, shared)
+				inBody = false
 			}
+		}()
+	}
 
-			if v.Kind == "" {
-				t.Fatalf("BDD configuration error: test case variant at index %d has no Kind detail", i)
-				continue
+	if variants := p.variants2; variants != nil && !it.limited {
+		var inBody bool
+		defer p.recoverVariants(t, "Variants2", &it, &inBody)
+
+		for v, err := range variants(getT(t), tc) {
+			it.i++
+			v.Kind = kindOf(p.inferKind, p.tc, v)
+
+			inBody = true
+			if p.overLimit(t, &it, v.Kind) {
+				inBody = false
+				break
 			}
 
-			tc := v.TC
-			if !v.SkipCloneTC {
-				if f := b.CloneTC; f != nil {
-					tc = f(tc)
-				}
+			if err != nil {
+				i := it.i
+				p.variantConfigFailure(t, i, "", func(t TestingT) {
+					t.Helper()
+
+					p.phases.configError(getT(t), "Variants2", "", i, err)
+					t.Error((&ConfigError{"Variants2", "", i, err}).Error())
+				})
+				inBody = false
+				continue
 			}
 
-			f(t, tc, v.Kind)(t)
+			p.variant(t, it.i, v, shared)
+			inBody = false
 		}
 	}
 }
 
-func (b lifecycle[T, R]) new(t testingT) func(testingT) {
+// variant runs the test case variant at index i, fingerprinting its test
+// case in shared when it is non-nil.
+func (p *plan[T, R]) variant(t TestingT, i int, v TestVariant[T], shared *sharedState) {
 	t.Helper()
 
-	return b.newI(t, -1)
+	if v.SkipTC {
+		return
+	}
+
+	if v.Kind == "" {
+		p.variantConfigFailure(t, i, v.Kind, func(t TestingT) {
+			t.Helper()
+
+			p.phases.configError(p.getT(t), "Kind", "", i, ErrEmptyVariantKind)
+			t.Fatalf("BDD configuration error: test case variant at index %d has no Kind detail", i)
+		})
+		return
+	}
+
+	clone := p.cloneTC
+	if v.SkipCloneTC {
+		clone = nil
+	}
+
+	priority := v.Priority
+	if priority == 0 {
+		priority = p.priority
+	}
+
+	p.scenario(t, v.TC, clone, i, v.Kind, priority, v.Timeout, shared)(t)
 }
 
 // GWT constructs a Lifecycle using the classic BDD shape
@@ -476,35 +1563,56 @@ func GWT[T, R any](
 	when string, whenF func(*testing.T, T) R,
 	then string, thenF func(*testing.T, T, R),
 ) Lifecycle[T, R] {
+	b, err := TryGWT(
+		tc,
+		given, givenF,
+		when, whenF,
+		then, thenF,
+	)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return b
+}
+
+// TryGWT is like GWT except it returns an error instead of panicking when
+// the arguments are invalid.
+//
+// The returned error wraps one of ErrEmptyGiven, ErrEmptyWhen, ErrNilAct,
+// ErrEmptyThen, or ErrNilAssert so it can be inspected with errors.Is. This
+// is useful when lifecycles are constructed from dynamic data such as loaded
+// files or generated tables.
+func TryGWT[T, R any](
+	tc T,
+	given string, givenF func(*testing.T, *T),
+	when string, whenF func(*testing.T, T) R,
+	then string, thenF func(*testing.T, T, R),
+) (Lifecycle[T, R], error) {
 
 	var arrange func(*testing.T, Arrange[T, R]) (string, func(*testing.T))
 	if givenF != nil {
 		if given == "" {
-			panic("tbdd.GWT: given description must be non-empty when given function is non-nil")
+			return Lifecycle[T, R]{}, constructorError{"tbdd.GWT: given description must be non-empty when given function is non-nil", ErrEmptyGiven}
 		}
 
-		arrange = func(_ *testing.T, cfg Arrange[T, R]) (string, func(*testing.T)) {
-			tc := cfg.TC
-			return given, func(t *testing.T) {
-				givenF(t, tc)
-			}
-		}
+		arrange = givenArrange[T, R](given, givenF)
 	}
 
 	if when == "" {
-		panic("tbdd.GWT: when description must be non-empty")
+		return Lifecycle[T, R]{}, constructorError{"tbdd.GWT: when description must be non-empty", ErrEmptyWhen}
 	}
 
 	if whenF == nil {
-		panic("tbdd.GWT: when function must be non-nil")
+		return Lifecycle[T, R]{}, constructorError{"tbdd.GWT: when function must be non-nil", ErrNilAct}
 	}
 
 	if then == "" {
-		panic("tbdd.GWT: then description must be non-empty")
+		return Lifecycle[T, R]{}, constructorError{"tbdd.GWT: then description must be non-empty", ErrEmptyThen}
 	}
 
 	if thenF == nil {
-		panic("tbdd.GWT: then function must be non-nil")
+		return Lifecycle[T, R]{}, constructorError{"tbdd.GWT: then function must be non-nil", ErrNilAssert}
 	}
 
 	return Lifecycle[T, R]{
@@ -514,10 +1622,9 @@ func GWT[T, R any](
 		When:    when,
 		Act:     whenF,
 		Then:    then,
-		Assert: func(t *testing.T, cfg Assert[T, R]) {
-			thenF(t, cfg.TC, cfg.Result)
-		},
-	}
+		Assert:  thenAssert(thenF),
+		Source:  definedAt(),
+	}, nil
 }
 
 // WT is a convenience wrapper around GWT for use when
@@ -537,11 +1644,58 @@ func WT[T, R any](
 	)
 }
 
+// TryWT is like WT except it returns an error instead of panicking when
+// the arguments are invalid.
+//
+// See TryGWT for more detail.
+func TryWT[T, R any](
+	tc T,
+	when string, whenF func(*testing.T, T) R,
+	then string, thenF func(*testing.T, T, R),
+) (Lifecycle[T, R], error) {
+	return TryGWT(
+		tc,
+		"", nil,
+		when, whenF,
+		then, thenF,
+	)
+}
+
 //
 // helpers
 //
 
-func defaultGetT(t testingT) *testing.T {
+// givenArrange adapts a given description and function into an Arrange function.
+func givenArrange[T, R any](given string, givenF func(*testing.T, *T)) func(*testing.T, Arrange[T, R]) (string, func(*testing.T)) {
+	return func(_ *testing.T, cfg Arrange[T, R]) (string, func(*testing.T)) {
+		// only the given function may access the test case; see arrangesLazily
+		tc := cfg.TC
+		return given, func(t *testing.T) {
+			givenF(t, tc)
+		}
+	}
+}
+
+// arrangesLazily reports whether f was made by givenArrange, so it only
+// accesses its test case within the given function it returns and the test
+// case need not be cloned before it runs. Closures share the code pointer of
+// the function literal which made them.
+func arrangesLazily[T, R any](f func(*testing.T, Arrange[T, R]) (string, func(*testing.T))) bool {
+	return reflect.ValueOf(f).Pointer() == reflect.ValueOf(givenArrange[T, R]("", nil)).Pointer()
+}
+
+// thenAssert adapts a then function into an Assert function.
+func thenAssert[T, R any](thenF func(*testing.T, T, R)) func(*testing.T, Assert[T, R]) {
+	return func(t *testing.T, cfg Assert[T, R]) {
+		thenF(t, cfg.TC, cfg.Result)
+	}
+}
+
+func defaultGetT(t TestingT) *testing.T {
+	if l, ok := t.(linkedT); ok {
+		t = l.T
+	}
+
 	v, _ := t.(*testing.T)
 	if v == nil {
 		panic("not a real *testing.T instance")
@@ -572,3 +1726,23 @@ func (t nillableT) Run(name string, f func(t *testing.T)) bool {
 	f(nil)
 	return true
 }
+
+func (t nillableT) Failed() bool {
+	return t.t != nil && t.t.Failed()
+}
+
+func (t nillableT) Skipped() bool {
+	return t.t != nil && t.t.Skipped()
+}
+
+func (t nillableT) Skip(args ...any) {
+	if t.t != nil {
+		t.t.Skip(args...)
+	}
+}
+
+func (t nillableT) Parallel() {
+	if t.t != nil {
+		t.t.Parallel()
+	}
+}
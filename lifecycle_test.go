@@ -1,25 +1,31 @@
 package tbdd
 
 import (
+	"errors"
 	"iter"
+	"os"
+	"os/exec"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
-var _ testingT = (*testing.T)(nil)
+var _ TestingT = (*testing.T)(nil)
 
 func Test_testingT(t *testing.T) {
 	t.Parallel()
 
-	if v, ok := any(t).(testingT); !ok || v == nil {
-		t.Fatal("somehow *testing.T no longer implements testingT")
+	if v, ok := any(t).(TestingT); !ok || v == nil {
+		t.Fatal("somehow *testing.T no longer implements TestingT")
 	}
 
 	{
 		var testRan, isNil bool
 
-		func(v testingT) {
+		func(v TestingT) {
 			isNil = (v == nil)
 			testRan = true
 		}(t)
@@ -29,7 +35,7 @@ func Test_testingT(t *testing.T) {
 		}
 
 		if isNil {
-			t.Fatal("somehow casting *testing.T to testingT returned nil")
+			t.Fatal("somehow casting *testing.T to TestingT returned nil")
 		}
 	}
 }
@@ -119,7 +125,7 @@ type tcr struct {
 
 type mTCR struct{}
 
-func nilGetT(testingT) *testing.T {
+func nilGetT(TestingT) *testing.T {
 	return nil
 }
 
@@ -150,7 +156,7 @@ func runTC(t *testing.T, tc tc) tcr {
 
 	mt := tc.mt
 
-	var f func(testingT)
+	var f func(TestingT)
 	if tc.tciPlusOne == 0 {
 		f = ((lifecycle[mTC, mTCR])(tc.b)).new(mt)
 	} else if tc.tciPlusOne > 0 {
@@ -438,6 +444,7 @@ func TestLifecycle(t *testing.T) {
 				return given, func(*testing.T) {}
 			},
 			TC: tc{
+				expRunCalls: []string{"tbdd-config-error"},
 				expFatalfCalls: []fatalfCallData{
 					{`when+then not run: BDD test not configured properly (prefix = "%s")`, []any{
 						"",
@@ -467,6 +474,7 @@ func TestLifecycle(t *testing.T) {
 				}
 			},
 			TC: tc{
+				expRunCalls: []string{"tbdd-config-error"},
 				expFatalfCalls: []fatalfCallData{
 					{`test setup not run: Arrange returned a nil given function (prefix = "%s")`, []any{
 						"",
@@ -496,6 +504,7 @@ func TestLifecycle(t *testing.T) {
 				}
 			},
 			TC: tc{
+				expRunCalls: []string{"tbdd-config-error"},
 				expFatalfCalls: []fatalfCallData{
 					{`test setup not run: Arrange function returned an empty Given string (prefix = "%s")`, []any{
 						"",
@@ -525,6 +534,7 @@ func TestLifecycle(t *testing.T) {
 				}
 			},
 			TC: tc{
+				expRunCalls: []string{"tbdd-config-error"},
 				expFatalfCalls: []fatalfCallData{
 					{`test setup not run: Arrange returned a nil given function (prefix = "%s")`, []any{
 						"",
@@ -589,6 +599,112 @@ func TestLifecycle_badVariants(t *testing.T) {
 	}
 }
 
+func TestLifecycle_configErrorSubtests(t *testing.T) {
+	if os.Getenv("TBDD_CONFIG_ERROR_HELPER") == "1" {
+		pass := func(*testing.T, int) {}
+
+		broken := WTN(0, "it acts", pass, "it passes", pass)
+		broken.Act = nil
+
+		for i, b := range []Lifecycle[int, struct{}]{
+			WTN(0, "it acts", pass, "it passes", pass),
+			broken,
+			WTN(0, "it acts", pass, "it passes", pass).With(
+				WithVariants[int, struct{}](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+					return slices.Values([]TestVariant[int]{{}, {Kind: "valid"}})
+				}),
+			),
+		} {
+			b.NewI(t, i)(t)
+		}
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_CONFIG_ERROR_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/0/when_it_acts ",
+		"--- FAIL: " + t.Name() + "/1/tbdd-config-error ",
+		"Act function of BDD test is not defined",
+		"--- PASS: " + t.Name() + "/2/when_it_acts ",
+		"--- FAIL: " + t.Name() + "/2/variant_0/tbdd-config-error ",
+		"test case variant at index 0 has no Kind detail",
+		"--- PASS: " + t.Name() + "/2/valid/when_it_acts ",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func TestLifecycle_variants2(t *testing.T) {
+	t.Parallel()
+
+	errBadRow := errors.New("bad row")
+
+	var configErrs []*ConfigError
+	var ran []string
+	b := Lifecycle[mTC, mTCR]{
+		When: "w",
+		Then: "t",
+		Act: func(*testing.T, mTC) mTCR {
+			return mTCR{}
+		},
+		Assert: func(*testing.T, Assert[mTC, mTCR]) {
+		},
+		hooks: Hooks[mTC, mTCR]{
+			ConfigError: func(_ *testing.T, err *ConfigError) {
+				configErrs = append(configErrs, err)
+			},
+		},
+	}
+
+	mt := &mT{}
+
+	b.getT = nilGetT
+	b.runObserver = func(s string) {
+		ran = append(ran, s)
+	}
+	b.Variants = func(*testing.T, mTC) iter.Seq[TestVariant[mTC]] {
+		return func(yield func(TestVariant[mTC]) bool) {
+			yield(TestVariant[mTC]{Kind: "a"})
+		}
+	}
+	b.Variants2 = func(*testing.T, mTC) iter.Seq2[TestVariant[mTC], error] {
+		return func(yield func(TestVariant[mTC], error) bool) {
+			_ = yield(TestVariant[mTC]{Kind: "b"}, nil) &&
+				yield(TestVariant[mTC]{}, errBadRow) &&
+				yield(TestVariant[mTC]{Kind: "c"}, nil)
+		}
+	}
+
+	f := ((lifecycle[mTC, mTCR])(b)).new(mt)
+	f(mt)
+
+	if exp := "when w,then t,a/when w,then t,b/when w,then t,variant 2/tbdd-config-error,c/when w,then t"; strings.Join(ran, ",") != exp {
+		t.Errorf("expected subtests '%s' but got '%s'", exp, strings.Join(ran, ","))
+	}
+
+	if len(configErrs) != 1 || configErrs[0].Field != "Variants2" || configErrs[0].VariantIndex != 2 || !errors.Is(configErrs[0], errBadRow) {
+		t.Errorf("expected one Variants2 config error for variant 2 but got %v", configErrs)
+	}
+
+	if len(mt.errorCalls) != 1 || mt.errorCalls[0][0] != "tbdd: invalid Variants2 of variant 2: bad row" {
+		t.Errorf("expected one error call for variant 2 but got %v", mt.errorCalls)
+	}
+
+	if len(mt.fatalfCalls) != 0 {
+		t.Errorf("expected no fatalf calls but got %v", mt.fatalfCalls)
+	}
+}
+
 func TestWT(t *testing.T) {
 	type TC struct{}
 	type Result struct{}
@@ -1011,3 +1127,364 @@ func TestGWT(t *testing.T) {
 		}
 	}
 }
+
+func TestTryGWT(t *testing.T) {
+	type TC struct{}
+	type Result struct{}
+
+	givenF := func(*testing.T, *TC) {}
+	whenF := func(*testing.T, TC) Result { return Result{} }
+	thenF := func(*testing.T, TC, Result) {}
+
+	{
+		b, err := TryGWT(
+			TC{},
+			"g", givenF,
+			"w", whenF,
+			"t", thenF,
+		)
+		if err != nil {
+			t.Fatalf("expected nil error but got '%v'", err)
+		}
+
+		if b.Given != "g" || b.When != "w" || b.Then != "t" || b.Arrange == nil || b.Act == nil || b.Assert == nil {
+			t.Error("lifecycle was not fully configured")
+		}
+	}
+
+	type errCase struct {
+		given  string
+		givenF func(*testing.T, *TC)
+		when   string
+		whenF  func(*testing.T, TC) Result
+		then   string
+		thenF  func(*testing.T, TC, Result)
+		expMsg string
+		expErr error
+	}
+
+	for i, v := range []errCase{
+		{"", givenF, "w", whenF, "t", thenF, "tbdd.GWT: given description must be non-empty when given function is non-nil", ErrEmptyGiven},
+		{"g", givenF, "", whenF, "t", thenF, "tbdd.GWT: when description must be non-empty", ErrEmptyWhen},
+		{"g", givenF, "w", nil, "t", thenF, "tbdd.GWT: when function must be non-nil", ErrNilAct},
+		{"g", givenF, "w", whenF, "", thenF, "tbdd.GWT: then description must be non-empty", ErrEmptyThen},
+		{"g", givenF, "w", whenF, "t", nil, "tbdd.GWT: then function must be non-nil", ErrNilAssert},
+	} {
+		_, err := TryGWT(
+			TC{},
+			v.given, v.givenF,
+			v.when, v.whenF,
+			v.then, v.thenF,
+		)
+
+		if err == nil {
+			t.Errorf("case %d: expected an error but got nil", i)
+			continue
+		}
+
+		if err.Error() != v.expMsg {
+			t.Errorf("case %d: expected '%s' but got '%s'", i, v.expMsg, err.Error())
+		}
+
+		if !errors.Is(err, v.expErr) {
+			t.Errorf("case %d: expected error to wrap '%v'", i, v.expErr)
+		}
+	}
+}
+
+func TestTryWT(t *testing.T) {
+	type TC struct{}
+	type Result struct{}
+
+	{
+		b, err := TryWT(
+			TC{},
+			"w", func(*testing.T, TC) Result { return Result{} },
+			"t", func(*testing.T, TC, Result) {},
+		)
+		if err != nil {
+			t.Fatalf("expected nil error but got '%v'", err)
+		}
+
+		if b.Given != "" || b.Arrange != nil {
+			t.Error("expected no given context")
+		}
+	}
+
+	{
+		_, err := TryWT(
+			TC{},
+			"w", nil,
+			"t", func(*testing.T, TC, Result) {},
+		)
+
+		if !errors.Is(err, ErrNilAct) {
+			t.Errorf("expected error to wrap ErrNilAct but got '%v'", err)
+		}
+	}
+}
+
+func Test_nillableT(t *testing.T) {
+	t.Parallel()
+
+	// nil *testing.T values must be tolerated
+	nillableT{nil, nil}.Skip("skip")
+	nillableT{nil, nil}.Parallel()
+	if (nillableT{nil, nil}).Failed() {
+		t.Error("expected a nil *testing.T to never be failed")
+	}
+
+	var skipped bool
+	t.Run("skip", func(t *testing.T) {
+		defer func() {
+			skipped = t.Skipped()
+		}()
+
+		nillableT{t, nil}.Skip("skip")
+	})
+
+	if !skipped {
+		t.Error("expected subtest to be skipped")
+	}
+}
+
+func TestLifecycle_actFatalSkipsThen(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv("TBDD_ACT_FATAL_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"the act fails", func(t *testing.T, _ struct{}) {
+				t.Fatal("act failed")
+			},
+			"it is never asserted", func(*testing.T, struct{}) {},
+		).New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_ACT_FATAL_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_the_act_fails ",
+		"--- SKIP: " + t.Name() + "/when_the_act_fails/then_it_is_never_asserted ",
+		"not run: Act failed",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func TestLifecycle_lazyCloneTC(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.filter = regexp.MustCompile(`^b`)
+
+	type TC struct {
+		name   string
+		cloned bool
+	}
+
+	var cloned []string
+	b := WTN(
+		TC{name: "basis"},
+		"the case runs", func(t *testing.T, tc TC) {
+			if !tc.cloned {
+				t.Errorf("expected Act to receive a cloned TC")
+			}
+		},
+		"it was cloned first", func(*testing.T, TC) {},
+	)
+	b.CloneTC = func(tc TC) TC {
+		cloned = append(cloned, tc.name)
+		tc.cloned = true
+		return tc
+	}
+	b.Variants = func(*testing.T, TC) iter.Seq[TestVariant[TC]] {
+		return func(yield func(TestVariant[TC]) bool) {
+			_ = yield(TestVariant[TC]{Kind: "a", TC: TC{name: "a"}}) &&
+				yield(TestVariant[TC]{Kind: "b", TC: TC{name: "b"}}) &&
+				yield(TestVariant[TC]{Kind: "bare", TC: TC{name: "bare", cloned: true}, SkipCloneTC: true}) &&
+				yield(TestVariant[TC]{Kind: "c", TC: TC{name: "c"}, SkipTC: true})
+		}
+	}
+
+	f := b.New(t)
+	f(t)
+
+	if strings.Join(cloned, ",") != "b" {
+		t.Errorf("expected only the selected variant to be cloned but got %v", cloned)
+	}
+
+	//
+	// skipped scenarios are never cloned, with or without a given phase
+	//
+
+	config.filter = nil
+
+	clone := func(tc TC) TC {
+		cloned = append(cloned, tc.name)
+		return tc
+	}
+	pass := func(*testing.T, TC) {}
+	given := func(*testing.T, *TC) {
+		t.Error("expected the given function not to run")
+	}
+
+	cloned = nil
+	for _, b := range []Lifecycle[TC, struct{}]{
+		WTN(TC{name: "quarantined"}, "it acts", pass, "it passes", pass).With(
+			WithSkipUntil[TC, struct{}](time.Now().Add(time.Hour), "flaky"),
+		),
+		GWTN(TC{name: "quarantined given"}, "a quarantined context", given, "it acts", pass, "it passes", pass).With(
+			WithSkipUntil[TC, struct{}](time.Now().Add(time.Hour), "flaky"),
+		),
+		GWTN(TC{name: "dependent given"}, "a dependent context", given, "it acts", pass, "it passes", pass).With(
+			WithDependsOn[TC, struct{}]("000000000000"),
+		),
+	} {
+		b.CloneTC = clone
+		b.New(t)(t)
+	}
+
+	if len(cloned) != 0 {
+		t.Errorf("expected no skipped scenario to be cloned but got %v", cloned)
+	}
+
+	// an Arrange function of its own may alter the test case, so it is
+	// cloned before Arrange runs
+	Equal(t, arrangesLazily(GWTN(TC{}, "a context", given, "it acts", pass, "it passes", pass).Arrange), true)
+	Equal(t, arrangesLazily(func(*testing.T, Arrange[TC, struct{}]) (string, func(*testing.T)) { return "", nil }), false)
+}
+
+// discardT runs subtests inline without recording anything, so benchmarks
+// measure the lifecycle rather than the mock.
+type discardT struct{}
+
+func (discardT) Helper() {
+}
+
+func (discardT) Run(_ string, f func(*testing.T)) bool {
+	f(nil)
+	return true
+}
+
+func (discardT) Fatalf(string, ...any) {
+}
+
+func (discardT) Error(...any) {
+}
+
+func TestLifecycle_warmupRuns(t *testing.T) {
+	if os.Getenv("TBDD_WARMUP_HELPER") == "1" {
+		var calls int
+		f := WT(
+			0,
+			"the first warmup fails", func(t *testing.T, _ int) int {
+				calls++
+				t.Errorf("warmup failed on call %d", calls)
+				return calls
+			},
+			"the result is asserted", func(*testing.T, int, int) {},
+		).With(WithWarmupRuns[int, int](3)).New(t)
+		f(t)
+		return
+	}
+
+	type counter struct {
+		calls  *int
+		clones int
+	}
+
+	var asserted, clones []int
+	f := WT(
+		counter{calls: new(int)},
+		"the behavior warms up", func(_ *testing.T, tc counter) int {
+			// warmups run within their own bubbles
+			time.Sleep(time.Hour)
+
+			*tc.calls++
+			clones = append(clones, tc.clones)
+			return *tc.calls
+		},
+		"only the last result is asserted", func(_ *testing.T, _ counter, r int) {
+			asserted = append(asserted, r)
+		},
+	).With(
+		WithWarmupRuns[counter, int](2),
+		WithSynctest[counter, int](),
+		WithCloneTC[counter, int](func(tc counter) counter {
+			tc.clones++
+			return tc
+		}),
+	).New(t)
+	f(t)
+
+	if !slices.Equal(asserted, []int{3}) {
+		t.Errorf("expected only the third result to be asserted but got %v", asserted)
+	}
+
+	// the scenario clone is cloned again for each warmup
+	if !slices.Equal(clones, []int{2, 2, 1}) {
+		t.Errorf("expected each warmup to receive its own clone but got %v", clones)
+	}
+
+	//
+	// a failing warmup ends the warmups and fails the scenario
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_WARMUP_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	if !strings.Contains(string(out), "warmup failed on call 1") || !strings.Contains(string(out), "warmup failed on call 2") || strings.Contains(string(out), "warmup failed on call 3") {
+		t.Errorf("expected the first warmup and the measured Act to run:\n%s", out)
+	}
+}
+
+func BenchmarkLifecycle_variants(b *testing.B) {
+	const n = 100_000
+
+	kinds := make([]string, n)
+	for i := range kinds {
+		kinds[i] = "variant " + strconv.Itoa(i)
+	}
+
+	l := WTN(
+		0,
+		"the variant runs", func(*testing.T, int) {},
+		"it passes", func(*testing.T, int) {},
+	)
+	l.getT = nilGetT
+	l.Variants = func(*testing.T, int) iter.Seq[TestVariant[int]] {
+		return func(yield func(TestVariant[int]) bool) {
+			for i, k := range kinds {
+				if !yield(TestVariant[int]{Kind: k, TC: i}) {
+					return
+				}
+			}
+		}
+	}
+
+	f := (lifecycle[int, struct{}])(l).newI(discardT{}, 7)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		f(discardT{})
+	}
+}
@@ -0,0 +1,87 @@
+package tbdd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// link formats the "file:line" location loc as a prefix of a failure
+// message on a line of its own, such as "\n./checkout_test.go:42: ", in the
+// form of compiler errors, so editors and CI annotate that line rather than
+// the line within tbdd which reported the failure. It returns an empty
+// prefix when loc is empty.
+func link(loc string) string {
+	if loc == "" {
+		return ""
+	}
+
+	return "\n" + relativeSource(loc) + ": "
+}
+
+// relativeSource returns loc with its file made relative to the working
+// directory, which go test sets to the directory of the package under test,
+// as "./file.go:line". Files outside of the working directory are kept as
+// they are.
+func relativeSource(loc string) string {
+	i := strings.LastIndexByte(loc, ':')
+	if i < 0 || !filepath.IsAbs(loc[:i]) {
+		return loc
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return loc
+	}
+
+	rel, err := filepath.Rel(wd, loc[:i])
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return loc
+	}
+
+	return "." + string(filepath.Separator) + rel + loc[i:]
+}
+
+// linkedT prefixes every failure reported on T with a link to loc, such as
+// the definition of a misconfigured scenario, or when loc is empty to the
+// code outside of tbdd which reported it, such as the caller of an exported
+// assertion.
+type linkedT struct {
+	*testing.T
+	loc string
+}
+
+// link returns the prefix of the failures reported on t.
+func (t linkedT) link() string {
+	if t.loc != "" {
+		return link(t.loc)
+	}
+
+	return link(definedAt())
+}
+
+func (t linkedT) Error(args ...any) {
+	t.T.Helper()
+
+	t.T.Error(t.link() + fmt.Sprint(args...))
+}
+
+func (t linkedT) Errorf(format string, args ...any) {
+	t.T.Helper()
+
+	t.T.Error(t.link() + fmt.Sprintf(format, args...))
+}
+
+func (t linkedT) Fatal(args ...any) {
+	t.T.Helper()
+
+	t.T.Fatal(t.link() + fmt.Sprint(args...))
+}
+
+func (t linkedT) Fatalf(format string, args ...any) {
+	t.T.Helper()
+
+	t.T.Fatal(t.link() + fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,72 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLinkedT(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+
+	if os.Getenv("TBDD_LINK_HELPER") == "1" {
+		WTN(0, "it is checked", func(*testing.T, int) {}, "it is linked", func(t *testing.T, tc int) {
+			Expect(t, tc).Equal(1)
+		}).New(t)(t)
+		Lifecycle[int, int]{When: "it is misconfigured", Then: "it is linked"}.New(t)(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_LINK_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	// go test still names the call site of the assertion, which is followed
+	// by links to it and to the definition of the scenario, as configuration
+	// failures are
+	base := filepath.Base(file)
+	for _, exp := range []string{
+		"    " + base + ":" + strconv.Itoa(line+4) + ": \n",
+		"        ./" + base + ":" + strconv.Itoa(line+4) + ": expected value to equal 1 but got 0\n",
+		"        ./" + base + ":" + strconv.Itoa(line+3) + ": tbdd: the failed scenario is defined here\n",
+		"        ./" + base + ":" + strconv.Itoa(line+6) + ": Act function of BDD test is not defined\n",
+		"        ./" + base + ":" + strconv.Itoa(line+6) + ": when+then not run: BDD test not configured properly",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain %q:\n%s", exp, out)
+		}
+	}
+}
+
+func Test_link(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sep := string(filepath.Separator)
+	for _, tc := range []struct {
+		loc, exp string
+	}{
+		{"", ""},
+		{filepath.Join(wd, "a_test.go") + ":7", "\n." + sep + "a_test.go:7: "},
+		{filepath.Join(wd, "sub", "b_test.go") + ":8", "\n." + sep + filepath.Join("sub", "b_test.go") + ":8: "},
+		{filepath.Join(filepath.Dir(wd), "c_test.go") + ":9", "\n" + filepath.Join(filepath.Dir(wd), "c_test.go") + ":9: "},
+		{"billing.feature:3", "\nbilling.feature:3: "},
+		{"unknown", "\nunknown: "},
+	} {
+		if s := link(tc.loc); s != tc.exp {
+			t.Errorf("expected the link %q of '%s' but got %q", tc.exp, tc.loc, s)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Localization is the locale and time zone of a variant yielded by
+// LocaleVariants.
+type Localization struct {
+	// Locale is a BCP 47 language tag such as "de-DE", or empty when the
+	// variant only varies the time zone.
+	Locale string
+	// Location is the time zone, or nil when the variant only varies the
+	// locale.
+	Location *time.Location
+}
+
+// Setenv sets the LANG and LC_ALL environment variables to the POSIX form of
+// the Locale, such as "de_DE.UTF-8", and TZ to the name of the Location for
+// the rest of the test via t.Setenv. Variables of an empty Locale or a nil
+// Location are left unchanged.
+//
+// Like t.Setenv it cannot be used by parallel tests. Setenv is meant for
+// given phases of behaviors which read their localization from the
+// environment rather than their test case.
+func (l Localization) Setenv(t *testing.T) {
+	t.Helper()
+
+	if l.Locale != "" {
+		lang := posixLocale(l.Locale)
+		t.Setenv("LANG", lang)
+		t.Setenv("LC_ALL", lang)
+	}
+
+	if l.Location != nil {
+		t.Setenv("TZ", l.Location.String())
+	}
+}
+
+// posixLocale converts a BCP 47 language tag to a POSIX locale name.
+func posixLocale(tag string) string {
+	if tag == "C" || tag == "POSIX" {
+		return tag
+	}
+
+	return strings.ReplaceAll(tag, "-", "_") + ".UTF-8"
+}
+
+// LocaleVariants returns a Variants2 function yielding a variant of the
+// basis test case for every combination of the locales and time zones,
+// named by the IANA time zone database, such as "Europe/Berlin". set installs
+// the Localization of each variant on a copy of the basis test case, before
+// it is cloned with CloneTC, and Kinds name both, such as
+// "locale de-DE, time zone Europe-Berlin". The slashes of time zone names
+// are replaced by dashes in Kinds, as t.Run would otherwise nest the
+// variants in subtests named after each part.
+//
+// When either list is empty the variants only vary the other. A time zone
+// which cannot be loaded, such as when the system has no time zone database
+// and time/tzdata is not imported, is yielded as an error and skipped.
+//
+// LocaleVariants panics if set is nil, both lists are empty, or a locale or
+// time zone is empty.
+func LocaleVariants[T any](locales, zones []string, set func(*T, Localization)) func(*testing.T, T) iter.Seq2[TestVariant[T], error] {
+	if set == nil {
+		panic("tbdd.LocaleVariants: set function must be non-nil")
+	}
+
+	if len(locales) == 0 && len(zones) == 0 {
+		panic("tbdd.LocaleVariants: at least one locale or time zone is required")
+	}
+
+	for _, s := range append(append([]string(nil), locales...), zones...) {
+		if s == "" {
+			panic("tbdd.LocaleVariants: locales and time zones must be non-empty")
+		}
+	}
+
+	if len(locales) == 0 {
+		locales = []string{""}
+	}
+
+	if len(zones) == 0 {
+		zones = []string{""}
+	}
+
+	return func(_ *testing.T, basis T) iter.Seq2[TestVariant[T], error] {
+		return func(yield func(TestVariant[T], error) bool) {
+			for _, zone := range zones {
+				var loc *time.Location
+				if zone != "" {
+					var err error
+					if loc, err = time.LoadLocation(zone); err != nil {
+						if !yield(TestVariant[T]{}, errors.New("tbdd: failed to load time zone "+zone+": "+err.Error())) {
+							return
+						}
+						continue
+					}
+				}
+
+				for _, locale := range locales {
+					tc := basis
+					set(&tc, Localization{locale, loc})
+
+					var kind []string
+					if locale != "" {
+						kind = append(kind, "locale "+locale)
+					}
+					if zone != "" {
+						kind = append(kind, "time zone "+strings.ReplaceAll(zone, "/", "-"))
+					}
+
+					if !yield(TestVariant[T]{Kind: strings.Join(kind, ", "), TC: tc}, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tbdd
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLocaleVariants(t *testing.T) {
+	type TC struct {
+		l Localization
+	}
+
+	set := func(tc *TC, l Localization) {
+		tc.l = l
+	}
+
+	// each variant is a single subtest, although time zones hold slashes
+	parent := t.Name()
+
+	var got []string
+	f := GWTN(
+		TC{},
+		"a localization", func(t *testing.T, tc *TC) {
+			tc.l.Setenv(t)
+		},
+		"the date is formatted", func(t *testing.T, tc TC) {
+			got = append(got, strings.TrimPrefix(t.Name(), parent+"/")+": "+os.Getenv("LC_ALL")+" "+os.Getenv("TZ"))
+		},
+		"it is localized", func(*testing.T, TC) {},
+	).With(WithVariants2[TC, struct{}](LocaleVariants([]string{"de-DE", "C"}, []string{"UTC", "Asia/Tokyo"}, set))).New(t)
+	f(t)
+
+	lcAll, tz := os.Getenv("LC_ALL"), os.Getenv("TZ")
+	exp := []string{
+		"given_a_localization/when_the_date_is_formatted: " + lcAll + " " + tz,
+		"locale_de-DE,_time_zone_UTC/given_a_localization/when_the_date_is_formatted: de_DE.UTF-8 UTC",
+		"locale_C,_time_zone_UTC/given_a_localization/when_the_date_is_formatted: C UTC",
+		"locale_de-DE,_time_zone_Asia-Tokyo/given_a_localization/when_the_date_is_formatted: de_DE.UTF-8 Asia/Tokyo",
+		"locale_C,_time_zone_Asia-Tokyo/given_a_localization/when_the_date_is_formatted: C Asia/Tokyo",
+	}
+	if !slices.Equal(got, exp) {
+		t.Errorf("expected:\n%s\nbut got:\n%s", strings.Join(exp, "\n"), strings.Join(got, "\n"))
+	}
+
+	var kinds, errs []string
+	for v, err := range LocaleVariants(nil, []string{"Nowhere/Land", "UTC"}, set)(t, TC{}) {
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		kinds = append(kinds, v.Kind)
+		if v.TC.l.Location.String() != "UTC" || v.TC.l.Locale != "" {
+			t.Errorf("unexpected localization: %+v", v.TC.l)
+		}
+	}
+
+	if !slices.Equal(kinds, []string{"time zone UTC"}) || len(errs) != 1 || !strings.HasPrefix(errs[0], "tbdd: failed to load time zone Nowhere/Land: ") {
+		t.Errorf("unexpected kinds %q and errors %q", kinds, errs)
+	}
+
+	for v := range LocaleVariants([]string{"fr-FR", "ja-JP"}, nil, set)(t, TC{}) {
+		if v.Kind != "locale fr-FR" || v.TC.l.Location != nil {
+			t.Errorf("unexpected variant: %+v", v)
+		}
+		break
+	}
+
+	for range LocaleVariants(nil, []string{"Nowhere/Land", "UTC"}, set)(t, TC{}) {
+		break
+	}
+}
+
+func TestLocaleVariants_panics(t *testing.T) {
+	set := func(*int, Localization) {}
+
+	for _, tc := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { LocaleVariants[int]([]string{"de-DE"}, nil, nil) }, "tbdd.LocaleVariants: set function must be non-nil"},
+		{func() { LocaleVariants(nil, nil, set) }, "tbdd.LocaleVariants: at least one locale or time zone is required"},
+		{func() { LocaleVariants([]string{"de-DE"}, []string{""}, set) }, "tbdd.LocaleVariants: locales and time zones must be non-empty"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			tc.f()
+		}()
+	}
+}
@@ -0,0 +1,424 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Report is the end of run summary handed to every Reporter by Main.
+type Report struct {
+	// Seed is the effective value of Seed for the run.
+	Seed    int64
+	Summary Summary
+	Results []ScenarioResult
+	// Filtered lists the scenarios excluded by -tbdd.filter, each as the name
+	// of its test and its Scenario sentence, such as
+	// "TestCart: when it is empty then it costs nothing".
+	Filtered []string `json:",omitempty"`
+	Warnings []Warning
+	// Updated lists the files rewritten because of the update flags.
+	Updated []Update
+}
+
+// Reporter exports a Report once all tests of a package have run.
+type Reporter interface {
+	Report(Report) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(Report) error
+
+func (f ReporterFunc) Report(r Report) error {
+	return f(r)
+}
+
+// JSONReporter returns a Reporter which writes the Report as indented JSON
+// to the file at path, replacing any existing content.
+func JSONReporter(path string) Reporter {
+	return ReporterFunc(func(r Report) error {
+		// a Report holds no values which can fail to encode
+		b, _ := json.MarshalIndent(r, "", "  ")
+
+		return os.WriteFile(path, append(b, '\n'), 0o644)
+	})
+}
+
+// settings holds the run configuration established by Main.
+//
+// It is written before any test starts and only read afterwards.
+type settings struct {
+	filter       *regexp.Regexp
+	seed         int64
+	report       string
+	updateGolden bool
+	artifacts    string
+	// duplicates is the -tbdd.duplicates mode, one of the duplicates
+	// constants.
+	duplicates string
+	budget     time.Duration
+	shard      Shard
+	// minPriority is the -tbdd.min-priority of the run, zero when every
+	// scenario runs.
+	minPriority  Priority
+	impactRecord string
+	// impact selects the scenarios impacted by the changes named by the
+	// -tbdd.impact and -tbdd.changed flags, or is nil when every scenario
+	// runs.
+	impact *impactSelection
+	// deadline is when the budget of the run is spent, or zero when it has
+	// none; it is set once the tests start.
+	deadline time.Time
+}
+
+var config = settings{seed: time.Now().UnixNano()}
+
+// updateGoldenEnv is the environment variable which sets the default of the
+// -tbdd.update-golden flag.
+const updateGoldenEnv = "TBDD_UPDATE_GOLDEN"
+
+// Seed returns the value of the -tbdd.seed flag, or a seed chosen at random
+// when the process started if the flag is unset or zero.
+//
+// Features which need randomness should derive it from this value so that a
+// failing run can be reproduced by passing the same seed. Once Seed has been
+// called every failing scenario logs the seed along with a go test command
+// reproducing it, which is also recorded as its ScenarioResult.Reproduce.
+func Seed() int64 {
+	seedUsed.Store(true)
+
+	return config.seed
+}
+
+// UpdateGolden reports whether the -tbdd.update-golden flag, or the
+// TBDD_UPDATE_GOLDEN environment variable, was set, in which case golden,
+// examples, snapshot, and baseline files should be rewritten rather than
+// compared against. Features which rewrite a file should log it and call
+// RecordUpdate.
+func UpdateGolden() bool {
+	return config.updateGolden
+}
+
+// Main registers the tbdd flags, runs the tests of m, then prints a summary of
+// the recorded scenario results to stderr and hands a Report to each reporter.
+// It returns the exit code for the process.
+//
+// It is meant to be called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(tbdd.Main(m))
+//	}
+//
+// The flags are:
+//
+//	-tbdd.filter regexp
+//		Only run scenarios whose Scenario sentence matches regexp. The
+//		scenarios it excludes are counted after the summary and listed in
+//		the Report.
+//	-tbdd.seed n
+//		Use n as the value returned by Seed.
+//	-tbdd.report file
+//		Write a JSON Report to file.
+//	-tbdd.update-golden
+//		Rewrite golden, examples, snapshot, and baseline files instead of
+//		comparing against them. Its default is read from the
+//		TBDD_UPDATE_GOLDEN environment variable.
+//	-tbdd.artifacts dir
+//		Create scenario artifact directories below dir; see Artifacts.
+//	-tbdd.update-baseline
+//		Deprecated: an alias of -tbdd.update-golden, which rewrites
+//		performance baselines along with the other files.
+//	-tbdd.duplicates mode
+//		Handle scenarios with the same sentence as a scenario defined
+//		elsewhere as mode selects: "warn", the default, records a Warning,
+//		"fail" fails the later scenario, and "off" ignores them.
+//	-tbdd.budget duration
+//		Skip the scenarios which have not started once duration has passed
+//		since the tests started, reporting them as not run due to budget,
+//		so the run ends in predictable time. Scenarios already running
+//		finish. Only the members of a Group are reordered, by descending
+//		Priority; tests, and the scenarios of each Lifecycle, run in the
+//		order they are declared.
+//	-tbdd.min-priority level
+//		Only run scenarios whose Priority is at least level: "low",
+//		"normal", "high", or "critical".
+//	-tbdd.impact-record file
+//		Map every scenario run to the packages the run covered, read from
+//		the profile of -coverprofile, in the JSON impact map at file,
+//		keeping the scenarios it already maps. Running one test per
+//		process, such as with -run, maps each scenario to the packages of
+//		its own test.
+//	-tbdd.impact file
+//		Only run the scenarios which the impact map at file records as
+//		exercising a package of -tbdd.changed, or a module whose version
+//		differs from the one the map was recorded with, along with the
+//		scenarios the map does not know of.
+//	-tbdd.changed list
+//		The comma separated import paths of the packages changed for
+//		-tbdd.impact, where a path ending in /... includes every package
+//		below it.
+//	-tbdd.shard index/total
+//		Only run the scenarios of one of total shards, such as 0/4, so CI
+//		can split the scenarios across machines; see Shard. Its default is
+//		read from the TBDD_SHARD environment variable.
+//
+// Every file rewritten because of an update flag is listed after the summary.
+// A failing reporter fails the run.
+func Main(m *testing.M, reporters ...Reporter) int {
+	return runMain(m, flag.CommandLine, os.Args[1:], os.Stderr, reporters)
+}
+
+// runner is the subset of *testing.M that Main depends on.
+type runner interface {
+	Run() int
+}
+
+func runMain(m runner, fs *flag.FlagSet, args []string, w io.Writer, reporters []Reporter) int {
+	s, err := parseFlags(fs, args, config.seed)
+	if err != nil {
+		fmt.Fprintln(w, "tbdd:", err)
+		return 2
+	}
+
+	if s.budget > 0 {
+		s.deadline = time.Now().Add(s.budget)
+	}
+
+	config = s
+
+	prev := swapRecords(records{})
+	defer swapRecords(prev)
+
+	code := m.Run()
+
+	rs := Results()
+	r := Report{
+		Seed:     s.seed,
+		Summary:  Summarize(rs),
+		Results:  rs,
+		Filtered: filteredOut(),
+		Warnings: Warnings(),
+		Updated:  Updates(),
+	}
+
+	if r.Summary.Total > 0 {
+		fmt.Fprintln(w, "tbdd:", r.Summary)
+	}
+
+	if n := len(r.Filtered); n > 0 {
+		fmt.Fprintf(w, "tbdd: %d scenarios excluded by -tbdd.filter %s\n", n, s.filter)
+	}
+
+	if n := overBudget(rs); n > 0 {
+		fmt.Fprintf(w, "tbdd: %d scenarios not run due to the -tbdd.budget of %s\n", n, s.budget)
+	}
+
+	if r.Summary.Failed > 0 && seedUsed.Load() {
+		fmt.Fprintf(w, "tbdd: randomized with seed %d; rerun with -tbdd.seed=%d to reproduce the failures\n", s.seed, s.seed)
+	}
+
+	if s.impactRecord != "" {
+		if err := recordImpact(s.impactRecord, coverProfile(fs), rs); err != nil {
+			fmt.Fprintln(w, "tbdd: recording the impact map failed:", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+
+	if len(r.Updated) > 0 {
+		noun := "files"
+		if len(r.Updated) == 1 {
+			noun = "file"
+		}
+
+		fmt.Fprintf(w, "tbdd: updated %d %s:\n", len(r.Updated), noun)
+		for _, u := range r.Updated {
+			fmt.Fprintf(w, "\t%s %s\n", u.Kind, u.Path)
+		}
+	}
+
+	if s.report != "" {
+		reporters = append(reporters, JSONReporter(s.report))
+	}
+
+	for _, rep := range reporters {
+		if err := rep.Report(r); err != nil {
+			fmt.Fprintln(w, "tbdd: reporter failed:", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+
+	return code
+}
+
+// records is the state of the registries of the process which a run of
+// runMain reports.
+type records struct {
+	results   []ScenarioResult
+	filtered  []string
+	warnings  []Warning
+	updates   []Update
+	sentences map[string]occurrence
+}
+
+// swapRecords replaces the state of the registries with r, returning the
+// state it replaced, so a run reports only what its own tests recorded and
+// the process can carry on with the records it had once the run ends.
+func swapRecords(r records) records {
+	var prev records
+
+	results.mu.Lock()
+	prev.results, prev.filtered = results.list, results.filtered
+	results.list, results.filtered = r.results, r.filtered
+	results.mu.Unlock()
+
+	warnings.mu.Lock()
+	prev.warnings, warnings.list = warnings.list, r.warnings
+	warnings.mu.Unlock()
+
+	updates.mu.Lock()
+	prev.updates, updates.list = updates.list, r.updates
+	updates.mu.Unlock()
+
+	sentences.mu.Lock()
+	prev.sentences, sentences.m = sentences.m, r.sentences
+	sentences.mu.Unlock()
+
+	return prev
+}
+
+func parseFlags(fs *flag.FlagSet, args []string, defaultSeed int64) (settings, error) {
+	var s settings
+	var filter, minPriority, impact, changed string
+
+	var updateGolden bool
+	if v := os.Getenv(updateGoldenEnv); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return settings{}, errors.New("invalid " + updateGoldenEnv + ": " + err.Error())
+		}
+
+		updateGolden = b
+	}
+
+	shard := os.Getenv(shardEnv)
+	if shard != "" {
+		if _, err := ParseShard(shard); err != nil {
+			return settings{}, errors.New("invalid " + shardEnv + ": " + err.Error())
+		}
+	}
+
+	fs.StringVar(&filter, "tbdd.filter", "", "only run tbdd scenarios whose sentence matches `regexp`")
+	fs.Int64Var(&s.seed, "tbdd.seed", 0, "seed for randomized tbdd features; zero picks one at random")
+	fs.StringVar(&s.report, "tbdd.report", "", "write a JSON report of tbdd scenario results to `file`")
+	fs.BoolVar(&s.updateGolden, "tbdd.update-golden", updateGolden, "rewrite golden, examples, snapshot, and baseline files instead of comparing against them")
+	fs.StringVar(&s.artifacts, "tbdd.artifacts", "", "create scenario artifact directories below `dir` instead of temporary directories")
+	fs.BoolVar(&s.updateGolden, "tbdd.update-baseline", updateGolden, "deprecated: use -tbdd.update-golden, which this is an alias of")
+	fs.StringVar(&s.duplicates, "tbdd.duplicates", duplicatesWarn, "handle duplicated tbdd scenarios as `mode` selects: warn, fail, or off")
+	fs.StringVar(&minPriority, "tbdd.min-priority", "", "only run tbdd scenarios whose priority is at least `level`: low, normal, high, or critical")
+	fs.StringVar(&s.impactRecord, "tbdd.impact-record", "", "map the tbdd scenarios run to the packages covered by -coverprofile in `file`")
+	fs.StringVar(&impact, "tbdd.impact", "", "only run the tbdd scenarios the impact map `file` records as exercising a changed package")
+	fs.StringVar(&changed, "tbdd.changed", "", "comma separated import paths of the packages changed for -tbdd.impact")
+	fs.StringVar(&shard, "tbdd.shard", shard, "only run the tbdd scenarios of the shard `index/total`, such as 0/4")
+	fs.DurationVar(&s.budget, "tbdd.budget", 0, "skip tbdd scenarios not started within `duration` of the tests starting")
+
+	if err := fs.Parse(args); err != nil {
+		return settings{}, err
+	}
+
+	if s.seed == 0 {
+		s.seed = defaultSeed
+	}
+
+	switch s.duplicates {
+	case duplicatesWarn, duplicatesFail, duplicatesOff:
+	default:
+		return settings{}, errors.New("invalid -tbdd.duplicates: " + strconv.Quote(s.duplicates) + " is not warn, fail, or off")
+	}
+
+	if minPriority != "" {
+		p, err := parsePriority(minPriority)
+		if err != nil {
+			return settings{}, errors.New("invalid -tbdd.min-priority: " + err.Error())
+		}
+
+		s.minPriority = p
+	}
+
+	if s.impactRecord != "" && coverProfile(fs) == "" {
+		return settings{}, errors.New("invalid -tbdd.impact-record: it requires -coverprofile")
+	}
+
+	var pkgs []string
+	for pkg := range strings.SplitSeq(changed, ",") {
+		if pkg = strings.TrimSpace(pkg); pkg != "" {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	switch {
+	case impact != "":
+		sel, err := loadImpact(impact, pkgs)
+		if err != nil {
+			return settings{}, errors.New("invalid -tbdd.impact: " + err.Error())
+		}
+
+		s.impact = sel
+	case len(pkgs) > 0:
+		return settings{}, errors.New("invalid -tbdd.changed: it requires -tbdd.impact")
+	}
+
+	if shard != "" {
+		sh, err := ParseShard(shard)
+		if err != nil {
+			return settings{}, errors.New("invalid -tbdd.shard: " + err.Error())
+		}
+
+		s.shard = sh
+	}
+
+	if s.budget < 0 {
+		return settings{}, errors.New("invalid -tbdd.budget: " + s.budget.String() + " is negative")
+	}
+
+	if filter != "" {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return settings{}, errors.New("invalid -tbdd.filter: " + err.Error())
+		}
+
+		s.filter = re
+	}
+
+	return s, nil
+}
+
+// selectedByID reports whether the scenario described by r, run below the
+// test named parent with the given subtest prefix, belongs to the
+// -tbdd.shard of the run and is selected by its -tbdd.impact and
+// -tbdd.changed flags.
+func selectedByID(parent, prefix string, r *ScenarioResult) bool {
+	if config.shard.Total <= 1 && config.impact == nil {
+		return true
+	}
+
+	id := resultID(parent, prefix, r)
+
+	return config.shard.Contains(id) && config.impact.selects(id)
+}
+
+// selected reports whether a scenario passes the -tbdd.filter flag.
+func selected(r ScenarioResult) bool {
+	return config.filter == nil || config.filter.MatchString(r.Scenario())
+}
@@ -0,0 +1,229 @@
+package tbdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(Main(m))
+}
+
+var _ runner = (*testing.M)(nil)
+
+type mRunner int
+
+func (m mRunner) Run() int {
+	return int(m)
+}
+
+// runFunc runs the tests of runMain by calling itself.
+type runFunc func() int
+
+func (f runFunc) Run() int {
+	return f()
+}
+
+func Test_runMain(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	report := filepath.Join(t.TempDir(), "report.json")
+	updated := filepath.Join(t.TempDir(), "updated.golden")
+
+	var reported []Report
+	var buf bytes.Buffer
+	code := runMain(
+		runFunc(func() int {
+			t.Run("tests", func(t *testing.T) {
+				WTN(0, "the cart is checked out", func(*testing.T, int) {}, "it is paid", func(*testing.T, int) {}).New(t)(t)
+				WTN(0, "the cart is emptied", func(*testing.T, int) {}, "it is empty", func(*testing.T, int) {}).New(t)(t)
+			})
+			RecordUpdate("golden", updated)
+
+			return 0
+		}),
+		flag.NewFlagSet("test", flag.ContinueOnError),
+		[]string{"-tbdd.filter=checked out", "-tbdd.seed=7", "-tbdd.update-golden", "-tbdd.report=" + report},
+		&buf,
+		[]Reporter{
+			ReporterFunc(func(r Report) error {
+				reported = append(reported, r)
+				return nil
+			}),
+			ReporterFunc(func(Report) error {
+				return errors.New("boom")
+			}),
+		},
+	)
+
+	if code != 1 {
+		t.Errorf("expected a failing reporter to fail the run but got exit code %d", code)
+	}
+
+	if Seed() != 7 || !UpdateGolden() || config.filter.String() != "checked out" {
+		t.Errorf("unexpected settings: %+v", config)
+	}
+
+	// the results of the tests which ran before are not reported
+	filtered := []string{t.Name() + "/tests: when the cart is emptied then it is empty"}
+	if len(reported) != 1 || reported[0].Seed != 7 || reported[0].Summary.Total != 1 || len(reported[0].Results) != 1 ||
+		!slices.Equal(reported[0].Filtered, filtered) || !slices.Equal(reported[0].Updated, []Update{{"golden", updated}}) {
+		t.Errorf("unexpected reports: %+v", reported)
+	}
+
+	exp := "tbdd: 1 scenarios: 1 passed, 0 failed, 0 skipped\n" +
+		"tbdd: 1 scenarios excluded by -tbdd.filter checked out\n" +
+		"tbdd: updated 1 file:\n" +
+		"\tgolden " + updated + "\n" +
+		"tbdd: reporter failed: boom\n"
+	if s := buf.String(); s != exp {
+		t.Errorf("unexpected output: %q", s)
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Seed    int64
+		Results []struct{ Status string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil || r.Seed != 7 || len(r.Results) == 0 || r.Results[0].Status == "" {
+		t.Errorf("unexpected JSON report (%v): %s", err, b)
+	}
+
+	//
+	// validate defaults and flag errors
+	//
+
+	config = orig
+
+	if code := runMain(mRunner(3), flag.NewFlagSet("test", flag.ContinueOnError), nil, io.Discard, nil); code != 3 || Seed() != orig.seed || UpdateGolden() || config.filter != nil {
+		t.Errorf("unexpected exit code %d or settings %+v", code, config)
+	}
+
+	// the deprecated -tbdd.update-baseline is an alias of -tbdd.update-golden
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), []string{"-tbdd.update-baseline"}, io.Discard, nil); code != 0 || !UpdateGolden() {
+		t.Errorf("expected -tbdd.update-baseline to update every file but got exit code %d and settings %+v", code, config)
+	}
+
+	t.Setenv(updateGoldenEnv, "true")
+
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), nil, io.Discard, nil); code != 0 || !UpdateGolden() {
+		t.Errorf("expected %s to update golden files and baselines but got exit code %d and settings %+v", updateGoldenEnv, code, config)
+	}
+
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), []string{"-tbdd.update-golden=false"}, io.Discard, nil); code != 0 || UpdateGolden() {
+		t.Errorf("expected the flag to override %s but got exit code %d and settings %+v", updateGoldenEnv, code, config)
+	}
+
+	t.Setenv(updateGoldenEnv, "x")
+
+	buf.Reset()
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), nil, &buf, nil); code != 2 || !strings.HasPrefix(buf.String(), "tbdd: invalid "+updateGoldenEnv+": ") {
+		t.Errorf("expected an invalid %s to fail but got exit code %d and output %q", updateGoldenEnv, code, buf.String())
+	}
+
+	t.Setenv(updateGoldenEnv, "")
+
+	t.Setenv(shardEnv, "1/3")
+
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), nil, io.Discard, nil); code != 0 || config.shard != (Shard{1, 3}) {
+		t.Errorf("expected %s to set the shard but got exit code %d and settings %+v", shardEnv, code, config)
+	}
+
+	t.Setenv(shardEnv, "x")
+
+	buf.Reset()
+	if code := runMain(mRunner(0), flag.NewFlagSet("test", flag.ContinueOnError), nil, &buf, nil); code != 2 || !strings.HasPrefix(buf.String(), "tbdd: invalid "+shardEnv+": ") {
+		t.Errorf("expected an invalid %s to fail but got exit code %d and output %q", shardEnv, code, buf.String())
+	}
+
+	t.Setenv(shardEnv, "")
+
+	for _, v := range []struct {
+		args []string
+		exp  string
+	}{
+		{[]string{"-tbdd.filter=("}, "tbdd: invalid -tbdd.filter: "},
+		{[]string{"-tbdd.seed=x"}, "tbdd: invalid value"},
+		{[]string{"-tbdd.duplicates=x"}, `tbdd: invalid -tbdd.duplicates: "x" is not warn, fail, or off`},
+		{[]string{"-tbdd.budget=-1s"}, "tbdd: invalid -tbdd.budget: -1s is negative"},
+		{[]string{"-tbdd.shard=4/4"}, `tbdd: invalid -tbdd.shard: "4/4" is not a shard: `},
+		{[]string{"-tbdd.min-priority=urgent"}, `tbdd: invalid -tbdd.min-priority: "urgent" is not low, normal, high, or critical`},
+		{[]string{"-tbdd.impact-record=impact.json"}, "tbdd: invalid -tbdd.impact-record: it requires -coverprofile"},
+		{[]string{"-tbdd.changed=example.com/m/a"}, "tbdd: invalid -tbdd.changed: it requires -tbdd.impact"},
+		{[]string{"-tbdd.impact=" + filepath.Join(t.TempDir(), "missing.json")}, "tbdd: invalid -tbdd.impact: "},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+
+		buf.Reset()
+		if code := runMain(mRunner(0), fs, v.args, &buf, nil); code != 2 || !strings.HasPrefix(buf.String(), v.exp) {
+			t.Errorf("expected exit code 2 and output prefix '%s' but got %d and %q", v.exp, code, buf.String())
+		}
+	}
+
+	if err := JSONReporter(filepath.Join(t.TempDir(), "missing", "report.json")).Report(Report{}); err == nil {
+		t.Error("expected an error writing to a missing directory")
+	}
+
+	if err := JSONReporter(report).Report(Report{Results: []ScenarioResult{{Status: Status(9)}}}); err != nil {
+		t.Errorf("expected unknown statuses to encode but got: %v", err)
+	}
+}
+
+func TestMain_filter(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.filter = regexp.MustCompile(`^admin: given a role .* then access is granted$`)
+
+	var ran []string
+	b := GWT(
+		"member",
+		"a role", func(*testing.T, *string) {},
+		"they open the console", func(_ *testing.T, tc string) string {
+			ran = append(ran, tc)
+			return tc
+		},
+		"access is granted", func(*testing.T, string, string) {},
+	)
+	b.Variants = func(*testing.T, string) iter.Seq[TestVariant[string]] {
+		return func(yield func(TestVariant[string]) bool) {
+			yield(TestVariant[string]{Kind: "admin", TC: "admin"})
+		}
+	}
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	f := b.New(t)
+	f(t)
+
+	if strings.Join(ran, ",") != "admin" {
+		t.Errorf("expected only the admin variant to run but got %v", ran)
+	}
+
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") && r.Kind != "admin" {
+			t.Errorf("expected filtered scenarios to not be recorded but got %+v", r)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package tbdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaskPlaceholder replaces masked values when a Mask has no
+// Placeholder.
+const DefaultMaskPlaceholder = "<masked>"
+
+// Mask replaces a volatile value, such as a generated ID or a timestamp,
+// with a placeholder so that golden comparisons and persisted artifacts do
+// not change from run to run.
+//
+// Path is either a JSON path or a struct path:
+//
+//   - JSON paths start with "$" followed by ".name", `["name"]`, "[index]",
+//     ".*", or "[*]" selectors, such as "$.items[*].id". A "*" selects every
+//     member of an object or element of an array.
+//   - Struct paths use the syntax of Expectation.Field, with "[*]" also
+//     selecting every element, such as "Items[*].CreatedAt". They are
+//     translated into JSON paths using the encoding/json field names of the
+//     masked value's type, so they can only mask Go values.
+//
+// Paths which select nothing are ignored, so a mask may be shared by
+// values which do not all have the masked field.
+type Mask struct {
+	Path string
+	// Placeholder is the JSON string the value is replaced with; it defaults
+	// to DefaultMaskPlaceholder.
+	Placeholder string
+}
+
+// MaskJSON returns the JSON document b indented with the masks applied.
+// Only JSON path masks can be applied to documents.
+func MaskJSON(b []byte, masks ...Mask) ([]byte, error) {
+	return maskJSON(b, nil, masks)
+}
+
+// MaskValue encodes v as indented JSON with the masks applied.
+func MaskValue(v any, masks ...Mask) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return maskJSON(b, reflect.TypeOf(v), masks)
+}
+
+// maskJSON applies masks to the JSON document b, translating struct paths
+// with the type t when it is non-nil.
+func maskJSON(b []byte, t reflect.Type, masks []Mask) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var doc any
+	if err := d.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	for _, m := range masks {
+		path := m.Path
+		if !strings.HasPrefix(path, "$") {
+			if t == nil {
+				return nil, errors.New("tbdd: mask " + path + ": struct paths can only mask Go values")
+			}
+
+			var err error
+			if path, err = structMaskPath(t, path); err != nil {
+				return nil, errors.New("tbdd: mask " + m.Path + ": " + err.Error())
+			}
+		}
+
+		segs, err := parseMaskPath(path)
+		if err != nil {
+			return nil, errors.New("tbdd: mask " + m.Path + ": " + err.Error())
+		}
+
+		placeholder := m.Placeholder
+		if placeholder == "" {
+			placeholder = DefaultMaskPlaceholder
+		}
+
+		doc = applyMask(doc, segs, placeholder)
+	}
+
+	var out bytes.Buffer
+	e := json.NewEncoder(&out)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	// decoded documents always encode
+	_ = e.Encode(doc)
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// maskSegment selects an object member by name, an array element by index,
+// or every member or element when wildcard is set.
+type maskSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseMaskPath parses a JSON path, which callers have checked starts with "$".
+func parseMaskPath(path string) ([]maskSegment, error) {
+	rest := path[1:]
+
+	var segs []maskSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+
+			name := rest[1 : end+1]
+			switch name {
+			case "":
+				return nil, errors.New("empty member name in path")
+			case "*":
+				segs = append(segs, maskSegment{wildcard: true})
+			default:
+				segs = append(segs, maskSegment{name: name})
+			}
+
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.New("unterminated selector in path")
+			}
+
+			sel := rest[1:end]
+			if sel == "*" {
+				segs = append(segs, maskSegment{wildcard: true})
+			} else if name, err := strconv.Unquote(sel); err == nil {
+				segs = append(segs, maskSegment{name: name})
+			} else if i, err := strconv.Atoi(sel); err == nil {
+				segs = append(segs, maskSegment{index: i, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("invalid selector [%s] in path", sel)
+			}
+
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in path", rest[0])
+		}
+	}
+
+	return segs, nil
+}
+
+// applyMask returns v with every value selected by segs replaced with
+// placeholder.
+func applyMask(v any, segs []maskSegment, placeholder string) any {
+	if len(segs) == 0 {
+		return placeholder
+	}
+
+	seg, rest := segs[0], segs[1:]
+
+	switch v := v.(type) {
+	case map[string]any:
+		if seg.wildcard {
+			for k, e := range v {
+				v[k] = applyMask(e, rest, placeholder)
+			}
+		} else if e, ok := v[seg.name]; ok && !seg.isIndex {
+			v[seg.name] = applyMask(e, rest, placeholder)
+		}
+	case []any:
+		if seg.wildcard {
+			for i, e := range v {
+				v[i] = applyMask(e, rest, placeholder)
+			}
+		} else if seg.isIndex && seg.index >= 0 && seg.index < len(v) {
+			v[seg.index] = applyMask(v[seg.index], rest, placeholder)
+		}
+	}
+
+	return v
+}
+
+// structMaskPath translates a struct path into the JSON path of the same
+// value as encoded by encoding/json.
+func structMaskPath(t reflect.Type, path string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("$")
+
+	for path != "" {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if path[0] == '[' {
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return "", errors.New("unterminated index in path")
+			}
+
+			sel := path[1:end]
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array:
+				if _, err := strconv.Atoi(sel); err != nil && sel != "*" {
+					return "", fmt.Errorf("index [%s]: not an integer", sel)
+				}
+
+				sb.WriteString("[" + sel + "]")
+			case reflect.Map:
+				if s, err := strconv.Unquote(sel); err == nil {
+					sel = s
+				}
+
+				if sel == "*" {
+					sb.WriteString("[*]")
+				} else {
+					sb.WriteString("[" + strconv.Quote(sel) + "]")
+				}
+			default:
+				return "", fmt.Errorf("index [%s]: %s cannot be indexed", sel, t)
+			}
+
+			t = t.Elem()
+			path = strings.TrimPrefix(path[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(path, ".[")
+		if end < 0 {
+			end = len(path)
+		}
+
+		name := path[:end]
+		if name == "" {
+			return "", errors.New("empty field name in path")
+		}
+
+		if t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("field %s: %s is not a struct", name, t)
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return "", fmt.Errorf("field %s: no such field in %s", name, t)
+		}
+
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case jsonName == "-":
+			return "", fmt.Errorf("field %s: not encoded as JSON", name)
+		case jsonName == "":
+			jsonName = f.Name
+		}
+
+		sb.WriteString("[" + strconv.Quote(jsonName) + "]")
+
+		t = f.Type
+		path = strings.TrimPrefix(path[end:], ".")
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,136 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type maskedEvent struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created_at,omitempty"`
+	Secret  string `json:"-"`
+	Name    string
+	Items   []maskedItem      `json:"items"`
+	Labels  map[string]string `json:"labels"`
+	Any     any               `json:"any"`
+}
+
+type maskedItem struct {
+	Token string `json:"token"`
+	Qty   int    `json:"qty"`
+}
+
+func TestMaskValue(t *testing.T) {
+	t.Parallel()
+
+	v := &maskedEvent{
+		ID:      "a1",
+		Created: 1700000000,
+		Name:    "n",
+		Items:   []maskedItem{{"x", 1}, {"y", 2}},
+		Labels:  map[string]string{"trace": "abc", "env": "ci"},
+	}
+
+	b, err := MaskValue(v,
+		Mask{Path: "ID"},
+		Mask{Path: "Created", Placeholder: "<time>"},
+		Mask{Path: "Items[*].Token"},
+		Mask{Path: `Labels["trace"]`},
+		Mask{Path: "$.items[1].qty"},
+		Mask{Path: "Name"},
+		Mask{Path: "$.missing.member"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	JSONEq(t, `{
+		"id": "<masked>",
+		"created_at": "<time>",
+		"Name": "<masked>",
+		"items": [{"token": "<masked>", "qty": 1}, {"token": "<masked>", "qty": "<masked>"}],
+		"labels": {"trace": "<masked>", "env": "ci"},
+		"any": null
+	}`, string(b))
+
+	b, err = MaskValue(v, Mask{Path: "Labels[*]"}, Mask{Path: "Items[0]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	JSONEq(t, `{
+		"id": "a1",
+		"created_at": 1700000000,
+		"Name": "n",
+		"items": ["<masked>", {"token": "y", "qty": 2}],
+		"labels": {"trace": "<masked>", "env": "<masked>"},
+		"any": null
+	}`, string(b))
+
+	if _, err := MaskValue(func() {}); err == nil {
+		t.Error("expected values which cannot be encoded to fail")
+	}
+}
+
+func TestMaskJSON(t *testing.T) {
+	t.Parallel()
+
+	b, err := MaskJSON([]byte(`{"a": [{"id": 1, "n": 12345678901234567890}, {"id": 2}], "b c": {"d": 1, "e": 2}, "f": 3}`),
+		Mask{Path: "$.a[*].id"},
+		Mask{Path: `$["b c"].*`},
+		Mask{Path: "$.a[5].id"},
+		Mask{Path: "$.f[0]"},
+		Mask{Path: "$[0]"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "{\n  \"a\": [\n    {\n      \"id\": \"<masked>\",\n      \"n\": 12345678901234567890\n    },\n    {\n      \"id\": \"<masked>\"\n    }\n  ],\n  \"b c\": {\n    \"d\": \"<masked>\",\n    \"e\": \"<masked>\"\n  },\n  \"f\": 3\n}"
+	if string(b) != exp {
+		t.Errorf("expected %s but got %s", exp, b)
+	}
+
+	b, err = MaskJSON([]byte(`{"a": 1}`), Mask{Path: "$", Placeholder: "all"})
+	if err != nil || string(b) != `"all"` {
+		t.Errorf("expected the root to be masked but got %s %v", b, err)
+	}
+}
+
+func TestMask_errors(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		f   func() ([]byte, error)
+		exp string
+	}{
+		{func() ([]byte, error) { return MaskJSON([]byte(`{`)) }, "unexpected EOF"},
+		{func() ([]byte, error) { return MaskJSON([]byte(`{}`), Mask{Path: "ID"}) }, "tbdd: mask ID: struct paths can only mask Go values"},
+		{func() ([]byte, error) { return MaskJSON([]byte(`{}`), Mask{Path: "$."}) }, "tbdd: mask $.: empty member name in path"},
+		{func() ([]byte, error) { return MaskJSON([]byte(`{}`), Mask{Path: "$[0"}) }, "tbdd: mask $[0: unterminated selector in path"},
+		{func() ([]byte, error) { return MaskJSON([]byte(`{}`), Mask{Path: "$[x]"}) }, "tbdd: mask $[x]: invalid selector [x] in path"},
+		{func() ([]byte, error) { return MaskJSON([]byte(`{}`), Mask{Path: "$x"}) }, `tbdd: mask $x: unexpected 'x' in path`},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Secret"}) }, "tbdd: mask Secret: field Secret: not encoded as JSON"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Nope"}) }, "tbdd: mask Nope: field Nope: no such field in tbdd.maskedEvent"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "ID.X"}) }, "tbdd: mask ID.X: field X: string is not a struct"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Any.X"}) }, "tbdd: mask Any.X: field X: interface {} is not a struct"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Items[0"}) }, "tbdd: mask Items[0: unterminated index in path"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Items[x]"}) }, "tbdd: mask Items[x]: index [x]: not an integer"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "ID[0]"}) }, "tbdd: mask ID[0]: index [0]: string cannot be indexed"},
+		{func() ([]byte, error) { return MaskValue(maskedEvent{}, Mask{Path: "Items..Qty"}) }, "tbdd: mask Items..Qty: empty field name in path"},
+	} {
+		b, err := v.f()
+		if err == nil || err.Error() != v.exp {
+			t.Errorf("expected error %q but got %s %v", v.exp, b, err)
+		}
+	}
+}
+
+func TestMask_structPathTranslation(t *testing.T) {
+	t.Parallel()
+
+	b, err := MaskValue(map[string]json.Number{"k": "1"}, Mask{Path: `["k"]`})
+	if err != nil || string(b) != "{\n  \"k\": \"<masked>\"\n}" {
+		t.Errorf("expected map keys to be masked but got %s %v", b, err)
+	}
+}
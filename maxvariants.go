@@ -0,0 +1,59 @@
+package tbdd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// listedKinds is the number of variant Kinds named by a MaxVariants failure.
+const listedKinds = 5
+
+// variantIter is the state of the iteration of Variants and Variants2.
+type variantIter struct {
+	// i is the index of the latest variant yielded.
+	i int
+	// limited is set once the variants exceeded MaxVariants.
+	limited bool
+	// kinds are the quoted Kinds of the first variants.
+	kinds []string
+}
+
+// overLimit reports whether the variant of the given kind at index it.i is
+// beyond MaxVariants, failing t if so with the Kinds of the first variants,
+// which it collects in it while the limit holds.
+func (p *plan[T, R]) overLimit(t TestingT, it *variantIter, kind string) bool {
+	t.Helper()
+
+	if p.maxVars <= 0 {
+		return false
+	}
+
+	i := it.i
+	if i < p.maxVars {
+		if kind != "" && len(it.kinds) < listedKinds {
+			it.kinds = append(it.kinds, strconv.Quote(kind))
+		}
+		return false
+	}
+
+	it.limited = true
+
+	msg := "more than " + strconv.Itoa(p.maxVars) + " yielded"
+	if len(it.kinds) > 0 {
+		msg += ", the first being " + strings.Join(it.kinds, ", ")
+		if i > len(it.kinds) {
+			msg += ", ..."
+		}
+	}
+	err := fmt.Errorf("%w: %s", ErrTooManyVariants, msg)
+
+	p.variantConfigFailure(t, i, "", func(t TestingT) {
+		t.Helper()
+
+		p.phases.configError(p.getT(t), "MaxVariants", "", i, err)
+		t.Error((&ConfigError{"MaxVariants", "", i, err}).Error())
+	})
+
+	return true
+}
@@ -0,0 +1,146 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_maxVariants(t *testing.T) {
+	t.Parallel()
+
+	// endless yields variants a, b, c, ... until told to stop
+	endless := func(*testing.T, mTC) iter.Seq[TestVariant[mTC]] {
+		return func(yield func(TestVariant[mTC]) bool) {
+			for i := 0; ; i++ {
+				if !yield(TestVariant[mTC]{Kind: string(rune('a' + i))}) {
+					return
+				}
+			}
+		}
+	}
+
+	type tcase struct {
+		max       int
+		variants  func(*testing.T, mTC) iter.Seq[TestVariant[mTC]]
+		variants2 func(*testing.T, mTC) iter.Seq2[TestVariant[mTC], error]
+		expRuns   string
+		expErr    string
+		// expField is the Field of the last config error, MaxVariants when
+		// empty.
+		expField string
+	}
+
+	for i, tc := range []tcase{
+		{
+			max:      2,
+			variants: endless,
+			expRuns:  "when w,then t,a/when w,then t,b/when w,then t,variant 2/tbdd-config-error",
+			expErr:   `tbdd: invalid MaxVariants of variant 2: too many test case variants: more than 2 yielded, the first being "a", "b"`,
+		},
+		{
+			max:      6,
+			variants: endless,
+			expRuns:  "when w,then t,a/when w,then t,b/when w,then t,c/when w,then t,d/when w,then t,e/when w,then t,f/when w,then t,variant 6/tbdd-config-error",
+			expErr:   `tbdd: invalid MaxVariants of variant 6: too many test case variants: more than 6 yielded, the first being "a", "b", "c", "d", "e", ...`,
+		},
+		{
+			max: 2,
+			variants: func(*testing.T, mTC) iter.Seq[TestVariant[mTC]] {
+				return func(yield func(TestVariant[mTC]) bool) {
+					yield(TestVariant[mTC]{Kind: "a"})
+				}
+			},
+			variants2: func(*testing.T, mTC) iter.Seq2[TestVariant[mTC], error] {
+				return func(yield func(TestVariant[mTC], error) bool) {
+					_ = yield(TestVariant[mTC]{Kind: "b"}, nil) &&
+						yield(TestVariant[mTC]{Kind: "c"}, nil) &&
+						yield(TestVariant[mTC]{Kind: "d"}, nil)
+				}
+			},
+			expRuns: "when w,then t,a/when w,then t,b/when w,then t,variant 2/tbdd-config-error",
+			expErr:  `tbdd: invalid MaxVariants of variant 2: too many test case variants: more than 2 yielded, the first being "a", "b"`,
+		},
+		{
+			max: 1,
+			variants2: func(*testing.T, mTC) iter.Seq2[TestVariant[mTC], error] {
+				return func(yield func(TestVariant[mTC], error) bool) {
+					_ = yield(TestVariant[mTC]{}, errors.New("bad row")) &&
+						yield(TestVariant[mTC]{}, errors.New("bad row"))
+				}
+			},
+			expRuns: "when w,then t,variant 0/tbdd-config-error,variant 1/tbdd-config-error",
+			expErr:  "tbdd: invalid MaxVariants of variant 1: too many test case variants: more than 1 yielded",
+		},
+		{
+			max: 1,
+			// unstoppable ignores yield returning false
+			variants: func(*testing.T, mTC) iter.Seq[TestVariant[mTC]] {
+				return func(yield func(TestVariant[mTC]) bool) {
+					for i := 0; ; i++ {
+						yield(TestVariant[mTC]{Kind: string(rune('a' + i))})
+					}
+				}
+			},
+			expRuns:  "when w,then t,a/when w,then t,variant 1/tbdd-config-error,variant 2/tbdd-config-error",
+			expErr:   "tbdd: invalid Variants of variant 2: too many test case variants: the generator kept yielding after yield returned false at the MaxVariants limit of 1: runtime error: range function continued iteration after function for loop body returned false",
+			expField: "Variants",
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+
+			var configErrs []*ConfigError
+			var ran []string
+			b := Lifecycle[mTC, mTCR]{
+				When: "w",
+				Then: "t",
+				Act: func(*testing.T, mTC) mTCR {
+					return mTCR{}
+				},
+				Assert: func(*testing.T, Assert[mTC, mTCR]) {
+				},
+				Variants:  tc.variants,
+				Variants2: tc.variants2,
+				hooks: Hooks[mTC, mTCR]{
+					ConfigError: func(_ *testing.T, err *ConfigError) {
+						configErrs = append(configErrs, err)
+					},
+				},
+			}
+			b = b.With(WithMaxVariants[mTC, mTCR](tc.max))
+
+			mt := &mT{}
+
+			b.getT = nilGetT
+			b.runObserver = func(s string) {
+				ran = append(ran, s)
+			}
+
+			f := ((lifecycle[mTC, mTCR])(b)).new(mt)
+			f(mt)
+
+			if strings.Join(ran, ",") != tc.expRuns {
+				t.Errorf("expected subtests '%s' but got '%s'", tc.expRuns, strings.Join(ran, ","))
+			}
+
+			// the generator which does not stop fails producing the variant
+			// after the one beyond the limit
+			expField, expIndex := tc.expField, tc.max+1
+			if expField == "" {
+				expField, expIndex = "MaxVariants", tc.max
+			}
+
+			last := configErrs[len(configErrs)-1]
+			if last.Field != expField || last.VariantIndex != expIndex || !errors.Is(last, ErrTooManyVariants) {
+				t.Errorf("expected a %s config error for variant %d but got %v", expField, expIndex, configErrs)
+			}
+
+			if msg := mt.errorCalls[len(mt.errorCalls)-1][0]; msg != tc.expErr {
+				t.Errorf("expected the error '%s' but got '%v'", tc.expErr, msg)
+			}
+		})
+	}
+}
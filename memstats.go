@@ -0,0 +1,46 @@
+package tbdd
+
+import "runtime"
+
+// MemDelta is the change in runtime.MemStats counters across an Act.
+//
+// The counters are process wide, so allocations made concurrently by other
+// goroutines, such as those of parallel tests, are included.
+type MemDelta struct {
+	// HeapAlloc is the change in bytes of allocated heap objects; it is
+	// negative when a collection freed more than the Act allocated.
+	HeapAlloc int64
+	// TotalAlloc is the number of bytes allocated for heap objects.
+	TotalAlloc uint64
+	// Mallocs and Frees are the numbers of heap objects allocated and freed.
+	Mallocs, Frees uint64
+	// NumGC is the number of completed garbage collection cycles.
+	NumGC uint32
+}
+
+// memDelta returns the change from before to after.
+func memDelta(before, after *runtime.MemStats) MemDelta {
+	return MemDelta{
+		HeapAlloc:  int64(after.HeapAlloc) - int64(before.HeapAlloc),
+		TotalAlloc: after.TotalAlloc - before.TotalAlloc,
+		Mallocs:    after.Mallocs - before.Mallocs,
+		Frees:      after.Frees - before.Frees,
+		NumGC:      after.NumGC - before.NumGC,
+	}
+}
+
+// measureMem calls f and stores the change in memory statistics across it
+// in d, even when f ends the test.
+func measureMem(d *MemDelta, f func()) {
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	defer func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+
+		*d = memDelta(&before, &after)
+	}()
+
+	f()
+}
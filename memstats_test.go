@@ -0,0 +1,74 @@
+package tbdd
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var memSink [][]byte
+
+func TestLifecycle_memStats(t *testing.T) {
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	var got *MemDelta
+	f := WTN(
+		struct{}{},
+		"the behavior allocates", func(*testing.T, struct{}) {
+			for range 100 {
+				memSink = append(memSink, make([]byte, 1024))
+			}
+			memSink = nil
+		},
+		"its memory statistics are reported", func(*testing.T, struct{}) {},
+	).With(
+		WithMemStats[struct{}, struct{}](),
+		WithHooks(Hooks[struct{}, struct{}]{
+			AfterAct: func(_ *testing.T, cfg AfterAct[struct{}, struct{}]) {
+				got = cfg.Mem
+			},
+		}),
+	).New(t)
+	f(t)
+
+	if got == nil || got.Mallocs < 100 || got.TotalAlloc < 100*1024 {
+		t.Fatalf("expected at least 100 allocations of 100KiB in total but got %+v", got)
+	}
+
+	var recorded []*MemDelta
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			recorded = append(recorded, r.Mem)
+		}
+	}
+
+	if len(recorded) != 1 || recorded[0] != got {
+		t.Errorf("expected the result to record the statistics %+v but got %v", got, recorded)
+	}
+
+	// statistics are not measured unless requested
+	f = WTN(
+		struct{}{},
+		"the behavior runs", func(*testing.T, struct{}) {},
+		"no statistics are reported", func(*testing.T, struct{}) {},
+	).With(WithHooks(Hooks[struct{}, struct{}]{
+		AfterAct: func(_ *testing.T, cfg AfterAct[struct{}, struct{}]) {
+			if cfg.Mem != nil {
+				t.Errorf("expected no memory statistics but got %+v", cfg.Mem)
+			}
+		},
+	})).New(t)
+	f(t)
+}
+
+func Test_memDelta(t *testing.T) {
+	t.Parallel()
+
+	before := &runtime.MemStats{HeapAlloc: 100, TotalAlloc: 10, Mallocs: 5, Frees: 1, NumGC: 2}
+	after := &runtime.MemStats{HeapAlloc: 40, TotalAlloc: 30, Mallocs: 9, Frees: 4, NumGC: 3}
+
+	if d := memDelta(before, after); d != (MemDelta{HeapAlloc: -60, TotalAlloc: 20, Mallocs: 4, Frees: 3, NumGC: 1}) {
+		t.Errorf("unexpected delta: %+v", d)
+	}
+}
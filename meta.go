@@ -0,0 +1,53 @@
+package tbdd
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Metadata keys set by the typed metadata fields of a Lifecycle.
+const (
+	MetaOwner    = "owner"
+	MetaTicket   = "ticket"
+	MetaSeverity = "severity"
+)
+
+// metadata merges the typed metadata fields of a Lifecycle into a copy of
+// meta, returning nil when there is no metadata at all.
+func metadata(owner, ticket, severity string, meta map[string]string) map[string]string {
+	if owner == "" && ticket == "" && severity == "" && len(meta) == 0 {
+		return nil
+	}
+
+	m := maps.Clone(meta)
+	if m == nil {
+		m = make(map[string]string, 3)
+	}
+
+	for k, v := range map[string]string{MetaOwner: owner, MetaTicket: ticket, MetaSeverity: severity} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+// formatMeta renders metadata as space separated key=value pairs sorted by
+// key, such as "owner=payments severity=high".
+func formatMeta(m map[string]string) string {
+	var sb strings.Builder
+
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(m[k])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_metadata(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]string{"team": "core", MetaOwner: "overridden"}
+
+	for _, v := range []struct {
+		owner, ticket, severity string
+		meta                    map[string]string
+		exp                     map[string]string
+	}{
+		{"", "", "", nil, nil},
+		{"", "", "", map[string]string{}, nil},
+		{"payments", "", "", nil, map[string]string{MetaOwner: "payments"}},
+		{"payments", "PAY-1", "high", meta, map[string]string{"team": "core", MetaOwner: "payments", MetaTicket: "PAY-1", MetaSeverity: "high"}},
+		{"", "", "", meta, meta},
+	} {
+		if m := metadata(v.owner, v.ticket, v.severity, v.meta); !reflect.DeepEqual(m, v.exp) {
+			t.Errorf("expected %v but got %v", v.exp, m)
+		}
+	}
+
+	if meta[MetaOwner] != "overridden" {
+		t.Error("expected the Meta map to be left unchanged")
+	}
+
+	if s := formatMeta(map[string]string{"b": "2", "a": "1"}); s != "a=1 b=2" {
+		t.Errorf("expected 'a=1 b=2' but got '%s'", s)
+	}
+}
+
+func TestLifecycle_meta(t *testing.T) {
+	if os.Getenv("TBDD_META_HELPER") == "1" {
+		b := WTN(
+			struct{}{},
+			"the behavior fails", func(t *testing.T, _ struct{}) {
+				t.Error("failure")
+			},
+			"its owner is named", func(*testing.T, struct{}) {},
+		)
+		b.Owner = "payments"
+		b.Severity = "high"
+		b.Source = "billing.feature:3"
+
+		f := b.New(t)
+		f(t)
+		return
+	}
+
+	base := WTN(
+		struct{}{},
+		"the behavior runs", func(*testing.T, struct{}) {},
+		"its metadata is recorded", func(*testing.T, struct{}) {},
+	)
+	base.Ticket = "PAY-1"
+
+	b := base.With(
+		WithMeta[struct{}, struct{}]("team", "core"),
+		WithMeta[struct{}, struct{}]("area", "refunds"),
+	)
+	if base.Meta != nil {
+		t.Fatalf("expected WithMeta to leave the original Lifecycle unchanged but got %v", base.Meta)
+	}
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	t.Run("scenario", b.New(t))
+
+	var got []ScenarioResult
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			got = append(got, r)
+		}
+	}
+
+	exp := map[string]string{"team": "core", "area": "refunds", MetaTicket: "PAY-1"}
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Meta, exp) || got[0].Source != base.Source {
+		t.Errorf("expected one result with metadata %v but got %+v", exp, got)
+	}
+
+	//
+	// failing scenarios log their metadata
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_META_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{"tbdd: scenario metadata: owner=payments severity=high", "\n        billing.feature:3: tbdd: the failed scenario is defined here\n"} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package tbdd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLifecycle_normalizeResult(t *testing.T) {
+	t.Parallel()
+
+	var afterAct, asserted [][]int
+	b := WT(0, "the ids are listed", func(*testing.T, int) []int {
+		return []int{3, 1, 2, 2}
+	}, "they are unique and sorted", func(_ *testing.T, _ int, r []int) {
+		asserted = append(asserted, r)
+	}).With(
+		WithNormalizeResult[int, []int](func(r *[]int) { slices.Sort(*r) }, nil),
+		WithNormalizeResult[int, []int](func(r *[]int) { *r = slices.Compact(*r) }),
+		WithHooks(Hooks[int, []int]{AfterAct: func(_ *testing.T, a AfterAct[int, []int]) {
+			afterAct = append(afterAct, *a.Result)
+		}}),
+	)
+
+	b.New(t)(t)
+
+	exp := [][]int{{1, 2, 3}}
+	Equal(t, afterAct, exp)
+	Equal(t, asserted, exp)
+}
+
+func TestLifecycle_Validate_normalizeResult(t *testing.T) {
+	t.Parallel()
+
+	b := WT(0, "w", func(*testing.T, int) int { return 0 }, "t", func(*testing.T, int, int) {})
+	b.NormalizeResult = []func(*int){func(*int) {}, nil}
+
+	err := b.Validate()
+
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Field != "NormalizeResult[1]" || !errors.Is(err, ErrNilNormalizer) {
+		t.Errorf("expected a nil normalizer error but got %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+package tbdd
+
+import (
+	"iter"
+	"maps"
+	"testing"
+	"time"
+)
+
+// Option configures a Lifecycle being constructed by New.
+//
+// Options are plain functions so they can be stored, shared, and reused
+// across scenarios. Options that do not mention R in their arguments (such as
+// WithGiven or WithVariants) require explicit type arguments, for example
+// WithGiven[TestCase, Result](...).
+type Option[T, R any] func(*Lifecycle[T, R])
+
+// New constructs a Lifecycle for test case tc by applying opts in order.
+//
+// Later options override the effects of earlier ones when they configure the
+// same fields. Unlike GWT, New never panics: use Lifecycle.Validate to check
+// the resulting configuration before running it if desired.
+//
+// The Source of the Lifecycle is the line which called New, unless an option
+// sets it.
+func New[T, R any](tc T, opts ...Option[T, R]) Lifecycle[T, R] {
+	b := Lifecycle[T, R]{TC: tc, Source: definedAt()}
+
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return b
+}
+
+// With applies opts to a copy of the Lifecycle and returns it.
+func (b Lifecycle[T, R]) With(opts ...Option[T, R]) Lifecycle[T, R] {
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return b
+}
+
+// Options combines several options into one so groups of options can be
+// reused as a unit.
+func Options[T, R any](opts ...Option[T, R]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		for _, opt := range opts {
+			opt(b)
+		}
+	}
+}
+
+// WithGiven sets the Given description and an Arrange function which calls
+// givenF with a pointer to the test case, just like GWT does.
+//
+// A nil givenF only sets the Given description.
+func WithGiven[T, R any](given string, givenF func(*testing.T, *T)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Given = given
+		if givenF == nil {
+			b.Arrange = nil
+			return
+		}
+
+		b.Arrange = givenArrange[T, R](given, givenF)
+	}
+}
+
+// WithWhen sets the When description and the Act function.
+func WithWhen[T, R any](when string, whenF func(*testing.T, T) R) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.When = when
+		b.Act = whenF
+	}
+}
+
+// WithThen sets the Then description and an Assert function which calls thenF.
+func WithThen[T, R any](then string, thenF func(*testing.T, T, R)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Then = then
+		if thenF == nil {
+			b.Assert = nil
+			return
+		}
+
+		b.Assert = thenAssert(thenF)
+	}
+}
+
+// WithArrange sets the Arrange function.
+func WithArrange[T, R any](f func(*testing.T, Arrange[T, R]) (string, func(*testing.T))) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Arrange = f
+	}
+}
+
+// WithDescribe sets the Describe function.
+func WithDescribe[T, R any](f func(*testing.T, Describe[T]) DescribeResponse) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Describe = f
+	}
+}
+
+// WithAct sets the Act function without altering the When description.
+func WithAct[T, R any](f func(*testing.T, T) R) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Act = f
+	}
+}
+
+// WithAssert sets the Assert function without altering the Then description.
+func WithAssert[T, R any](f func(*testing.T, Assert[T, R])) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Assert = f
+	}
+}
+
+// WithHooks sets the lifecycle hooks, replacing any previously configured.
+func WithHooks[T, R any](hooks Hooks[T, R]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.hooks = hooks
+	}
+}
+
+// WithVariants sets the Variants function.
+func WithVariants[T, R any](f func(*testing.T, T) iter.Seq[TestVariant[T]]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Variants = f
+	}
+}
+
+// WithVariants2 sets the Variants2 function.
+func WithVariants2[T, R any](f func(*testing.T, T) iter.Seq2[TestVariant[T], error]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Variants2 = f
+	}
+}
+
+// WithInferKind sets InferKind, inferring the Kinds of variants yielded
+// without one from how their test cases differ from the basis.
+func WithInferKind[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.InferKind = true
+	}
+}
+
+// WithDefaultTC appends defaults to the DefaultTC functions of the Lifecycle,
+// applied to the test case of every scenario before Arrange.
+func WithDefaultTC[T, R any](defaults ...func(*T)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.DefaultTC = append(b.DefaultTC[:len(b.DefaultTC):len(b.DefaultTC)], defaults...)
+	}
+}
+
+// WithInvariants appends invariants to the Invariants of the Lifecycle,
+// checked after every phase of every scenario.
+func WithInvariants[T, R any](invariants ...func(*testing.T, T)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Invariants = append(b.Invariants[:len(b.Invariants):len(b.Invariants)], invariants...)
+	}
+}
+
+// WithNormalizeResult appends normalize to the NormalizeResult functions of
+// the Lifecycle, applied to the result of Act before it is asserted.
+func WithNormalizeResult[T, R any](normalize ...func(*R)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.NormalizeResult = append(b.NormalizeResult[:len(b.NormalizeResult):len(b.NormalizeResult)], normalize...)
+	}
+}
+
+// WithMaxVariants sets MaxVariants, failing the test once Variants and
+// Variants2 yield more than n variants.
+func WithMaxVariants[T, R any](n int) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.MaxVariants = n
+	}
+}
+
+// WithCloneTC sets the CloneTC function.
+func WithCloneTC[T, R any](f func(T) T) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.CloneTC = f
+	}
+}
+
+// WithLayout sets the SubtestLayout.
+func WithLayout[T, R any](layout SubtestLayout) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Layout = layout
+	}
+}
+
+// WithSynctest sets Synctest, running the Act and Assert functions within
+// testing/synctest bubbles.
+func WithSynctest[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Synctest = true
+	}
+}
+
+// WithTrace sets Trace, wrapping every scenario in a runtime/trace task.
+func WithTrace[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Trace = true
+	}
+}
+
+// WithMemStats sets MemStats, reporting the change in memory statistics
+// across Act in AfterAct.Mem.
+func WithMemStats[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.MemStats = true
+	}
+}
+
+// WithWatchdog sets Watchdog, ending the test binary with a goroutine dump
+// when a scenario takes longer than d.
+func WithWatchdog[T, R any](d time.Duration) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Watchdog = d
+	}
+}
+
+// WithSlowPhase sets SlowPhase, warning about scenario phases which take at
+// least d.
+func WithSlowPhase[T, R any](d time.Duration) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.SlowPhase = d
+	}
+}
+
+// WithWarmupRuns sets WarmupRuns, calling Act n times before the call whose
+// result is asserted.
+func WithWarmupRuns[T, R any](n int) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.WarmupRuns = n
+	}
+}
+
+// WithFDLeakCheck sets FDLeakCheck, failing scenarios which leave file
+// descriptors open.
+func WithFDLeakCheck[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.FDLeakCheck = true
+	}
+}
+
+// WithSharedStateCheck sets SharedStateCheck, failing the test when the test
+// cases of its scenarios share mutable memory.
+func WithSharedStateCheck[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.SharedStateCheck = true
+	}
+}
+
+// WithReadOnlyTC sets ReadOnlyTC, failing the scenario when its Describe or
+// Assert phase mutates its test case.
+func WithReadOnlyTC[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.ReadOnlyTC = true
+	}
+}
+
+// WithParallelSafe sets ParallelSafe, allowing the given and act phases of
+// its scenarios to call t.Parallel.
+func WithParallelSafe[T, R any]() Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.ParallelSafe = true
+	}
+}
+
+// WithRequire sets the Require phase, which validates the preconditions of
+// the behavior, and the RequirePolicy applied when they are not met.
+func WithRequire[T, R any](policy RequirePolicy, f func(*testing.T, T) error) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Require = f
+		b.RequirePolicy = policy
+	}
+}
+
+// WithSeed sets Seed, the base of the seeds of its scenarios.
+func WithSeed[T, R any](seed int64) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Seed = seed
+	}
+}
+
+// WithMasks appends masks to the Masks of the Lifecycle.
+func WithMasks[T, R any](masks ...Mask) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Masks = append(b.Masks[:len(b.Masks):len(b.Masks)], masks...)
+	}
+}
+
+// WithDependsOn appends ids to the DependsOn of the Lifecycle, the IDs of the
+// scenarios which must pass before its scenarios run.
+func WithDependsOn[T, R any](ids ...string) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.DependsOn = append(b.DependsOn[:len(b.DependsOn):len(b.DependsOn)], ids...)
+	}
+}
+
+// WithPriority sets Priority, ranking the scenarios of the Lifecycle for
+// the -tbdd.min-priority flag.
+func WithPriority[T, R any](p Priority) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.Priority = p
+	}
+}
+
+// WithTCCodec sets the TCCodec used to persist and load test cases.
+func WithTCCodec[T, R any](codec TCCodec[T]) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.TCCodec = codec
+	}
+}
+
+// WithMeta sets the metadata value of key, leaving the Meta map of any
+// Lifecycle it was copied from unchanged.
+func WithMeta[T, R any](key, value string) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		m := maps.Clone(b.Meta)
+		if m == nil {
+			m = map[string]string{}
+		}
+
+		m[key] = value
+		b.Meta = m
+	}
+}
+
+// WithSkipUntil sets SkipUntil and SkipUntilReason.
+func WithSkipUntil[T, R any](until time.Time, reason string) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.SkipUntil = until
+		b.SkipUntilReason = reason
+	}
+}
+
+// WithTestingTAdapter sets the function which converts the TestingT values a
+// Lifecycle runs with into the *testing.T passed to its phase functions. It
+// lets frameworks built on tbdd drive a Lifecycle through NewT or NewTI with
+// their own TestingT implementation.
+//
+// By default the TestingT must be a *testing.T.
+func WithTestingTAdapter[T, R any](f func(TestingT) *testing.T) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.getT = f
+	}
+}
+
+// WithRunObserver sets a function which is called with the name of every
+// subtest a Lifecycle starts, just before it starts.
+func WithRunObserver[T, R any](f func(name string)) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		b.runObserver = f
+	}
+}
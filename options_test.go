@@ -0,0 +1,183 @@
+package tbdd
+
+import (
+	"iter"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	type TC struct {
+		seeded bool
+		kind   string
+	}
+	type Result struct {
+		seeded bool
+	}
+
+	var givenCalls, whenCalls, thenCalls, cloneCalls, afterActCalls int
+	var kinds []string
+
+	common := Options(
+		WithWhen("w", func(_ *testing.T, tc TC) Result {
+			whenCalls++
+			return Result{tc.seeded}
+		}),
+		WithThen("t", func(t *testing.T, tc TC, r Result) {
+			thenCalls++
+			kinds = append(kinds, tc.kind)
+			if !r.seeded {
+				t.Error("expected given to seed the test case")
+			}
+		}),
+	)
+
+	b := New(
+		TC{},
+		WithGiven[TC, Result]("g", func(_ *testing.T, tc *TC) {
+			givenCalls++
+			tc.seeded = true
+		}),
+		common,
+		WithHooks(Hooks[TC, Result]{
+			AfterAct: func(*testing.T, AfterAct[TC, Result]) {
+				afterActCalls++
+			},
+		}),
+		WithCloneTC[TC, Result](func(tc TC) TC {
+			cloneCalls++
+			return tc
+		}),
+		WithVariants[TC, Result](func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+			return func(yield func(TestVariant[TC]) bool) {
+				tc.kind = "v"
+				yield(TestVariant[TC]{TC: tc, Kind: "v"})
+			}
+		}),
+		WithVariants2[TC, Result](func(_ *testing.T, tc TC) iter.Seq2[TestVariant[TC], error] {
+			return func(yield func(TestVariant[TC], error) bool) {
+				tc.kind = "v2"
+				yield(TestVariant[TC]{TC: tc, Kind: "v2"}, nil)
+			}
+		}),
+	)
+
+	if err := b.Validate(); err != nil {
+		t.Fatalf("expected valid lifecycle but got '%v'", err)
+	}
+
+	f := b.New(t)
+	f(t)
+
+	if givenCalls != 3 || whenCalls != 3 || thenCalls != 3 || afterActCalls != 3 || cloneCalls != 3 {
+		t.Errorf("unexpected call counts: given=%d when=%d then=%d afterAct=%d clone=%d", givenCalls, whenCalls, thenCalls, afterActCalls, cloneCalls)
+	}
+
+	if len(kinds) != 3 || kinds[0] != "" || kinds[1] != "v" || kinds[2] != "v2" {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+}
+
+func TestNew_overrides(t *testing.T) {
+	type TC struct{}
+	type Result struct{}
+
+	var arrangeCalled, describeCalled, actCalled, assertCalled bool
+
+	b := New(
+		TC{},
+		WithGiven[TC, Result]("g", func(*testing.T, *TC) {}),
+		WithGiven[TC, Result]("g2", nil),
+		WithThen[TC, Result]("t", nil),
+		WithArrange(func(_ *testing.T, cfg Arrange[TC, Result]) (string, func(*testing.T)) {
+			arrangeCalled = true
+			return cfg.Given, func(*testing.T) {}
+		}),
+		WithDescribe[TC, Result](func(*testing.T, Describe[TC]) DescribeResponse {
+			describeCalled = true
+			return DescribeResponse{"w", "t"}
+		}),
+		WithAct(func(*testing.T, TC) Result {
+			actCalled = true
+			return Result{}
+		}),
+		WithAssert(func(*testing.T, Assert[TC, Result]) {
+			assertCalled = true
+		}),
+	)
+
+	if b.Given != "g2" {
+		t.Errorf("expected Given to be 'g2' but got '%s'", b.Given)
+	}
+
+	b.With(WithAct(func(*testing.T, TC) Result {
+		t.Error("With must not modify the receiver")
+		return Result{}
+	}))
+
+	f := b.New(t)
+	f(t)
+
+	if !(arrangeCalled && describeCalled && actCalled && assertCalled) {
+		t.Error()
+	}
+}
+
+// frameworkT is an alternative TestingT implementation such as a framework
+// built on tbdd might provide.
+type frameworkT struct {
+	*testing.T
+	runs *[]string
+}
+
+func (t frameworkT) Run(name string, f func(*testing.T)) bool {
+	*t.runs = append(*t.runs, name)
+	return t.T.Run(name, f)
+}
+
+func TestWithTestingTAdapter(t *testing.T) {
+	var runs, observed []string
+	var adapted int
+
+	b := GWTN(
+		struct{}{},
+		"a framework", func(*testing.T, *struct{}) {},
+		"it drives the lifecycle", func(*testing.T, struct{}) {},
+		"phases receive the adapted T", func(*testing.T, struct{}) {},
+	).With(
+		WithTestingTAdapter[struct{}, struct{}](func(t TestingT) *testing.T {
+			adapted++
+			if ft, ok := t.(frameworkT); ok {
+				return ft.T
+			}
+
+			return t.(*testing.T)
+		}),
+		WithRunObserver[struct{}, struct{}](func(name string) {
+			observed = append(observed, name)
+		}),
+	)
+
+	ft := frameworkT{t, &runs}
+	f := b.NewT(ft)
+	f(ft)
+
+	if exp := "given a framework"; len(runs) != 1 || runs[0] != exp {
+		t.Errorf("expected the framework to start subtest '%s' but got %v", exp, runs)
+	}
+
+	if exp := "given a framework|when it drives the lifecycle|then phases receive the adapted T"; strings.Join(observed, "|") != exp {
+		t.Errorf("expected observed runs '%s' but got '%s'", exp, strings.Join(observed, "|"))
+	}
+
+	if adapted == 0 {
+		t.Error("expected the adapter to be used")
+	}
+
+	f = b.NewTI(ft, 3)
+	f(ft)
+
+	if exp := "3/given a framework"; len(runs) != 2 || runs[1] != exp {
+		t.Errorf("expected the framework to start subtest '%s' but got %v", exp, runs)
+	}
+}
@@ -0,0 +1,196 @@
+package tbdd
+
+import (
+	"reflect"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+// Outcome is the result of an action captured by GWTOutcome or WTOutcome:
+// the value and error it returned, or the value it panicked with.
+type Outcome[R any] struct {
+	Value R
+	Err   error
+	// Panic is the value recovered from the action, or nil if it returned.
+	Panic any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack    []byte
+	Duration time.Duration
+}
+
+// Panicked reports whether the action panicked rather than returned.
+func (o Outcome[R]) Panicked() bool {
+	return o.Panic != nil
+}
+
+// GWTOutcome is a convenience wrapper around GWT for behaviors whose action
+// returns a value and an error, and which may panic.
+//
+// thenF receives the final test case and the Outcome of whenF, so failure
+// modes are conveyed without a bespoke result type. A panic of whenF is
+// recovered into the Outcome; a whenF which stops the test via t.FailNow or
+// t.SkipNow still does so. ExpectSuccess, ExpectValue, ExpectFailure, and
+// ExpectPanic provide ready-made then functions.
+//
+// See GWT for more detail, including the panic conditions.
+func GWTOutcome[T, R any](
+	tc T,
+	given string, givenF func(*testing.T, *T),
+	when string, whenF func(*testing.T, T) (R, error),
+	then string, thenF func(*testing.T, T, Outcome[R]),
+) Lifecycle[T, Outcome[R]] {
+	return GWT(
+		tc,
+		given, givenF,
+		when, outcomeAct(whenF),
+		then, thenF,
+	)
+}
+
+// WTOutcome is a convenience wrapper around GWTOutcome for use when there is
+// no given context to convey.
+//
+// See GWT for more detail.
+func WTOutcome[T, R any](
+	tc T,
+	when string, whenF func(*testing.T, T) (R, error),
+	then string, thenF func(*testing.T, T, Outcome[R]),
+) Lifecycle[T, Outcome[R]] {
+	return GWTOutcome(
+		tc,
+		"", nil,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+// outcomeAct adapts an action into an Act function capturing its Outcome,
+// preserving nil-ness so constructor validation still applies.
+func outcomeAct[T, R any](whenF func(*testing.T, T) (R, error)) func(*testing.T, T) Outcome[R] {
+	if whenF == nil {
+		return nil
+	}
+
+	return func(t *testing.T, tc T) Outcome[R] {
+		return outcomeOf(func() (R, error) {
+			return whenF(t, tc)
+		})
+	}
+}
+
+func outcomeOf[R any](f func() (R, error)) (o Outcome[R]) {
+	start := time.Now()
+	defer func() {
+		o.Duration = time.Since(start)
+
+		// a nil recovery is either a return or runtime.Goexit, which must
+		// carry on unwinding
+		if r := recover(); r != nil {
+			o.Panic = r
+			o.Stack = debug.Stack()
+		}
+	}()
+
+	o.Value, o.Err = f()
+	return o
+}
+
+// ExpectSuccess returns a then function which fails the test if the action
+// returned a non-nil error or panicked.
+func ExpectSuccess[T, R any]() func(*testing.T, T, Outcome[R]) {
+	return func(t *testing.T, _ T, o Outcome[R]) {
+		t.Helper()
+
+		expectSuccess(t, o)
+	}
+}
+
+// ExpectValue returns a then function which fails the test unless the
+// action returned no error and a value deeply equal to want, as reported by
+// reflect.DeepEqual.
+func ExpectValue[T, R any](want R) func(*testing.T, T, Outcome[R]) {
+	return func(t *testing.T, _ T, o Outcome[R]) {
+		t.Helper()
+
+		expectValue(t, o, want)
+	}
+}
+
+// ExpectFailure returns a then function which fails the test unless the
+// action returned a non-nil error for which match returns nil, as described
+// by ExpectError. A panic is not a failure in this sense.
+func ExpectFailure[T, R any](match func(error) error) func(*testing.T, T, Outcome[R]) {
+	return func(t *testing.T, _ T, o Outcome[R]) {
+		t.Helper()
+
+		if expectReturned(t, o) {
+			expectError(t, o.Err, match)
+		}
+	}
+}
+
+// ExpectPanic returns a then function which fails the test unless the
+// action panicked with a value for which match returns nil. The error
+// returned by match, if any, describes why the value did not match.
+//
+// A nil match accepts any panic.
+func ExpectPanic[T, R any](match func(any) error) func(*testing.T, T, Outcome[R]) {
+	return func(t *testing.T, _ T, o Outcome[R]) {
+		t.Helper()
+
+		expectPanic(t, o, match)
+	}
+}
+
+// expectReturned fails the test and returns false if the action panicked.
+func expectReturned[R any](t assertT, o Outcome[R]) bool {
+	t.Helper()
+
+	if o.Panicked() {
+		t.Fatalf("expected no panic but got: %v\n\n%s", o.Panic, o.Stack)
+		return false
+	}
+
+	return true
+}
+
+func expectSuccess[R any](t assertT, o Outcome[R]) bool {
+	t.Helper()
+
+	if !expectReturned(t, o) {
+		return false
+	}
+
+	if o.Err != nil {
+		t.Fatalf("expected no error but got: %v", o.Err)
+		return false
+	}
+
+	return true
+}
+
+func expectValue[R any](t assertT, o Outcome[R], want R) {
+	t.Helper()
+
+	if expectSuccess(t, o) && !reflect.DeepEqual(o.Value, want) {
+		t.Fatalf("expected value %#v but got %#v", want, o.Value)
+	}
+}
+
+func expectPanic[R any](t assertT, o Outcome[R], match func(any) error) {
+	t.Helper()
+
+	if !o.Panicked() {
+		t.Fatalf("expected a panic but got value %#v and error: %v", o.Value, o.Err)
+		return
+	}
+
+	if match == nil {
+		return
+	}
+
+	if reason := match(o.Panic); reason != nil {
+		t.Fatalf("expected a matching panic but got: %v: %v", o.Panic, reason)
+	}
+}
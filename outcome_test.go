@@ -0,0 +1,114 @@
+package tbdd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGWTOutcome(t *testing.T) {
+	type TC struct {
+		mode string
+	}
+
+	errBoom := errors.New("boom")
+
+	act := func(t *testing.T, tc TC) (int, error) {
+		switch tc.mode {
+		case "fail":
+			return 0, fmt.Errorf("wrapped: %w", errBoom)
+		case "panic":
+			panic("kaboom")
+		case "skip":
+			t.SkipNow()
+		}
+
+		return 1, nil
+	}
+
+	b := GWTOutcome(
+		TC{},
+		"g", func(*testing.T, *TC) {},
+		"w", act,
+		"t", ExpectValue[TC](1),
+	)
+	b.New(t)(t)
+
+	for _, tc := range []struct {
+		mode  string
+		thenF func(*testing.T, TC, Outcome[int])
+	}{
+		{"", ExpectSuccess[TC, int]()},
+		{"fail", ExpectFailure[TC, int](func(err error) error {
+			if !errors.Is(err, errBoom) {
+				return errors.New("not boom")
+			}
+			return nil
+		})},
+		{"panic", ExpectPanic[TC, int](nil)},
+		{"panic", func(t *testing.T, _ TC, o Outcome[int]) {
+			if !strings.Contains(string(o.Stack), "TestGWTOutcome") || o.Duration <= 0 {
+				t.Errorf("expected the stack and duration of the panic but got: %+v", o)
+			}
+		}},
+		{"skip", func(t *testing.T, _ TC, _ Outcome[int]) {
+			t.Error("expected SkipNow to stop the scenario")
+		}},
+	} {
+		WTOutcome(TC{tc.mode}, "w", act, "t", tc.thenF).New(t)(t)
+	}
+}
+
+func Test_outcomeAct(t *testing.T) {
+	t.Parallel()
+
+	if outcomeAct[int, int](nil) != nil {
+		t.Error("expected a nil when function to stay nil")
+	}
+}
+
+func Test_expectOutcome(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	errMismatch := errors.New("mismatch")
+
+	value := Outcome[int]{Value: 1}
+	failed := Outcome[int]{Err: errBoom}
+	panicked := Outcome[int]{Panic: "kaboom", Stack: []byte("stack")}
+
+	tests := []struct {
+		name   string
+		assert func(assertT)
+		format string
+	}{
+		{"success", func(t assertT) { expectSuccess(t, value) }, ""},
+		{"success with error", func(t assertT) { expectSuccess(t, failed) }, "expected no error but got: %v"},
+		{"success with panic", func(t assertT) { expectSuccess(t, panicked) }, "expected no panic but got: %v\n\n%s"},
+		{"value", func(t assertT) { expectValue(t, value, 1) }, ""},
+		{"other value", func(t assertT) { expectValue(t, value, 2) }, "expected value %#v but got %#v"},
+		{"value with error", func(t assertT) { expectValue(t, failed, 0) }, "expected no error but got: %v"},
+		{"panic", func(t assertT) { expectPanic(t, panicked, nil) }, ""},
+		{"matching panic", func(t assertT) { expectPanic(t, panicked, func(any) error { return nil }) }, ""},
+		{"mismatched panic", func(t assertT) { expectPanic(t, panicked, func(any) error { return errMismatch }) }, "expected a matching panic but got: %v: %v"},
+		{"no panic", func(t assertT) { expectPanic(t, value, nil) }, "expected a panic but got value %#v and error: %v"},
+		{"failure with panic", func(t assertT) { expectReturned(t, panicked) }, "expected no panic but got: %v\n\n%s"},
+	}
+
+	for _, tc := range tests {
+		mt := &mT{}
+		tc.assert(mt)
+
+		if tc.format == "" {
+			if mt.Failed() {
+				t.Errorf("%s: expected no failure but got %+v", tc.name, mt.fatalfCalls)
+			}
+			continue
+		}
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != tc.format {
+			t.Errorf("%s: expected exactly one fatalf call with format %q but got %+v", tc.name, tc.format, mt.fatalfCalls)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tbdd
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// isParallelField is the index of the field of testing.T recording whether
+// t.Parallel was called, or nil if this version of the testing package has
+// no such field.
+var isParallelField = sync.OnceValue(func() []int {
+	f, ok := reflect.TypeFor[testing.T]().FieldByName("isParallel")
+	if !ok || f.Type.Kind() != reflect.Bool {
+		return nil
+	}
+
+	return f.Index
+})
+
+// parallelUnknownWarned is set once isParallel has warned that whether
+// t.Parallel was called cannot be determined.
+var parallelUnknownWarned atomic.Bool
+
+// isParallel reports whether t.Parallel has been called on t. It always
+// reports false when that cannot be determined, warning on t the first time,
+// as Lifecycles which are not ParallelSafe are then not checked for calling
+// it.
+func isParallel(t *testing.T) bool {
+	if t == nil {
+		return false
+	}
+
+	i := isParallelField()
+	if i == nil {
+		if !parallelUnknownWarned.Swap(true) {
+			t.Helper()
+
+			warn(t, Warning{Message: "tbdd: this version of the testing package does not record whether t.Parallel was called, " +
+				"so phases of Lifecycles which are not ParallelSafe are not checked for calling it"})
+		}
+
+		return false
+	}
+
+	return reflect.ValueOf(t).Elem().FieldByIndex(i).Bool()
+}
+
+// errorfT is the subset of *testing.T that reporting parallel misuse
+// depends on.
+type errorfT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// parallelMisuse fails t because the named phase of a Lifecycle which is not
+// ParallelSafe called t.Parallel.
+func parallelMisuse(t errorfT, phase string) {
+	t.Helper()
+
+	t.Errorf("tbdd: the %s phase called t.Parallel, which paused the scenario until its parent test returned, "+
+		"so it resumed after any teardown the parent deferred and any fixtures it shares were released; "+
+		"call t.Parallel before running the Lifecycle instead, or set ParallelSafe if the scenario shares nothing with its parent", phase)
+}
@@ -0,0 +1,114 @@
+package tbdd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_parallelMisuse(t *testing.T) {
+	parallel := func(t *testing.T, _ *int) {
+		t.Parallel()
+	}
+	act := func(*testing.T, int) {}
+	then := func(*testing.T, int) {}
+
+	if os.Getenv("TBDD_PARALLEL_HELPER") == "1" {
+		GWTN(0, "a parallel given", parallel, "acting", act, "it passes", then).New(t)(t)
+		WTN(0, "acting in parallel", func(t *testing.T, _ int) {
+			t.Parallel()
+		}, "it passes", then).New(t)(t)
+		return
+	}
+
+	// lifecycles which are parallel safe may call t.Parallel
+	GWTN(0, "a parallel given", parallel, "acting", act, "it passes", then).With(
+		WithParallelSafe[int, struct{}](),
+	).New(t)(t)
+
+	if isParallel(nil) {
+		t.Error("expected a nil T to not be parallel")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_PARALLEL_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/given_a_parallel_given ",
+		"tbdd: the given phase called t.Parallel",
+		"--- FAIL: " + t.Name() + "/when_acting_in_parallel ",
+		"tbdd: the act phase called t.Parallel",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func Test_isParallel(t *testing.T) {
+	// the check of ParallelSafe depends on this field of testing.T
+	if isParallelField() == nil {
+		t.Fatal("expected testing.T to record whether t.Parallel was called in a bool field named isParallel")
+	}
+
+	// the parallel subtest resumes once the group returns
+	var before, after bool
+	t.Run("group", func(t *testing.T) {
+		t.Run("parallel", func(t *testing.T) {
+			before = isParallel(t)
+			t.Parallel()
+			after = isParallel(t)
+		})
+	})
+
+	if before || !after {
+		t.Errorf("expected t.Parallel to be detected but got %v before and %v after calling it", before, after)
+	}
+
+	// without the field, the first lookup warns
+	orig, origWarned := isParallelField, parallelUnknownWarned.Load()
+	defer func() {
+		isParallelField = orig
+		parallelUnknownWarned.Store(origWarned)
+	}()
+
+	isParallelField = func() []int { return nil }
+	parallelUnknownWarned.Store(false)
+
+	n := len(Warnings())
+	if isParallel(t) || isParallel(t) {
+		t.Error("expected t to not be parallel when that cannot be determined")
+	}
+
+	if w := Warnings()[n:]; len(w) != 1 || !strings.HasPrefix(w[0].Message, "tbdd: this version of the testing package does not record whether t.Parallel was called") {
+		t.Errorf("expected one warning but got %+v", w)
+	}
+}
+
+type mErrorfT struct {
+	errors []string
+}
+
+func (m *mErrorfT) Helper() {}
+
+func (m *mErrorfT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func Test_parallelMisuse(t *testing.T) {
+	t.Parallel()
+
+	mt := &mErrorfT{}
+	parallelMisuse(mt, "given")
+
+	if len(mt.errors) != 1 || !strings.HasPrefix(mt.errors[0], "tbdd: the given phase called t.Parallel, which paused the scenario until its parent test returned") {
+		t.Errorf("unexpected errors: %q", mt.errors)
+	}
+}
@@ -0,0 +1,189 @@
+package tbdd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StepPattern is a compiled step description containing placeholders such
+// as "user {name} deposits {amount:int}".
+//
+// Each placeholder is written as {name} or {name:type} where name is a Go
+// style identifier and type is one of:
+//
+//   - string (the default): one or more of any character, matched lazily
+//   - word: one or more non-space characters
+//   - int: a base 10 integer with an optional sign
+//   - float: a decimal floating point number with an optional sign and exponent
+//
+// Every other part of the pattern is matched literally and the whole
+// description must match. A "{" always starts a placeholder.
+type StepPattern struct {
+	pattern string
+	re      *regexp.Regexp
+	names   []string
+	types   []string
+}
+
+// StepArgs holds the values extracted from the placeholders of a
+// StepPattern.
+type StepArgs struct {
+	values map[string]any
+}
+
+var placeholderTypes = map[string]string{
+	"string": `(.+?)`,
+	"word":   `(\S+)`,
+	"int":    `([-+]?[0-9]+)`,
+	"float":  `([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)`,
+}
+
+var placeholderNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CompileStepPattern parses a step description pattern.
+func CompileStepPattern(pattern string) (*StepPattern, error) {
+	var sb strings.Builder
+	var names, types []string
+
+	sb.WriteByte('^')
+	for s := pattern; s != ""; {
+		i := strings.IndexByte(s, '{')
+		if i == -1 {
+			sb.WriteString(regexp.QuoteMeta(s))
+			break
+		}
+
+		sb.WriteString(regexp.QuoteMeta(s[:i]))
+		s = s[i+1:]
+
+		j := strings.IndexByte(s, '}')
+		if j == -1 {
+			return nil, errors.New("tbdd: step pattern has an unterminated placeholder: " + pattern)
+		}
+
+		name, typ, _ := strings.Cut(s[:j], ":")
+		s = s[j+1:]
+
+		if typ == "" {
+			typ = "string"
+		}
+
+		if !placeholderNameRE.MatchString(name) {
+			return nil, errors.New("tbdd: step pattern has an invalid placeholder name " + strconv.Quote(name) + ": " + pattern)
+		}
+
+		expr, ok := placeholderTypes[typ]
+		if !ok {
+			return nil, errors.New("tbdd: step pattern has an unknown placeholder type " + strconv.Quote(typ) + ": " + pattern)
+		}
+
+		for _, v := range names {
+			if v == name {
+				return nil, errors.New("tbdd: step pattern has a duplicate placeholder name " + strconv.Quote(name) + ": " + pattern)
+			}
+		}
+
+		names = append(names, name)
+		types = append(types, typ)
+		sb.WriteString(expr)
+	}
+	sb.WriteByte('$')
+
+	return &StepPattern{
+		pattern: pattern,
+		re:      regexp.MustCompile(sb.String()),
+		names:   names,
+		types:   types,
+	}, nil
+}
+
+// MustCompileStepPattern is like CompileStepPattern but panics if the
+// pattern cannot be parsed.
+func MustCompileStepPattern(pattern string) *StepPattern {
+	p, err := CompileStepPattern(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return p
+}
+
+// String returns the source pattern.
+func (p *StepPattern) String() string {
+	return p.pattern
+}
+
+// Match reports whether description matches the pattern and, if so, returns
+// the extracted placeholder values.
+func (p *StepPattern) Match(description string) (StepArgs, bool) {
+	m := p.re.FindStringSubmatch(description)
+	if m == nil {
+		return StepArgs{}, false
+	}
+
+	values := make(map[string]any, len(p.names))
+	for i, name := range p.names {
+		s := m[i+1]
+
+		switch p.types[i] {
+		case "int":
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return StepArgs{}, false
+			}
+			values[name] = v
+		case "float":
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return StepArgs{}, false
+			}
+			values[name] = v
+		default:
+			values[name] = s
+		}
+	}
+
+	return StepArgs{values}, true
+}
+
+// String returns the value of a string or word placeholder.
+//
+// It panics if the placeholder does not exist or is of a different type.
+func (a StepArgs) String(name string) string {
+	return stepArg[string](a, name, "string")
+}
+
+// Int returns the value of an int placeholder.
+//
+// It panics if the placeholder does not exist or is of a different type.
+func (a StepArgs) Int(name string) int {
+	return stepArg[int](a, name, "int")
+}
+
+// Float returns the value of a float placeholder.
+//
+// It panics if the placeholder does not exist or is of a different type.
+func (a StepArgs) Float(name string) float64 {
+	return stepArg[float64](a, name, "float")
+}
+
+// Len returns the number of extracted placeholder values.
+func (a StepArgs) Len() int {
+	return len(a.values)
+}
+
+func stepArg[V any](a StepArgs, name, typ string) V {
+	raw, ok := a.values[name]
+	if !ok {
+		panic("tbdd.StepArgs: no placeholder named " + strconv.Quote(name))
+	}
+
+	v, ok := raw.(V)
+	if !ok {
+		panic("tbdd.StepArgs: placeholder " + strconv.Quote(name) + " is not of type " + typ)
+	}
+
+	return v
+}
@@ -0,0 +1,106 @@
+package tbdd
+
+import (
+	"testing"
+)
+
+func TestStepPattern(t *testing.T) {
+	t.Parallel()
+
+	p := MustCompileStepPattern("user {name} deposits {amount:int} ({rate:float}) into {account:word}.")
+
+	if p.String() != "user {name} deposits {amount:int} ({rate:float}) into {account:word}." {
+		t.Errorf("unexpected pattern string: %s", p.String())
+	}
+
+	{
+		args, ok := p.Match("user alice smith deposits -100 (1.5e2) into savings-1.")
+		if !ok {
+			t.Fatal("expected description to match")
+		}
+
+		if args.Len() != 4 || args.String("name") != "alice smith" || args.Int("amount") != -100 || args.Float("rate") != 150 || args.String("account") != "savings-1" {
+			t.Errorf("unexpected args: %v", args.values)
+		}
+	}
+
+	for _, s := range []string{
+		"user alice deposits ten (1) into savings.",
+		"user alice deposits 10 (1) into two words.",
+		"user alice deposits 10 (1) into savings",
+		"user alice deposits 99999999999999999999 (1) into savings.",
+		"user alice deposits 10 (1e999) into savings.",
+	} {
+		if _, ok := p.Match(s); ok {
+			t.Errorf("expected %q to not match", s)
+		}
+	}
+}
+
+func TestCompileStepPattern(t *testing.T) {
+	t.Parallel()
+
+	if p, err := CompileStepPattern("no placeholders (at all)"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if args, ok := p.Match("no placeholders (at all)"); !ok || args.Len() != 0 {
+		t.Error("expected literal pattern to match itself")
+	}
+
+	for _, v := range []struct {
+		pattern, exp string
+	}{
+		{"user {name", "tbdd: step pattern has an unterminated placeholder: user {name"},
+		{"user {}", `tbdd: step pattern has an invalid placeholder name "": user {}`},
+		{"user {1x}", `tbdd: step pattern has an invalid placeholder name "1x": user {1x}`},
+		{"user {n:bool}", `tbdd: step pattern has an unknown placeholder type "bool": user {n:bool}`},
+		{"{a} and {a}", `tbdd: step pattern has a duplicate placeholder name "a": {a} and {a}`},
+	} {
+		_, err := CompileStepPattern(v.pattern)
+		if err == nil || err.Error() != v.exp {
+			t.Errorf("expected error '%s' but got '%v'", v.exp, err)
+		}
+	}
+
+	{
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			MustCompileStepPattern("{")
+		}()
+
+		if exp := "tbdd: step pattern has an unterminated placeholder: {"; r != exp {
+			t.Errorf("expected panic '%s' but got '%v'", exp, r)
+		}
+	}
+}
+
+func TestStepArgs_panics(t *testing.T) {
+	t.Parallel()
+
+	args, _ := MustCompileStepPattern("{n:int}").Match("1")
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { args.String("missing") }, `tbdd.StepArgs: no placeholder named "missing"`},
+		{func() { args.String("n") }, `tbdd.StepArgs: placeholder "n" is not of type string`},
+		{func() { args.Float("n") }, `tbdd.StepArgs: placeholder "n" is not of type float`},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
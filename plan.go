@@ -0,0 +1,196 @@
+package tbdd
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Plan describes the scenarios a Lifecycle would run within a test without
+// running any of them, for use by documentation generators, editor
+// integrations, and custom runners.
+type Plan struct {
+	// Test is the name of the test the plan was made for.
+	Test string
+	// Err holds the configuration problems reported by Validate, if any.
+	Err error
+	// Basis is the scenario of the basis test case.
+	Basis ScenarioPlan
+	// Variants are the scenarios of the test case variants in the order
+	// they would run, including those skipped with SkipTC.
+	Variants []ScenarioPlan
+}
+
+// ScenarioPlan describes one scenario of a Plan.
+//
+// Its descriptions are those of the Lifecycle, as replaced by Describe when
+// it is set. Descriptions that Arrange replaces at run time are not known
+// until it runs.
+type ScenarioPlan struct {
+	// ID is the ScenarioResult.ID the scenario would record when run by New,
+	// or by NewI for a plan made by PlanI.
+	ID                string
+	Given, When, Then string
+	// Kind is the variant kind, or empty for the basis test case.
+	Kind     string
+	Priority Priority
+	// Source is the "file:line" of the code which defined the scenario; see
+	// Lifecycle.Source.
+	Source string
+	// Seed is the seed the scenario would receive; see Lifecycle.Seed.
+	Seed int64
+	// Skip explains why the scenario would not run, such as "SkipTC",
+	// "excluded by -tbdd.filter", or the reason given with SkipUntil; it is
+	// empty when the scenario would run.
+	Skip string
+	// Err is the *ConfigError which would fail the scenario, such as a
+	// variant without a Kind, if any.
+	Err error
+}
+
+// Scenario returns the descriptions of s as a single sentence in the form
+// of ScenarioResult.Scenario.
+func (s ScenarioPlan) Scenario() string {
+	return s.result().Scenario()
+}
+
+func (s ScenarioPlan) result() ScenarioResult {
+	return ScenarioResult{Given: s.Given, When: s.When, Then: s.Then, Kind: s.Kind}
+}
+
+// Plan returns the scenarios the Lifecycle would run within t, calling only
+// its Variants, Variants2, and Describe functions, along with CloneTC and
+// DefaultTC to prepare the test cases passed to Describe when it has
+// defaults.
+//
+// Like running the Lifecycle, planning stops at the first variant without a
+// Kind.
+func (b Lifecycle[T, R]) Plan(t *testing.T) Plan {
+	t.Helper()
+
+	return b.PlanI(t, -1)
+}
+
+// PlanI is Plan for the Lifecycle run by NewI with the same index in a table
+// driven test, whose scenario IDs include the index.
+func (b Lifecycle[T, R]) PlanI(t *testing.T, tableTestIndex int) Plan {
+	t.Helper()
+
+	if b.Source == "" {
+		b.Source = definedAt()
+	}
+
+	var prefix string
+	if tableTestIndex >= 0 {
+		prefix = strconv.Itoa(tableTestIndex) + "/"
+	}
+
+	p := Plan{Test: t.Name(), Err: b.Validate()}
+	p.Basis = b.planScenario(t, prefix, b.TC, b.CloneTC, -1, "", b.Priority.orNormal())
+
+	variantBases.Store(t, b.Seed)
+	defer variantBases.Delete(t)
+
+	i := -1
+	if b.Variants != nil {
+		for v := range b.Variants(t, b.TC) {
+			i++
+
+			s, ok := b.planVariant(t, prefix, i, v)
+			p.Variants = append(p.Variants, s)
+			if !ok {
+				return p
+			}
+		}
+	}
+
+	if b.Variants2 != nil {
+		for v, err := range b.Variants2(t, b.TC) {
+			i++
+
+			if err != nil {
+				p.Variants = append(p.Variants, ScenarioPlan{Err: &ConfigError{"Variants2", "", i, err}})
+				continue
+			}
+
+			s, ok := b.planVariant(t, prefix, i, v)
+			p.Variants = append(p.Variants, s)
+			if !ok {
+				return p
+			}
+		}
+	}
+
+	return p
+}
+
+// planVariant returns the plan of the variant at index i and whether
+// planning may continue.
+func (b Lifecycle[T, R]) planVariant(t *testing.T, prefix string, i int, v TestVariant[T]) (ScenarioPlan, bool) {
+	t.Helper()
+
+	if v.Kind = kindOf(b.InferKind, b.TC, v); v.Kind == "" {
+		return ScenarioPlan{Err: &ConfigError{"Kind", "", i, ErrEmptyVariantKind}}, false
+	}
+
+	priority := v.Priority
+	if priority == 0 {
+		priority = b.Priority.orNormal()
+	}
+
+	clone := b.CloneTC
+	if v.SkipCloneTC {
+		clone = nil
+	}
+
+	s := b.planScenario(t, prefix, v.TC, clone, i, v.Kind, priority)
+	if v.SkipTC {
+		s.Skip = "SkipTC"
+	}
+
+	return s, true
+}
+
+func (b Lifecycle[T, R]) planScenario(t *testing.T, prefix string, tc T, clone func(T) T, index int, kind string, priority Priority) ScenarioPlan {
+	t.Helper()
+
+	s := ScenarioPlan{Given: b.Given, When: b.When, Then: b.Then, Kind: kind, Priority: priority, Source: b.Source, Seed: scenarioSeed(b.Seed, kind)}
+
+	// Describe sees the test case the scenario would run, whose defaults only
+	// its clone receives
+	if f := b.Describe; f != nil && len(b.DefaultTC) > 0 {
+		if clone != nil {
+			if err := cloneInto(&tc, clone); err != nil {
+				s.Err = &ConfigError{"CloneTC", "", index, err}
+			}
+		}
+
+		if s.Err == nil {
+			applyDefaults(b.DefaultTC, &tc)
+		}
+	}
+
+	if f := b.Describe; f != nil && s.Err == nil {
+		r := f(t, Describe[T]{tc, b.Given, b.When, b.Then, s.Seed})
+
+		s.When = r.When
+		s.Then = r.Then
+	}
+
+	s.ID = scenarioID(t.Name(), prefix, s.Scenario())
+
+	switch {
+	case !selected(s.result()):
+		s.Skip = "excluded by -tbdd.filter"
+	case belowMinPriority(priority):
+		s.Skip = "excluded by -tbdd.min-priority " + config.minPriority.String()
+	case !config.shard.Contains(s.ID):
+		s.Skip = "excluded by -tbdd.shard " + config.shard.String()
+	case !config.impact.selects(s.ID):
+		s.Skip = "excluded by -tbdd.changed"
+	case !b.SkipUntil.IsZero() && time.Now().Before(b.SkipUntil):
+		s.Skip = skipUntilMessage(b.SkipUntil, b.SkipUntilReason)
+	}
+
+	return s
+}
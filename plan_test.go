@@ -0,0 +1,171 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_Plan(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	var acted int
+	b := GWT(
+		"ann",
+		"a user", func(*testing.T, *string) {},
+		"they log in", func(*testing.T, string) string {
+			acted++
+			return ""
+		},
+		"they are welcomed", func(*testing.T, string, string) {},
+	).With(
+		WithDescribe[string, string](func(_ *testing.T, d Describe[string]) DescribeResponse {
+			return DescribeResponse{When: d.TC + " logs in", Then: d.Then}
+		}),
+		WithVariants[string, string](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+			return func(yield func(TestVariant[string]) bool) {
+				_ = yield(TestVariant[string]{Kind: "admin", TC: "bob"}) &&
+					yield(TestVariant[string]{Kind: "guest", TC: "eve", SkipTC: true})
+			}
+		}),
+		WithVariants2[string, string](func(*testing.T, string) iter.Seq2[TestVariant[string], error] {
+			return func(yield func(TestVariant[string], error) bool) {
+				_ = yield(TestVariant[string]{}, errors.New("boom")) &&
+					yield(TestVariant[string]{Kind: "locked", TC: "mal"}, nil)
+			}
+		}),
+	)
+
+	p := b.Plan(t)
+	if acted != 0 {
+		t.Fatal("expected planning to not run any scenario")
+	}
+
+	if p.Test != t.Name() || p.Err != nil || len(p.Variants) != 4 {
+		t.Fatalf("unexpected plan: %+v", p)
+	}
+
+	var sentences []string
+	for _, s := range append([]ScenarioPlan{p.Basis}, p.Variants...) {
+		sentences = append(sentences, s.Scenario()+"|"+s.Skip)
+
+		if s.Err == nil && (s.Source == "" || s.Source != b.Source) {
+			t.Errorf("expected the scenario to be defined at '%s' but got '%s'", b.Source, s.Source)
+		}
+	}
+
+	exp := []string{
+		"given a user when ann logs in then they are welcomed|",
+		"admin: given a user when bob logs in then they are welcomed|",
+		"guest: given a user when eve logs in then they are welcomed|SkipTC",
+		"when  then |",
+		"locked: given a user when mal logs in then they are welcomed|",
+	}
+	if strings.Join(sentences, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected scenarios:\n%s\nbut got:\n%s", strings.Join(exp, "\n"), strings.Join(sentences, "\n"))
+	}
+
+	var cerr *ConfigError
+	if !errors.As(p.Variants[2].Err, &cerr) || cerr.Field != "Variants2" || cerr.VariantIndex != 2 {
+		t.Errorf("expected the generator error to be planned but got %v", p.Variants[2].Err)
+	}
+
+	// the planned IDs are those recorded when running
+	b.Variants2 = func(*testing.T, string) iter.Seq2[TestVariant[string], error] {
+		return func(yield func(TestVariant[string], error) bool) {
+			yield(TestVariant[string]{Kind: "locked", TC: "mal"}, nil)
+		}
+	}
+
+	f := b.New(t)
+	f(t)
+
+	ids := map[string]string{}
+	for _, r := range Results() {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			ids[r.Kind] = r.ID
+		}
+	}
+
+	if ids[""] != p.Basis.ID || ids["admin"] != p.Variants[0].ID || ids["locked"] != p.Variants[3].ID {
+		t.Errorf("expected the recorded IDs %v to match the plan %+v", ids, p)
+	}
+
+	// as are those of a table driven test, which include its index
+	pi := b.PlanI(t, 3)
+	if pi.Basis.ID == p.Basis.ID {
+		t.Errorf("expected the index to change the planned ID %s", pi.Basis.ID)
+	}
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	b.NewI(t, 3)(t)
+
+	clear(ids)
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/3/") {
+			ids[r.Kind] = r.ID
+		}
+	}
+
+	if len(ids) != 3 || ids[""] != pi.Basis.ID || ids["admin"] != pi.Variants[0].ID || ids["locked"] != pi.Variants[2].ID {
+		t.Errorf("expected the recorded IDs %v to match the plan %+v", ids, pi)
+	}
+
+	//
+	// skips, filters, and configuration errors are planned
+	//
+
+	config.filter = regexp.MustCompile("admin")
+
+	b2 := WTN(
+		0,
+		"the behavior runs", func(*testing.T, int) {},
+		"it succeeds", func(*testing.T, int) {},
+	).With(
+		WithSkipUntil[int, struct{}](time.Now().Add(time.Hour), "flaky"),
+		WithVariants[int, struct{}](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+			return func(yield func(TestVariant[int]) bool) {
+				_ = yield(TestVariant[int]{Kind: "admin"}) &&
+					yield(TestVariant[int]{}) &&
+					yield(TestVariant[int]{Kind: "unreachable"})
+			}
+		}),
+		WithVariants2[int, struct{}](func(*testing.T, int) iter.Seq2[TestVariant[int], error] {
+			t.Error("expected planning to stop at the variant without a Kind")
+			return nil
+		}),
+	)
+	b2.Then = ""
+
+	p = b2.Plan(t)
+	if !errors.Is(p.Err, ErrEmptyThen) {
+		t.Errorf("expected the configuration problems of the Lifecycle but got %v", p.Err)
+	}
+
+	if p.Basis.Skip != "excluded by -tbdd.filter" {
+		t.Errorf("expected the basis to be excluded but got %q", p.Basis.Skip)
+	}
+
+	if len(p.Variants) != 2 || !strings.HasPrefix(p.Variants[0].Skip, "skipped until ") || !strings.HasSuffix(p.Variants[0].Skip, ": flaky") || !errors.Is(p.Variants[1].Err, ErrEmptyVariantKind) {
+		t.Errorf("unexpected variants: %+v", p.Variants)
+	}
+
+	b2.Variants = nil
+	b2.Variants2 = func(*testing.T, int) iter.Seq2[TestVariant[int], error] {
+		return func(yield func(TestVariant[int], error) bool) {
+			_ = yield(TestVariant[int]{}, nil) && yield(TestVariant[int]{Kind: "unreachable"}, nil)
+		}
+	}
+
+	if p = b2.Plan(t); len(p.Variants) != 1 || !errors.Is(p.Variants[0].Err, ErrEmptyVariantKind) {
+		t.Errorf("expected planning to stop at the variant without a Kind but got %+v", p.Variants)
+	}
+}
@@ -0,0 +1,102 @@
+package tbdd
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Priority ranks scenarios by how important they are to run, so one set of
+// definitions can serve both quick runs, such as on every commit, and
+// exhaustive ones, such as nightly, by passing -tbdd.min-priority.
+type Priority uint8
+
+const (
+	// PriorityLow is a scenario which is only worth running exhaustively,
+	// such as a generated edge case.
+	PriorityLow Priority = iota + 1
+	// PriorityNormal is the priority of scenarios which do not set one.
+	PriorityNormal
+	// PriorityHigh is a scenario covering a primary behavior.
+	PriorityHigh
+	// PriorityCritical is a smoke scenario which should run on every
+	// change.
+	PriorityCritical
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	}
+
+	return "Priority(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText encodes p as its String form.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// parsePriority returns the Priority named s.
+func parsePriority(s string) (Priority, error) {
+	for p := PriorityLow; p <= PriorityCritical; p++ {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+
+	return 0, errors.New(strconv.Quote(s) + " is not low, normal, high, or critical")
+}
+
+// orNormal returns p, or PriorityNormal when p is unset.
+func (p Priority) orNormal() Priority {
+	if p == 0 {
+		return PriorityNormal
+	}
+
+	return p
+}
+
+// belowMinPriority reports whether a scenario of priority p is excluded by
+// the -tbdd.min-priority flag.
+func belowMinPriority(p Priority) bool {
+	return p < config.minPriority
+}
+
+// prioritized is implemented by the scenarios which have a Priority, so a
+// Group under a -tbdd.budget can run the most important first.
+type prioritized interface {
+	priority() Priority
+}
+
+func (b Lifecycle[T, R]) priority() Priority {
+	return b.Priority.orNormal()
+}
+
+// priority returns the highest Priority of the members of g.
+func (g Group) priority() Priority {
+	var p Priority
+	for _, s := range g.scenarios {
+		if s, ok := s.(prioritized); ok {
+			p = max(p, s.priority())
+		}
+	}
+
+	return p.orNormal()
+}
+
+// priorityOf returns the Priority of s, which is PriorityNormal when it has
+// none.
+func priorityOf(s Scenario) Priority {
+	if s, ok := s.(prioritized); ok {
+		return s.priority()
+	}
+
+	return PriorityNormal
+}
@@ -0,0 +1,106 @@
+package tbdd
+
+import (
+	"iter"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPriority_String(t *testing.T) {
+	t.Parallel()
+
+	for p, exp := range map[Priority]string{
+		PriorityLow:      "low",
+		PriorityNormal:   "normal",
+		PriorityHigh:     "high",
+		PriorityCritical: "critical",
+		Priority(9):      "Priority(9)",
+	} {
+		Equal(t, p.String(), exp)
+
+		if got, err := parsePriority(exp); err == nil {
+			Equal(t, got, p)
+		}
+	}
+
+	_, err := parsePriority("urgent")
+	True(t, err != nil && err.Error() == `"urgent" is not low, normal, high, or critical`)
+}
+
+func TestLifecycle_minPriority(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.minPriority = PriorityHigh
+
+	var ran []string
+	record := func(t *testing.T, _ int) {
+		ran = append(ran, t.Name()[len("TestLifecycle_minPriority/"):])
+	}
+
+	b := WTN(0, "the basis runs", record, "it passes", func(*testing.T, int) {}).With(
+		WithVariants[int, struct{}](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+			return func(yield func(TestVariant[int]) bool) {
+				_ = yield(TestVariant[int]{Kind: "smoke", Priority: PriorityCritical}) &&
+					yield(TestVariant[int]{Kind: "edge", Priority: PriorityLow})
+			}
+		}),
+	)
+	b.New(t)(t)
+
+	g := GWTN(0, "a primary behavior", func(*testing.T, *int) {
+		ran = append(ran, "given")
+	}, "it runs", record, "it passes", func(*testing.T, int) {}).With(WithPriority[int, struct{}](PriorityHigh))
+	g.New(t)(t)
+
+	g.Priority = PriorityLow
+	g.New(t)(t)
+
+	Equal(t, ran, []string{
+		"smoke/when_the_basis_runs",
+		"given",
+		"given_a_primary_behavior/when_it_runs",
+	})
+
+	p := b.Plan(t)
+	Equal(t, p.Basis.Priority, PriorityNormal)
+	Equal(t, p.Basis.Skip, "excluded by -tbdd.min-priority high")
+	Equal(t, p.Variants[0].Skip, "")
+	Equal(t, p.Variants[1].Priority, PriorityLow)
+}
+
+func TestGroup_budgetPriority(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	var log []string
+	step := func(name string, p Priority) Lifecycle[int, struct{}] {
+		b := WTN(0, name, func(*testing.T, int) {
+			log = append(log, name)
+		}, "it is recorded", func(*testing.T, int) {})
+		b.Priority = p
+
+		return b
+	}
+
+	g := Independent(
+		step("low", PriorityLow),
+		step("normal", 0),
+		Independent(step("nested", PriorityCritical)),
+		step("high", PriorityHigh),
+	)
+
+	t.Run("unbudgeted", g.New(t))
+
+	config.budget = time.Hour
+	config.deadline = time.Now().Add(config.budget)
+
+	t.Run("budgeted", g.New(t))
+
+	Equal(t, strings.Join(log, " "), "low normal nested high nested high normal low")
+}
@@ -0,0 +1,148 @@
+package tbdd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// ActProfileEnv names the environment variable which enables the profiles
+// configured by WithActProfile: "cpu" captures a CPU profile and "heap" a
+// heap profile. Profiling is disabled when it is unset or has any other
+// value.
+const ActProfileEnv = "TBDD_PROFILE_ACT"
+
+// actProfiler captures pprof profiles of Act for a Lifecycle.
+type actProfiler struct {
+	// kind is "cpu" or "heap".
+	kind string
+	slow time.Duration
+}
+
+// WithActProfile captures a pprof profile scoped to the Act of every
+// scenario when the ActProfileEnv environment variable enables it. Profiles
+// of scenarios which fail, or whose Act takes at least slow, are written to
+// their Artifacts as act.cpu.pprof or act.heap.pprof and the path is
+// logged alongside the failure; a slow scenario's artifacts are kept even
+// when it passes. A zero slow only keeps profiles of failing scenarios.
+//
+// Only one CPU profile can be captured by a process at a time, so scenarios
+// whose Act overlaps with another being profiled, such as in parallel tests,
+// log that their profile was not captured.
+//
+// Heap profiles describe the whole process since it started, so two are
+// taken, each after forcing a garbage collection: act.heap.base.pprof just
+// before Act and act.heap.pprof once it returns. Passing the first to the
+// -base flag of go tool pprof shows what was allocated during Act, by it and
+// by any other goroutines running at the time.
+func WithActProfile[T, R any](slow time.Duration) Option[T, R] {
+	return func(b *Lifecycle[T, R]) {
+		switch kind := os.Getenv(ActProfileEnv); kind {
+		case "cpu", "heap":
+			b.actProfiler = &actProfiler{kind, slow}
+		default:
+			b.actProfiler = nil
+		}
+	}
+}
+
+// profileT is the subset of *testing.T that Act profiling depends on.
+type profileT interface {
+	Cleanup(func())
+	Failed() bool
+	Logf(format string, args ...any)
+}
+
+// profile calls act, capturing a profile of it which is written to art once
+// t completes if it failed or act was slow.
+func (p *actProfiler) profile(t profileT, art *Artifacts, act func()) {
+	var base, buf bytes.Buffer
+	var elapsed time.Duration
+
+	var cpuErr error
+	if p.kind == "cpu" {
+		cpuErr = pprof.StartCPUProfile(&buf)
+	}
+
+	t.Cleanup(func() {
+		slow := p.slow > 0 && elapsed >= p.slow
+		if !slow && !t.Failed() {
+			return
+		}
+
+		if cpuErr != nil {
+			t.Logf("tbdd: Act CPU profile not captured: %v", cpuErr)
+			return
+		}
+
+		dir := art.create(nopFatalT{t})
+		if dir == "" {
+			return
+		}
+
+		path := filepath.Join(dir, "act."+p.kind+".pprof")
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Logf("tbdd: Act %s profile not written: %v", p.kind, err)
+			return
+		}
+
+		var basePath string
+		if p.kind == "heap" {
+			basePath = filepath.Join(dir, "act.heap.base.pprof")
+			if err := os.WriteFile(basePath, base.Bytes(), 0o644); err != nil {
+				t.Logf("tbdd: Act %s profile not written: %v", p.kind, err)
+				return
+			}
+		}
+
+		if slow {
+			art.Keep()
+		}
+
+		if basePath != "" {
+			t.Logf("tbdd: Act took %s; heap profiles written to %s, compare them with go tool pprof -base %s %s", elapsed, dir, basePath, path)
+			return
+		}
+
+		t.Logf("tbdd: Act took %s; %s profile written to %s", elapsed, p.kind, path)
+	})
+
+	if p.kind == "heap" {
+		runtime.GC()
+		// writes to a bytes.Buffer cannot fail
+		_ = pprof.WriteHeapProfile(&base)
+	}
+
+	start := time.Now()
+
+	// the profile is stopped even when act ends the test
+	defer func() {
+		elapsed = time.Since(start)
+
+		switch {
+		case p.kind == "heap":
+			runtime.GC()
+			// writes to a bytes.Buffer cannot fail
+			_ = pprof.WriteHeapProfile(&buf)
+		case cpuErr == nil:
+			pprof.StopCPUProfile()
+		}
+	}()
+
+	act()
+}
+
+// nopFatalT reports the failures of assertT calls as logs, for use once a
+// test can no longer be failed.
+type nopFatalT struct {
+	t interface{ Logf(string, ...any) }
+}
+
+func (nopFatalT) Helper() {}
+
+func (t nopFatalT) Fatalf(format string, args ...any) {
+	t.t.Logf("tbdd: "+format, args...)
+}
@@ -0,0 +1,177 @@
+package tbdd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"testing"
+	"time"
+)
+
+var _ profileT = (*testing.T)(nil)
+
+type mProfileT struct {
+	mFinalizeT
+	cleanups []func()
+}
+
+func (t *mProfileT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *mProfileT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestWithActProfile(t *testing.T) {
+	for env, exp := range map[string]*actProfiler{
+		"":     nil,
+		"mem":  nil,
+		"cpu":  {"cpu", time.Second},
+		"heap": {"heap", time.Second},
+	} {
+		t.Setenv(ActProfileEnv, env)
+
+		b := New[struct{}, struct{}](struct{}{}, WithActProfile[struct{}, struct{}](time.Second))
+		if (b.actProfiler == nil) != (exp == nil) || (exp != nil && *b.actProfiler != *exp) {
+			t.Errorf("expected %s=%q to configure %+v but got %+v", ActProfileEnv, env, exp, b.actProfiler)
+		}
+	}
+}
+
+func TestActProfiler_profile(t *testing.T) {
+	for _, v := range []struct {
+		name   string
+		p      actProfiler
+		failed bool
+		sleep  time.Duration
+		file   string
+		keep   bool
+	}{
+		{"slow cpu", actProfiler{"cpu", time.Nanosecond}, false, time.Millisecond, "act.cpu.pprof", true},
+		{"failed heap", actProfiler{"heap", time.Hour}, true, 0, "act.heap.pprof", false},
+		{"fast and passing", actProfiler{"cpu", time.Hour}, false, 0, "", false},
+		{"passing without a threshold", actProfiler{"heap", 0}, false, time.Millisecond, "", false},
+	} {
+		art := &Artifacts{root: t.TempDir(), test: "x"}
+		mt := &mProfileT{mFinalizeT: mFinalizeT{failed: v.failed}}
+
+		var ran bool
+		v.p.profile(mt, art, func() {
+			ran = true
+			time.Sleep(v.sleep)
+		})
+		mt.finish()
+
+		if !ran {
+			t.Errorf("%s: expected act to run", v.name)
+		}
+
+		if v.file == "" {
+			if art.dir != "" || len(mt.logs) != 0 {
+				t.Errorf("%s: expected no profile to be written but got %s %v", v.name, art.dir, mt.logs)
+			}
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(art.dir, v.file))
+		if err != nil || len(b) == 0 {
+			t.Errorf("%s: expected a profile to be written: %v", v.name, err)
+		}
+
+		exp := "tbdd: Act took %s; %s profile written to %s"
+		if v.p.kind == "heap" {
+			// with the profile Act started from, as heap profiles are cumulative
+			if b, err := os.ReadFile(filepath.Join(art.dir, "act.heap.base.pprof")); err != nil || len(b) == 0 {
+				t.Errorf("%s: expected a base profile to be written: %v", v.name, err)
+			}
+
+			exp = "tbdd: Act took %s; heap profiles written to %s, compare them with go tool pprof -base %s %s"
+		}
+
+		if len(mt.logs) != 1 || mt.logs[0] != exp || art.keep != v.keep {
+			t.Errorf("%s: expected the profile to be logged and kept=%v but got %v %v", v.name, v.keep, mt.logs, art.keep)
+		}
+	}
+}
+
+func TestActProfiler_profileErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &actProfiler{"cpu", 0}
+
+	for _, v := range []struct {
+		art *Artifacts
+		exp string
+	}{
+		{&Artifacts{root: file, test: "x"}, "tbdd: failed to clear artifact directory: %v"},
+		{&Artifacts{dir: file}, "tbdd: Act %s profile not written: %v"},
+	} {
+		mt := &mProfileT{mFinalizeT: mFinalizeT{failed: true}}
+		p.profile(mt, v.art, func() {})
+		mt.finish()
+
+		if len(mt.logs) != 1 || mt.logs[0] != v.exp {
+			t.Errorf("expected log '%s' but got %v", v.exp, mt.logs)
+		}
+	}
+
+	// a CPU profile already in progress prevents capturing another
+	if err := pprof.StartCPUProfile(&bytes.Buffer{}); err != nil {
+		t.Skip("a CPU profile is already in progress:", err)
+	}
+	defer pprof.StopCPUProfile()
+
+	mt := &mProfileT{mFinalizeT: mFinalizeT{failed: true}}
+	p.profile(mt, &Artifacts{}, func() {})
+	mt.finish()
+
+	if len(mt.logs) != 1 || mt.logs[0] != "tbdd: Act CPU profile not captured: %v" {
+		t.Errorf("expected the profile to not be captured but got %v", mt.logs)
+	}
+}
+
+func TestLifecycle_actProfile(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.artifacts = t.TempDir()
+	t.Setenv(ActProfileEnv, "heap")
+
+	f := WTN(
+		struct{}{},
+		"a slow behavior runs", func(*testing.T, struct{}) {
+			time.Sleep(time.Millisecond)
+		},
+		"its profile is kept", func(*testing.T, struct{}) {},
+	).With(WithActProfile[struct{}, struct{}](time.Nanosecond)).New(t)
+	f(t)
+
+	path := filepath.Join(config.artifacts, "TestLifecycle_actProfile", "basis-"+scenarioID(t.Name(), "", "when a slow behavior runs then its profile is kept"), "act.heap.pprof")
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the profile of the slow scenario to be kept: %v", err)
+	}
+
+	// mocked runs are not profiled
+	b := WTN(
+		struct{}{},
+		"a behavior runs", func(*testing.T, struct{}) {},
+		"it is not profiled", func(*testing.T, struct{}) {},
+	).With(WithActProfile[struct{}, struct{}](time.Nanosecond))
+	b.getT = nilGetT
+
+	mt := &mT{}
+	(lifecycle[struct{}, struct{}])(b).new(mt)(mt)
+
+	if len(mt.fatalfCalls) != 0 || len(mt.errorCalls) != 0 {
+		t.Errorf("unexpected failures: %v %v", mt.fatalfCalls, mt.errorCalls)
+	}
+}
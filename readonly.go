@@ -0,0 +1,186 @@
+package tbdd
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tcLeaf is a value reachable from a test case, such as a number or the
+// address held by a pointer, by the path locating it, such as
+// "TC.Items[2].Price".
+type tcLeaf struct {
+	path, value string
+}
+
+// snapshotTC returns every value reachable from tc, in the order it was
+// reached, so a mutation of the memory tc refers to can be located by
+// comparing the snapshots taken before and after it.
+func snapshotTC(tc any) []tcLeaf {
+	w := leafWalker{visited: map[visit]bool{}}
+	w.walk(reflect.ValueOf(tc), "TC")
+
+	return w.leaves
+}
+
+// mutatedPath returns the path of the first value of the snapshot before
+// which differs in the snapshot after, and true, or false when they are
+// equal.
+func mutatedPath(before, after []tcLeaf) (string, bool) {
+	for i := range min(len(before), len(after)) {
+		if before[i] != after[i] {
+			return before[i].path, true
+		}
+	}
+
+	switch {
+	case len(before) > len(after):
+		return before[len(after)].path, true
+	case len(after) > len(before):
+		return after[len(before)].path, true
+	}
+
+	return "", false
+}
+
+// readOnlyTC calls phase, failing t and the scenario sr as misconfigured
+// when it mutates memory reachable from tc and the plan sets ReadOnlyTC.
+// name names the phase, such as "assert". When cleanup is non-nil the test
+// case is compared in the function it registers rather than once phase
+// returns, so the parallel subtests phase started, such as the checks of a
+// ParallelThenTable, have finished.
+func (p *plan[T, R]) readOnlyTC(t errorT, name string, tc *T, sr *scenario, cleanup func(func()), phase func()) {
+	if !p.readOnly || t == nil {
+		phase()
+		return
+	}
+
+	t.Helper()
+
+	before := snapshotTC(*tc)
+	check := func() {
+		t.Helper()
+
+		if path, ok := mutatedPath(before, snapshotTC(*tc)); ok {
+			sr.fail(ClassFailedConfig)
+			t.Error("tbdd: the " + name + " phase mutated " + path + ", but the test case is read-only during it; mutate it in Arrange, Given, or AfterAct instead")
+		}
+	}
+
+	phase()
+
+	if cleanup != nil {
+		cleanup(check)
+		return
+	}
+
+	check()
+}
+
+// leafWalker collects the values reachable from a value.
+type leafWalker struct {
+	leaves  []tcLeaf
+	visited map[visit]bool
+}
+
+func (w *leafWalker) add(path, value string) {
+	w.leaves = append(w.leaves, tcLeaf{path, value})
+}
+
+func (w *leafWalker) walk(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		w.add(path, "nil")
+	case reflect.Pointer:
+		if v.IsNil() {
+			w.add(path, "nil")
+			return
+		}
+
+		w.add(path, "0x"+strconv.FormatUint(uint64(v.Pointer()), 16))
+
+		if w.enter(v.Pointer(), v.Type(), 0) {
+			w.walk(v.Elem(), path)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			w.add(path, "nil")
+			return
+		}
+
+		w.add(path, v.Elem().Type().String())
+		w.walk(v.Elem(), path)
+	case reflect.Map:
+		if v.IsNil() {
+			w.add(path, "nil")
+			return
+		}
+
+		w.add(path, "0x"+strconv.FormatUint(uint64(v.Pointer()), 16)+" len "+strconv.Itoa(v.Len()))
+
+		if !w.enter(v.Pointer(), v.Type(), 0) {
+			return
+		}
+
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+
+		entries := make([]entry, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			entries = append(entries, entry{mapKeyString(it.Key()), it.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(a.key, b.key)
+		})
+
+		for _, e := range entries {
+			w.walk(e.value, path+"["+e.key+"]")
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			w.add(path, "nil")
+			return
+		}
+
+		w.add(path, "0x"+strconv.FormatUint(uint64(v.Pointer()), 16)+" len "+strconv.Itoa(v.Len()))
+
+		if !w.enter(v.Pointer(), v.Type(), v.Len()) {
+			return
+		}
+
+		for i := range v.Len() {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if f := v.Type().Field(i); f.Tag.Get("tbdd") != "shared" {
+				w.walk(v.Field(i), path+"."+f.Name)
+			}
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		w.add(path, "0x"+strconv.FormatUint(uint64(v.Pointer()), 16))
+	default:
+		// fmt formats the unexported fields reflect cannot Interface
+		w.add(path, fmt.Sprint(v))
+	}
+}
+
+// enter reports whether the memory at ptr has not been walked as type typ
+// with length n yet, marking it as walked.
+func (w *leafWalker) enter(ptr uintptr, typ reflect.Type, n int) bool {
+	k := visit{ptr, typ, n}
+	if w.visited[k] {
+		return false
+	}
+
+	w.visited[k] = true
+	return true
+}
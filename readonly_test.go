@@ -0,0 +1,128 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type readOnlyTC struct {
+	Items  []int
+	Prices map[string]*int
+	Next   *readOnlyTC
+	Cache  map[string]int `tbdd:"shared"`
+	Any    any
+	count  *int
+}
+
+func Test_mutatedPath(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		mutate func(*readOnlyTC)
+		exp    string
+	}{
+		{func(*readOnlyTC) {}, ""},
+		{func(tc *readOnlyTC) { tc.Items[1] = 3 }, "TC.Items[1]"},
+		{func(tc *readOnlyTC) { tc.Items = append(tc.Items, 3) }, "TC.Items"},
+		{func(tc *readOnlyTC) { *tc.Prices["apple"] = 2 }, `TC.Prices["apple"]`},
+		{func(tc *readOnlyTC) { tc.Prices["pear"] = new(int) }, "TC.Prices"},
+		{func(tc *readOnlyTC) { tc.Next.Items = nil }, "TC.Next.Items"},
+		{func(tc *readOnlyTC) { tc.Cache["hits"]++ }, ""},
+		{func(tc *readOnlyTC) { tc.Any = 2 }, "TC.Any"},
+		{func(tc *readOnlyTC) { *tc.count = 1 }, "TC.count"},
+	} {
+		apple := 1
+		tc := readOnlyTC{
+			Items:  []int{1, 2},
+			Prices: map[string]*int{"apple": &apple},
+			Cache:  map[string]int{},
+			Any:    1,
+			count:  new(int),
+		}
+		tc.Next = &readOnlyTC{Items: []int{3}}
+		tc.Next.Next = tc.Next
+
+		before := snapshotTC(tc)
+		v.mutate(&tc)
+
+		path, ok := mutatedPath(before, snapshotTC(tc))
+		Equal(t, path, v.exp)
+		Equal(t, ok, v.exp != "")
+	}
+}
+
+func TestLifecycle_readOnlyTC(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv("TBDD_READ_ONLY_TC_HELPER") == "1" {
+		WTN(readOnlyTC{Items: []int{1}}, "the items are summed", func(*testing.T, readOnlyTC) {}, "the sum is checked", func(_ *testing.T, tc readOnlyTC) {
+			tc.Items[0] = 0
+		}).With(WithReadOnlyTC[readOnlyTC, struct{}]()).New(t)(t)
+		WTN(readOnlyTC{Items: []int{1}}, "the items are counted", func(*testing.T, readOnlyTC) {}, "the count is checked", func(t *testing.T, tc readOnlyTC) {
+			ParallelThenTable(map[string]func(*testing.T, readOnlyTC, struct{}){
+				"in parallel": func(_ *testing.T, tc readOnlyTC, _ struct{}) {
+					tc.Items[0] = 0
+				},
+			})(t, tc, struct{}{})
+		}).With(WithReadOnlyTC[readOnlyTC, struct{}]()).New(t)(t)
+		return
+	}
+
+	// a Describe mutating its test case fails the scenario as misconfigured
+	mt := &mT{}
+
+	b := WTN(readOnlyTC{Items: []int{1}}, "w", func(*testing.T, readOnlyTC) {}, "t", func(*testing.T, readOnlyTC) {}).With(WithReadOnlyTC[readOnlyTC, struct{}]())
+	b.Describe = func(_ *testing.T, d Describe[readOnlyTC]) DescribeResponse {
+		d.TC.Items[0]++
+		return DescribeResponse{d.When, d.Then}
+	}
+	b.getT = nilGetT
+
+	(lifecycle[readOnlyTC, struct{}])(b).new(mt)(mt)
+
+	if len(mt.errorCalls) != 1 || mt.errorCalls[0][0] != "tbdd: the describe phase mutated TC.Items[0], but the test case is read-only during it; mutate it in Arrange, Given, or AfterAct instead" {
+		t.Errorf("expected the mutation to be reported but got %v", mt.errorCalls)
+	}
+
+	// so does an Assert
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_READ_ONLY_TC_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	// including within the parallel checks of a then table
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_the_items_are_summed/then_the_sum_is_checked ",
+		"--- FAIL: " + t.Name() + "/when_the_items_are_counted/then_the_count_is_checked ",
+		"tbdd: the assert phase mutated TC.Items[0], but the test case is read-only during it",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	b2, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ Class string }
+	}
+	if err := json.Unmarshal(b2, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Results) != 2 || r.Results[0].Class != "failed-config" || r.Results[1].Class != "failed-config" {
+		t.Errorf("expected the scenario to fail as misconfigured but got %+v", r.Results)
+	}
+}
@@ -0,0 +1,139 @@
+package tbdd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Recorder collects failures reported by goroutines which may outlive the
+// test that started them, where calling t.Error directly could panic.
+//
+// Within a Lifecycle, the Recorder returned by RecorderFor during Act is
+// drained in the then subtest: the lifecycle waits for every goroutine
+// started with Go and then reports each recorded failure via t.Error before
+// Assert runs. Failures recorded after the Recorder has been drained can no
+// longer fail the test and are retained as a Warning instead.
+type Recorder struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	test     string
+	failures []string
+	drained  bool
+}
+
+// recorders holds the Recorder of each test currently running an Act phase,
+// or created by RecorderFor outside of a Lifecycle.
+var recorders struct {
+	mu sync.Mutex
+	m  map[*testing.T]*Recorder
+}
+
+// RecorderFor returns the Recorder for t.
+//
+// When t is running the Act phase of a Lifecycle this is the Recorder of the
+// scenario. Otherwise a Recorder is created for t on first use and drained
+// once t and its subtests complete.
+func RecorderFor(t *testing.T) *Recorder {
+	recorders.mu.Lock()
+	defer recorders.mu.Unlock()
+
+	if r, ok := recorders.m[t]; ok {
+		return r
+	}
+
+	r := &Recorder{}
+	registerRecorderLocked(t, r)
+	return r
+}
+
+// Go runs f in a new goroutine which is waited for before the Recorder is
+// drained.
+func (r *Recorder) Go(f func()) {
+	r.wg.Go(f)
+}
+
+// Error records a failure with its arguments formatted in the manner of
+// fmt.Sprint. It is safe to call from any goroutine.
+func (r *Recorder) Error(args ...any) {
+	r.record(fmt.Sprint(args...))
+}
+
+// Errorf records a failure with its arguments formatted in the manner of
+// fmt.Sprintf. It is safe to call from any goroutine.
+func (r *Recorder) Errorf(format string, args ...any) {
+	r.record(fmt.Sprintf(format, args...))
+}
+
+func (r *Recorder) record(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drained {
+		warnings.mu.Lock()
+		defer warnings.mu.Unlock()
+
+		warnings.list = append(warnings.list, Warning{Test: r.test, Message: "failure reported after its test completed: " + msg})
+		return
+	}
+
+	r.failures = append(r.failures, msg)
+}
+
+// reportT is the subset of *testing.T that Recorder draining depends on.
+type reportT interface {
+	Helper()
+	Name() string
+	Error(args ...any)
+}
+
+// drain waits for the goroutines started with Go and then reports every
+// recorded failure to t. Only the first call has any effect.
+func (r *Recorder) drain(t reportT) {
+	t.Helper()
+
+	r.wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drained {
+		return
+	}
+
+	r.drained = true
+	r.test = t.Name()
+
+	for _, msg := range r.failures {
+		t.Error(msg)
+	}
+	r.failures = nil
+}
+
+// registerRecorder makes r the Recorder returned by RecorderFor(t) until t
+// completes, at which point r is drained if it has not been already.
+func registerRecorder(t *testing.T, r *Recorder) {
+	if t == nil {
+		return
+	}
+
+	recorders.mu.Lock()
+	defer recorders.mu.Unlock()
+
+	registerRecorderLocked(t, r)
+}
+
+func registerRecorderLocked(t *testing.T, r *Recorder) {
+	if recorders.m == nil {
+		recorders.m = map[*testing.T]*Recorder{}
+	}
+	recorders.m[t] = r
+
+	t.Cleanup(func() {
+		recorders.mu.Lock()
+		delete(recorders.m, t)
+		recorders.mu.Unlock()
+
+		r.drain(t)
+	})
+}
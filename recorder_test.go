@@ -0,0 +1,142 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var _ reportT = (*testing.T)(nil)
+
+type mReportT struct {
+	errorCalls []string
+}
+
+func (t *mReportT) Helper() {
+}
+
+func (t *mReportT) Name() string {
+	return "TestRecorded"
+}
+
+func (t *mReportT) Error(args ...any) {
+	t.errorCalls = append(t.errorCalls, args[0].(string))
+}
+
+func TestRecorder_lifecycle(t *testing.T) {
+	var acts atomic.Int32
+
+	b := WT(
+		struct{}{},
+		"background work is started", func(t *testing.T, _ struct{}) *atomic.Int32 {
+			r := RecorderFor(t)
+			if RecorderFor(t) != r {
+				t.Error("expected the scenario Recorder for every call during Act")
+			}
+
+			for range 3 {
+				r.Go(func() {
+					acts.Add(1)
+				})
+			}
+
+			return &acts
+		},
+		"all of it completes before the assertions", func(t *testing.T, _ struct{}, r *atomic.Int32) {
+			if n := r.Load(); n != 3 {
+				t.Errorf("expected 3 completed goroutines but got %d", n)
+			}
+		},
+	)
+
+	f := b.New(t)
+	f(t)
+}
+
+func TestRecorder_lifecycleFailure(t *testing.T) {
+	if os.Getenv("TBDD_RECORDER_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"background work fails", func(t *testing.T, _ struct{}) {
+				r := RecorderFor(t)
+				r.Go(func() {
+					r.Errorf("background check %d failed", 1)
+				})
+			},
+			"it is reported", func(*testing.T, struct{}) {},
+		).New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_RECORDER_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_background_work_fails/then_it_is_reported ",
+		"background check 1 failed",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func TestRecorder_drain(t *testing.T) {
+	r := &Recorder{}
+	r.Error("first ", 1)
+	r.Go(func() {
+		r.Errorf("second %d", 2)
+	})
+
+	mt := &mReportT{}
+	r.drain(mt)
+	r.drain(mt)
+
+	if exp := "first 1|second 2"; strings.Join(mt.errorCalls, "|") != exp {
+		t.Errorf("expected reported failures '%s' but got '%s'", exp, strings.Join(mt.errorCalls, "|"))
+	}
+
+	r.Error("late")
+
+	var found bool
+	for _, w := range Warnings() {
+		if w.Test == "TestRecorded" && w.Message == "failure reported after its test completed: late" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Error("expected a late failure to be retained as a warning")
+	}
+}
+
+func TestRecorderFor(t *testing.T) {
+	var r *Recorder
+	t.Run("outside a lifecycle", func(t *testing.T) {
+		r = RecorderFor(t)
+		if RecorderFor(t) != r {
+			t.Error("expected the same Recorder for every call")
+		}
+	})
+
+	if !r.drained || r.test != t.Name()+"/outside_a_lifecycle" {
+		t.Errorf("expected the Recorder to be drained by its test but got %v and '%s'", r.drained, r.test)
+	}
+
+	recorders.mu.Lock()
+	n := len(recorders.m)
+	recorders.mu.Unlock()
+
+	if n != 0 {
+		t.Errorf("expected no registered recorders after the test but got %d", n)
+	}
+}
@@ -0,0 +1,72 @@
+package tbdd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// recoverVariants is deferred around the iteration of the field Variants or
+// Variants2 and converts a panic of the generator itself into a
+// configuration failure of the variant following it.i, the one it failed to
+// produce. Panics of the loop body, which runs the variants, are left to
+// unwind untouched while *inBody is true.
+//
+// A generator which ignores yield returning false once the variants exceeded
+// MaxVariants panics with a runtime error as it yields again; it is reported
+// as a generator which does not stop rather than as a panic.
+func (p *plan[T, R]) recoverVariants(t TestingT, field string, it *variantIter, inBody *bool) {
+	if *inBody {
+		return
+	}
+
+	// a nil recovery is either a return or runtime.Goexit, which must carry
+	// on unwinding
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	it.i++
+	index := it.i
+
+	var err error
+	if _, ok := r.(runtime.Error); ok && it.limited {
+		err = fmt.Errorf("%w: the generator kept yielding after yield returned false at the MaxVariants limit of %d: %v", ErrTooManyVariants, p.maxVars, r)
+	} else {
+		err = fmt.Errorf("%w: %v\n\n%s", ErrVariantsPanic, r, debug.Stack())
+	}
+
+	p.variantConfigFailure(t, index, "", func(t TestingT) {
+		t.Helper()
+
+		p.phases.configError(p.getT(t), field, "", index, err)
+		t.Error((&ConfigError{field, "", index, err}).Error())
+	})
+}
+
+// cloneInto replaces *tc with its clone, returning an error wrapping
+// ErrCloneTCPanic and the stack if clone panics, in which case *tc is left
+// as the zero T so no phase works on a partial clone.
+func cloneInto[T any](tc *T, clone func(T) T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			*tc = zero
+			err = fmt.Errorf("%w: %v\n\n%s", ErrCloneTCPanic, r, debug.Stack())
+		}
+	}()
+
+	*tc = clone(*tc)
+	return nil
+}
+
+// cloneFailure fails t with the panic of CloneTC recorded in sr, naming the
+// scenario by prefix and its variant index.
+func (p *plan[T, R]) cloneFailure(t TestingT, b *phases[T, R], prefix string, index int, sr *scenario) {
+	t.Helper()
+
+	sr.fail(ClassFailedConfig)
+	b.configError(p.getT(t), "CloneTC", prefix, index, sr.cloneErr)
+	t.Fatalf("%v", &ConfigError{"CloneTC", prefix, index, sr.cloneErr})
+}
@@ -0,0 +1,224 @@
+package tbdd
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_variantsPanic(t *testing.T) {
+	t.Parallel()
+
+	var configErrs []*ConfigError
+	var ran []string
+	b := Lifecycle[mTC, mTCR]{
+		When: "w",
+		Then: "t",
+		Act: func(*testing.T, mTC) mTCR {
+			return mTCR{}
+		},
+		Assert: func(*testing.T, Assert[mTC, mTCR]) {
+		},
+		hooks: Hooks[mTC, mTCR]{
+			ConfigError: func(_ *testing.T, err *ConfigError) {
+				configErrs = append(configErrs, err)
+			},
+		},
+	}
+
+	mt := &mT{}
+
+	b.getT = nilGetT
+	b.runObserver = func(s string) {
+		ran = append(ran, s)
+	}
+	b.Variants = func(*testing.T, mTC) iter.Seq[TestVariant[mTC]] {
+		return func(yield func(TestVariant[mTC]) bool) {
+			yield(TestVariant[mTC]{Kind: "a"})
+			panic("boom")
+		}
+	}
+	b.Variants2 = func(*testing.T, mTC) iter.Seq2[TestVariant[mTC], error] {
+		return func(yield func(TestVariant[mTC], error) bool) {
+			yield(TestVariant[mTC]{Kind: "b"}, nil)
+			panic("bang")
+		}
+	}
+
+	f := ((lifecycle[mTC, mTCR])(b)).new(mt)
+	f(mt)
+
+	if exp := "when w,then t,a/when w,then t,variant 1/tbdd-config-error,b/when w,then t,variant 3/tbdd-config-error"; strings.Join(ran, ",") != exp {
+		t.Errorf("expected subtests '%s' but got '%s'", exp, strings.Join(ran, ","))
+	}
+
+	if len(configErrs) != 2 ||
+		configErrs[0].Field != "Variants" || configErrs[0].VariantIndex != 1 || !errors.Is(configErrs[0], ErrVariantsPanic) ||
+		configErrs[1].Field != "Variants2" || configErrs[1].VariantIndex != 3 || !errors.Is(configErrs[1], ErrVariantsPanic) {
+		t.Errorf("expected panics of variants 1 and 3 but got %v", configErrs)
+	}
+
+	if len(mt.errorCalls) != 2 {
+		t.Fatalf("expected two error calls but got %v", mt.errorCalls)
+	}
+
+	for i, exp := range []string{"tbdd: invalid Variants of variant 1: variant generator panicked: boom", "tbdd: invalid Variants2 of variant 3: variant generator panicked: bang"} {
+		msg := mt.errorCalls[i][0].(string)
+		if !strings.HasPrefix(msg, exp+"\n\n") || !strings.Contains(msg, "goroutine ") {
+			t.Errorf("expected error call %d to start with '%s' followed by the stack but got '%s'", i, exp, msg)
+		}
+	}
+}
+
+func TestLifecycle_variantsBodyPanic(t *testing.T) {
+	t.Parallel()
+
+	b := WTN(0, "w", func(*testing.T, int) {}, "t", func(_ *testing.T, tc int) {
+		if tc == 1 {
+			panic("body")
+		}
+	})
+	b.getT = nilGetT
+	b.Variants = func(*testing.T, int) iter.Seq[TestVariant[int]] {
+		return func(yield func(TestVariant[int]) bool) {
+			yield(TestVariant[int]{Kind: "a", TC: 1})
+		}
+	}
+
+	mt := &mT{}
+	f := ((lifecycle[int, struct{}])(b)).new(mt)
+
+	defer func() {
+		if r := recover(); r != "body" {
+			t.Errorf("expected the panic of the variant to propagate but got %v", r)
+		}
+	}()
+
+	f(mt)
+}
+
+func Test_cloneInto(t *testing.T) {
+	t.Parallel()
+
+	tc := 1
+	if err := cloneInto(&tc, func(v int) int { return v + 1 }); err != nil || tc != 2 {
+		t.Errorf("expected the clone 2 and no error but got %d and %v", tc, err)
+	}
+
+	err := cloneInto(&tc, func(int) int { panic("deep") })
+	if !errors.Is(err, ErrCloneTCPanic) || !strings.HasPrefix(err.Error(), "CloneTC function panicked: deep\n\n") {
+		t.Errorf("expected a CloneTC panic error but got %v", err)
+	}
+	if tc != 0 {
+		t.Errorf("expected the zero test case after a panic but got %d", tc)
+	}
+}
+
+func TestLifecycle_cloneTCPanic(t *testing.T) {
+	if os.Getenv("TBDD_CLONE_PANIC_HELPER") == "1" {
+		pass := func(*testing.T, int) {}
+		variants := WithVariants[int, struct{}](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+			return func(yield func(TestVariant[int]) bool) {
+				_ = yield(TestVariant[int]{Kind: "bad", TC: 1}) &&
+					yield(TestVariant[int]{Kind: "good", TC: 2})
+			}
+		})
+		clone := func(tc int) int {
+			if tc == 1 {
+				panic("cannot clone")
+			}
+			return tc
+		}
+
+		var warmups int
+		for i, b := range []Lifecycle[int, struct{}]{
+			WTN(0, "it acts", pass, "it passes", pass).With(variants, WithCloneTC[int, struct{}](clone)),
+			GWTN(0, "a context", func(*testing.T, *int) {}, "it acts", pass, "it passes", pass).With(variants, WithCloneTC[int, struct{}](clone)),
+			WTN(3, "it warms up", pass, "it passes", pass).With(
+				WithWarmupRuns[int, struct{}](2),
+				WithCloneTC[int, struct{}](func(tc int) int {
+					if warmups++; warmups > 1 {
+						panic("cannot clone again")
+					}
+					return tc
+				}),
+			),
+		} {
+			b.NewI(t, i)(t)
+		}
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_CLONE_PANIC_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/0/when_it_acts ",
+		"--- FAIL: " + t.Name() + "/0/bad/when_it_acts ",
+		`tbdd: invalid CloneTC of variant 0 (prefix = "0/bad/"): CloneTC function panicked: cannot clone`,
+		"--- PASS: " + t.Name() + "/0/good/when_it_acts ",
+		"--- PASS: " + t.Name() + "/1/given_a_context ",
+		// GWT clones within the given subtest, once it is known to run
+		"--- FAIL: " + t.Name() + "/1/bad/given_a_context ",
+		`tbdd: invalid CloneTC of variant 0 (prefix = "1/bad/"): CloneTC function panicked: cannot clone`,
+		"--- PASS: " + t.Name() + "/1/good/given_a_context ",
+		"--- FAIL: " + t.Name() + "/2/when_it_warms_up ",
+		"tbdd: invalid CloneTC: CloneTC function panicked: cannot clone again",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	if strings.Count(out, "runtime/debug.Stack") != 3 {
+		t.Errorf("expected the stack of each of the three panics:\n%s", out)
+	}
+}
+
+func TestLifecycle_cloneTCPanicConfigError(t *testing.T) {
+	t.Parallel()
+
+	var configErrs []*ConfigError
+	var ran []string
+	b := GWTN(0, "g", func(*testing.T, *int) {}, "w", func(*testing.T, int) {}, "t", func(*testing.T, int) {})
+	// an Arrange function of its own receives the clone
+	b.Arrange = func(*testing.T, Arrange[int, struct{}]) (string, func(*testing.T)) {
+		return "g", func(*testing.T) {}
+	}
+	b.getT = nilGetT
+	b.runObserver = func(s string) {
+		ran = append(ran, s)
+	}
+	b.CloneTC = func(int) int {
+		panic("cannot clone")
+	}
+	b.hooks.ConfigError = func(_ *testing.T, err *ConfigError) {
+		configErrs = append(configErrs, err)
+	}
+
+	mt := &mT{}
+	f := ((lifecycle[int, struct{}])(b)).new(mt)
+	f(mt)
+
+	if exp := "tbdd-config-error"; strings.Join(ran, ",") != exp {
+		t.Errorf("expected subtests '%s' but got '%s'", exp, strings.Join(ran, ","))
+	}
+
+	if len(configErrs) != 1 || configErrs[0].Field != "CloneTC" || configErrs[0].VariantIndex != -1 || !errors.Is(configErrs[0], ErrCloneTCPanic) {
+		t.Errorf("expected one CloneTC config error but got %v", configErrs)
+	}
+
+	if len(mt.fatalfCalls) != 1 || !strings.HasPrefix(fmt.Sprintf(mt.fatalfCalls[0].format, mt.fatalfCalls[0].args...), "tbdd: invalid CloneTC: CloneTC function panicked: cannot clone\n\n") {
+		t.Errorf("expected one fatalf call with the CloneTC panic but got %v", mt.fatalfCalls)
+	}
+}
@@ -0,0 +1,264 @@
+package tbdd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// ErrUnknownStep is wrapped by errors returned when a StepRegistry is asked
+// for a step description that has not been registered.
+var ErrUnknownStep = errors.New("step is not registered")
+
+// StepRegistry holds reusable Given, When, and Then step implementations
+// registered once under their descriptions so many lifecycles can reference
+// them by name.
+//
+// The referenced description is used verbatim as the Given, When, or Then
+// description of lifecycles built by the registry. Steps registered with
+// GivenPattern, WhenPattern, or ThenPattern match any description fitting
+// their StepPattern and receive the extracted placeholder values. Exact
+// registrations take precedence over patterns, and patterns are tried in
+// registration order.
+//
+// A StepRegistry is safe for concurrent use; the zero value is ready to use.
+type StepRegistry[T, R any] struct {
+	rwm    sync.RWMutex
+	givens map[string]func(*testing.T, *T)
+	whens  map[string]func(*testing.T, T) R
+	thens  map[string]func(*testing.T, T, R)
+
+	givenPatterns []patternStep[func(*testing.T, *T, StepArgs)]
+	whenPatterns  []patternStep[func(*testing.T, T, StepArgs) R]
+	thenPatterns  []patternStep[func(*testing.T, T, R, StepArgs)]
+}
+
+type patternStep[F any] struct {
+	p *StepPattern
+	f F
+}
+
+// NewStepRegistry returns an empty StepRegistry.
+func NewStepRegistry[T, R any]() *StepRegistry[T, R] {
+	return &StepRegistry[T, R]{}
+}
+
+// Given registers a given step under description. It returns the registry so
+// registrations can be chained.
+//
+// Given panics if description is empty, f is nil, or a given step with the
+// same description is already registered.
+func (r *StepRegistry[T, R]) Given(description string, f func(*testing.T, *T)) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	if r.givens == nil {
+		r.givens = map[string]func(*testing.T, *T){}
+	}
+	registerStep(r.givens, "Given", description, f, f == nil)
+
+	return r
+}
+
+// When registers a when step under description. It returns the registry so
+// registrations can be chained.
+//
+// When panics if description is empty, f is nil, or a when step with the
+// same description is already registered.
+func (r *StepRegistry[T, R]) When(description string, f func(*testing.T, T) R) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	if r.whens == nil {
+		r.whens = map[string]func(*testing.T, T) R{}
+	}
+	registerStep(r.whens, "When", description, f, f == nil)
+
+	return r
+}
+
+// Then registers a then step under description. It returns the registry so
+// registrations can be chained.
+//
+// Then panics if description is empty, f is nil, or a then step with the
+// same description is already registered.
+func (r *StepRegistry[T, R]) Then(description string, f func(*testing.T, T, R)) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	if r.thens == nil {
+		r.thens = map[string]func(*testing.T, T, R){}
+	}
+	registerStep(r.thens, "Then", description, f, f == nil)
+
+	return r
+}
+
+// GivenPattern registers a given step matching pattern. See StepPattern for
+// the placeholder syntax.
+//
+// GivenPattern panics if pattern cannot be compiled, f is nil, or a given
+// step with the same pattern is already registered.
+func (r *StepRegistry[T, R]) GivenPattern(pattern string, f func(*testing.T, *T, StepArgs)) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	r.givenPatterns = registerPatternStep(r.givenPatterns, "Given", pattern, f, f == nil)
+
+	return r
+}
+
+// WhenPattern registers a when step matching pattern. See StepPattern for
+// the placeholder syntax.
+//
+// WhenPattern panics if pattern cannot be compiled, f is nil, or a when step
+// with the same pattern is already registered.
+func (r *StepRegistry[T, R]) WhenPattern(pattern string, f func(*testing.T, T, StepArgs) R) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	r.whenPatterns = registerPatternStep(r.whenPatterns, "When", pattern, f, f == nil)
+
+	return r
+}
+
+// ThenPattern registers a then step matching pattern. See StepPattern for
+// the placeholder syntax.
+//
+// ThenPattern panics if pattern cannot be compiled, f is nil, or a then step
+// with the same pattern is already registered.
+func (r *StepRegistry[T, R]) ThenPattern(pattern string, f func(*testing.T, T, R, StepArgs)) *StepRegistry[T, R] {
+	r.rwm.Lock()
+	defer r.rwm.Unlock()
+
+	r.thenPatterns = registerPatternStep(r.thenPatterns, "Then", pattern, f, f == nil)
+
+	return r
+}
+
+// GWT constructs a Lifecycle for tc from registered steps, just like the
+// package level GWT function does from explicit arguments.
+//
+// An empty given results in a lifecycle without given context. GWT panics if
+// any non-empty description does not refer to a registered step.
+func (r *StepRegistry[T, R]) GWT(tc T, given, when, then string) Lifecycle[T, R] {
+	b, err := r.TryGWT(tc, given, when, then)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return b
+}
+
+// WT is a convenience wrapper around GWT for use when there is no given
+// context to convey.
+func (r *StepRegistry[T, R]) WT(tc T, when, then string) Lifecycle[T, R] {
+	return r.GWT(tc, "", when, then)
+}
+
+// TryGWT is like GWT except it returns an error instead of panicking. This
+// is useful when step references come from dynamic data such as loaded
+// files.
+//
+// Errors for unregistered steps wrap ErrUnknownStep.
+func (r *StepRegistry[T, R]) TryGWT(tc T, given, when, then string) (Lifecycle[T, R], error) {
+	r.rwm.RLock()
+	defer r.rwm.RUnlock()
+
+	var givenF func(*testing.T, *T)
+	if given != "" {
+		givenF = r.givens[given]
+		if givenF == nil {
+			f, args, ok := matchPatternStep(r.givenPatterns, given)
+			if !ok {
+				return Lifecycle[T, R]{}, unknownStepError("Given", given)
+			}
+
+			givenF = func(t *testing.T, tc *T) {
+				f(t, tc, args)
+			}
+		}
+	}
+
+	whenF := r.whens[when]
+	if whenF == nil {
+		f, args, ok := matchPatternStep(r.whenPatterns, when)
+		if !ok {
+			return Lifecycle[T, R]{}, unknownStepError("When", when)
+		}
+
+		whenF = func(t *testing.T, tc T) R {
+			return f(t, tc, args)
+		}
+	}
+
+	thenF := r.thens[then]
+	if thenF == nil {
+		f, args, ok := matchPatternStep(r.thenPatterns, then)
+		if !ok {
+			return Lifecycle[T, R]{}, unknownStepError("Then", then)
+		}
+
+		thenF = func(t *testing.T, tc T, r R) {
+			f(t, tc, r, args)
+		}
+	}
+
+	return TryGWT(
+		tc,
+		given, givenF,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+func registerStep[F any](m map[string]F, kind, description string, f F, isNil bool) {
+	if description == "" {
+		panic("tbdd.StepRegistry: " + kind + " step description must be non-empty")
+	}
+
+	if isNil {
+		panic("tbdd.StepRegistry: " + kind + " step function must be non-nil")
+	}
+
+	if _, ok := m[description]; ok {
+		panic("tbdd.StepRegistry: " + kind + " step already registered: " + description)
+	}
+
+	m[description] = f
+}
+
+func registerPatternStep[F any](steps []patternStep[F], kind, pattern string, f F, isNil bool) []patternStep[F] {
+	p, err := CompileStepPattern(pattern)
+	if err != nil {
+		panic("tbdd.StepRegistry: " + kind + " step pattern is invalid: " + err.Error())
+	}
+
+	if isNil {
+		panic("tbdd.StepRegistry: " + kind + " step function must be non-nil")
+	}
+
+	for _, v := range steps {
+		if v.p.pattern == pattern {
+			panic("tbdd.StepRegistry: " + kind + " step pattern already registered: " + pattern)
+		}
+	}
+
+	return append(steps, patternStep[F]{p, f})
+}
+
+func matchPatternStep[F any](steps []patternStep[F], description string) (F, StepArgs, bool) {
+	for _, v := range steps {
+		if args, ok := v.p.Match(description); ok {
+			return v.f, args, true
+		}
+	}
+
+	var zero F
+	return zero, StepArgs{}, false
+}
+
+func unknownStepError(kind, description string) error {
+	return fmt.Errorf("tbdd.StepRegistry: %s step %q: %w", kind, description, ErrUnknownStep)
+}
@@ -0,0 +1,192 @@
+package tbdd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStepRegistry(t *testing.T) {
+	type TC struct {
+		balance int
+	}
+
+	var calls []string
+
+	r := NewStepRegistry[TC, int]().
+		Given("an account with 10", func(_ *testing.T, tc *TC) {
+			calls = append(calls, "given")
+			tc.balance = 10
+		}).
+		When("5 is deposited", func(_ *testing.T, tc TC) int {
+			calls = append(calls, "when")
+			return tc.balance + 5
+		}).
+		Then("the balance is 15", func(t *testing.T, _ TC, r int) {
+			calls = append(calls, "then")
+			if r != 15 {
+				t.Errorf("expected 15 but got %d", r)
+			}
+		}).
+		Then("the balance is 5", func(t *testing.T, _ TC, r int) {
+			calls = append(calls, "then")
+			if r != 5 {
+				t.Errorf("expected 5 but got %d", r)
+			}
+		})
+
+	{
+		b := r.GWT(TC{}, "an account with 10", "5 is deposited", "the balance is 15")
+		if b.Given != "an account with 10" || b.When != "5 is deposited" || b.Then != "the balance is 15" {
+			t.Errorf("unexpected descriptions: given=%q when=%q then=%q", b.Given, b.When, b.Then)
+		}
+
+		f := b.New(t)
+		f(t)
+	}
+
+	{
+		f := r.WT(TC{}, "5 is deposited", "the balance is 5").New(t)
+		f(t)
+	}
+
+	if exp := "given when then when then"; strings.Join(calls, " ") != exp {
+		t.Errorf("expected calls '%s' but got '%s'", exp, strings.Join(calls, " "))
+	}
+
+	//
+	// unknown steps
+	//
+
+	for _, v := range [][3]string{
+		{"missing", "5 is deposited", "the balance is 15"},
+		{"", "missing", "the balance is 15"},
+		{"", "5 is deposited", "missing"},
+	} {
+		_, err := r.TryGWT(TC{}, v[0], v[1], v[2])
+		if !errors.Is(err, ErrUnknownStep) {
+			t.Errorf("expected ErrUnknownStep for %v but got '%v'", v, err)
+		}
+	}
+
+	{
+		var r2 any
+		func() {
+			defer func() {
+				r2 = recover()
+			}()
+
+			r.WT(TC{}, "missing", "the balance is 5")
+		}()
+
+		if exp := `tbdd.StepRegistry: When step "missing": step is not registered`; r2 != exp {
+			t.Errorf("expected panic '%s' but got '%v'", exp, r2)
+		}
+	}
+
+	//
+	// validate registration panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { r.Given("", func(*testing.T, *TC) {}) }, "tbdd.StepRegistry: Given step description must be non-empty"},
+		{func() { r.When("w", nil) }, "tbdd.StepRegistry: When step function must be non-nil"},
+		{func() { r.Then("the balance is 5", func(*testing.T, TC, int) {}) }, "tbdd.StepRegistry: Then step already registered: the balance is 5"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+func TestStepRegistry_zeroValue(t *testing.T) {
+	t.Parallel()
+
+	var r StepRegistry[struct{}, struct{}]
+
+	if _, err := r.TryGWT(struct{}{}, "", "w", "t"); !errors.Is(err, ErrUnknownStep) {
+		t.Errorf("expected ErrUnknownStep but got '%v'", err)
+	}
+}
+
+func TestStepRegistry_patterns(t *testing.T) {
+	type TC struct {
+		user    string
+		balance int
+	}
+
+	r := NewStepRegistry[TC, int]().
+		GivenPattern("user {name} has {amount:int}", func(_ *testing.T, tc *TC, args StepArgs) {
+			tc.user = args.String("name")
+			tc.balance = args.Int("amount")
+		}).
+		WhenPattern("{amount:int} is deposited", func(_ *testing.T, tc TC, args StepArgs) int {
+			return tc.balance + args.Int("amount")
+		}).
+		When("nothing happens", func(_ *testing.T, tc TC) int {
+			return tc.balance
+		}).
+		WhenPattern("{anything}", func(t *testing.T, tc TC, args StepArgs) int {
+			t.Error("exact registrations and earlier patterns must take precedence")
+			return 0
+		}).
+		ThenPattern("the balance of {name} is {amount:int}", func(t *testing.T, tc TC, r int, args StepArgs) {
+			if tc.user != args.String("name") || r != args.Int("amount") {
+				t.Errorf("expected %s to have %d but %s has %d", args.String("name"), args.Int("amount"), tc.user, r)
+			}
+		})
+
+	for _, v := range [][3]string{
+		{"user alice has 10", "5 is deposited", "the balance of alice is 15"},
+		{"user bob has 7", "nothing happens", "the balance of bob is 7"},
+	} {
+		f := r.GWT(TC{}, v[0], v[1], v[2]).New(t)
+		f(t)
+	}
+
+	if _, err := r.TryGWT(TC{}, "user alice has ten", "nothing happens", "the balance of alice is 10"); !errors.Is(err, ErrUnknownStep) {
+		t.Errorf("expected ErrUnknownStep but got '%v'", err)
+	}
+
+	if _, err := r.TryGWT(TC{}, "", "nothing happens", "the balance is 10"); !errors.Is(err, ErrUnknownStep) {
+		t.Errorf("expected ErrUnknownStep but got '%v'", err)
+	}
+
+	//
+	// validate registration panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { r.GivenPattern("{", func(*testing.T, *TC, StepArgs) {}) }, "tbdd.StepRegistry: Given step pattern is invalid: tbdd: step pattern has an unterminated placeholder: {"},
+		{func() { r.WhenPattern("{x}", nil) }, "tbdd.StepRegistry: When step function must be non-nil"},
+		{func() { r.WhenPattern("{anything}", func(*testing.T, TC, StepArgs) int { return 0 }) }, "tbdd.StepRegistry: When step pattern already registered: {anything}"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tbdd
+
+import (
+	"strconv"
+	"testing"
+)
+
+// RequirePolicy selects how a scenario ends when its Require phase reports
+// an unmet precondition.
+type RequirePolicy uint8
+
+const (
+	// RequireSkip skips the scenario, so an environment which is not ready is
+	// not reported as a broken behavior. It is the zero value.
+	RequireSkip RequirePolicy = iota
+	// RequireFatal fails the scenario, for environments which must always be
+	// ready, such as CI.
+	RequireFatal
+)
+
+func (p RequirePolicy) String() string {
+	switch p {
+	case RequireSkip:
+		return "skip"
+	case RequireFatal:
+		return "fatal"
+	}
+
+	return "RequirePolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// require runs the Require phase f of a scenario with t and tc and reports
+// whether its preconditions are met. An unmet precondition is recorded in sr
+// and ends t as policy selects; when t is nil, as it is in self-test
+// contexts, require only returns false.
+func require[T any](t *testing.T, f func(*testing.T, T) error, tc T, policy RequirePolicy, sr *scenario) bool {
+	err := f(t, tc)
+	if err == nil {
+		return true
+	}
+
+	sr.Precondition = err.Error()
+	if t == nil {
+		return false
+	}
+
+	t.Helper()
+
+	msg := "precondition not met: " + err.Error()
+	if policy == RequireFatal {
+		sr.fail(ClassFailedArrange)
+		t.Fatal(msg)
+	} else {
+		skip(t, msg)
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package tbdd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLifecycle_require(t *testing.T) {
+	unreachable := func(*testing.T, int) error {
+		return errors.New("service unreachable")
+	}
+	act := func(t *testing.T, _ int) {
+		t.Error("Act must not run once a precondition is not met")
+	}
+	then := func(*testing.T, int) {}
+
+	if os.Getenv("TBDD_REQUIRE_HELPER") == "1" {
+		WTN(0, "the service is called", act, "it responds", then).With(
+			WithRequire[int, struct{}](RequireFatal, unreachable),
+		).New(t)(t)
+		return
+	}
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	var skipped []string
+	for _, b := range []Lifecycle[int, struct{}]{
+		GWTN(0, "a service", func(*testing.T, *int) {}, "the service is called", act, "it responds", then),
+		WTN(0, "the service is called", act, "it responds", then).With(WithLayout[int, struct{}](LayoutMerged)),
+	} {
+		b.With(
+			WithRequire[int, struct{}](RequireSkip, unreachable),
+			WithHooks(Hooks[int, struct{}]{
+				AfterSkip: func(_ *testing.T, cfg AfterSkip[int]) {
+					skipped = append(skipped, cfg.Phase+": "+cfg.Reason)
+				},
+			}),
+		).New(t)(t)
+	}
+
+	if exp := "require: precondition not met: service unreachable"; len(skipped) != 2 || skipped[0] != exp || skipped[1] != exp {
+		t.Errorf("expected both scenarios to skip in the require phase but got %q", skipped)
+	}
+
+	var found int
+	for _, r := range Results()[n:] {
+		if !strings.HasPrefix(r.Test, t.Name()+"/") {
+			continue
+		}
+
+		found++
+		if r.Status != StatusSkipped || r.SkipPhase != "require" || r.Precondition != "service unreachable" {
+			t.Errorf("unexpected result of an unmet precondition: %+v", r)
+		}
+	}
+
+	if found != 2 {
+		t.Errorf("expected 2 results but got %d", found)
+	}
+
+	// met preconditions run the rest of the scenario
+	var acted bool
+	WTN(0, "the service is called", func(*testing.T, int) {
+		acted = true
+	}, "it responds", then).With(
+		WithRequire[int, struct{}](RequireFatal, func(*testing.T, int) error {
+			return nil
+		}),
+	).New(t)(t)
+
+	if !acted {
+		t.Error("expected Act to run once the preconditions are met")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_REQUIRE_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_the_service_is_called ",
+		"precondition not met: service unreachable",
+		"not run: precondition not met",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	if strings.Contains(string(out), "Act must not run") {
+		t.Errorf("expected Act to not run:\n%s", out)
+	}
+}
+
+func Test_require(t *testing.T) {
+	t.Parallel()
+
+	sr := &scenario{}
+	if require(nil, func(*testing.T, int) error { return errors.New("not ready") }, 0, RequireFatal, sr) || sr.Precondition != "not ready" {
+		t.Errorf("expected an unmet precondition to be recorded but got %+v", sr)
+	}
+}
+
+func TestRequirePolicy_String(t *testing.T) {
+	t.Parallel()
+
+	for p, exp := range map[RequirePolicy]string{RequireSkip: "skip", RequireFatal: "fatal", 7: "RequirePolicy(7)"} {
+		if p.String() != exp {
+			t.Errorf("expected %s but got %s", exp, p)
+		}
+	}
+}
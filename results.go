@@ -0,0 +1,412 @@
+package tbdd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Status is the terminal state of a scenario.
+type Status uint8
+
+const (
+	StatusPassed Status = iota + 1
+	StatusFailed
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPassed:
+		return "passed"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	}
+
+	return "Status(" + strconv.Itoa(int(s)) + ")"
+}
+
+// MarshalText encodes s as its String form.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Class classifies the terminal state of a scenario in more detail than its
+// Status, so that triage can tell misconfigured tests and unready
+// environments apart from broken behaviors.
+type Class uint8
+
+const (
+	// ClassPassed is a scenario which passed on the first attempt of every
+	// check.
+	ClassPassed Class = iota + 1
+	// ClassFailedAssert is a scenario which failed in its when or then phase:
+	// a failure of the behavior under test.
+	ClassFailedAssert
+	// ClassFailedConfig is a scenario which failed because its Lifecycle is
+	// misconfigured, such as an empty When or a nil Act, or because its
+	// SkipUntil expired.
+	ClassFailedConfig
+	// ClassFailedArrange is a scenario which failed while arranging its given
+	// context or because a precondition of its Require phase was not met under
+	// RequireFatal.
+	ClassFailedArrange
+	// ClassSkippedEnv is a scenario which was skipped, such as because a
+	// precondition of its Require phase was not met or a phase called Skip.
+	ClassSkippedEnv
+	// ClassSkippedQuarantine is a scenario skipped by the SkipUntil of its
+	// Lifecycle.
+	ClassSkippedQuarantine
+	// ClassFlaky is a scenario which passed only after a Retry check failed at
+	// least once.
+	ClassFlaky
+	// ClassSkippedDependency is a scenario skipped because a scenario of its
+	// Lifecycle's DependsOn did not pass.
+	ClassSkippedDependency
+	// ClassSkippedBudget is a scenario which was not run because the
+	// -tbdd.budget of the run was spent before it started.
+	ClassSkippedBudget
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassPassed:
+		return "passed"
+	case ClassFailedAssert:
+		return "failed-assert"
+	case ClassFailedConfig:
+		return "failed-config"
+	case ClassFailedArrange:
+		return "failed-arrange"
+	case ClassSkippedEnv:
+		return "skipped-env"
+	case ClassSkippedQuarantine:
+		return "skipped-quarantine"
+	case ClassFlaky:
+		return "flaky"
+	case ClassSkippedDependency:
+		return "skipped-dependency"
+	case ClassSkippedBudget:
+		return "skipped-budget"
+	}
+
+	return "Class(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText encodes c as its String form.
+func (c Class) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// ScenarioResult is the outcome of one "when" subtest executed by a Lifecycle.
+type ScenarioResult struct {
+	// ID is a short identifier of the scenario which is stable across runs as
+	// long as the scenario's enclosing test, table index, variant Kind, and
+	// descriptions do not change.
+	ID string
+	// Test is the full name of the outermost subtest of the scenario: the
+	// "given" subtest when there is a given phase, otherwise the "when" subtest.
+	Test string
+	// Source is the "file:line" of the code which defined the scenario; see
+	// Lifecycle.Source.
+	Source            string `json:",omitempty"`
+	Given, When, Then string
+	// Kind is the variant kind, or empty for the basis test case.
+	Kind     string
+	Priority Priority
+	Status   Status
+	// Class refines Status with the reason a scenario failed or was skipped,
+	// or whether it passed only after retries.
+	Class    Class
+	Duration time.Duration
+	// SkipPhase is "given", "require", or "when" when that phase skipped the
+	// scenario.
+	SkipPhase string
+	// SkipReason is the message passed to tbdd.Skip or tbdd.Skipf by the
+	// skipping phase, if any. It is empty for a plain t.Skip or t.Skipf,
+	// whose message the testing package does not expose.
+	SkipReason string
+	// Precondition is the error returned by the Require phase of the
+	// Lifecycle when a precondition was not met, whether the scenario was
+	// skipped or failed because of it.
+	Precondition string `json:",omitempty"`
+	// Invariants names the Lifecycle.Invariants the scenario broke, each with
+	// the phase which broke it, such as "invariant 0 after act".
+	Invariants []string `json:",omitempty"`
+	// Doubles are the test doubles the scenario injected with InjectDouble;
+	// it is empty for scenarios which ran every dependency for real, as far
+	// as they reported.
+	Doubles []Double `json:",omitempty"`
+	// Meta is the metadata of the Lifecycle, including its Owner, Ticket, and
+	// Severity, or nil when it has none.
+	Meta map[string]string `json:",omitempty"`
+	// Mem is the change in memory statistics across Act when the Lifecycle
+	// sets MemStats, otherwise nil.
+	Mem *MemDelta `json:",omitempty"`
+	// SlowPhases names the phases, such as "act", which took at least the
+	// SlowPhase threshold of the Lifecycle, in the order they ran.
+	SlowPhases []string `json:",omitempty"`
+	// Seed is the seed of the scenario; see Lifecycle.Seed.
+	Seed int64 `json:",omitempty"`
+	// Reproduce is a go test command which reruns a failed scenario with the
+	// same -tbdd.seed, set once Seed has been called.
+	Reproduce string `json:",omitempty"`
+}
+
+// scenario tracks the result of a scenario while it runs.
+type scenario struct {
+	ScenarioResult
+	// unselected is true when the scenario was excluded by -tbdd.filter, or
+	// another flag selecting scenarios, and must not be recorded as run.
+	unselected bool
+	// class is the Class of a failure or skip once the phase responsible for
+	// it is known, otherwise zero.
+	class Class
+	// prepared is set once the test case was cloned and its DefaultTC
+	// applied.
+	prepared bool
+	// cloneErr is the panic of CloneTC, after which the test case is the
+	// zero T, or nil.
+	cloneErr error
+	// broken flags the invariants broken so far by index, allocated once
+	// one is.
+	broken []bool
+}
+
+// fail records c as the class of a failure of s, unless an earlier phase
+// already did.
+func (s *scenario) fail(c Class) {
+	if s.class == 0 {
+		s.class = c
+	}
+}
+
+// classify returns the Class of a scenario which ended with status, and
+// passed only after retries when flaky.
+func (s *scenario) classify(status Status, flaky bool) Class {
+	switch status {
+	case StatusFailed:
+		if s.class == ClassFailedConfig || s.class == ClassFailedArrange {
+			return s.class
+		}
+
+		return ClassFailedAssert
+	case StatusSkipped:
+		if s.class == ClassSkippedQuarantine || s.class == ClassSkippedDependency || s.class == ClassSkippedBudget {
+			return s.class
+		}
+
+		return ClassSkippedEnv
+	}
+
+	if flaky {
+		return ClassFlaky
+	}
+
+	return ClassPassed
+}
+
+// Scenario returns the descriptions of r as a single sentence, such as
+// "given a user when they log in then they see their dashboard", prefixed
+// with "<Kind>: " for variants.
+func (r ScenarioResult) Scenario() string {
+	s := "when " + r.When + " then " + r.Then
+	if r.Given != "" {
+		s = "given " + r.Given + " " + s
+	}
+
+	if r.Kind != "" {
+		s = r.Kind + ": " + s
+	}
+
+	return s
+}
+
+// appendScenario appends the Scenario sentence of r to b.
+func appendScenario(b []byte, r *ScenarioResult) []byte {
+	if r.Kind != "" {
+		b = append(append(b, r.Kind...), ": "...)
+	}
+	if r.Given != "" {
+		b = append(append(append(b, "given "...), r.Given...), ' ')
+	}
+
+	return append(append(append(append(b, "when "...), r.When...), " then "...), r.Then...)
+}
+
+// idInputs holds the buffers in which the hashed inputs of scenario IDs are
+// built, as large tables hash a great many scenarios and only their IDs
+// outlive the inputs.
+var idInputs = sync.Pool{New: func() any { return new([]byte) }}
+
+// scenarioID returns the ID of the scenario described by sentence, run
+// below the test named parent with the given subtest prefix.
+func scenarioID(parent, prefix, sentence string) string {
+	return hashScenario(parent, prefix, nil, sentence)
+}
+
+// resultID is scenarioID of the Scenario sentence of r, which it appends to
+// the pooled input rather than building it as a string.
+func resultID(parent, prefix string, r *ScenarioResult) string {
+	return hashScenario(parent, prefix, r, "")
+}
+
+// hashScenario returns the ID of the scenario described by r, or by sentence
+// when r is nil, run below the test named parent with the given prefix.
+func hashScenario(parent, prefix string, r *ScenarioResult, sentence string) string {
+	bp := idInputs.Get().(*[]byte)
+
+	b := append(append(append(append((*bp)[:0], parent...), 0), prefix...), 0)
+	if r != nil {
+		b = appendScenario(b, r)
+	} else {
+		b = append(b, sentence...)
+	}
+
+	h := sha256.Sum256(b)
+
+	*bp = b
+	idInputs.Put(bp)
+
+	return hex.EncodeToString(h[:6])
+}
+
+// Summary counts scenario results by Status.
+type Summary struct {
+	Total, Passed, Failed, Skipped int
+}
+
+// results holds every ScenarioResult recorded by the process.
+var results struct {
+	mu   sync.Mutex
+	list []ScenarioResult
+	// filtered holds the scenarios excluded by -tbdd.filter, each as the
+	// name of its test and its Scenario sentence.
+	filtered []string
+}
+
+// Results returns a copy of every ScenarioResult recorded so far, in the
+// order the scenarios completed.
+//
+// Results are only recorded for scenarios run with a real *testing.T.
+func Results() []ScenarioResult {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+
+	return slices.Clone(results.list)
+}
+
+// recordFiltered records that -tbdd.filter excluded the scenario of r, run
+// below the test named parent.
+func recordFiltered(parent string, r ScenarioResult) {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+
+	results.filtered = append(results.filtered, parent+": "+r.Scenario())
+}
+
+// filteredOut returns a copy of the scenarios excluded by -tbdd.filter so
+// far, in the order they were excluded.
+func filteredOut() []string {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+
+	return slices.Clone(results.filtered)
+}
+
+// Summarize counts rs by Status.
+func Summarize(rs []ScenarioResult) Summary {
+	s := Summary{Total: len(rs)}
+
+	for _, r := range rs {
+		switch r.Status {
+		case StatusPassed:
+			s.Passed++
+		case StatusFailed:
+			s.Failed++
+		case StatusSkipped:
+			s.Skipped++
+		}
+	}
+
+	return s
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d scenarios: %d passed, %d failed, %d skipped", s.Total, s.Passed, s.Failed, s.Skipped)
+}
+
+// recordResult registers a cleanup on t which records the result of s once
+// t and all of its subtests have completed.
+func recordResult(t *testing.T, s *scenario) {
+	if t == nil {
+		return
+	}
+
+	start := time.Now()
+
+	// doubles left by an earlier test of the same name, such as with -count,
+	// or by one which recorded no result are not the scenario's
+	_ = doublesOf(t.Name())
+
+	t.Cleanup(func() {
+		if s.unselected {
+			return
+		}
+
+		r := s.ScenarioResult
+		r.Test = t.Name()
+		r.Duration = time.Since(start)
+		checkDuplicate(t, s, r)
+		r.Status = statusOf(t)
+		if r.Status == StatusPassed && r.SkipPhase != "" {
+			// the when subtest was skipped within a passing given subtest
+			r.Status = StatusSkipped
+		}
+		r.Class = s.classify(r.Status, retried(r.Test))
+		t.Attr("tbdd.class", r.Class.String())
+		if r.Doubles = doublesOf(r.Test); len(r.Doubles) > 0 {
+			t.Attr("tbdd.doubles", doublesAttr(r.Doubles))
+		}
+
+		if r.Status == StatusFailed && r.Source != "" {
+			t.Log(link(r.Source) + "tbdd: the failed scenario is defined here")
+		}
+		if r.Status == StatusFailed && len(r.Meta) > 0 {
+			t.Logf("tbdd: scenario metadata: %s", formatMeta(r.Meta))
+		}
+		if r.Status == StatusFailed && seedUsed.Load() {
+			r.Reproduce = reproduction(r.Test, config.seed)
+			t.Logf("tbdd: randomized with seed %d; reproduce with: %s", config.seed, r.Reproduce)
+		}
+
+		results.mu.Lock()
+		defer results.mu.Unlock()
+
+		results.list = append(results.list, r)
+	})
+}
+
+// statusT is the subset of *testing.T that result classification depends on.
+type statusT interface {
+	Failed() bool
+	Skipped() bool
+}
+
+func statusOf(t statusT) Status {
+	switch {
+	case t.Failed():
+		return StatusFailed
+	case t.Skipped():
+		return StatusSkipped
+	}
+
+	return StatusPassed
+}
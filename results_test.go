@@ -0,0 +1,269 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"errors"
+	"iter"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ statusT = (*testing.T)(nil)
+
+func TestResults(t *testing.T) {
+	type TC struct {
+		skipIn string
+	}
+
+	var skips []AfterSkip[TC]
+
+	b := GWTN(
+		TC{},
+		"a scenario", func(t *testing.T, tc *TC) {
+			if tc.skipIn == "given" {
+				t.SkipNow()
+			}
+		},
+		"it runs", func(t *testing.T, tc TC) {
+			switch tc.skipIn {
+			case "when":
+				Skipf(t, "skipped %s", "variant")
+			case "when plainly":
+				t.Skip("the reason is not recorded")
+			}
+		},
+		"it completes", func(*testing.T, TC) {},
+	).With(WithHooks(Hooks[TC, struct{}]{
+		AfterSkip: func(_ *testing.T, cfg AfterSkip[TC]) {
+			skips = append(skips, cfg)
+		},
+	}))
+	b.Variants = func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+		return func(yield func(TestVariant[TC]) bool) {
+			for _, phase := range []string{"given", "when", "when plainly"} {
+				tc.skipIn = phase
+				if !yield(TestVariant[TC]{Kind: "skip " + phase, TC: tc}) {
+					return
+				}
+			}
+		}
+	}
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	t.Run("scenarios", b.New(t))
+
+	var got []ScenarioResult
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			got = append(got, r)
+		}
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("expected 4 results but got %d: %+v", len(got), got)
+	}
+
+	if r := got[0]; r.Given != "a scenario" || r.When != "it runs" || r.Then != "it completes" || r.Kind != "" || r.Status != StatusPassed || r.Duration <= 0 || r.SkipPhase != "" {
+		t.Errorf("unexpected basis result: %+v", r)
+	}
+
+	if r := got[1]; r.Kind != "skip given" || r.Status != StatusSkipped || r.SkipPhase != "given" || r.SkipReason != "" || !strings.HasSuffix(r.Test, "/skip_given/given_a_scenario") {
+		t.Errorf("unexpected given variant result: %+v", r)
+	}
+
+	if r := got[2]; r.Kind != "skip when" || r.Status != StatusSkipped || r.SkipPhase != "when" || r.SkipReason != "skipped variant" || !strings.HasSuffix(r.Test, "/skip_when/given_a_scenario") {
+		t.Errorf("unexpected when variant result: %+v", r)
+	}
+
+	if r := got[3]; r.Kind != "skip when plainly" || r.Status != StatusSkipped || r.SkipPhase != "when" || r.SkipReason != "" {
+		t.Errorf("unexpected plain when variant result: %+v", r)
+	}
+
+	if len(skips) != 3 || skips[0].Phase != "given" || skips[0].TC.skipIn != "given" || skips[1].Phase != "when" || skips[1].Reason != "skipped variant" || skips[2].Phase != "when" || skips[2].Reason != "" {
+		t.Errorf("unexpected AfterSkip hook calls: %+v", skips)
+	}
+
+	if s := Summarize(append(got, ScenarioResult{Status: StatusFailed})); s != (Summary{5, 1, 1, 3}) {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+
+	// results are not recorded without a real *testing.T
+	recordResult(nil, &scenario{})
+}
+
+type mStatusT struct {
+	failed, skipped bool
+}
+
+func (t mStatusT) Failed() bool {
+	return t.failed
+}
+
+func (t mStatusT) Skipped() bool {
+	return t.skipped
+}
+
+func Test_statusOf(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		t   mStatusT
+		exp Status
+	}{
+		{mStatusT{}, StatusPassed},
+		{mStatusT{failed: true}, StatusFailed},
+		{mStatusT{skipped: true}, StatusSkipped},
+	} {
+		if s := statusOf(v.t); s != v.exp {
+			t.Errorf("expected %s but got %s", v.exp, s)
+		}
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	t.Parallel()
+
+	for s, exp := range map[Status]string{
+		StatusPassed:  "passed",
+		StatusFailed:  "failed",
+		StatusSkipped: "skipped",
+		0:             "Status(0)",
+	} {
+		if v := s.String(); v != exp {
+			t.Errorf("expected '%s' but got '%s'", exp, v)
+		}
+	}
+}
+
+func TestScenarioResult_Scenario(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		r   ScenarioResult
+		exp string
+	}{
+		{ScenarioResult{When: "w", Then: "t"}, "when w then t"},
+		{ScenarioResult{Given: "g", When: "w", Then: "t", Kind: "k"}, "k: given g when w then t"},
+	} {
+		if s := v.r.Scenario(); s != v.exp {
+			t.Errorf("expected '%s' but got '%s'", v.exp, s)
+		}
+	}
+}
+
+func TestScenarioResult_class(t *testing.T) {
+	pass := func(*testing.T, int) {}
+	fail := func(t *testing.T, _ int) {
+		t.Error("failure")
+	}
+	unready := func(*testing.T, int) error {
+		return errors.New("not ready")
+	}
+
+	if os.Getenv("TBDD_CLASS_HELPER") == "1" {
+		var attempts int
+		for _, b := range []Lifecycle[int, struct{}]{
+			WTN(0, "it passes", pass, "nothing fails", pass),
+			WT(0, "it is flaky", func(*testing.T, int) struct{} { return struct{}{} }, "it passes on retry", Retry(RetryAssert{Attempts: 2}, func(*testing.T, int, struct{}) error {
+				if attempts++; attempts == 1 {
+					return errors.New("not yet")
+				}
+				return nil
+			})),
+			WTN(0, "the behavior is broken", pass, "an assertion fails", fail),
+			GWTN(0, "a failing setup", func(t *testing.T, _ *int) {
+				t.Error("failure")
+			}, "the setup fails", pass, "nothing is asserted", pass),
+			GWTN(0, "a setup", func(*testing.T, *int) {}, "it is misconfigured", pass, "nothing is asserted", pass).With(
+				WithDescribe[int, struct{}](func(*testing.T, Describe[int]) DescribeResponse {
+					return DescribeResponse{"it is misconfigured", ""}
+				}),
+			),
+			WTN(0, "it is quarantined", fail, "nothing is asserted", pass).With(
+				WithSkipUntil[int, struct{}](time.Now().Add(time.Hour), "flaky upstream"),
+			),
+			WTN(0, "the environment is not ready", fail, "it is skipped", pass).With(
+				WithRequire[int, struct{}](RequireSkip, unready),
+			),
+			WTN(0, "the environment must be ready", fail, "it fails", pass).With(
+				WithRequire[int, struct{}](RequireFatal, unready),
+			),
+		} {
+			b.New(t)(t)
+		}
+		return
+	}
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_CLASS_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	if exp := "=== ATTR  " + t.Name() + "/when_it_passes tbdd.class passed\n"; !strings.Contains(string(out), exp) {
+		t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ When, Class string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, v := range r.Results {
+		got[v.When] = v.Class
+	}
+
+	exp := map[string]string{
+		"it passes":                     "passed",
+		"it is flaky":                   "flaky",
+		"the behavior is broken":        "failed-assert",
+		"the setup fails":               "failed-arrange",
+		"it is misconfigured":           "failed-config",
+		"it is quarantined":             "skipped-quarantine",
+		"the environment is not ready":  "skipped-env",
+		"the environment must be ready": "failed-arrange",
+	}
+	if !maps.Equal(got, exp) {
+		t.Errorf("expected classes %v but got %v", exp, got)
+	}
+}
+
+func TestClass_String(t *testing.T) {
+	t.Parallel()
+
+	for c, exp := range map[Class]string{
+		ClassPassed:            "passed",
+		ClassFailedAssert:      "failed-assert",
+		ClassFailedConfig:      "failed-config",
+		ClassFailedArrange:     "failed-arrange",
+		ClassSkippedEnv:        "skipped-env",
+		ClassSkippedQuarantine: "skipped-quarantine",
+		ClassFlaky:             "flaky",
+		ClassSkippedDependency: "skipped-dependency",
+		ClassSkippedBudget:     "skipped-budget",
+		0:                      "Class(0)",
+	} {
+		if b, _ := c.MarshalText(); string(b) != exp {
+			t.Errorf("expected '%s' but got '%s'", exp, b)
+		}
+	}
+}
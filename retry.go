@@ -0,0 +1,126 @@
+package tbdd
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RetryAssert configures how an eventually consistent observation is
+// retried by Retry.
+type RetryAssert struct {
+	// Attempts is the maximum number of times the check is evaluated.
+	// Values less than 1 are treated as 1.
+	Attempts int
+	// Backoff is the base delay between attempts. The delay doubles after
+	// every failed attempt and is jittered to a random value between half
+	// and all of the computed delay.
+	Backoff time.Duration
+	// MaxBackoff optionally caps the computed delay before jitter is applied.
+	MaxBackoff time.Duration
+}
+
+// Retry returns a then function which evaluates check until it returns nil
+// or the configured number of attempts is exhausted.
+//
+// It is intended for flaky external observations such as metrics or queue
+// contents. If every attempt fails, the test is failed with a report listing
+// the outcome of each attempt.
+func Retry[T, R any](cfg RetryAssert, check func(*testing.T, T, R) error) func(*testing.T, T, R) {
+	if check == nil {
+		panic("tbdd.Retry: check function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		if cfg.retry(t, func() error {
+			return check(t, tc, r)
+		}, time.Sleep) {
+			markRetried(t)
+		}
+	}
+}
+
+// retriedTests holds the names of the tests whose Retry checks passed only after
+// failing at least once, until the scenarios running them are recorded.
+var retriedTests struct {
+	mu sync.Mutex
+	m  map[string]bool
+}
+
+// markRetried records that a Retry check of t passed after failing.
+func markRetried(t *testing.T) {
+	retriedTests.mu.Lock()
+	defer retriedTests.mu.Unlock()
+
+	if retriedTests.m == nil {
+		retriedTests.m = map[string]bool{}
+	}
+
+	retriedTests.m[t.Name()] = true
+}
+
+// retried reports whether a Retry check of the test named name, or of one of
+// its subtests, passed after failing, forgetting them.
+func retried(name string) bool {
+	retriedTests.mu.Lock()
+	defer retriedTests.mu.Unlock()
+
+	var found bool
+	for n := range retriedTests.m {
+		if n == name || strings.HasPrefix(n, name+"/") {
+			delete(retriedTests.m, n)
+			found = true
+		}
+	}
+
+	return found
+}
+
+// retry evaluates check until it returns nil or the attempts are exhausted,
+// failing t in the latter case. It reports whether check passed after
+// failing at least once.
+func (cfg RetryAssert) retry(t assertT, check func() error, sleep func(time.Duration)) bool {
+	t.Helper()
+
+	attempts := max(cfg.Attempts, 1)
+
+	var failures []string
+	delay := cfg.Backoff
+	for i := range attempts {
+		err := check()
+		if err == nil {
+			return i > 0
+		}
+
+		failures = append(failures, "attempt "+strconv.Itoa(i+1)+": "+err.Error())
+
+		if i+1 == attempts {
+			break
+		}
+
+		if cfg.MaxBackoff > 0 {
+			delay = min(delay, cfg.MaxBackoff)
+		}
+
+		if delay > 0 {
+			half := delay / 2
+			sleep(half + rand.N(delay-half+1))
+		}
+
+		// without a MaxBackoff the delay saturates rather than overflowing
+		if delay > math.MaxInt64/2 {
+			delay = math.MaxInt64
+		} else {
+			delay *= 2
+		}
+	}
+
+	t.Fatalf("assertion failed after %d attempt(s):\n\t%s", attempts, strings.Join(failures, "\n\t"))
+	return false
+}
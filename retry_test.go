@@ -0,0 +1,127 @@
+package tbdd
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var calls int
+	b := WT(
+		struct{}{},
+		"a message is published", func(*testing.T, struct{}) int {
+			return 3
+		},
+		"it is eventually consumed", Retry(RetryAssert{Attempts: 5, Backoff: time.Microsecond}, func(_ *testing.T, _ struct{}, n int) error {
+			calls++
+			if calls < n {
+				return errors.New("not yet")
+			}
+			return nil
+		}),
+	)
+
+	f := b.New(t)
+	f(t)
+
+	if calls != 3 {
+		t.Errorf("expected 3 attempts but got %d", calls)
+	}
+
+	{
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			Retry[struct{}, int](RetryAssert{}, nil)
+		}()
+
+		if exp := "tbdd.Retry: check function must be non-nil"; r != exp {
+			t.Errorf("expected panic '%s' but got '%v'", exp, r)
+		}
+	}
+}
+
+func TestRetryAssert_retry(t *testing.T) {
+	t.Parallel()
+
+	{
+		mt := &mT{}
+
+		var delays []time.Duration
+		var calls int
+		RetryAssert{Attempts: 4, Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}.retry(mt, func() error {
+			calls++
+			return errors.New("fail " + strconv.Itoa(calls))
+		}, func(d time.Duration) {
+			delays = append(delays, d)
+		})
+
+		if calls != 4 {
+			t.Errorf("expected 4 attempts but got %d", calls)
+		}
+
+		// base delays are 10ms, 20ms, then capped at 25ms
+		for i, maxDelay := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+			if i >= len(delays) {
+				t.Fatalf("expected %d delays but got %d", 3, len(delays))
+			}
+
+			if delays[i] < maxDelay/2 || delays[i] > maxDelay {
+				t.Errorf("delay %d: expected a value in [%v, %v] but got %v", i, maxDelay/2, maxDelay, delays[i])
+			}
+		}
+
+		if len(mt.fatalfCalls) != 1 {
+			t.Fatalf("expected 1 fatalf call but got %d", len(mt.fatalfCalls))
+		}
+
+		if exp := "fail 1\n\tattempt 2: fail 2\n\tattempt 3: fail 3\n\tattempt 4: fail 4"; mt.fatalfCalls[0].args[1] != "attempt 1: "+exp {
+			t.Errorf("unexpected failure report: %v", mt.fatalfCalls[0].args[1])
+		}
+	}
+
+	{
+		mt := &mT{}
+
+		var calls int
+		RetryAssert{}.retry(mt, func() error {
+			calls++
+			return errors.New("fail")
+		}, func(time.Duration) {
+			t.Error("no delay expected")
+		})
+
+		if calls != 1 || len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].args[0] != 1 {
+			t.Error("expected a single failed attempt")
+		}
+	}
+
+	{
+		mt := &mT{}
+
+		// without a MaxBackoff, the delays of many attempts saturate rather than
+		// overflowing to negative durations which skip the sleep
+		var delays []time.Duration
+		RetryAssert{Attempts: 100, Backoff: time.Nanosecond}.retry(mt, func() error {
+			return errors.New("fail")
+		}, func(d time.Duration) {
+			delays = append(delays, d)
+		})
+
+		if len(delays) != 99 {
+			t.Fatalf("expected 99 delays but got %d", len(delays))
+		}
+
+		for i, d := range delays[63:] {
+			if d < math.MaxInt64/2 {
+				t.Errorf("delay %d: expected a saturated delay but got %v", i+63, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package tbdd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Branch is one of the expected outcomes of a behavior among which ThenRoute
+// selects at runtime.
+type Branch[T, R any] struct {
+	// Then describes the outcome and names the subtest of the branch.
+	Then string
+	// Assert performs the assertions of the outcome.
+	Assert func(*testing.T, T, R)
+}
+
+// routeT is the subset of *testing.T a route needs.
+type routeT interface {
+	assertT
+	runT
+}
+
+// ThenRoute returns a then function for behaviors whose expected outcome
+// depends on runtime conditions declared in the test case. route maps the
+// final test case and result to the Then description of one of the
+// branches, whose Assert function then runs in a subtest named after it, so
+// every scenario reports the outcome it was held to:
+//
+//	tbdd.GWT(tc, given, givenF, when, whenF,
+//		"it is handled as the quota allows", tbdd.ThenRoute(
+//			func(tc TC, r Result) string {
+//				if tc.Quota == 0 {
+//					return "it is rejected"
+//				}
+//				return "it is accepted"
+//			},
+//			tbdd.Branch[TC, Result]{Then: "it is accepted", Assert: accepted},
+//			tbdd.Branch[TC, Result]{Then: "it is rejected", Assert: rejected},
+//		),
+//	)
+//
+// A route selecting an unknown branch fails the test naming the branches.
+//
+// ThenRoute panics if route is nil, no branches are given, or a branch has
+// an empty or duplicate Then description or a nil Assert function.
+func ThenRoute[T, R any](route func(T, R) string, branches ...Branch[T, R]) func(*testing.T, T, R) {
+	if route == nil {
+		panic("tbdd.ThenRoute: route function must be non-nil")
+	}
+
+	if len(branches) == 0 {
+		panic("tbdd.ThenRoute: at least one branch is required")
+	}
+
+	index := make(map[string]int, len(branches))
+	for i, b := range branches {
+		prefix := "tbdd.ThenRoute: branch " + strconv.Itoa(i)
+		switch _, dup := index[b.Then]; {
+		case b.Then == "":
+			panic(prefix + " must have a non-empty Then description")
+		case dup:
+			panic(prefix + " has the duplicate Then description " + strconv.Quote(b.Then))
+		case b.Assert == nil:
+			panic(prefix + " must have a non-nil Assert function")
+		}
+
+		index[b.Then] = i
+	}
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		refuseBubble(t, "ThenRoute")
+		routeThen(t, branches, index, route(tc, r), tc, r)
+	}
+}
+
+// routeThen runs the branch described by then as a subtest of t, failing t
+// if there is no such branch.
+func routeThen[T, R any](t routeT, branches []Branch[T, R], index map[string]int, then string, tc T, r R) {
+	t.Helper()
+
+	i, ok := index[then]
+	if !ok {
+		names := make([]string, len(branches))
+		for i, b := range branches {
+			names[i] = strconv.Quote(b.Then)
+		}
+
+		t.Fatalf("tbdd: route selected the unknown then branch %q; the branches are %s", then, strings.Join(names, ", "))
+		return
+	}
+
+	assert := branches[i].Assert
+	t.Run(then, func(t *testing.T) {
+		assert(t, tc, r)
+	})
+}
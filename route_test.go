@@ -0,0 +1,100 @@
+package tbdd
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestThenRoute(t *testing.T) {
+	var ran []string
+	record := func(t *testing.T, _, _ int) {
+		ran = append(ran, t.Name())
+	}
+
+	f := GWT(
+		2,
+		"a number", func(*testing.T, *int) {},
+		"it is halved", func(_ *testing.T, tc int) int { return tc / 2 },
+		"it is routed by parity", ThenRoute(
+			func(tc int, r int) string {
+				if r*2 == tc {
+					return "it is exact"
+				}
+				return "it is truncated"
+			},
+			Branch[int, int]{Then: "it is exact", Assert: record},
+			Branch[int, int]{Then: "it is truncated", Assert: record},
+		),
+	).With(WithVariants[int, int](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+		return slices.Values([]TestVariant[int]{{Kind: "odd", TC: 3}})
+	})).New(t)
+	f(t)
+
+	exp := []string{
+		t.Name() + "/given_a_number/when_it_is_halved/then_it_is_routed_by_parity/it_is_exact",
+		t.Name() + "/odd/given_a_number/when_it_is_halved/then_it_is_routed_by_parity/it_is_truncated",
+	}
+	if !slices.Equal(ran, exp) {
+		t.Errorf("expected the branches %v to run but got %v", exp, ran)
+	}
+}
+
+func Test_routeThen(t *testing.T) {
+	t.Parallel()
+
+	pass := func(*testing.T, int, int) {}
+	branches := []Branch[int, int]{{"a", pass}, {"b", pass}}
+	index := map[string]int{"a": 0, "b": 1}
+
+	mt := &mT{}
+	routeThen(mt, branches, index, "b", 0, 0)
+
+	if len(mt.runCalls) != 1 || mt.runCalls[0] != "b" || len(mt.fatalfCalls) != 0 {
+		t.Errorf("expected the branch b to run but got runs %v and fatalf calls %v", mt.runCalls, mt.fatalfCalls)
+	}
+
+	mt = &mT{}
+	routeThen(mt, branches, index, "c", 0, 0)
+
+	if len(mt.runCalls) != 0 || len(mt.fatalfCalls) != 1 {
+		t.Fatalf("expected one fatalf call and no runs but got runs %v and fatalf calls %v", mt.runCalls, mt.fatalfCalls)
+	}
+
+	if msg, exp := mt.fatalfCalls[0].format, `tbdd: route selected the unknown then branch %q; the branches are %s`; msg != exp {
+		t.Errorf("expected the format '%s' but got '%s'", exp, msg)
+	}
+
+	if args := mt.fatalfCalls[0].args; args[0] != "c" || args[1] != `"a", "b"` {
+		t.Errorf("unexpected fatalf args: %v", args)
+	}
+}
+
+func TestThenRoute_panics(t *testing.T) {
+	route := func(int, int) string { return "" }
+	pass := func(*testing.T, int, int) {}
+
+	tests := []struct {
+		name string
+		f    func()
+		exp  string
+	}{
+		{"nil route", func() { ThenRoute(nil, Branch[int, int]{"a", pass}) }, "tbdd.ThenRoute: route function must be non-nil"},
+		{"no branches", func() { ThenRoute(route) }, "tbdd.ThenRoute: at least one branch is required"},
+		{"empty then", func() { ThenRoute(route, Branch[int, int]{Assert: pass}) }, "tbdd.ThenRoute: branch 0 must have a non-empty Then description"},
+		{"duplicate then", func() { ThenRoute(route, Branch[int, int]{"a", pass}, Branch[int, int]{"a", pass}) }, `tbdd.ThenRoute: branch 1 has the duplicate Then description "a"`},
+		{"nil assert", func() { ThenRoute(route, Branch[int, int]{Then: "a"}) }, "tbdd.ThenRoute: branch 0 must have a non-nil Assert function"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			tc.f()
+		})
+	}
+}
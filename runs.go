@@ -0,0 +1,75 @@
+package tbdd
+
+import (
+	"strconv"
+	"testing"
+)
+
+// ActRuns returns an Act function which runs act n times in sequence and
+// returns the result of every run in order, so Assert can make statistical
+// assertions about a nondeterministic behavior, such as all results being
+// equal or a percentile staying within bounds:
+//
+//	tbdd.WT(tc,
+//		"the request is repeated", tbdd.ActRuns(100, send),
+//		"it is fast enough", func(t *testing.T, _ TC, rs []Response) { ... },
+//	)
+//
+// Each run executes in its own subtest named "run 1" through "run n". When
+// any run fails the Act fails once every run has completed, so Assert is not
+// evaluated against an incomplete set of results.
+//
+// ActRuns panics if n is less than 1 or act is nil.
+func ActRuns[T, R any](n int, act func(*testing.T, T) R) func(*testing.T, T) []R {
+	return actRuns("tbdd.ActRuns", n, false, act)
+}
+
+// ActRunsParallel is like ActRuns except the runs execute in parallel. They
+// are grouped under a subtest named "runs" which completes once every run
+// has.
+//
+// Since the runs share the test case, act must not mutate it.
+func ActRunsParallel[T, R any](n int, act func(*testing.T, T) R) func(*testing.T, T) []R {
+	return actRuns("tbdd.ActRunsParallel", n, true, act)
+}
+
+func actRuns[T, R any](name string, n int, parallel bool, act func(*testing.T, T) R) func(*testing.T, T) []R {
+	if n < 1 {
+		panic(name + ": number of runs must be at least 1")
+	}
+
+	if act == nil {
+		panic(name + ": act function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) []R {
+		t.Helper()
+
+		refuseBubble(t, name)
+
+		rs := make([]R, n)
+		runAll := func(t *testing.T) {
+			for i := range rs {
+				t.Run("run "+strconv.Itoa(i+1), func(t *testing.T) {
+					if parallel {
+						t.Parallel()
+					}
+
+					rs[i] = act(t, tc)
+				})
+			}
+		}
+
+		if parallel {
+			t.Run("runs", runAll)
+		} else {
+			runAll(t)
+		}
+
+		if t.Failed() {
+			t.Fatalf("%d runs did not all succeed", n)
+		}
+
+		return rs
+	}
+}
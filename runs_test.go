@@ -0,0 +1,103 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestActRuns(t *testing.T) {
+	var n atomic.Int32
+	count := func(*testing.T, struct{}) int32 {
+		return n.Add(1)
+	}
+
+	WT(
+		struct{}{},
+		"the action is repeated", ActRuns(3, count),
+		"every result is kept in order", func(t *testing.T, _ struct{}, rs []int32) {
+			if !slices.Equal(rs, []int32{1, 2, 3}) {
+				t.Errorf("expected results [1 2 3] but got %v", rs)
+			}
+		},
+	).New(t)(t)
+
+	WT(
+		struct{}{},
+		"the action is repeated in parallel", ActRunsParallel(5, count),
+		"every result is kept", func(t *testing.T, _ struct{}, rs []int32) {
+			slices.Sort(rs)
+			if !slices.Equal(rs, []int32{4, 5, 6, 7, 8}) {
+				t.Errorf("expected results [4 5 6 7 8] but got %v", rs)
+			}
+		},
+	).New(t)(t)
+}
+
+func TestActRuns_panics(t *testing.T) {
+	t.Parallel()
+
+	act := func(*testing.T, struct{}) int { return 0 }
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { ActRuns(0, act) }, "tbdd.ActRuns: number of runs must be at least 1"},
+		{func() { ActRunsParallel[struct{}, int](1, nil) }, "tbdd.ActRunsParallel: act function must be non-nil"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != v.exp {
+					t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+				}
+			}()
+
+			v.f()
+		}()
+	}
+}
+
+func TestActRuns_failure(t *testing.T) {
+	if os.Getenv("TBDD_ACT_RUNS_HELPER") == "1" {
+		var n int
+		f := WT(
+			struct{}{},
+			"one run fails", ActRuns(3, func(t *testing.T, _ struct{}) int {
+				n++
+				if n == 2 {
+					t.Error("flaked")
+				}
+
+				return n
+			}),
+			"it is not asserted", func(*testing.T, struct{}, []int) {},
+		).New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_ACT_RUNS_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", b)
+	}
+
+	out := string(b)
+	for _, exp := range []string{
+		"--- PASS: " + t.Name() + "/when_one_run_fails/run_1 ",
+		"--- FAIL: " + t.Name() + "/when_one_run_fails/run_2 ",
+		"--- PASS: " + t.Name() + "/when_one_run_fails/run_3 ",
+		"3 runs did not all succeed",
+		"--- SKIP: " + t.Name() + "/when_one_run_fails/then_it_is_not_asserted ",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
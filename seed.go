@@ -0,0 +1,70 @@
+package tbdd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// seedUsed is set once Seed is first called, after which failures report
+// the seed needed to reproduce them.
+var seedUsed atomic.Bool
+
+// variantBases maps the test generating the variants of a Lifecycle to the
+// Lifecycle's Seed while they are generated.
+var variantBases sync.Map
+
+// variantSeed returns the seed of the basis scenario of the Lifecycle
+// generating variants within t, or the run seed outside of one, from which
+// Variants functions such as StateMachine.Random derive their randomness.
+func variantSeed(t *testing.T) int64 {
+	if t != nil {
+		if base, ok := variantBases.Load(t); ok && base.(int64) != 0 {
+			return base.(int64)
+		}
+	}
+
+	return Seed()
+}
+
+// reproduction returns a go test command which reruns the test named name
+// with -tbdd.seed set to seed.
+func reproduction(name string, seed int64) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(p) + "$"
+	}
+
+	pattern := strings.ReplaceAll(strings.Join(parts, "/"), "'", `'\''`)
+
+	return "go test -run '" + pattern + "' -tbdd.seed=" + strconv.FormatInt(seed, 10)
+}
+
+// scenarioSeed returns the seed of the scenario of the variant kind of a
+// Lifecycle whose Seed is base: base itself for the basis test case, which
+// has no kind, otherwise the FNV-1a hash of base and kind. A zero base is
+// replaced by the run seed.
+func scenarioSeed(base int64, kind string) int64 {
+	if base == 0 {
+		base = config.seed
+	}
+
+	if kind == "" {
+		return base
+	}
+
+	const prime = 1099511628211
+
+	h := uint64(14695981039346656037)
+	for i := range 8 {
+		h = (h ^ uint64(byte(base>>(8*i)))) * prime
+	}
+	for i := range len(kind) {
+		h = (h ^ uint64(kind[i])) * prime
+	}
+
+	return int64(h)
+}
@@ -0,0 +1,174 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"iter"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSeed_failures(t *testing.T) {
+	if os.Getenv("TBDD_SEED_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"a randomized behavior fails", func(t *testing.T, _ struct{}) {
+				t.Errorf("failed with seed %d", Seed())
+			},
+			"the seed is reported", func(*testing.T, struct{}) {},
+		).New(t)
+		f(t)
+
+		failing := WTN(
+			struct{}{},
+			"a shuffled scenario fails", func(t *testing.T, _ struct{}) {
+				t.Error("failure")
+			},
+			"the shuffle seed is reported", func(*testing.T, struct{}) {},
+		)
+		t.Run("group", Independent(failing).Shuffle(9).New(t))
+		return
+	}
+
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.seed=42", "-tbdd.report="+report)
+	cmd.Env = append(os.Environ(), "TBDD_SEED_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	repro := "go test -run '^" + t.Name() + "$/^when_a_randomized_behavior_fails$' -tbdd.seed=42"
+	for _, exp := range []string{
+		"failed with seed 42",
+		"tbdd: randomized with seed 42; reproduce with: " + repro + "\n",
+		"tbdd: ran in an order shuffled with seed 9; pass the same seed to Shuffle to reproduce it\n",
+		"tbdd: randomized with seed 42; rerun with -tbdd.seed=42 to reproduce the failures\n",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Results []struct{ Reproduce string }
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Results) != 2 || r.Results[0].Reproduce != repro {
+		t.Errorf("expected the report to record the reproduction command but got %+v", r.Results)
+	}
+}
+
+func Test_reproduction(t *testing.T) {
+	t.Parallel()
+
+	exp := `go test -run '^TestX$/^given_a_\(user\)$/^when_it'\''s_\[1\]$' -tbdd.seed=-7`
+	if got := reproduction("TestX/given_a_(user)/when_it's_[1]", -7); got != exp {
+		t.Errorf("expected %s but got %s", exp, got)
+	}
+}
+
+func Test_scenarioSeed(t *testing.T) {
+	t.Parallel()
+
+	if got := scenarioSeed(7, ""); got != 7 {
+		t.Errorf("expected the basis scenario to receive the base seed but got %d", got)
+	}
+
+	if got := scenarioSeed(0, ""); got != config.seed {
+		t.Errorf("expected a zero base to be replaced by the run seed %d but got %d", config.seed, got)
+	}
+
+	a, b := scenarioSeed(7, "a"), scenarioSeed(7, "b")
+	if a == b || a == 7 || a != scenarioSeed(7, "a") || a == scenarioSeed(8, "a") {
+		t.Errorf("expected deterministic seeds distinct per base and kind but got %d and %d", a, b)
+	}
+}
+
+func TestLifecycle_seed(t *testing.T) {
+	seeds := map[string][]int64{}
+	record := func(tc string, seed int64) {
+		seeds[tc] = append(seeds[tc], seed)
+	}
+
+	b := New(
+		"basis",
+		WithSeed[string, struct{}](7),
+		WithArrange(func(_ *testing.T, cfg Arrange[string, struct{}]) (string, func(*testing.T)) {
+			record(*cfg.TC, cfg.Seed)
+			return "a seeded lifecycle", func(*testing.T) {}
+		}),
+		WithDescribe[string, struct{}](func(_ *testing.T, cfg Describe[string]) DescribeResponse {
+			record(cfg.TC, cfg.Seed)
+			return DescribeResponse{"it runs", "every phase receives the scenario seed"}
+		}),
+		WithAct(func(*testing.T, string) struct{} {
+			return struct{}{}
+		}),
+		WithAssert(func(_ *testing.T, cfg Assert[string, struct{}]) {
+			record(cfg.TC, cfg.Seed)
+		}),
+		WithHooks(Hooks[string, struct{}]{
+			AfterArrange: func(_ *testing.T, cfg AfterArrange[string]) {
+				record(*cfg.TC, cfg.Seed)
+			},
+			AfterGiven: func(_ *testing.T, cfg AfterGiven[string]) {
+				record(*cfg.TC, cfg.Seed)
+			},
+			AfterAct: func(_ *testing.T, cfg AfterAct[string, struct{}]) {
+				record(*cfg.TC, cfg.Seed)
+			},
+			AfterAssert: func(_ *testing.T, cfg AfterAssert[string, struct{}]) {
+				record(*cfg.TC, cfg.Seed)
+			},
+		}),
+		WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[TestVariant[string]] {
+			return slices.Values([]TestVariant[string]{{Kind: "a", TC: "a"}, {Kind: "b", TC: "b"}})
+		}),
+	)
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	b.New(t)(t)
+
+	for tc, exp := range map[string]int64{"basis": 7, "a": scenarioSeed(7, "a"), "b": scenarioSeed(7, "b")} {
+		if got := seeds[tc]; len(got) != 7 || slices.ContainsFunc(got, func(s int64) bool { return s != exp }) {
+			t.Errorf("expected every phase of %s to receive seed %d but got %v", tc, exp, got)
+		}
+	}
+
+	var recorded []int64
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			recorded = append(recorded, r.Seed)
+		}
+	}
+
+	if exp := []int64{7, scenarioSeed(7, "a"), scenarioSeed(7, "b")}; !slices.Equal(recorded, exp) {
+		t.Errorf("expected results to record seeds %v but got %v", exp, recorded)
+	}
+
+	plan := b.Plan(t)
+	planned := []int64{plan.Basis.Seed}
+	for _, s := range plan.Variants {
+		planned = append(planned, s.Seed)
+	}
+
+	if exp := []int64{7, scenarioSeed(7, "a"), scenarioSeed(7, "b")}; !slices.Equal(planned, exp) {
+		t.Errorf("expected plans to report seeds %v but got %v", exp, planned)
+	}
+}
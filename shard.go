@@ -0,0 +1,70 @@
+package tbdd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Shard is one of Total parts of the scenarios of a run, so CI can split a
+// large suite across machines, each running one shard, by passing
+// -tbdd.shard=Index/Total or setting TBDD_SHARD.
+//
+// Scenarios are assigned to shards by their ScenarioResult.ID, so the
+// assignment is the same on every machine and does not change as other
+// scenarios are added or removed.
+type Shard struct {
+	// Index is the zero based index of the shard, less than Total.
+	Index int
+	// Total is the number of shards; zero and one mean the run is not
+	// sharded.
+	Total int
+}
+
+// shardEnv is the environment variable which sets the default of the
+// -tbdd.shard flag.
+const shardEnv = "TBDD_SHARD"
+
+// ParseShard parses a shard in the form "index/total", such as "0/4".
+func ParseShard(s string) (Shard, error) {
+	index, total, ok := strings.Cut(s, "/")
+	if !ok {
+		return Shard{}, errors.New(strconv.Quote(s) + " is not of the form index/total")
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return Shard{}, errors.New(strconv.Quote(s) + " has an invalid index: " + err.Error())
+	}
+
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return Shard{}, errors.New(strconv.Quote(s) + " has an invalid total: " + err.Error())
+	}
+
+	if n < 1 || i < 0 || i >= n {
+		return Shard{}, errors.New(strconv.Quote(s) + " is not a shard: the index must be at least 0 and less than the total")
+	}
+
+	return Shard{i, n}, nil
+}
+
+func (s Shard) String() string {
+	return strconv.Itoa(s.Index) + "/" + strconv.Itoa(s.Total)
+}
+
+// Contains reports whether the scenario with the given ID belongs to s. Every
+// scenario belongs to a run which is not sharded.
+func (s Shard) Contains(id string) bool {
+	if s.Total <= 1 {
+		return true
+	}
+
+	// IDs are hex encoded hashes, so any prefix of one is evenly distributed
+	h, err := strconv.ParseUint(id[:min(len(id), 8)], 16, 32)
+	if err != nil {
+		return true
+	}
+
+	return int(h%uint64(s.Total)) == s.Index
+}
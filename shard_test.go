@@ -0,0 +1,104 @@
+package tbdd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseShard(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		s   string
+		exp Shard
+		err string
+	}{
+		{"0/4", Shard{0, 4}, ""},
+		{"3/4", Shard{3, 4}, ""},
+		{"0/1", Shard{0, 1}, ""},
+		{"4", Shard{}, `"4" is not of the form index/total`},
+		{"x/4", Shard{}, `"x/4" has an invalid index: `},
+		{"0/x", Shard{}, `"0/x" has an invalid total: `},
+		{"4/4", Shard{}, `"4/4" is not a shard: `},
+		{"-1/4", Shard{}, `"-1/4" is not a shard: `},
+		{"0/0", Shard{}, `"0/0" is not a shard: `},
+	} {
+		t.Run(c.s, func(t *testing.T) {
+			s, err := ParseShard(c.s)
+
+			Equal(t, s, c.exp)
+			if c.err == "" {
+				True(t, err == nil && s.String() == c.s)
+			} else {
+				True(t, err != nil && strings.HasPrefix(err.Error(), c.err))
+			}
+		})
+	}
+}
+
+func TestShard_Contains(t *testing.T) {
+	t.Parallel()
+
+	counts := make([]int, 3)
+	for i := range 3000 {
+		id := scenarioID("TestX", "", strconv.Itoa(i))
+
+		var in int
+		for j := range counts {
+			if (Shard{j, 3}).Contains(id) {
+				counts[j]++
+				in++
+			}
+		}
+
+		Equal(t, in, 1)
+		True(t, Shard{}.Contains(id) && (Shard{0, 1}).Contains(id))
+	}
+
+	for _, n := range counts {
+		True(t, n > 900 && n < 1100)
+	}
+}
+
+func TestLifecycle_shard(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	config.shard = Shard{1, 2}
+
+	var ran []string
+	for i := range 20 {
+		when := "scenario " + strconv.Itoa(i) + " runs"
+		act := func(*testing.T, int) {
+			ran = append(ran, when)
+		}
+
+		if i%2 == 0 {
+			WTN(0, when, act, "it passes", func(*testing.T, int) {}).New(t)(t)
+		} else {
+			GWTN(0, "a shard", func(*testing.T, *int) {}, when, act, "it passes", func(*testing.T, int) {}).New(t)(t)
+		}
+	}
+
+	var exp []string
+	for i := range 20 {
+		sentence := "when scenario " + strconv.Itoa(i) + " runs then it passes"
+		if i%2 != 0 {
+			sentence = "given a shard " + sentence
+		}
+
+		if config.shard.Contains(scenarioID(t.Name(), "", sentence)) {
+			exp = append(exp, "scenario "+strconv.Itoa(i)+" runs")
+		}
+	}
+
+	Equal(t, ran, exp)
+	True(t, len(exp) > 0 && len(exp) < 20)
+
+	p := WTN(0, "scenario 0 runs", func(*testing.T, int) {}, "it passes", func(*testing.T, int) {}).Plan(t)
+	True(t, p.Basis.Skip == "" || p.Basis.Skip == "excluded by -tbdd.shard 1/2")
+	Equal(t, p.Basis.Skip == "", config.shard.Contains(p.Basis.ID))
+}
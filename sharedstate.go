@@ -0,0 +1,250 @@
+package tbdd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// region is a span of mutable memory reachable from a test case.
+type region struct {
+	start, end uintptr
+	// kind is "pointer", "slice", "map", or "channel".
+	kind string
+	// path locates the value referring to the memory, such as
+	// "TC.Items[2].Tags".
+	path string
+	// parent is the index of the region the memory was reached through,
+	// or -1.
+	parent int
+}
+
+// maxSharedStateScenarios is the number of scenarios of a Lifecycle whose
+// test cases are compared by SharedStateCheck. Every scenario is compared
+// with each earlier one, and their test cases are kept reachable, so the
+// time and memory of the check grow without bound with large tables.
+const maxSharedStateScenarios = 1000
+
+// sharedState fingerprints the mutable memory reachable from the test case
+// of each scenario of a Lifecycle so scenarios sharing it can be reported.
+type sharedState struct {
+	scenarios []fingerprint
+	// tcs keeps earlier test cases reachable so their memory cannot be
+	// reused by later ones.
+	tcs []any
+	// full is set once maxSharedStateScenarios were fingerprinted.
+	full bool
+}
+
+type fingerprint struct {
+	kind    string
+	regions []region
+}
+
+// add fingerprints tc, the test case of the scenario of the given variant
+// kind, and returns a message for each earlier scenario whose test case
+// shares mutable memory with it. Once maxSharedStateScenarios were
+// fingerprinted, tc is not checked, and add reports whether it is the first
+// test case left unchecked.
+func (s *sharedState) add(kind string, tc any) ([]string, bool) {
+	if len(s.scenarios) >= maxSharedStateScenarios {
+		first := !s.full
+		s.full = true
+
+		return nil, first
+	}
+
+	w := regionWalker{visited: map[visit]bool{}, parent: -1}
+	w.walk(reflect.ValueOf(tc), "TC")
+
+	var msgs []string
+	for _, prev := range s.scenarios {
+		var shared []string
+
+		// memory reached through shared memory is shared as well, so only
+		// the outermost regions are reported
+		reported := make([]bool, len(w.regions))
+		for i, r := range w.regions {
+			if r.parent >= 0 && reported[r.parent] {
+				reported[i] = true
+				continue
+			}
+
+			// the shortest path best describes memory reachable many ways
+			var match *region
+			for j, p := range prev.regions {
+				if r.start < p.end && p.start < r.end && (match == nil || len(p.path) < len(match.path)) {
+					match = &prev.regions[j]
+				}
+			}
+
+			if match != nil {
+				reported[i] = true
+				shared = append(shared, r.kind+" "+r.path+" ("+match.path+")")
+			}
+		}
+
+		if len(shared) > 0 {
+			msgs = append(msgs, fmt.Sprintf(
+				"the test case of %s shares mutable memory with that of %s, which races when they run in parallel; "+
+					"set CloneTC to a deep copy or tag fields holding intentionally shared state `tbdd:\"shared\"`:\n\t%s",
+				scenarioLabel(kind), scenarioLabel(prev.kind), strings.Join(shared, "\n\t"),
+			))
+		}
+	}
+
+	s.scenarios = append(s.scenarios, fingerprint{kind, w.regions})
+	s.tcs = append(s.tcs, tc)
+
+	return msgs, false
+}
+
+func scenarioLabel(kind string) string {
+	if kind == "" {
+		return "the basis scenario"
+	}
+
+	return "variant " + strconv.Quote(kind)
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// regionWalker collects the regions reachable from a value.
+type regionWalker struct {
+	regions []region
+	visited map[visit]bool
+	// parent is the index of the region being walked, or -1.
+	parent int
+}
+
+// add records a region and returns a function restoring the current
+// parent once the memory reachable through the region has been walked.
+func (w *regionWalker) add(ptr, size uintptr, kind, path string) func() {
+	parent := w.parent
+
+	w.parent = len(w.regions)
+	w.regions = append(w.regions, region{ptr, ptr + size, kind, path, parent})
+
+	return func() {
+		w.parent = parent
+	}
+}
+
+// enter reports whether the memory at ptr has not been walked as type typ
+// with length n yet, marking it as walked.
+func (w *regionWalker) enter(ptr uintptr, typ reflect.Type, n int) bool {
+	k := visit{ptr, typ, n}
+	if w.visited[k] {
+		return false
+	}
+
+	w.visited[k] = true
+	return true
+}
+
+func (w *regionWalker) walk(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		// every pointer to a zero-size value may share one address
+		if size := v.Type().Elem().Size(); size > 0 {
+			defer w.add(v.Pointer(), size, "pointer", path)()
+		}
+
+		if w.enter(v.Pointer(), v.Type(), 0) {
+			w.walk(v.Elem(), path)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			w.walk(v.Elem(), path)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+
+		defer w.add(v.Pointer(), 1, "map", path)()
+
+		if !w.enter(v.Pointer(), v.Type(), 0) || !mayRefer(v.Type().Elem()) {
+			return
+		}
+
+		for it := v.MapRange(); it.Next(); {
+			w.walk(it.Value(), path+"["+mapKeyString(it.Key())+"]")
+		}
+	case reflect.Slice:
+		size := uintptr(v.Cap()) * v.Type().Elem().Size()
+		if size == 0 {
+			return
+		}
+
+		defer w.add(v.Pointer(), size, "slice", path)()
+
+		if !w.enter(v.Pointer(), v.Type(), v.Len()) || !mayRefer(v.Type().Elem()) {
+			return
+		}
+
+		for i := range v.Len() {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Array:
+		if !mayRefer(v.Type().Elem()) {
+			return
+		}
+
+		for i := range v.Len() {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if f.Tag.Get("tbdd") == "shared" || !mayRefer(f.Type) {
+				continue
+			}
+
+			w.walk(v.Field(i), path+"."+f.Name)
+		}
+	case reflect.Chan:
+		if !v.IsNil() {
+			w.add(v.Pointer(), 1, "channel", path)()
+		}
+	}
+}
+
+// mayRefer reports whether values of type t may refer to mutable memory.
+func mayRefer(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && mayRefer(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if mayRefer(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func mapKeyString(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return strconv.Quote(k.String())
+	}
+
+	if k.CanInterface() {
+		return fmt.Sprint(k.Interface())
+	}
+
+	return "?"
+}
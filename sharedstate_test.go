@@ -0,0 +1,210 @@
+package tbdd
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type sharedTC struct {
+	Items  []int
+	Tags   map[string]*int
+	Next   *sharedTC
+	Config *struct{ Name string } `tbdd:"shared"`
+	Any    any
+	Events chan int
+	Empty  *struct{}
+	Names  [2]string
+	ptrs   [1]*int
+	Fn     func()
+	Nested []struct{ P *int }
+}
+
+func TestLifecycle_sharedStateCheck(t *testing.T) {
+	t.Parallel()
+
+	deepClone := func(tc sharedTC) sharedTC {
+		tc.Items = slices.Clone(tc.Items)
+		tc.Tags = maps.Clone(tc.Tags)
+		return tc
+	}
+
+	for _, v := range []struct {
+		clone func(sharedTC) sharedTC
+		exp   []string
+	}{
+		{nil, []string{"slice TC.Items (TC.Items)", "map TC.Tags (TC.Tags)"}},
+		{deepClone, nil},
+	} {
+		mt := &mT{}
+
+		b := WTN(
+			sharedTC{Items: []int{1}, Tags: map[string]*int{}},
+			"the variants run", func(*testing.T, sharedTC) {},
+			"they do not share state", func(*testing.T, sharedTC) {},
+		).With(
+			WithSharedStateCheck[sharedTC, struct{}](),
+			WithVariants[sharedTC, struct{}](func(_ *testing.T, tc sharedTC) iter.Seq[TestVariant[sharedTC]] {
+				return func(yield func(TestVariant[sharedTC]) bool) {
+					yield(TestVariant[sharedTC]{Kind: "copy", TC: tc})
+				}
+			}),
+		)
+		b.CloneTC = v.clone
+		b.getT = nilGetT
+
+		(lifecycle[sharedTC, struct{}])(b).new(mt)(mt)
+
+		if v.exp == nil {
+			if len(mt.errorCalls) != 0 {
+				t.Errorf("expected deep clones to share nothing but got %v", mt.errorCalls)
+			}
+			continue
+		}
+
+		if len(mt.errorCalls) != 1 {
+			t.Fatalf("expected one error but got %v", mt.errorCalls)
+		}
+
+		msg := mt.errorCalls[0][0].(string)
+		if !strings.HasPrefix(msg, `the test case of variant "copy" shares mutable memory with that of the basis scenario, `) || !strings.HasSuffix(msg, ":\n\t"+strings.Join(v.exp, "\n\t")) {
+			t.Errorf("unexpected error: %s", msg)
+		}
+	}
+}
+
+func Test_sharedState(t *testing.T) {
+	t.Parallel()
+
+	one, two := 1, 2
+	backing := make([]int, 4)
+	cfg := &struct{ Name string }{"shared"}
+
+	a := sharedTC{
+		Items:  backing[:2],
+		Tags:   map[string]*int{"one": &one},
+		Config: cfg,
+		Any:    &[]string{"x"},
+		Events: make(chan int),
+		Empty:  &struct{}{},
+		Names:  [2]string{"a", "b"},
+		ptrs:   [1]*int{new(int)},
+		Fn:     func() {},
+		Nested: []struct{ P *int }{{&two}},
+	}
+	a.Next = &a
+
+	var s sharedState
+	if msgs, _ := s.add("", a); msgs != nil {
+		t.Fatalf("expected the first test case to share nothing but got %q", msgs)
+	}
+
+	// distinct memory, an intentionally shared field, and zero-size values
+	// are not reported
+	b := sharedTC{Items: []int{1}, Config: cfg, Empty: &struct{}{}, Names: a.Names, Fn: a.Fn}
+	if msgs, _ := s.add("fresh", b); msgs != nil {
+		t.Errorf("expected distinct memory to not be reported but got %q", msgs)
+	}
+
+	c := sharedTC{
+		Items:  backing[3:],
+		Tags:   map[string]*int{"alias": &one},
+		Any:    a.Any,
+		Events: a.Events,
+		ptrs:   a.ptrs,
+		Next:   &sharedTC{Next: a.Next},
+		Nested: []struct{ P *int }{{&two}},
+	}
+	msgs, _ := s.add("alias", c)
+	if len(msgs) != 1 {
+		t.Fatalf("expected only the basis to share memory but got %q", msgs)
+	}
+
+	// memory is described by the path it was first reached through, which
+	// for the basis is through its Next field when it was reached both ways
+	exp := []string{
+		"slice TC.Items (TC.Items)",
+		`pointer TC.Tags["alias"] (TC.Tags["one"])`,
+		"pointer TC.Next.Next (TC.Next)",
+		"pointer TC.Any (TC.Any)",
+		"channel TC.Events (TC.Events)",
+		"pointer TC.ptrs[0] (TC.ptrs[0])",
+		"pointer TC.Nested[0].P (TC.Next.Nested[0].P)",
+	}
+	if !strings.HasSuffix(msgs[0], ":\n\t"+strings.Join(exp, "\n\t")) {
+		t.Errorf("expected the shared memory:\n\t%s\nbut got:\n%s", strings.Join(exp, "\n\t"), msgs[0])
+	}
+
+	// map keys which are not strings, or cannot be inspected, are still
+	// reported
+	type key struct{ n int }
+
+	var m sharedState
+	m.add("", map[int]*int{7: &one})
+	m.add("", map[key]*int{{1}: &one})
+	if msgs, _ := m.add("keys", struct{ m map[int]*int }{map[int]*int{8: &one}}); len(msgs) != 2 || !strings.HasSuffix(msgs[0], "pointer TC.m[?] (TC[7])") || !strings.HasSuffix(msgs[1], "pointer TC.m[?] (TC[{1}])") {
+		t.Errorf("unexpected messages: %q", msgs)
+	}
+}
+
+func Test_sharedState_limit(t *testing.T) {
+	t.Parallel()
+
+	shared := new(int)
+
+	var s sharedState
+	for i := range maxSharedStateScenarios {
+		if _, unchecked := s.add(strconv.Itoa(i), &shared); unchecked {
+			t.Fatalf("expected scenario %d to be checked", i)
+		}
+	}
+
+	// only the first test case beyond the limit is reported as unchecked
+	for i, exp := range []bool{true, false} {
+		if msgs, unchecked := s.add("beyond", &shared); msgs != nil || unchecked != exp {
+			t.Errorf("call %d: expected no messages and unchecked to be %v but got %q and %v", i, exp, msgs, unchecked)
+		}
+	}
+
+	if len(s.scenarios) != maxSharedStateScenarios || len(s.tcs) != maxSharedStateScenarios {
+		t.Errorf("expected only %d test cases to be kept but got %d", maxSharedStateScenarios, len(s.tcs))
+	}
+}
+
+func TestLifecycle_sharedStateCheck_limit(t *testing.T) {
+	// only the warnings of this run count, as -count runs the test again
+	n := len(Warnings())
+
+	WTN(
+		0,
+		"many variants run", func(*testing.T, int) {},
+		"only the first are checked", func(*testing.T, int) {},
+	).With(
+		WithSharedStateCheck[int, struct{}](),
+		WithVariants[int, struct{}](func(*testing.T, int) iter.Seq[TestVariant[int]] {
+			return func(yield func(TestVariant[int]) bool) {
+				for i := range maxSharedStateScenarios {
+					if !yield(TestVariant[int]{Kind: strconv.Itoa(i + 1), TC: i + 1}) {
+						return
+					}
+				}
+			}
+		}),
+	).New(t)(t)
+
+	exp := "tbdd: SharedStateCheck compares the test cases of the first 1000 scenarios of a Lifecycle; " +
+		`variant "1000" and those after it are not checked`
+	var ws []string
+	for _, w := range Warnings()[n:] {
+		if w.Test == t.Name() {
+			ws = append(ws, w.Message)
+		}
+	}
+
+	if len(ws) != 1 || ws[0] != exp {
+		t.Errorf("expected the warning '%s' but got %q", exp, ws)
+	}
+}
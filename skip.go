@@ -0,0 +1,98 @@
+package tbdd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// skipReasons holds the reason passed to Skip or Skipf for each running test.
+var skipReasons struct {
+	mu sync.Mutex
+	m  map[*testing.T]string
+}
+
+// Skip is equivalent to t.Skip except the reason is also recorded, so it can
+// be surfaced to the AfterSkip hook and in scenario results when called from
+// a given or when phase.
+func Skip(t *testing.T, args ...any) {
+	t.Helper()
+
+	skip(t, fmt.Sprint(args...))
+}
+
+// Skipf is like Skip but formats its arguments in the manner of fmt.Sprintf.
+func Skipf(t *testing.T, format string, args ...any) {
+	t.Helper()
+
+	skip(t, fmt.Sprintf(format, args...))
+}
+
+func skip(t *testing.T, reason string) {
+	t.Helper()
+
+	recordSkipReason(t, reason)
+	t.Skip(reason)
+}
+
+// recordSkipReason records reason as the skip reason of t until t completes.
+func recordSkipReason(t *testing.T, reason string) {
+	skipReasons.mu.Lock()
+	if skipReasons.m == nil {
+		skipReasons.m = map[*testing.T]string{}
+	}
+	skipReasons.m[t] = reason
+	skipReasons.mu.Unlock()
+
+	t.Cleanup(func() {
+		skipReasons.mu.Lock()
+		defer skipReasons.mu.Unlock()
+
+		delete(skipReasons.m, t)
+	})
+}
+
+// skipReason returns the reason recorded for t by Skip or Skipf, if any.
+func skipReason(t *testing.T) string {
+	skipReasons.mu.Lock()
+	defer skipReasons.mu.Unlock()
+
+	return skipReasons.m[t]
+}
+
+// skipUntil skips t with reason while the current time is before until and
+// fails it once until has passed, so temporary skips cannot outlive their
+// intent, classifying sr accordingly. A zero until or nil t is a no-op.
+func skipUntil(t *testing.T, sr *scenario, until time.Time, reason string) {
+	if until.IsZero() || t == nil {
+		return
+	}
+
+	t.Helper()
+
+	if time.Now().Before(until) {
+		sr.class = ClassSkippedQuarantine
+		skip(t, skipUntilMessage(until, reason))
+		return
+	}
+
+	sr.fail(ClassFailedConfig)
+	t.Fatalf("skip expired on %s: %s", skipUntilDate(until), reason)
+}
+
+// skipUntilMessage returns the reason a scenario is skipped with until the
+// given time.
+func skipUntilMessage(until time.Time, reason string) string {
+	return "skipped until " + skipUntilDate(until) + ": " + reason
+}
+
+// skipUntilDate formats until as a date, or as a timestamp when it is not
+// midnight.
+func skipUntilDate(until time.Time) string {
+	if h, m, s := until.Clock(); h != 0 || m != 0 || s != 0 || until.Nanosecond() != 0 {
+		return until.Format(time.RFC3339)
+	}
+
+	return until.Format(time.DateOnly)
+}
@@ -0,0 +1,109 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSkip(t *testing.T) {
+	t.Parallel()
+
+	var sub *testing.T
+	var reason string
+	t.Run("skipped", func(t *testing.T) {
+		sub = t
+		defer func() {
+			reason = skipReason(t)
+		}()
+
+		Skip(t, "service ", "unavailable")
+	})
+
+	if !sub.Skipped() || reason != "service unavailable" {
+		t.Errorf("expected a skip with reason 'service unavailable' but got %v and '%s'", sub.Skipped(), reason)
+	}
+
+	if r := skipReason(sub); r != "" {
+		t.Errorf("expected the reason to be released after the test but got '%s'", r)
+	}
+}
+
+func TestLifecycle_skipUntil(t *testing.T) {
+	if os.Getenv("TBDD_SKIP_UNTIL_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"the flaky behavior runs", func(*testing.T, struct{}) {},
+			"it is not skipped forever", func(*testing.T, struct{}) {},
+		).With(
+			WithSkipUntil[struct{}, struct{}](time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local), "waiting on a fix"),
+		).New(t)
+		f(t)
+		return
+	}
+
+	until := time.Date(9999, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var ran []string
+	wt := WTN(
+		struct{}{},
+		"the flaky behavior runs", func(*testing.T, struct{}) {
+			ran = append(ran, "when")
+		},
+		"it is skipped", func(*testing.T, struct{}) {},
+	).With(WithSkipUntil[struct{}, struct{}](until, "waiting on a fix"))
+
+	gwt := GWTN(
+		struct{}{},
+		"a flaky dependency", func(*testing.T, *struct{}) {
+			ran = append(ran, "given")
+		},
+		"the behavior runs", func(*testing.T, struct{}) {
+			ran = append(ran, "when")
+		},
+		"it is skipped", func(*testing.T, struct{}) {},
+	).With(WithSkipUntil[struct{}, struct{}](until, "waiting on a fix"))
+
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	t.Run("wt", wt.New(t))
+	t.Run("gwt", gwt.New(t))
+
+	if len(ran) != 0 {
+		t.Errorf("expected no phases to run but got %v", ran)
+	}
+
+	var got []ScenarioResult
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			got = append(got, r)
+		}
+	}
+
+	exp := "skipped until 9999-01-02T03:04:05Z: waiting on a fix"
+	if len(got) != 2 || got[0].SkipPhase != "when" || got[0].SkipReason != exp || got[1].SkipPhase != "given" || got[1].SkipReason != exp {
+		t.Errorf("expected both scenarios to be skipped with reason '%s' but got %+v", exp, got)
+	}
+
+	// mocked runs are never skipped
+	skipUntil(nil, nil, until, "")
+
+	//
+	// expired skips fail
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_SKIP_UNTIL_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	if exp := "skip expired on 2000-01-02: waiting on a fix"; !strings.Contains(string(out), exp) {
+		t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+	}
+}
@@ -0,0 +1,72 @@
+package tbdd
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// modulePath is the import path of tbdd, which prefixes those of its
+// subpackages.
+const modulePath = "github.com/josephcopenhaver/tbdd-go"
+
+// definedAt returns the "file:line" of the innermost caller outside of tbdd
+// and its subpackages, not counting their tests, such as the GWT call
+// defining a Lifecycle, or empty when there is none or it is the testing
+// package running a function of tbdd.
+func definedAt() string {
+	f, _ := callerOutside()
+
+	return frameSource(f)
+}
+
+// frameSource returns the "file:line" of f, or empty for the zero frame.
+func frameSource(f runtime.Frame) string {
+	if f.File == "" {
+		return ""
+	}
+
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// callerOutside returns the frame of the innermost caller outside of tbdd
+// and its subpackages, not counting their tests, unless there is none or it
+// is the testing package running a function of tbdd.
+func callerOutside() (runtime.Frame, bool) {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+
+	for {
+		f, more := frames.Next()
+		if f.Function != "" && !inModule(f) {
+			if strings.HasPrefix(f.Function, "testing.") || strings.HasPrefix(f.Function, "runtime.") {
+				return runtime.Frame{}, false
+			}
+
+			return f, true
+		}
+
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// inModule reports whether f is a frame of tbdd or its subpackages, other
+// than of their tests.
+func inModule(f runtime.Frame) bool {
+	if strings.HasSuffix(f.File, "_test.go") {
+		return false
+	}
+
+	return strings.HasPrefix(f.Function, modulePath+".") || strings.HasPrefix(f.Function, modulePath+"/")
+}
+
+// sourceOrUnknown returns source, or "unknown" when it is empty.
+func sourceOrUnknown(source string) string {
+	if source == "" {
+		return "unknown"
+	}
+
+	return source
+}
@@ -0,0 +1,108 @@
+package tbdd
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestLifecycle_source(t *testing.T) {
+	t.Parallel()
+
+	_, file, line, _ := runtime.Caller(0)
+	l := WTN(0, "it is located", func(*testing.T, int) {}, "it is on this line", func(*testing.T, int) {})
+
+	if exp := file + ":" + strconv.Itoa(line+1); l.Source != exp {
+		t.Errorf("expected the source '%s' but got '%s'", exp, l.Source)
+	}
+
+}
+
+// here returns the "file:line" of its caller.
+func here() string {
+	_, file, line, _ := runtime.Caller(1)
+
+	return file + ":" + strconv.Itoa(line)
+}
+
+func TestLifecycle_source_constructors(t *testing.T) {
+	t.Parallel()
+
+	type TC struct{}
+
+	newSuite := func() *cartSuite { return &cartSuite{log: new([]string)} }
+	newController := func(t *testing.T) *fakeMockController { return &fakeMockController{t: t, finished: new(int)} }
+	givenF := func(*testing.T, *TC) int { return 0 }
+	whenF := func(*testing.T, TC, int) int { return 0 }
+	thenF := func(*testing.T, TC, int) {}
+
+	// each case returns the Source of its Lifecycle and the line constructing it
+	for _, tc := range []struct {
+		name string
+		f    func() (string, string)
+	}{
+		{"New", func() (string, string) { return New[TC, int](TC{}).Source, here() }},
+		{"GWTFixture", func() (string, string) { return GWTFixture(TC{}, "g", givenF, "w", whenF, "t", thenF).Source, here() }},
+		{"GWTMock", func() (string, string) {
+			return GWTMock(TC{}, newController, "g", func(*testing.T, *TC, *fakeMockController) {}, "w", func(*testing.T, TC, *fakeMockController) int { return 0 }, "t", thenF).Source, here()
+		}},
+		{"SuiteGWT", func() (string, string) {
+			return SuiteGWT(newSuite, (*cartSuite).GivenAnEmptyCart, (*cartSuite).WhenAnItemIsAdded, (*cartSuite).ThenTheCartHasOneItem).Source, here()
+		}},
+		{"SuiteWT", func() (string, string) {
+			return SuiteWT(newSuite, (*cartSuite).WhenAnItemIsAdded, (*cartSuite).ThenTheCartHasOneItem).Source, here()
+		}},
+	} {
+		if source, exp := tc.f(); source != exp {
+			t.Errorf("expected %s to set the source '%s' but got '%s'", tc.name, exp, source)
+		}
+	}
+}
+
+func TestLifecycle_source_literal(t *testing.T) {
+	// the code running a Lifecycle literal locates it
+	l := Lifecycle[int, struct{}]{
+		When: "a literal runs", Act: func(*testing.T, int) struct{} { return struct{}{} },
+		Then: "it is located", Assert: func(*testing.T, Assert[int, struct{}]) {},
+	}
+
+	// only the warnings of this run count, as -count runs the test again
+	n := len(Warnings())
+
+	_, file, line, _ := runtime.Caller(0)
+	t.Run("first", l.New(t))
+	t.Run("second", l.New(t))
+
+	ws := duplicateWarnings(t, n)
+	if len(ws) != 1 {
+		t.Fatalf("expected one duplicate warning but got %q", ws)
+	}
+
+	exp := `scenario "when a literal runs then it is located" defined at ` + file + ":" + strconv.Itoa(line+2) + " duplicates the scenario of " + t.Name() + "/first/when_a_literal_runs defined at " + file + ":" + strconv.Itoa(line+1)
+	if ws[0] != exp {
+		t.Errorf("expected the warning '%s' but got '%s'", exp, ws[0])
+	}
+}
+
+func Test_inModule(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		f   runtime.Frame
+		exp bool
+	}{
+		{runtime.Frame{Function: modulePath + ".TryGWT[...]", File: "/src/lifecycle.go"}, true},
+		{runtime.Frame{Function: modulePath + "/tbddhttp.Handler", File: "/src/tbddhttp/handler.go"}, true},
+		{runtime.Frame{Function: modulePath + ".TestGWT", File: "/src/lifecycle_test.go"}, false},
+		{runtime.Frame{Function: modulePath + "-extra.GWT", File: "/src/extra.go"}, false},
+		{runtime.Frame{Function: "testing.tRunner", File: "/go/src/testing/testing.go"}, false},
+	} {
+		if ok := inModule(tc.f); ok != tc.exp {
+			t.Errorf("expected inModule to be %v for %+v", tc.exp, tc.f)
+		}
+	}
+
+	if s := sourceOrUnknown(""); s != "unknown" {
+		t.Errorf("expected an empty source to be unknown but got '%s'", s)
+	}
+}
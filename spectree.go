@@ -0,0 +1,200 @@
+package tbdd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ANSI escape sequences used by the colored console reporters.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// ConveyReporter returns a Reporter which writes the scenario results to w
+// as GoConvey style nested spec trees, one per top level test:
+//
+//	TestLogin
+//	  Given a user
+//	    When they log in
+//	      Then they see their dashboard ✔
+//
+// Variants are nested below their Kind. Marks are colored with ANSI escape
+// sequences when color is true.
+func ConveyReporter(w io.Writer, color bool) Reporter {
+	return ReporterFunc(func(r Report) error {
+		c := consoleColors(color)
+
+		var sb strings.Builder
+		var prevTest string
+		var prev []string
+		for _, res := range r.Results {
+			test, _, _ := strings.Cut(res.Test, "/")
+			if test != prevTest {
+				if prevTest != "" {
+					sb.WriteByte('\n')
+				}
+
+				sb.WriteString(test + "\n")
+				prevTest, prev = test, nil
+			}
+
+			var levels []string
+			if res.Kind != "" {
+				levels = append(levels, res.Kind)
+			}
+			if res.Given != "" {
+				levels = append(levels, "Given "+res.Given)
+			}
+			levels = append(levels, "When "+res.When, "Then "+res.Then)
+
+			// only the levels which differ from those of the previous
+			// scenario are written, so shared contexts form a tree
+			same := 0
+			for same < len(prev) && same < len(levels)-1 && prev[same] == levels[same] {
+				same++
+			}
+
+			for i := same; i < len(levels); i++ {
+				sb.WriteString(strings.Repeat("  ", i+1) + levels[i])
+				if i == len(levels)-1 {
+					sb.WriteString(" " + c.mark(res.Status, "✔", "✘", "⚠"))
+					if res.SkipReason != "" {
+						sb.WriteString(" (" + res.SkipReason + ")")
+					}
+				}
+				sb.WriteByte('\n')
+			}
+
+			prev = levels
+		}
+
+		if len(r.Results) > 0 {
+			sb.WriteByte('\n')
+		}
+
+		s := r.Summary
+		fmt.Fprintf(&sb, "%d total scenarios (%s, %s, %s)\n",
+			s.Total,
+			c.paint(ansiGreen, fmt.Sprintf("%d passed", s.Passed)),
+			c.paint(ansiRed, fmt.Sprintf("%d failed", s.Failed)),
+			c.paint(ansiYellow, fmt.Sprintf("%d skipped", s.Skipped)),
+		)
+
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// GinkgoReporter returns a Reporter which writes the scenario results to w
+// in the style of Ginkgo: a "•" for every passing scenario; the full
+// description of every failed or skipped one, followed by its Source; then a
+// summary line.
+//
+//	••
+//	• [FAILED] [0.002 seconds]
+//	TestLogin
+//	  given a user
+//	    when they log in
+//	      then they see their dashboard
+//	/src/login_test.go:12
+//
+//	Ran 2 of 3 Specs in 0.004 seconds
+//	FAIL! -- 2 Passed | 1 Failed | 0 Skipped
+//
+// Output is colored with ANSI escape sequences when color is true.
+func GinkgoReporter(w io.Writer, color bool) Reporter {
+	return ReporterFunc(func(r Report) error {
+		c := consoleColors(color)
+
+		var sb strings.Builder
+		var total time.Duration
+		var dots bool
+		for _, res := range r.Results {
+			total += res.Duration
+
+			if res.Status == StatusPassed {
+				sb.WriteString(c.paint(ansiGreen, "•"))
+				dots = true
+				continue
+			}
+
+			if dots {
+				sb.WriteByte('\n')
+				dots = false
+			}
+
+			label := c.paint(ansiRed, "• [FAILED]")
+			if res.Status == StatusSkipped {
+				label = c.paint(ansiYellow, "S [SKIPPED]")
+			}
+
+			fmt.Fprintf(&sb, "%s [%.3f seconds]\n", label, res.Duration.Seconds())
+
+			test, _, _ := strings.Cut(res.Test, "/")
+			sb.WriteString(test + "\n")
+
+			depth := 1
+			if res.Kind != "" {
+				sb.WriteString("  " + res.Kind + "\n")
+				depth++
+			}
+			if res.Given != "" {
+				sb.WriteString(strings.Repeat("  ", depth) + "given " + res.Given + "\n")
+				depth++
+			}
+			sb.WriteString(strings.Repeat("  ", depth) + "when " + res.When + "\n")
+			sb.WriteString(strings.Repeat("  ", depth+1) + "then " + res.Then + "\n")
+			if res.SkipReason != "" {
+				sb.WriteString(strings.Repeat("  ", depth+2) + res.SkipReason + "\n")
+			}
+			if res.Source != "" {
+				sb.WriteString(res.Source + "\n")
+			}
+			sb.WriteByte('\n')
+		}
+
+		if dots {
+			sb.WriteString("\n\n")
+		}
+
+		s := r.Summary
+		fmt.Fprintf(&sb, "Ran %d of %d Specs in %.3f seconds\n", s.Total-s.Skipped, s.Total, total.Seconds())
+
+		verdict := c.paint(ansiGreen, "SUCCESS!")
+		if s.Failed > 0 {
+			verdict = c.paint(ansiRed, "FAIL!")
+		}
+		fmt.Fprintf(&sb, "%s -- %d Passed | %d Failed | %d Skipped\n", verdict, s.Passed, s.Failed, s.Skipped)
+
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// consoleColors paints console reporter output when it is true.
+type consoleColors bool
+
+func (c consoleColors) paint(code, s string) string {
+	if !c {
+		return s
+	}
+
+	return code + s + ansiReset
+}
+
+// mark returns the mark of status s, painted in its color.
+func (c consoleColors) mark(s Status, passed, failed, skipped string) string {
+	switch s {
+	case StatusPassed:
+		return c.paint(ansiGreen, passed)
+	case StatusFailed:
+		return c.paint(ansiRed, failed)
+	}
+
+	return c.paint(ansiYellow, skipped)
+}
@@ -0,0 +1,116 @@
+package tbdd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var specReport = Report{
+	Summary: Summary{Total: 4, Passed: 2, Failed: 1, Skipped: 1},
+	Results: []ScenarioResult{
+		{Test: "TestLogin/given_a_user", Given: "a user", When: "they log in", Then: "they see their dashboard", Status: StatusPassed, Duration: time.Millisecond},
+		{Test: "TestLogin/given_a_user", Given: "a user", When: "they log in", Then: "they are greeted", Source: "/src/login_test.go:12", Status: StatusFailed, Duration: 2 * time.Millisecond},
+		{Test: "TestLogin/admin/given_a_user", Kind: "admin", Given: "a user", When: "they log in", Then: "they see the console", Status: StatusSkipped, SkipReason: "console offline"},
+		{Test: "TestHealth/when_the_service_starts", When: "the service starts", Then: "it is healthy", Status: StatusPassed, Duration: time.Millisecond},
+	},
+}
+
+func TestConveyReporter(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	if err := ConveyReporter(&sb, false).Report(specReport); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `TestLogin
+  Given a user
+    When they log in
+      Then they see their dashboard ✔
+      Then they are greeted ✘
+  admin
+    Given a user
+      When they log in
+        Then they see the console ⚠ (console offline)
+
+TestHealth
+  When the service starts
+    Then it is healthy ✔
+
+4 total scenarios (2 passed, 1 failed, 1 skipped)
+`
+	if sb.String() != exp {
+		t.Errorf("expected:\n%s\nbut got:\n%s", exp, sb.String())
+	}
+
+	sb.Reset()
+	if err := ConveyReporter(&sb, true).Report(Report{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "0 total scenarios (\x1b[32m0 passed\x1b[0m, \x1b[31m0 failed\x1b[0m, \x1b[33m0 skipped\x1b[0m)\n"; sb.String() != exp {
+		t.Errorf("expected %q but got %q", exp, sb.String())
+	}
+}
+
+func TestGinkgoReporter(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	if err := GinkgoReporter(&sb, false).Report(specReport); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `•
+• [FAILED] [0.002 seconds]
+TestLogin
+  given a user
+    when they log in
+      then they are greeted
+/src/login_test.go:12
+
+S [SKIPPED] [0.000 seconds]
+TestLogin
+  admin
+    given a user
+      when they log in
+        then they see the console
+          console offline
+
+•
+
+Ran 3 of 4 Specs in 0.004 seconds
+FAIL! -- 2 Passed | 1 Failed | 1 Skipped
+`
+	if sb.String() != exp {
+		t.Errorf("expected:\n%s\nbut got:\n%s", exp, sb.String())
+	}
+
+	sb.Reset()
+	r := Report{Summary: Summary{Total: 1, Passed: 1}, Results: specReport.Results[3:]}
+	if err := GinkgoReporter(&sb, true).Report(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "\x1b[32m•\x1b[0m\n\nRan 1 of 1 Specs in 0.001 seconds\n\x1b[32mSUCCESS!\x1b[0m -- 1 Passed | 0 Failed | 0 Skipped\n"; sb.String() != exp {
+		t.Errorf("expected %q but got %q", exp, sb.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestSpecReporters_writeErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, rep := range []Reporter{ConveyReporter(failingWriter{}, false), GinkgoReporter(failingWriter{}, false)} {
+		if err := rep.Report(specReport); err == nil || err.Error() != "write failed" {
+			t.Errorf("expected the write error to be returned but got %v", err)
+		}
+	}
+}
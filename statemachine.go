@@ -0,0 +1,362 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// StateMachine models the system under test as a state machine for
+// model-based testing: its Transitions are the steps a scenario may take
+// from one named state to another and its Invariants are checked in every
+// state reached. Lifecycle turns walks of the machine, generated by
+// Exhaustive or Random, into scenarios complementing hand-written ones.
+type StateMachine[T any] struct {
+	// Initial is the state every walk starts in.
+	Initial string
+	// Start, when non-nil, is the given phase of every walk, setting up the
+	// system under test held by the test case in the Initial state.
+	Start func(*testing.T, *T)
+	// Transitions are the steps of walks, taken in declaration order by
+	// Exhaustive.
+	Transitions []Transition[T]
+	// Invariants are checked in the initial state and after every
+	// transition.
+	Invariants []Invariant[T]
+}
+
+// Transition is a step from the state From to the state To.
+type Transition[T any] struct {
+	// Name identifies the transition within walks and their Kinds, in which
+	// transitions are separated by ", ", so it must not contain ", ".
+	Name     string
+	From, To string
+	// Step performs the transition on the system under test held by tc.
+	Step func(t *testing.T, tc *T)
+}
+
+// Invariant is a property of the system under test which must hold in every
+// state of a StateMachine.
+type Invariant[T any] struct {
+	Name string
+	// Check returns an error describing how tc violates the invariant in
+	// the state the machine is expected to be in, or nil.
+	Check func(tc T, state string) error
+}
+
+// Walk is the test case of a scenario of a StateMachine: the transitions of
+// Path taken in order, from the initial state, on a copy of TC. The basis
+// scenario has an empty Path and only checks the initial state.
+type Walk[T any] struct {
+	TC   T
+	Path []string
+}
+
+// WalkResult is the result of taking a Walk.
+type WalkResult[T any] struct {
+	// TC is the test case once the walk ended.
+	TC T
+	// State is the state the walk ended in.
+	State string
+	// Steps is the number of transitions taken.
+	Steps int
+	// Violations are the invariants which did not hold in the last state
+	// reached; a walk ends at the first state violating any.
+	Violations []Violation
+}
+
+// Violation is an invariant which did not hold in a state of a walk.
+type Violation struct {
+	Invariant string
+	State     string
+	// Step is the number of transitions taken before the violation, zero
+	// for the initial state.
+	Step int
+	// Transition is the name of the transition of Step, if any.
+	Transition string
+	Err        error
+}
+
+func (v Violation) Error() string {
+	s := "invariant " + strconv.Quote(v.Invariant) + " violated in state " + strconv.Quote(v.State)
+	if v.Step == 0 {
+		s += " initially"
+	} else {
+		s += " after step " + strconv.Itoa(v.Step) + " " + strconv.Quote(v.Transition)
+	}
+
+	return s + ": " + v.Err.Error()
+}
+
+// Lifecycle returns a Lifecycle whose basis scenario checks the invariants
+// of the initial state of tc and whose variants take the walks yielded by
+// walks, such as those of Exhaustive or Random, each with its path as its
+// Kind, such as "walk: open, close". walks may be nil.
+//
+// Every scenario is given the machine in its initial state, via Start, then
+// takes its walk, ending it at the first state violating an invariant, and
+// then reports every violated invariant. A transition which cannot be taken
+// in the state reached, or is unknown, fails the walk.
+//
+// Lifecycle panics if the machine is misconfigured: its Initial state is
+// empty, a transition has an empty or duplicate Name, an empty state, or a
+// nil Step function, or an invariant has an empty Name or a nil Check
+// function.
+func (m StateMachine[T]) Lifecycle(tc T, walks func(*testing.T, Walk[T]) iter.Seq[TestVariant[Walk[T]]]) Lifecycle[Walk[T], WalkResult[T]] {
+	index := m.validate("tbdd.StateMachine.Lifecycle")
+
+	return GWT(
+		Walk[T]{TC: tc},
+		"the machine is in state "+m.Initial, func(t *testing.T, w *Walk[T]) {
+			if m.Start != nil {
+				m.Start(t, &w.TC)
+			}
+		},
+		"the walk is taken", func(t *testing.T, w Walk[T]) WalkResult[T] {
+			t.Helper()
+
+			return m.walk(t, index, w)
+		},
+		"every invariant holds", func(t *testing.T, _ Walk[T], r WalkResult[T]) {
+			t.Helper()
+
+			for _, v := range r.Violations {
+				t.Error(v.Error())
+			}
+		},
+	).With(WithVariants[Walk[T], WalkResult[T]](walks))
+}
+
+// walk takes the walk w, checking the invariants in every state reached.
+func (m StateMachine[T]) walk(t *testing.T, index map[string]int, w Walk[T]) WalkResult[T] {
+	t.Helper()
+
+	r := WalkResult[T]{TC: w.TC, State: m.Initial}
+	if err := m.checkPath(index, w.Path); err != nil {
+		t.Fatal(err.Error())
+		return r
+	}
+
+	if m.check(&r, ""); len(r.Violations) > 0 {
+		return r
+	}
+
+	for _, name := range w.Path {
+		tr := m.Transitions[index[name]]
+		tr.Step(t, &r.TC)
+		r.State = tr.To
+		r.Steps++
+
+		if m.check(&r, name); len(r.Violations) > 0 {
+			return r
+		}
+	}
+
+	return r
+}
+
+// checkPath returns an error if a transition of path is unknown or cannot
+// be taken in the state reached by those before it.
+func (m StateMachine[T]) checkPath(index map[string]int, path []string) error {
+	state := m.Initial
+	for i, name := range path {
+		j, ok := index[name]
+		if !ok {
+			return errors.New("tbdd: step " + strconv.Itoa(i+1) + " of the walk names the unknown transition " + strconv.Quote(name))
+		}
+
+		tr := m.Transitions[j]
+		if tr.From != state {
+			return errors.New("tbdd: step " + strconv.Itoa(i+1) + " of the walk, transition " + strconv.Quote(name) + ", cannot be taken in state " + strconv.Quote(state))
+		}
+
+		state = tr.To
+	}
+
+	return nil
+}
+
+// check records the invariants violated by r in its state, reached by the
+// named transition.
+func (m StateMachine[T]) check(r *WalkResult[T], transition string) {
+	for _, inv := range m.Invariants {
+		if err := inv.Check(r.TC, r.State); err != nil {
+			r.Violations = append(r.Violations, Violation{inv.Name, r.State, r.Steps, transition, err})
+		}
+	}
+}
+
+// Exhaustive returns a Variants function yielding every walk of depth
+// transitions from the initial state, along with the shorter walks ending in
+// states without any transitions, in declaration order of the transitions.
+// Walks are not repeated as shorter prefixes, since the invariants are
+// checked in every state of a walk.
+//
+// The number of walks grows exponentially with depth; MaxVariants guards
+// against a depth which generates far more scenarios than intended.
+//
+// Exhaustive panics if depth is not positive or the machine is
+// misconfigured as described by Lifecycle.
+func (m StateMachine[T]) Exhaustive(depth int) func(*testing.T, Walk[T]) iter.Seq[TestVariant[Walk[T]]] {
+	m.validate("tbdd.StateMachine.Exhaustive")
+
+	if depth <= 0 {
+		panic("tbdd.StateMachine.Exhaustive: depth must be positive")
+	}
+
+	return func(_ *testing.T, basis Walk[T]) iter.Seq[TestVariant[Walk[T]]] {
+		return func(yield func(TestVariant[Walk[T]]) bool) {
+			var path []string
+
+			var dfs func(state string) bool
+			dfs = func(state string) bool {
+				var moved bool
+				if len(path) < depth {
+					for _, tr := range m.Transitions {
+						if tr.From != state {
+							continue
+						}
+
+						moved = true
+						path = append(path, tr.Name)
+						more := dfs(tr.To)
+						path = path[:len(path)-1]
+						if !more {
+							return false
+						}
+					}
+				}
+
+				if moved || len(path) == 0 {
+					return true
+				}
+
+				return yield(walkVariant(basis, path))
+			}
+
+			dfs(m.Initial)
+		}
+	}
+}
+
+// Random returns a Variants function yielding n distinct random walks of
+// up to depth transitions from the initial state, each choosing uniformly
+// among the transitions of the state reached and ending early in states
+// without any. Fewer walks are yielded when fewer than n distinct walks are
+// found.
+//
+// The walks are determined by seed, or when seed is zero by the seed of the
+// basis scenario of the Lifecycle generating them, as described by
+// Lifecycle.Seed, so passing the same -tbdd.seed reproduces a failing walk.
+//
+// Random panics if n or depth is not positive or the machine is
+// misconfigured as described by Lifecycle.
+func (m StateMachine[T]) Random(seed int64, n, depth int) func(*testing.T, Walk[T]) iter.Seq[TestVariant[Walk[T]]] {
+	m.validate("tbdd.StateMachine.Random")
+
+	if n <= 0 || depth <= 0 {
+		panic("tbdd.StateMachine.Random: number of walks and depth must be positive")
+	}
+
+	return func(t *testing.T, basis Walk[T]) iter.Seq[TestVariant[Walk[T]]] {
+		return func(yield func(TestVariant[Walk[T]]) bool) {
+			s := seed
+			if s == 0 {
+				s = variantSeed(t)
+			}
+			r := rand.New(rand.NewPCG(uint64(s), uint64(s)))
+
+			seen := map[string]bool{}
+			var next []Transition[T]
+
+			// give up on distinct walks once many attempts in a row were
+			// repeats, as small machines have few walks
+			for misses := 0; len(seen) < n && misses < 100*n; {
+				var path []string
+				for state := m.Initial; len(path) < depth; {
+					next = next[:0]
+					for _, tr := range m.Transitions {
+						if tr.From == state {
+							next = append(next, tr)
+						}
+					}
+
+					if len(next) == 0 {
+						break
+					}
+
+					tr := next[r.IntN(len(next))]
+					path = append(path, tr.Name)
+					state = tr.To
+				}
+
+				v := walkVariant(basis, path)
+				if len(path) == 0 || seen[v.Kind] {
+					misses++
+					continue
+				}
+
+				seen[v.Kind] = true
+				misses = 0
+
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// walkSeparator separates the transitions of a walk within its Kind, which
+// tells walks apart, so transition names must not contain it.
+const walkSeparator = ", "
+
+// walkVariant returns the variant of basis taking a copy of path.
+func walkVariant[T any](basis Walk[T], path []string) TestVariant[Walk[T]] {
+	return TestVariant[Walk[T]]{
+		Kind: "walk: " + strings.Join(path, walkSeparator),
+		TC:   Walk[T]{TC: basis.TC, Path: append([]string(nil), path...)},
+	}
+}
+
+// validate panics on behalf of fn if m is misconfigured and returns the
+// index of every transition by name.
+func (m StateMachine[T]) validate(fn string) map[string]int {
+	if m.Initial == "" {
+		panic(fn + ": Initial state must be non-empty")
+	}
+
+	index := make(map[string]int, len(m.Transitions))
+	for i, tr := range m.Transitions {
+		prefix := fn + ": transition " + strconv.Itoa(i)
+		switch _, dup := index[tr.Name]; {
+		case tr.Name == "":
+			panic(prefix + " must have a non-empty Name")
+		case dup:
+			panic(prefix + " has the duplicate Name " + strconv.Quote(tr.Name))
+		case strings.Contains(tr.Name, walkSeparator):
+			panic(prefix + " has the Name " + strconv.Quote(tr.Name) + ", which must not contain " + strconv.Quote(walkSeparator))
+		case tr.From == "" || tr.To == "":
+			panic(prefix + " must have non-empty From and To states")
+		case tr.Step == nil:
+			panic(prefix + " must have a non-nil Step function")
+		}
+
+		index[tr.Name] = i
+	}
+
+	for i, inv := range m.Invariants {
+		prefix := fn + ": invariant " + strconv.Itoa(i)
+		switch {
+		case inv.Name == "":
+			panic(prefix + " must have a non-empty Name")
+		case inv.Check == nil:
+			panic(prefix + " must have a non-nil Check function")
+		}
+	}
+
+	return index
+}
@@ -0,0 +1,320 @@
+package tbdd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type turnstile struct {
+	locked bool
+	coins  int
+}
+
+type turnstileTC struct {
+	ts *turnstile
+	// jams makes a push leave the turnstile unlocked
+	jams bool
+}
+
+func turnstileMachine() StateMachine[turnstileTC] {
+	return StateMachine[turnstileTC]{
+		Initial: "locked",
+		Start: func(_ *testing.T, tc *turnstileTC) {
+			tc.ts = &turnstile{locked: true}
+		},
+		Transitions: []Transition[turnstileTC]{
+			{"coin", "locked", "unlocked", func(_ *testing.T, tc *turnstileTC) {
+				tc.ts.locked = false
+				tc.ts.coins++
+			}},
+			{"push", "unlocked", "locked", func(_ *testing.T, tc *turnstileTC) {
+				tc.ts.locked = !tc.jams
+			}},
+			{"extra coin", "unlocked", "unlocked", func(_ *testing.T, tc *turnstileTC) {
+				tc.ts.coins++
+			}},
+		},
+		Invariants: []Invariant[turnstileTC]{
+			{"locked in state locked", func(tc turnstileTC, state string) error {
+				if tc.ts.locked != (state == "locked") {
+					return errors.New("the turnstile is in the wrong state")
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+func TestStateMachine_Exhaustive(t *testing.T) {
+	t.Parallel()
+
+	m := turnstileMachine()
+
+	for _, tc := range []struct {
+		depth int
+		exp   []string
+	}{
+		{1, []string{"walk: coin"}},
+		{3, []string{"walk: coin, push, coin", "walk: coin, extra coin, push", "walk: coin, extra coin, extra coin"}},
+	} {
+		var kinds []string
+		for v := range m.Exhaustive(tc.depth)(nil, Walk[turnstileTC]{}) {
+			kinds = append(kinds, v.Kind)
+		}
+
+		if !slices.Equal(kinds, tc.exp) {
+			t.Errorf("expected the walks %q of depth %d but got %q", tc.exp, tc.depth, kinds)
+		}
+	}
+
+	// walks end early in states without transitions, and iteration stops
+	// when told to
+	m.Transitions = append(m.Transitions, Transition[turnstileTC]{"break", "locked", "broken", func(*testing.T, *turnstileTC) {}})
+
+	var kinds []string
+	for v := range m.Exhaustive(2)(nil, Walk[turnstileTC]{}) {
+		kinds = append(kinds, v.Kind)
+		if len(kinds) == 3 {
+			break
+		}
+	}
+
+	if exp := []string{"walk: coin, push", "walk: coin, extra coin", "walk: break"}; !slices.Equal(kinds, exp) {
+		t.Errorf("expected the walks %q but got %q", exp, kinds)
+	}
+
+	// a machine without transitions from its initial state has no walks
+	m.Initial = "broken"
+	for v := range m.Exhaustive(2)(nil, Walk[turnstileTC]{}) {
+		t.Errorf("unexpected walk %q", v.Kind)
+	}
+}
+
+func TestStateMachine_Random(t *testing.T) {
+	t.Parallel()
+
+	m := turnstileMachine()
+
+	walks := func(seed int64, n, depth int) []string {
+		var kinds []string
+		for v := range m.Random(seed, n, depth)(nil, Walk[turnstileTC]{}) {
+			kinds = append(kinds, v.Kind)
+			if len(v.TC.Path) == 0 || len(v.TC.Path) > depth {
+				t.Errorf("unexpected walk length %d", len(v.TC.Path))
+			}
+		}
+		return kinds
+	}
+
+	a := walks(1, 4, 5)
+	if len(a) != 4 || !slices.Equal(a, walks(1, 4, 5)) {
+		t.Errorf("expected the same 4 walks for the same seed but got %q", a)
+	}
+
+	seen := map[string]bool{}
+	for _, k := range a {
+		if seen[k] {
+			t.Errorf("unexpected repeated walk %q", k)
+		}
+		seen[k] = true
+	}
+
+	// there are only 3 walks of depth 3
+	if kinds := walks(0, 10, 3); len(kinds) != 3 {
+		t.Errorf("expected every one of the 3 walks but got %q", kinds)
+	}
+
+	// iteration stops when told to
+	for range m.Random(1, 4, 5)(nil, Walk[turnstileTC]{}) {
+		break
+	}
+
+	m.Initial = "broken"
+	if kinds := walks(1, 2, 2); len(kinds) != 0 {
+		t.Errorf("expected no walks but got %q", kinds)
+	}
+}
+
+func TestStateMachine_Random_lifecycleSeed(t *testing.T) {
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	m := turnstileMachine()
+
+	walks := func(seed int64) []string {
+		var kinds []string
+		for v := range m.Random(seed, 3, 6)(nil, Walk[turnstileTC]{}) {
+			kinds = append(kinds, v.Kind)
+		}
+		return kinds
+	}
+
+	planned := func(b Lifecycle[Walk[turnstileTC], WalkResult[turnstileTC]]) []string {
+		var kinds []string
+		for _, s := range b.Plan(t).Variants {
+			kinds = append(kinds, s.Kind)
+		}
+		return kinds
+	}
+
+	b := m.Lifecycle(turnstileTC{}, m.Random(0, 3, 6))
+
+	// a zero seed derives the walks from the run seed
+	config.seed = 11
+	if kinds, exp := planned(b), walks(11); !slices.Equal(kinds, exp) {
+		t.Errorf("expected the walks %q of the run seed but got %q", exp, kinds)
+	}
+
+	// and from the Seed of the Lifecycle when it has one, both when planned and run
+	b.Seed = 5
+	if kinds, exp := planned(b), walks(5); !slices.Equal(kinds, exp) {
+		t.Errorf("expected the planned walks %q of the Lifecycle seed but got %q", exp, kinds)
+	}
+
+	n := len(Results())
+	b.New(t)(t)
+
+	var kinds []string
+	for _, r := range Results()[n:] {
+		if r.Kind != "" {
+			kinds = append(kinds, r.Kind)
+		}
+	}
+
+	if exp := walks(5); !slices.Equal(kinds, exp) {
+		t.Errorf("expected the walks %q of the Lifecycle seed to run but got %q", exp, kinds)
+	}
+}
+
+func TestStateMachine_Lifecycle(t *testing.T) {
+	m := turnstileMachine()
+
+	var ran []string
+	m.Invariants = append(m.Invariants, Invariant[turnstileTC]{"observed", func(tc turnstileTC, state string) error {
+		ran = append(ran, state)
+		return nil
+	}})
+
+	m.Lifecycle(turnstileTC{}, m.Exhaustive(2)).New(t)(t)
+
+	// the initial state of the basis, then both states of each of the two walks
+	if exp := []string{"locked", "locked", "unlocked", "locked", "locked", "unlocked", "unlocked"}; !slices.Equal(ran, exp) {
+		t.Errorf("expected the invariants to be checked in the states %q but got %q", exp, ran)
+	}
+
+	// a nil walks function leaves only the basis scenario
+	ran = nil
+	m.Lifecycle(turnstileTC{}, nil).New(t)(t)
+
+	if exp := []string{"locked"}; !slices.Equal(ran, exp) {
+		t.Errorf("expected the invariants to be checked in the states %q but got %q", exp, ran)
+	}
+}
+
+func TestStateMachine_walk(t *testing.T) {
+	t.Parallel()
+
+	m := turnstileMachine()
+	index := m.validate("test")
+
+	var r WalkResult[turnstileTC]
+	t.Run("jammed", func(t *testing.T) {
+		tc := turnstileTC{jams: true}
+		m.Start(t, &tc)
+
+		r = m.walk(t, index, Walk[turnstileTC]{TC: tc, Path: []string{"coin", "push", "coin"}})
+	})
+
+	if r.State != "locked" || r.Steps != 2 || len(r.Violations) != 1 {
+		t.Fatalf("expected the walk to end with one violation after step 2 but got %+v", r)
+	}
+
+	if s, exp := r.Violations[0].Error(), `invariant "locked in state locked" violated in state "locked" after step 2 "push": the turnstile is in the wrong state`; s != exp {
+		t.Errorf("expected the violation '%s' but got '%s'", exp, s)
+	}
+
+	// an initial violation ends the walk before any transition
+	t.Run("unlocked", func(t *testing.T) {
+		r = m.walk(t, index, Walk[turnstileTC]{TC: turnstileTC{ts: &turnstile{}}, Path: []string{"coin"}})
+	})
+
+	if r.Steps != 0 || len(r.Violations) != 1 {
+		t.Fatalf("expected an initial violation but got %+v", r)
+	}
+
+	if s, exp := r.Violations[0].Error(), `invariant "locked in state locked" violated in state "locked" initially: the turnstile is in the wrong state`; s != exp {
+		t.Errorf("expected the violation '%s' but got '%s'", exp, s)
+	}
+}
+
+func TestStateMachine_checkPath(t *testing.T) {
+	t.Parallel()
+
+	m := turnstileMachine()
+	index := m.validate("test")
+
+	for _, tc := range []struct {
+		path []string
+		exp  string
+	}{
+		{[]string{"coin", "push"}, ""},
+		{[]string{"coin", "kick"}, `tbdd: step 2 of the walk names the unknown transition "kick"`},
+		{[]string{"push"}, `tbdd: step 1 of the walk, transition "push", cannot be taken in state "locked"`},
+	} {
+		err := m.checkPath(index, tc.path)
+		if (err == nil) != (tc.exp == "") || err != nil && err.Error() != tc.exp {
+			t.Errorf("expected the error '%s' for the path %q but got %v", tc.exp, tc.path, err)
+		}
+	}
+}
+
+func TestStateMachine_panics(t *testing.T) {
+	step := func(*testing.T, *int) {}
+	check := func(int, string) error { return nil }
+	valid := StateMachine[int]{Initial: "a", Transitions: []Transition[int]{{"t", "a", "b", step}}}
+
+	with := func(f func(*StateMachine[int])) StateMachine[int] {
+		m := valid
+		m.Transitions = slices.Clone(valid.Transitions)
+		f(&m)
+		return m
+	}
+
+	tests := []struct {
+		name string
+		f    func()
+		exp  string
+	}{
+		{"empty initial", func() { with(func(m *StateMachine[int]) { m.Initial = "" }).Lifecycle(0, nil) }, "tbdd.StateMachine.Lifecycle: Initial state must be non-empty"},
+		{"empty name", func() { with(func(m *StateMachine[int]) { m.Transitions[0].Name = "" }).Exhaustive(1) }, "tbdd.StateMachine.Exhaustive: transition 0 must have a non-empty Name"},
+		{"duplicate name", func() {
+			with(func(m *StateMachine[int]) { m.Transitions = append(m.Transitions, m.Transitions[0]) }).Random(1, 1, 1)
+		}, `tbdd.StateMachine.Random: transition 1 has the duplicate Name "t"`},
+		{"separator in name", func() { with(func(m *StateMachine[int]) { m.Transitions[0].Name = "a, b" }).Random(1, 1, 1) }, `tbdd.StateMachine.Random: transition 0 has the Name "a, b", which must not contain ", "`},
+		{"empty state", func() { with(func(m *StateMachine[int]) { m.Transitions[0].To = "" }).Lifecycle(0, nil) }, "tbdd.StateMachine.Lifecycle: transition 0 must have non-empty From and To states"},
+		{"nil step", func() { with(func(m *StateMachine[int]) { m.Transitions[0].Step = nil }).Lifecycle(0, nil) }, "tbdd.StateMachine.Lifecycle: transition 0 must have a non-nil Step function"},
+		{"empty invariant name", func() {
+			with(func(m *StateMachine[int]) { m.Invariants = []Invariant[int]{{Check: check}} }).Lifecycle(0, nil)
+		}, "tbdd.StateMachine.Lifecycle: invariant 0 must have a non-empty Name"},
+		{"nil check", func() {
+			with(func(m *StateMachine[int]) { m.Invariants = []Invariant[int]{{Name: "i"}} }).Lifecycle(0, nil)
+		}, "tbdd.StateMachine.Lifecycle: invariant 0 must have a non-nil Check function"},
+		{"depth", func() { valid.Exhaustive(0) }, "tbdd.StateMachine.Exhaustive: depth must be positive"},
+		{"random walks", func() { valid.Random(1, 0, 1) }, "tbdd.StateMachine.Random: number of walks and depth must be positive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			tc.f()
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package tbdd
+
+import (
+	"testing"
+)
+
+// GivenOnlyIf returns a given function which calls givenF only when
+// onlyIf reports true for the test case, and otherwise logs that the step
+// was skipped.
+//
+// Combined with JoinGivens it lets a single lifecycle and its variants
+// declare branch-specific preconditions instead of duplicating near
+// identical scenarios.
+func GivenOnlyIf[T any](onlyIf func(T) bool, givenF func(*testing.T, *T)) func(*testing.T, *T) {
+	if onlyIf == nil {
+		panic("tbdd.GivenOnlyIf: predicate must be non-nil")
+	}
+
+	if givenF == nil {
+		panic("tbdd.GivenOnlyIf: given function must be non-nil")
+	}
+
+	return func(t *testing.T, tc *T) {
+		t.Helper()
+
+		if !onlyIf(*tc) {
+			t.Log("tbdd: skipped a given step, as its predicate is false for the test case")
+			return
+		}
+
+		givenF(t, tc)
+	}
+}
+
+// ThenOnlyIf returns a then function which calls thenF only when onlyIf
+// reports true for the test case, and otherwise logs that the step was
+// skipped.
+//
+// Combined with JoinThens it lets a single lifecycle and its variants
+// declare branch-specific expectations instead of duplicating near
+// identical scenarios.
+func ThenOnlyIf[T, R any](onlyIf func(T) bool, thenF func(*testing.T, T, R)) func(*testing.T, T, R) {
+	if onlyIf == nil {
+		panic("tbdd.ThenOnlyIf: predicate must be non-nil")
+	}
+
+	if thenF == nil {
+		panic("tbdd.ThenOnlyIf: then function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		if !onlyIf(tc) {
+			t.Log("tbdd: skipped a then step, as its predicate is false for the test case")
+			return
+		}
+
+		thenF(t, tc, r)
+	}
+}
+
+// JoinGivens returns a given function which calls each of givenFs in order.
+func JoinGivens[T any](givenFs ...func(*testing.T, *T)) func(*testing.T, *T) {
+	for _, f := range givenFs {
+		if f == nil {
+			panic("tbdd.JoinGivens: given functions must be non-nil")
+		}
+	}
+
+	return func(t *testing.T, tc *T) {
+		t.Helper()
+
+		for _, f := range givenFs {
+			f(t, tc)
+		}
+	}
+}
+
+// JoinThens returns a then function which calls each of thenFs in order.
+func JoinThens[T, R any](thenFs ...func(*testing.T, T, R)) func(*testing.T, T, R) {
+	for _, f := range thenFs {
+		if f == nil {
+			panic("tbdd.JoinThens: then functions must be non-nil")
+		}
+	}
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		for _, f := range thenFs {
+			f(t, tc, r)
+		}
+	}
+}
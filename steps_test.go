@@ -0,0 +1,143 @@
+package tbdd
+
+import (
+	"iter"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestOnlyIf(t *testing.T) {
+	type TC struct {
+		premium bool
+		log     *[]string
+	}
+
+	isPremium := func(tc TC) bool { return tc.premium }
+
+	var log []string
+	b := GWT(
+		TC{log: &log},
+		"a customer", JoinGivens(
+			func(_ *testing.T, tc *TC) { *tc.log = append(*tc.log, "customer") },
+			GivenOnlyIf(isPremium, func(_ *testing.T, tc *TC) { *tc.log = append(*tc.log, "premium") }),
+		),
+		"they check out", func(*testing.T, TC) int {
+			return 0
+		},
+		"the order is placed", JoinThens(
+			func(_ *testing.T, tc TC, _ int) { *tc.log = append(*tc.log, "placed") },
+			ThenOnlyIf(isPremium, func(_ *testing.T, tc TC, _ int) { *tc.log = append(*tc.log, "discounted") }),
+		),
+	)
+	b.Variants = func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+		return func(yield func(TestVariant[TC]) bool) {
+			tc.premium = true
+			yield(TestVariant[TC]{TC: tc, Kind: "premium"})
+		}
+	}
+
+	f := b.New(t)
+	f(t)
+
+	if exp := "customer placed customer premium placed discounted"; strings.Join(log, " ") != exp {
+		t.Errorf("expected log '%s' but got '%s'", exp, strings.Join(log, " "))
+	}
+
+	//
+	// validate panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { GivenOnlyIf(nil, func(*testing.T, *TC) {}) }, "tbdd.GivenOnlyIf: predicate must be non-nil"},
+		{func() { GivenOnlyIf(isPremium, nil) }, "tbdd.GivenOnlyIf: given function must be non-nil"},
+		{func() { ThenOnlyIf(nil, func(*testing.T, TC, int) {}) }, "tbdd.ThenOnlyIf: predicate must be non-nil"},
+		{func() { ThenOnlyIf[TC, int](isPremium, nil) }, "tbdd.ThenOnlyIf: then function must be non-nil"},
+		{func() { JoinGivens[TC](nil) }, "tbdd.JoinGivens: given functions must be non-nil"},
+		{func() { JoinThens[TC, int](nil) }, "tbdd.JoinThens: then functions must be non-nil"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+func TestOnlyIf_logsSkips(t *testing.T) {
+	type TC struct {
+		premium bool
+	}
+
+	if os.Getenv("TBDD_ONLY_IF_HELPER") == "1" {
+		isPremium := func(tc TC) bool { return tc.premium }
+
+		b := GWT(
+			TC{},
+			"a customer", GivenOnlyIf(isPremium, func(*testing.T, *TC) {}),
+			"they check out", func(*testing.T, TC) int {
+				return 0
+			},
+			"the order is discounted", ThenOnlyIf(isPremium, func(*testing.T, TC, int) {}),
+		)
+		b.Variants = func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+			return func(yield func(TestVariant[TC]) bool) {
+				tc.premium = true
+				yield(TestVariant[TC]{TC: tc, Kind: "premium"})
+			}
+		}
+
+		f := b.New(t)
+		f(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_ONLY_IF_HELPER=1")
+
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected the helper test to pass: %v\n%s", err, b)
+	}
+
+	// the output of each test follows the line announcing it
+	logs := map[string]string{}
+	var name string
+	for _, line := range strings.Split(string(b), "\n") {
+		if s, ok := strings.CutPrefix(line, "=== RUN   "); ok {
+			name = s
+			continue
+		}
+
+		logs[name] += line + "\n"
+	}
+
+	for _, v := range []struct {
+		test    string
+		skipped string
+	}{
+		{"/given_a_customer", "given"},
+		{"/given_a_customer/when_they_check_out/then_the_order_is_discounted", "then"},
+	} {
+		msg := "tbdd: skipped a " + v.skipped + " step, as its predicate is false for the test case"
+
+		if out := logs[t.Name()+v.test]; !strings.Contains(out, msg) {
+			t.Errorf("expected the basis test %s to log '%s' but got:\n%s", v.test, msg, out)
+		}
+
+		if out := logs[t.Name()+"/premium"+v.test]; strings.Contains(out, msg) {
+			t.Errorf("expected the premium test %s to run its %s step but got:\n%s", v.test, v.skipped, out)
+		}
+	}
+}
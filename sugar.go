@@ -0,0 +1,373 @@
+package tbdd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// GWTN is a convenience wrapper around GWT for behaviors whose action
+// produces no meaningful result.
+//
+// whenF performs the action purely for its side-effects and thenF receives
+// only the final test case. The returned Lifecycle uses struct{} as its
+// result type.
+//
+// See GWT for more detail, including the panic conditions.
+func GWTN[T any](
+	tc T,
+	given string, givenF func(*testing.T, *T),
+	when string, whenF func(*testing.T, T),
+	then string, thenF func(*testing.T, T),
+) Lifecycle[T, struct{}] {
+	return GWT(
+		tc,
+		given, givenF,
+		when, noResultAct(whenF),
+		then, noResultThen(thenF),
+	)
+}
+
+// WTN is a convenience wrapper around GWTN for use when there is no given
+// context to convey.
+//
+// See GWT for more detail.
+func WTN[T any](
+	tc T,
+	when string, whenF func(*testing.T, T),
+	then string, thenF func(*testing.T, T),
+) Lifecycle[T, struct{}] {
+	return GWTN(
+		tc,
+		"", nil,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+// noResultAct adapts a side-effect only action into an Act function,
+// preserving nil-ness so constructor validation still applies.
+func noResultAct[T any](whenF func(*testing.T, T)) func(*testing.T, T) struct{} {
+	if whenF == nil {
+		return nil
+	}
+
+	return func(t *testing.T, tc T) struct{} {
+		whenF(t, tc)
+		return struct{}{}
+	}
+}
+
+// noResultThen adapts a test case only assertion into a then function,
+// preserving nil-ness so constructor validation still applies.
+func noResultThen[T any](thenF func(*testing.T, T)) func(*testing.T, T, struct{}) {
+	if thenF == nil {
+		return nil
+	}
+
+	return func(t *testing.T, tc T, _ struct{}) {
+		thenF(t, tc)
+	}
+}
+
+// GWTErr is a convenience wrapper around GWT for behaviors whose action
+// produces only an error.
+//
+// thenF receives the final test case and the error returned by whenF.
+// ExpectNoError, ExpectError, ExpectErrorContains, ExpectErrorIs, and
+// ExpectErrorAs provide ready-made then functions for the most common
+// expectations.
+//
+// See GWT for more detail, including the panic conditions.
+func GWTErr[T any](
+	tc T,
+	given string, givenF func(*testing.T, *T),
+	when string, whenF func(*testing.T, T) error,
+	then string, thenF func(*testing.T, T, error),
+) Lifecycle[T, error] {
+	return GWT(
+		tc,
+		given, givenF,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+// WTErr is a convenience wrapper around GWTErr for use when there is no
+// given context to convey.
+//
+// See GWT for more detail.
+func WTErr[T any](
+	tc T,
+	when string, whenF func(*testing.T, T) error,
+	then string, thenF func(*testing.T, T, error),
+) Lifecycle[T, error] {
+	return GWTErr(
+		tc,
+		"", nil,
+		when, whenF,
+		then, thenF,
+	)
+}
+
+// ExpectNoError returns a then function which fails the test if the action
+// returned a non-nil error.
+func ExpectNoError[T any]() func(*testing.T, T, error) {
+	return func(t *testing.T, _ T, err error) {
+		t.Helper()
+
+		expectNoError(t, err)
+	}
+}
+
+// ExpectErrorIs returns a then function which fails the test unless the
+// action returned an error matching target according to errors.Is.
+func ExpectErrorIs[T any](target error) func(*testing.T, T, error) {
+	return func(t *testing.T, _ T, err error) {
+		t.Helper()
+
+		expectErrorIs(t, err, target)
+	}
+}
+
+// ExpectError returns a then function which fails the test unless the
+// action returned a non-nil error for which match returns nil. The error
+// returned by match, if any, describes why the error did not match.
+//
+// A nil match accepts any non-nil error.
+func ExpectError[T any](match func(error) error) func(*testing.T, T, error) {
+	return func(t *testing.T, _ T, err error) {
+		t.Helper()
+
+		expectError(t, err, match)
+	}
+}
+
+// ExpectErrorContains returns a then function which fails the test unless
+// the action returned an error whose message contains substr.
+func ExpectErrorContains[T any](substr string) func(*testing.T, T, error) {
+	return func(t *testing.T, _ T, err error) {
+		t.Helper()
+
+		expectErrorContains(t, err, substr)
+	}
+}
+
+// ExpectErrorAs returns a then function which fails the test unless the
+// action returned an error with an error of type E in its tree, according
+// to errors.As:
+//
+//	tbdd.ExpectErrorAs[*fs.PathError, TestCase]()
+func ExpectErrorAs[E error, T any]() func(*testing.T, T, error) {
+	return func(t *testing.T, _ T, err error) {
+		t.Helper()
+
+		expectErrorAs[E](t, err)
+	}
+}
+
+// assertT is the subset of *testing.T that assertion helpers depend on.
+//
+// In normal use it is always satisfied by a standard non-nil *testing.T value.
+type assertT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+func expectNoError(t assertT, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func expectErrorIs(t assertT, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("expected error matching %v but got: %v", target, err)
+	}
+}
+
+func expectError(t assertT, err error, match func(error) error) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("expected an error but got none")
+		return
+	}
+
+	if match == nil {
+		return
+	}
+
+	if reason := match(err); reason != nil {
+		t.Fatalf("expected a matching error but got: %v: %v", err, reason)
+	}
+}
+
+func expectErrorContains(t assertT, err error, substr string) {
+	t.Helper()
+
+	if err == nil || !strings.Contains(err.Error(), substr) {
+		t.Fatalf("expected error containing %q but got: %v", substr, err)
+	}
+}
+
+func expectErrorAs[E error](t assertT, err error) {
+	t.Helper()
+
+	var target E
+	if !errors.As(err, &target) {
+		t.Fatalf("expected error of type %v but got: %v", reflect.TypeFor[E](), err)
+	}
+}
+
+// GWTFixture is like GWT except givenF returns a fixture value G which is
+// passed to whenF alongside the test case.
+//
+// This allows arranged resources (servers, temporary directories, clients,
+// etc.) to flow from the given phase into the action without being smuggled
+// through pointer fields of the test case. Each execution of the lifecycle,
+// including each variant, receives the fixture produced by its own given
+// phase.
+//
+// GWTFixture panics if given, when, or then are empty, or if givenF, whenF,
+// or thenF are nil.
+func GWTFixture[T, G, R any](
+	tc T,
+	given string, givenF func(*testing.T, *T) G,
+	when string, whenF func(*testing.T, T, G) R,
+	then string, thenF func(*testing.T, T, R),
+) Lifecycle[T, R] {
+	var wrappedThenF func(*testing.T, T, R, G)
+	if thenF != nil {
+		wrappedThenF = func(t *testing.T, tc T, r R, _ G) {
+			thenF(t, tc, r)
+		}
+	}
+
+	return fixtureLifecycle(
+		"tbdd.GWTFixture",
+		tc,
+		given, givenF,
+		when, whenF,
+		then, wrappedThenF,
+	)
+}
+
+// fixtureLifecycle constructs a Lifecycle whose given phase produces a
+// fixture that is passed to both the act and assert phases of the same
+// execution.
+func fixtureLifecycle[T, G, R any](
+	fn string,
+	tc T,
+	given string, givenF func(*testing.T, *T) G,
+	when string, whenF func(*testing.T, T, G) R,
+	then string, thenF func(*testing.T, T, R, G),
+) Lifecycle[T, R] {
+
+	if given == "" {
+		panic(fn + ": given description must be non-empty")
+	}
+
+	if givenF == nil {
+		panic(fn + ": given function must be non-nil")
+	}
+
+	if when == "" {
+		panic(fn + ": when description must be non-empty")
+	}
+
+	if whenF == nil {
+		panic(fn + ": when function must be non-nil")
+	}
+
+	if then == "" {
+		panic(fn + ": then description must be non-empty")
+	}
+
+	if thenF == nil {
+		panic(fn + ": then function must be non-nil")
+	}
+
+	return Lifecycle[T, R]{
+		TC:    tc,
+		Given: given,
+		Arrange: func(_ *testing.T, cfg Arrange[T, R]) (string, func(*testing.T)) {
+			tc := cfg.TC
+
+			var fixture G
+			*cfg.Act = func(t *testing.T, tc T) R {
+				return whenF(t, tc, fixture)
+			}
+			*cfg.Assert = func(t *testing.T, cfg Assert[T, R]) {
+				thenF(t, cfg.TC, cfg.Result, fixture)
+			}
+
+			return given, func(t *testing.T) {
+				fixture = givenF(t, tc)
+			}
+		},
+		When:   when,
+		Then:   then,
+		Source: definedAt(),
+	}
+}
+
+// MockController is implemented by mock controllers such as those of gomock
+// (go.uber.org/mock/gomock) and minimock, whose Finish method verifies that
+// all expectations were met.
+type MockController interface {
+	Finish()
+}
+
+// GWTMock is like GWTFixture except the fixture is a mock controller created
+// by newController for every execution of the lifecycle.
+//
+// The controller is passed to givenF so expectations can be declared and to
+// whenF so mocks can be exercised. Its Finish method is registered with
+// t.Cleanup of the given phase once givenF returns, so it is called after the
+// when and then phases complete, even if either failed fatally, and
+// forgotten Finish calls can no longer hide unmet expectations.
+//
+// GWTMock panics if given, when, or then are empty, or if newController,
+// givenF, whenF, or thenF are nil.
+func GWTMock[T any, C MockController, R any](
+	tc T,
+	newController func(*testing.T) C,
+	given string, givenF func(*testing.T, *T, C),
+	when string, whenF func(*testing.T, T, C) R,
+	then string, thenF func(*testing.T, T, R),
+) Lifecycle[T, R] {
+	if newController == nil {
+		panic("tbdd.GWTMock: controller constructor must be non-nil")
+	}
+
+	var wrappedGivenF func(*testing.T, *T) C
+	if givenF != nil {
+		wrappedGivenF = func(t *testing.T, tc *T) C {
+			c := newController(t)
+			givenF(t, tc, c)
+			t.Cleanup(c.Finish)
+			return c
+		}
+	}
+
+	var wrappedThenF func(*testing.T, T, R, C)
+	if thenF != nil {
+		wrappedThenF = func(t *testing.T, tc T, r R, _ C) {
+			thenF(t, tc, r)
+		}
+	}
+
+	return fixtureLifecycle(
+		"tbdd.GWTMock",
+		tc,
+		given, wrappedGivenF,
+		when, whenF,
+		then, wrappedThenF,
+	)
+}
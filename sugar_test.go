@@ -0,0 +1,462 @@
+package tbdd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGWTN(t *testing.T) {
+	type TC struct {
+		seeded bool
+	}
+
+	var givenCalled, whenCalled, thenCalled bool
+	b := GWTN(
+		TC{},
+		"g", func(_ *testing.T, tc *TC) {
+			givenCalled = true
+			tc.seeded = true
+		},
+		"w", func(_ *testing.T, tc TC) {
+			whenCalled = tc.seeded
+		},
+		"t", func(_ *testing.T, tc TC) {
+			thenCalled = tc.seeded
+		},
+	)
+
+	f := b.New(t)
+	f(t)
+
+	if !(givenCalled && whenCalled && thenCalled) {
+		t.Error()
+	}
+}
+
+func TestWTN(t *testing.T) {
+	type TC struct{}
+
+	{
+		var whenCalled, thenCalled bool
+		b := WTN(
+			TC{},
+			"w", func(*testing.T, TC) {
+				whenCalled = true
+			},
+			"t", func(*testing.T, TC) {
+				thenCalled = true
+			},
+		)
+
+		f := b.New(t)
+		f(t)
+
+		if !(whenCalled && thenCalled) {
+			t.Error()
+		}
+	}
+
+	//
+	// validate panics
+	//
+
+	for _, v := range []struct {
+		whenF func(*testing.T, TC)
+		thenF func(*testing.T, TC)
+		exp   string
+	}{
+		{nil, func(*testing.T, TC) {}, "tbdd.GWT: when function must be non-nil"},
+		{func(*testing.T, TC) {}, nil, "tbdd.GWT: then function must be non-nil"},
+	} {
+		var panicked bool
+		var r any
+
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			panicked = true
+			WTN(
+				TC{},
+				"w", v.whenF,
+				"t", v.thenF,
+			)
+
+			panicked = false
+		}()
+
+		if !(panicked && v.exp == r) {
+			t.Error()
+		}
+	}
+}
+
+var _ assertT = (*testing.T)(nil)
+
+func TestGWTErr(t *testing.T) {
+	type TC struct {
+		fail bool
+	}
+
+	errBoom := errors.New("boom")
+
+	act := func(_ *testing.T, tc TC) error {
+		if tc.fail {
+			return fmt.Errorf("wrapped: %w", errBoom)
+		}
+		return nil
+	}
+
+	b := GWTErr(
+		TC{},
+		"g", func(*testing.T, *TC) {},
+		"w", act,
+		"t", ExpectNoError[TC](),
+	)
+	f := b.New(t)
+	f(t)
+
+	b = WTErr(
+		TC{fail: true},
+		"w", act,
+		"t", ExpectErrorIs[TC](errBoom),
+	)
+	f = b.New(t)
+	f(t)
+
+	for _, thenF := range []func(*testing.T, TC, error){
+		ExpectError[TC](nil),
+		ExpectErrorContains[TC]("wrapped"),
+		ExpectErrorAs[interface {
+			error
+			Unwrap() error
+		}, TC](),
+	} {
+		b = WTErr(TC{fail: true}, "w", act, "t", thenF)
+		b.New(t)(t)
+	}
+}
+
+func Test_expectNoError(t *testing.T) {
+	t.Parallel()
+
+	{
+		mt := &mT{}
+		expectNoError(mt, nil)
+
+		if mt.Failed() {
+			t.Error("expected no failure for a nil error")
+		}
+	}
+
+	{
+		mt := &mT{}
+		err := errors.New("boom")
+		expectNoError(mt, err)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "expected no error but got: %v" || mt.fatalfCalls[0].args[0] != err {
+			t.Error("expected exactly one fatalf call describing the error")
+		}
+	}
+}
+
+func Test_expectErrorIs(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	{
+		mt := &mT{}
+		expectErrorIs(mt, fmt.Errorf("wrapped: %w", errBoom), errBoom)
+
+		if mt.Failed() {
+			t.Error("expected no failure for a matching error")
+		}
+	}
+
+	for _, err := range []error{nil, errors.New("other")} {
+		mt := &mT{}
+		expectErrorIs(mt, err, errBoom)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "expected error matching %v but got: %v" {
+			t.Error("expected exactly one fatalf call describing the mismatch")
+		}
+	}
+}
+
+func TestGWTFixture(t *testing.T) {
+	type TC struct {
+		n int
+	}
+
+	var fixtures []int
+	b := GWTFixture(
+		TC{n: 1},
+		"g", func(_ *testing.T, tc *TC) int {
+			return tc.n * 10
+		},
+		"w", func(_ *testing.T, tc TC, fixture int) int {
+			fixtures = append(fixtures, fixture)
+			return fixture + tc.n
+		},
+		"t", func(t *testing.T, tc TC, r int) {
+			if r != tc.n*11 {
+				t.Errorf("expected %d but got %d", tc.n*11, r)
+			}
+		},
+	)
+	b.Variants = func(_ *testing.T, tc TC) iter.Seq[TestVariant[TC]] {
+		return func(yield func(TestVariant[TC]) bool) {
+			yield(TestVariant[TC]{TC: TC{n: 2}, Kind: "n=2"})
+		}
+	}
+
+	f := b.New(t)
+	f(t)
+
+	if len(fixtures) != 2 || fixtures[0] != 10 || fixtures[1] != 20 {
+		t.Errorf("unexpected fixtures: %v", fixtures)
+	}
+
+	//
+	// validate panics
+	//
+
+	givenF := func(*testing.T, *TC) int { return 0 }
+	whenF := func(*testing.T, TC, int) int { return 0 }
+	thenF := func(*testing.T, TC, int) {}
+
+	for _, v := range []struct {
+		given  string
+		givenF func(*testing.T, *TC) int
+		when   string
+		whenF  func(*testing.T, TC, int) int
+		then   string
+		thenF  func(*testing.T, TC, int)
+		exp    string
+	}{
+		{"", givenF, "w", whenF, "t", thenF, "tbdd.GWTFixture: given description must be non-empty"},
+		{"g", nil, "w", whenF, "t", thenF, "tbdd.GWTFixture: given function must be non-nil"},
+		{"g", givenF, "", whenF, "t", thenF, "tbdd.GWTFixture: when description must be non-empty"},
+		{"g", givenF, "w", nil, "t", thenF, "tbdd.GWTFixture: when function must be non-nil"},
+		{"g", givenF, "w", whenF, "", thenF, "tbdd.GWTFixture: then description must be non-empty"},
+		{"g", givenF, "w", whenF, "t", nil, "tbdd.GWTFixture: then function must be non-nil"},
+	} {
+		var panicked bool
+		var r any
+
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			panicked = true
+			GWTFixture(
+				TC{},
+				v.given, v.givenF,
+				v.when, v.whenF,
+				v.then, v.thenF,
+			)
+
+			panicked = false
+		}()
+
+		if !(panicked && v.exp == r) {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+type fakeMockController struct {
+	t        *testing.T
+	expected int
+	calls    int
+	finished *int
+}
+
+func (c *fakeMockController) Finish() {
+	*c.finished++
+	if c.calls != c.expected {
+		c.t.Errorf("expected %d calls but got %d", c.expected, c.calls)
+	}
+}
+
+func TestGWTMock(t *testing.T) {
+	type TC struct{}
+
+	if os.Getenv("TBDD_MOCK_HELPER") == "1" {
+		var finished int
+		GWTMock(
+			TC{},
+			func(t *testing.T) *fakeMockController {
+				return &fakeMockController{t: t, finished: &finished}
+			},
+			"a mocked dependency", func(_ *testing.T, _ *TC, c *fakeMockController) {
+				c.expected = 1
+			},
+			"it fails before calling it", func(t *testing.T, _ TC, _ *fakeMockController) int {
+				t.Fatal("boom")
+				return 0
+			},
+			"it is not checked", func(*testing.T, TC, int) {},
+		).New(t)(t)
+		return
+	}
+
+	var finished, controllers int
+	b := GWTMock(
+		TC{},
+		func(t *testing.T) *fakeMockController {
+			controllers++
+			return &fakeMockController{t: t, finished: &finished}
+		},
+		"a mocked dependency", func(_ *testing.T, _ *TC, c *fakeMockController) {
+			c.expected = 1
+		},
+		"it is called", func(_ *testing.T, _ TC, c *fakeMockController) int {
+			c.calls++
+			return c.calls
+		},
+		"the mock observed the call", func(t *testing.T, _ TC, r int) {
+			if r != 1 {
+				t.Errorf("expected 1 call but got %d", r)
+			}
+		},
+	)
+
+	f := b.New(t)
+	f(t)
+	f(t)
+
+	if controllers != 2 || finished != 2 {
+		t.Errorf("expected 2 controllers to be created and finished but got %d and %d", controllers, finished)
+	}
+
+	// the controller is finished even when the when phase fails fatally
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_MOCK_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{"boom", "expected 1 calls but got 0"} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	//
+	// validate panics
+	//
+
+	newController := func(*testing.T) *fakeMockController { return nil }
+	givenF := func(*testing.T, *TC, *fakeMockController) {}
+	whenF := func(*testing.T, TC, *fakeMockController) int { return 0 }
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { GWTMock(TC{}, nil, "g", givenF, "w", whenF, "t", func(*testing.T, TC, int) {}) }, "tbdd.GWTMock: controller constructor must be non-nil"},
+		{func() { GWTMock(TC{}, newController, "g", nil, "w", whenF, "t", func(*testing.T, TC, int) {}) }, "tbdd.GWTMock: given function must be non-nil"},
+		{func() { GWTMock(TC{}, newController, "g", givenF, "w", whenF, "t", nil) }, "tbdd.GWTMock: then function must be non-nil"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+func Test_expectError(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	errMismatch := errors.New("mismatch")
+
+	for _, tc := range []struct {
+		err    error
+		match  func(error) error
+		format string
+	}{
+		{errBoom, nil, ""},
+		{errBoom, func(error) error { return nil }, ""},
+		{nil, nil, "expected an error but got none"},
+		{errBoom, func(error) error { return errMismatch }, "expected a matching error but got: %v: %v"},
+	} {
+		mt := &mT{}
+		expectError(mt, tc.err, tc.match)
+
+		if tc.format == "" {
+			if mt.Failed() {
+				t.Errorf("expected no failure for %v", tc.err)
+			}
+			continue
+		}
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != tc.format {
+			t.Errorf("expected exactly one fatalf call with format %q but got %+v", tc.format, mt.fatalfCalls)
+		}
+	}
+}
+
+func Test_expectErrorContains(t *testing.T) {
+	t.Parallel()
+
+	{
+		mt := &mT{}
+		expectErrorContains(mt, errors.New("disk full"), "full")
+
+		if mt.Failed() {
+			t.Error("expected no failure for a containing error")
+		}
+	}
+
+	for _, err := range []error{nil, errors.New("other")} {
+		mt := &mT{}
+		expectErrorContains(mt, err, "full")
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "expected error containing %q but got: %v" {
+			t.Error("expected exactly one fatalf call describing the mismatch")
+		}
+	}
+}
+
+func Test_expectErrorAs(t *testing.T) {
+	t.Parallel()
+
+	{
+		mt := &mT{}
+		expectErrorAs[*fs.PathError](mt, fmt.Errorf("wrapped: %w", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}))
+
+		if mt.Failed() {
+			t.Error("expected no failure for an error of the type")
+		}
+	}
+
+	for _, err := range []error{nil, errors.New("other")} {
+		mt := &mT{}
+		expectErrorAs[*fs.PathError](mt, err)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != "expected error of type %v but got: %v" || fmt.Sprint(mt.fatalfCalls[0].args[0]) != "*fs.PathError" {
+			t.Error("expected exactly one fatalf call describing the mismatch")
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package tbdd
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// SuiteGWT binds methods of a suite struct S into a Given / When / Then
+// lifecycle so shared scenario state lives on the receiver:
+//
+//	type CartSuite struct{ cart *Cart }
+//
+//	func (s *CartSuite) GivenAnEmptyCart(t *testing.T)          { s.cart = NewCart() }
+//	func (s *CartSuite) WhenAnItemIsAdded(t *testing.T) error   { return s.cart.Add("apple") }
+//	func (s *CartSuite) ThenTheCartHasOneItem(t *testing.T, err error) { ... }
+//
+//	b := tbdd.SuiteGWT(
+//		func() *CartSuite { return &CartSuite{} },
+//		(*CartSuite).GivenAnEmptyCart,
+//		(*CartSuite).WhenAnItemIsAdded,
+//		(*CartSuite).ThenTheCartHasOneItem,
+//	)
+//
+// Descriptions are derived from the method names: the leading Given, When,
+// or Then word is removed and the remaining words are lower cased, so
+// GivenAnEmptyCart is described as "an empty cart". Steps are expected to be
+// method expressions such as (*CartSuite).GivenAnEmptyCart.
+//
+// newSuite is called to produce the initial test case and again, via
+// CloneTC, for every execution of the lifecycle so each basis run and
+// variant receives a fresh receiver.
+//
+// SuiteGWT panics if any argument is nil or if a description cannot be
+// derived from a step's name, such as when an anonymous function is given.
+func SuiteGWT[S, R any](
+	newSuite func() *S,
+	givenF func(*S, *testing.T),
+	whenF func(*S, *testing.T) R,
+	thenF func(*S, *testing.T, R),
+) Lifecycle[*S, R] {
+	if givenF == nil {
+		panic("tbdd.SuiteGWT: given method must be non-nil")
+	}
+
+	b := suiteWT("tbdd.SuiteGWT", newSuite, whenF, thenF)
+
+	b.Given = suiteStepDescription("tbdd.SuiteGWT", "given", "Given", givenF)
+	b.Arrange = givenArrange[*S, R](b.Given, func(t *testing.T, s **S) {
+		givenF(*s, t)
+	})
+
+	return b
+}
+
+// SuiteWT is like SuiteGWT for suites with no given context to convey.
+//
+// See SuiteGWT for more detail.
+func SuiteWT[S, R any](
+	newSuite func() *S,
+	whenF func(*S, *testing.T) R,
+	thenF func(*S, *testing.T, R),
+) Lifecycle[*S, R] {
+	return suiteWT("tbdd.SuiteWT", newSuite, whenF, thenF)
+}
+
+func suiteWT[S, R any](
+	fn string,
+	newSuite func() *S,
+	whenF func(*S, *testing.T) R,
+	thenF func(*S, *testing.T, R),
+) Lifecycle[*S, R] {
+	if newSuite == nil {
+		panic(fn + ": suite constructor must be non-nil")
+	}
+
+	if whenF == nil {
+		panic(fn + ": when method must be non-nil")
+	}
+
+	if thenF == nil {
+		panic(fn + ": then method must be non-nil")
+	}
+
+	return Lifecycle[*S, R]{
+		TC: newSuite(),
+		CloneTC: func(*S) *S {
+			return newSuite()
+		},
+		When: suiteStepDescription(fn, "when", "When", whenF),
+		Act: func(t *testing.T, s *S) R {
+			return whenF(s, t)
+		},
+		Then: suiteStepDescription(fn, "then", "Then", thenF),
+		Assert: func(t *testing.T, cfg Assert[*S, R]) {
+			thenF(cfg.TC, t, cfg.Result)
+		},
+		Source: definedAt(),
+	}
+}
+
+func suiteStepDescription(fn, step, keyword string, f any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+
+	s, ok := stepDescription(name, keyword)
+	if !ok {
+		panic(fn + ": could not derive " + step + " description from function name " + name)
+	}
+
+	return s
+}
+
+// stepDescription converts the fully qualified name of a named step
+// function into a human readable description.
+//
+// keyword is trimmed from the front of the method name when present.
+func stepDescription(funcName, keyword string) (string, bool) {
+	name := funcName
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if after, ok := strings.CutPrefix(name, keyword); ok && (after == "" || !unicode.IsLower(rune(after[0]))) {
+		name = strings.TrimLeft(after, "_")
+	}
+
+	if name == "" || isAnonymousFuncName(name) {
+		return "", false
+	}
+
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 {
+			prevUpper := unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !prevUpper || nextLower {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	for i, w := range words {
+		// words with more than one upper case rune are acronyms and retain their casing
+		var upper int
+		for _, r := range w {
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+
+		if upper <= 1 {
+			words[i] = strings.ToLower(w)
+		}
+	}
+
+	return strings.Join(words, " "), true
+}
+
+// isAnonymousFuncName reports whether name is the compiler generated name of
+// a function literal, such as "func1" or the "2" of a nested "func1.2".
+func isAnonymousFuncName(name string) bool {
+	after := strings.TrimPrefix(name, "func")
+	if after == "" {
+		return false
+	}
+
+	for _, r := range after {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,165 @@
+package tbdd
+
+import (
+	"strings"
+	"testing"
+)
+
+type cartSuite struct {
+	items []string
+	log   *[]string
+}
+
+func (s *cartSuite) GivenAnEmptyCart(*testing.T) {
+	s.items = []string{}
+	*s.log = append(*s.log, "given")
+}
+
+func (s *cartSuite) WhenAnItemIsAdded(*testing.T) int {
+	s.items = append(s.items, "apple")
+	*s.log = append(*s.log, "when")
+	return len(s.items)
+}
+
+func (s *cartSuite) ThenTheCartHasOneItem(t *testing.T, n int) {
+	*s.log = append(*s.log, "then")
+	if n != 1 || len(s.items) != 1 {
+		t.Errorf("expected one item but got %d", n)
+	}
+}
+
+func TestSuiteGWT(t *testing.T) {
+	var log []string
+	var suites int
+
+	newSuite := func() *cartSuite {
+		suites++
+		return &cartSuite{log: &log}
+	}
+
+	b := SuiteGWT(
+		newSuite,
+		(*cartSuite).GivenAnEmptyCart,
+		(*cartSuite).WhenAnItemIsAdded,
+		(*cartSuite).ThenTheCartHasOneItem,
+	)
+
+	if b.Given != "an empty cart" || b.When != "an item is added" || b.Then != "the cart has one item" {
+		t.Fatalf("unexpected descriptions: given=%q when=%q then=%q", b.Given, b.When, b.Then)
+	}
+
+	// running twice shows the receiver is fresh for each execution
+	f := b.New(t)
+	f(t)
+	f(t)
+
+	if suites != 3 {
+		t.Errorf("expected 3 suites to be constructed but got %d", suites)
+	}
+
+	if exp := "given when then given when then"; strings.Join(log, " ") != exp {
+		t.Errorf("expected step log '%s' but got '%s'", exp, strings.Join(log, " "))
+	}
+
+	//
+	// validate panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() {
+			SuiteGWT(newSuite, nil, (*cartSuite).WhenAnItemIsAdded, (*cartSuite).ThenTheCartHasOneItem)
+		}, "tbdd.SuiteGWT: given method must be non-nil"},
+		{func() {
+			SuiteGWT[cartSuite, int](nil, (*cartSuite).GivenAnEmptyCart, (*cartSuite).WhenAnItemIsAdded, (*cartSuite).ThenTheCartHasOneItem)
+		}, "tbdd.SuiteGWT: suite constructor must be non-nil"},
+		{func() {
+			SuiteWT(newSuite, nil, (*cartSuite).ThenTheCartHasOneItem)
+		}, "tbdd.SuiteWT: when method must be non-nil"},
+		{func() {
+			SuiteWT(newSuite, (*cartSuite).WhenAnItemIsAdded, nil)
+		}, "tbdd.SuiteWT: then method must be non-nil"},
+	} {
+		var panicked bool
+		var r any
+
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			panicked = true
+			v.f()
+			panicked = false
+		}()
+
+		if !(panicked && v.exp == r) {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+
+	{
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			SuiteWT(newSuite, (*cartSuite).WhenAnItemIsAdded, func(*cartSuite, *testing.T, int) {})
+		}()
+
+		if s, _ := r.(string); s == "" {
+			t.Error("expected a panic for an anonymous then function")
+		}
+	}
+}
+
+func TestSuiteWT(t *testing.T) {
+	var log []string
+
+	b := SuiteWT(
+		func() *cartSuite {
+			return &cartSuite{log: &log}
+		},
+		(*cartSuite).WhenAnItemIsAdded,
+		(*cartSuite).ThenTheCartHasOneItem,
+	)
+
+	if b.Given != "" || b.Arrange != nil {
+		t.Error("expected no given context")
+	}
+
+	f := b.New(t)
+	f(t)
+
+	if exp := "when then"; strings.Join(log, " ") != exp {
+		t.Errorf("expected step log '%s' but got '%s'", exp, strings.Join(log, " "))
+	}
+}
+
+func Test_stepDescription(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		name, keyword, exp string
+		ok                 bool
+	}{
+		{"pkg.(*Suite).GivenAnEmptyCart", "Given", "an empty cart", true},
+		{"example.com/x/pkg.(*Suite[...]).WhenTheUserPostsJSONData", "When", "the user posts JSON data", true},
+		{"pkg.(*Suite).Then_a_B_result", "Then", "a b result", true},
+		{"pkg.(*Suite).ThenAnID", "Then", "an ID", true},
+		{"pkg.(*Suite).Thenceforth", "Then", "thenceforth", true},
+		{"pkg.AddItem", "When", "add item", true},
+		{"pkg.func", "When", "func", true},
+		{"pkg.(*Suite).When", "When", "", false},
+		{"pkg.TestX.func1", "When", "", false},
+		{"pkg.TestX.func1.2", "When", "", false},
+	} {
+		s, ok := stepDescription(v.name, v.keyword)
+		if s != v.exp || ok != v.ok {
+			t.Errorf("stepDescription(%q, %q): expected (%q, %t) but got (%q, %t)", v.name, v.keyword, v.exp, v.ok, s, ok)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tbdd
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+)
+
+// bubbles holds the scenario of every running synctest bubble, which may be
+// nil, by the *testing.T of the bubble.
+var bubbles sync.Map
+
+// bubble calls f with t within a new testing/synctest bubble of the scenario
+// sr, or directly when t is nil as it is in self-test contexts.
+//
+// A skip within the bubble skips t as well, with the same reason; any
+// failure within the bubble ends t.
+func bubble(t *testing.T, sr *scenario, f func(*testing.T)) {
+	if t == nil {
+		f(t)
+		return
+	}
+
+	t.Helper()
+
+	var skipped bool
+	var reason string
+	synctest.Test(t, func(t *testing.T) {
+		bubbles.Store(t, sr)
+		defer func() {
+			bubbles.Delete(t)
+			skipped, reason = t.Skipped(), skipReason(t)
+		}()
+
+		f(t)
+	})
+
+	if skipped {
+		// the reason was already logged within the bubble
+		recordSkipReason(t, reason)
+		t.SkipNow()
+	}
+}
+
+// refuseBubble fails t, and its scenario as misconfigured, when t runs within
+// a synctest bubble, where t.Run panics, so the helper fn running subtests
+// of t reports which helper Synctest cannot be combined with instead.
+func refuseBubble(t *testing.T, fn string) {
+	v, ok := bubbles.Load(t)
+	if !ok {
+		return
+	}
+
+	t.Helper()
+
+	var loc string
+	if sr, _ := v.(*scenario); sr != nil {
+		sr.fail(ClassFailedConfig)
+		loc = sr.Source
+	}
+
+	linkedT{t, loc}.Fatal("tbdd-config-error: Synctest cannot be combined with " + strings.TrimPrefix(fn, "tbdd.") + ", which runs subtests")
+}
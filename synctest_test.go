@@ -0,0 +1,163 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_synctest(t *testing.T) {
+	if os.Getenv("TBDD_SYNCTEST_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"a background timer fails", func(t *testing.T, _ struct{}) {
+				r := RecorderFor(t)
+				r.Go(func() {
+					time.Sleep(time.Minute)
+					r.Error("timer fired")
+				})
+			},
+			"it is reported", func(*testing.T, struct{}) {},
+		).With(WithSynctest[struct{}, struct{}]()).New(t)
+		f(t)
+
+		WT(
+			struct{}{},
+			"the behavior runs twice", ActRuns(2, func(*testing.T, struct{}) int { return 1 }),
+			"it is refused", func(*testing.T, struct{}, []int) {},
+		).With(WithSynctest[struct{}, []int]()).New(t)(t)
+
+		WT(
+			struct{}{},
+			"the behavior runs", func(*testing.T, struct{}) int { return 1 },
+			"a table checks it", ThenTable(map[string]func(*testing.T, struct{}, int){
+				"one": func(*testing.T, struct{}, int) {},
+			}),
+		).With(WithSynctest[struct{}, int]()).New(t)(t)
+		return
+	}
+
+	type result struct {
+		start   time.Time
+		elapsed time.Duration
+	}
+
+	var asserted time.Time
+	f := WT(
+		struct{}{},
+		"the behavior waits an hour", func(*testing.T, struct{}) result {
+			start := time.Now()
+
+			done := make(chan struct{})
+			time.AfterFunc(time.Hour, func() {
+				close(done)
+			})
+			<-done
+
+			return result{start, time.Since(start)}
+		},
+		"no real time passes", func(t *testing.T, _ struct{}, r result) {
+			asserted = time.Now()
+
+			if r.elapsed != time.Hour {
+				t.Errorf("expected exactly an hour to pass but got %s", r.elapsed)
+			}
+		},
+	).With(WithSynctest[struct{}, result]()).New(t)
+
+	start := time.Now()
+	f(t)
+
+	if d := time.Since(start); d > time.Minute {
+		t.Errorf("expected the scenario to run instantly but it took %s", d)
+	}
+
+	// bubbles start at midnight UTC 2000-01-01
+	if asserted.Year() != 2000 {
+		t.Errorf("expected the assertions to run within a bubble but they ran at %s", asserted)
+	}
+
+	//
+	// skips within a bubble skip the scenario
+	//
+
+	f = WT(
+		struct{}{},
+		"the behavior is unavailable", func(t *testing.T, _ struct{}) result {
+			Skip(t, "not supported")
+			return result{}
+		},
+		"it is skipped", func(*testing.T, struct{}, result) {},
+	).With(WithSynctest[struct{}, result]()).New(t)
+	// only the results of this run count, as -count runs the test again
+	n := len(Results())
+
+	t.Run("skipped", f)
+
+	var got []ScenarioResult
+	for _, r := range Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/skipped/") {
+			got = append(got, r)
+		}
+	}
+
+	if len(got) != 1 || got[0].Status != StatusSkipped || got[0].SkipReason != "not supported" {
+		t.Errorf("expected a skipped result with reason 'not supported' but got %+v", got)
+	}
+
+	//
+	// invariants are checked outside the bubbles
+	//
+
+	var checks int
+	WT(
+		struct{}{},
+		"the behavior runs", func(*testing.T, struct{}) result { return result{} },
+		"its invariants hold", func(*testing.T, struct{}, result) {},
+	).With(
+		WithSynctest[struct{}, result](),
+		WithInvariants[struct{}, result](func(*testing.T, struct{}) { checks++ }),
+	).New(t)(t)
+
+	if checks == 0 {
+		t.Error("expected the invariants to be checked")
+	}
+
+	// self-test contexts run without a bubble
+	var ran bool
+	bubble(nil, nil, func(*testing.T) {
+		ran = true
+	})
+	if !ran {
+		t.Error("expected the function to run")
+	}
+
+	//
+	// failures within a bubble fail the scenario
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_SYNCTEST_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- FAIL: " + t.Name() + "/when_a_background_timer_fails ",
+		"timer fired",
+		"--- FAIL: " + t.Name() + "/when_the_behavior_runs_twice ",
+		"tbdd-config-error: Synctest cannot be combined with ActRuns, which runs subtests",
+		"=== ATTR  " + t.Name() + "/when_the_behavior_runs_twice tbdd.class failed-config",
+		"--- FAIL: " + t.Name() + "/when_the_behavior_runs ",
+		"tbdd-config-error: Synctest cannot be combined with ThenTable, which runs subtests",
+		"=== ATTR  " + t.Name() + "/when_the_behavior_runs tbdd.class failed-config",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
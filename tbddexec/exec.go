@@ -0,0 +1,182 @@
+// Package tbddexec provides tbdd Act adapters and assertion helpers for
+// command line behaviors exercised through os/exec.
+//
+// Like tbdd itself, this package is intended exclusively for use in
+// *_test.go files.
+package tbddexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// Command declaratively describes a command to run.
+type Command struct {
+	// Args holds the program name followed by its arguments; it must be non-empty.
+	Args []string
+	// Env is appended to the environment of the current process.
+	Env []string
+	// Dir is the working directory; empty means the current directory.
+	Dir   string
+	Stdin []byte
+	// Timeout kills the command after the duration elapses when positive, along
+	// with the processes it started on Unix systems.
+	Timeout time.Duration
+}
+
+// Result is the outcome of running a Command.
+type Result struct {
+	// ExitCode is the exit status of the process or -1 if it did not exit
+	// normally, such as when it could not be started or was killed.
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
+	Duration time.Duration
+	// TimedOut is true when the command was killed because its timeout elapsed.
+	TimedOut bool
+	// Err is any error other than a non-zero exit status, such as failing to
+	// start the program.
+	Err error
+}
+
+// waitDelay bounds how long Run waits for the output of a command once it
+// exited or was killed.
+const waitDelay = time.Second
+
+// Act returns an Act function which runs the command described by cmd for a
+// test case and records the result.
+func Act[T any](cmd func(T) Command) func(*testing.T, T) Result {
+	if cmd == nil {
+		panic("tbddexec.Act: command function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) Result {
+		t.Helper()
+
+		return Run(t.Context(), cmd(tc))
+	}
+}
+
+// Run executes c and returns its Result. A non-zero exit status is reported
+// through Result.ExitCode rather than Result.Err.
+//
+// Run stops waiting for the output of the command waitDelay after it exits or
+// is killed, even if processes it started still hold the output open, and
+// then reports exec.ErrWaitDelay through Result.Err.
+func Run(ctx context.Context, c Command) Result {
+	if len(c.Args) == 0 {
+		return Result{ExitCode: -1, Err: errors.New("tbddexec: command has no arguments")}
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, c.Args[0], c.Args[1:]...)
+	killProcessGroup(cmd)
+	cmd.WaitDelay = waitDelay
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	cmd.Stdin = bytes.NewReader(c.Stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	r := Result{
+		ExitCode: -1,
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		Duration: time.Since(start),
+		TimedOut: c.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}
+
+	if cmd.ProcessState != nil {
+		r.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	if _, ok := err.(*exec.ExitError); !ok || r.TimedOut {
+		r.Err = err
+	}
+
+	return r
+}
+
+// ExpectExitCode returns a then function which fails the test unless the
+// command exited with code.
+func ExpectExitCode[T any](code int) func(*testing.T, T, Result) {
+	return func(t *testing.T, _ T, r Result) {
+		t.Helper()
+
+		expectExitCode(t, r, code)
+	}
+}
+
+// ExpectSuccess returns a then function which fails the test unless the
+// command ran without error and exited with code zero.
+func ExpectSuccess[T any]() func(*testing.T, T, Result) {
+	return ExpectExitCode[T](0)
+}
+
+// ExpectStdoutMatches returns a then function which fails the test unless
+// stdout matches the regular expression pattern.
+func ExpectStdoutMatches[T any](pattern string) func(*testing.T, T, Result) {
+	re := regexp.MustCompile(pattern)
+
+	return func(t *testing.T, _ T, r Result) {
+		t.Helper()
+
+		expectMatches(t, "stdout", r.Stdout, re)
+	}
+}
+
+// ExpectStderrMatches returns a then function which fails the test unless
+// stderr matches the regular expression pattern.
+func ExpectStderrMatches[T any](pattern string) func(*testing.T, T, Result) {
+	re := regexp.MustCompile(pattern)
+
+	return func(t *testing.T, _ T, r Result) {
+		t.Helper()
+
+		expectMatches(t, "stderr", r.Stderr, re)
+	}
+}
+
+// assertT is the subset of *testing.T that assertion helpers depend on.
+type assertT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+func expectExitCode(t assertT, r Result, code int) {
+	t.Helper()
+
+	if r.Err != nil {
+		t.Fatalf("command failed to run: %v; stderr: %s", r.Err, r.Stderr)
+		return
+	}
+
+	if r.ExitCode != code {
+		t.Fatalf("exit code: expected %d but got %d; stderr: %s", code, r.ExitCode, r.Stderr)
+	}
+}
+
+func expectMatches(t assertT, stream string, b []byte, re *regexp.Regexp) {
+	t.Helper()
+
+	if !re.Match(b) {
+		t.Fatalf("%s: expected a match for %q but got: %s", stream, re.String(), b)
+	}
+}
@@ -0,0 +1,172 @@
+package tbddexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+var _ assertT = (*testing.T)(nil)
+
+type mT struct {
+	fatalfCalls []string
+}
+
+func (t *mT) Helper() {
+}
+
+func (t *mT) Fatalf(format string, args ...any) {
+	t.fatalfCalls = append(t.fatalfCalls, format)
+}
+
+// TestHelperProcess is not a real test; it is the command executed by the
+// other tests in this file.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("TBDDEXEC_HELPER") != "1" {
+		t.Skip("helper process only")
+	}
+
+	switch os.Getenv("TBDDEXEC_MODE") {
+	case "echo":
+		b, _ := io.ReadAll(os.Stdin)
+		fmt.Fprintf(os.Stdout, "stdin=%s", b)
+		fmt.Fprint(os.Stderr, "warning: echoed")
+		os.Exit(0)
+	case "fail":
+		fmt.Fprint(os.Stderr, "boom")
+		os.Exit(3)
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+
+	os.Exit(2)
+}
+
+func helperCommand(mode string) Command {
+	return Command{
+		Args: []string{os.Args[0], "-test.run=^TestHelperProcess$"},
+		Env:  []string{"TBDDEXEC_HELPER=1", "TBDDEXEC_MODE=" + mode},
+	}
+}
+
+func TestAct(t *testing.T) {
+	type TC struct {
+		mode  string
+		stdin string
+	}
+
+	b := tbdd.WT(
+		TC{mode: "echo", stdin: "hello"},
+		"the command runs", Act(func(tc TC) Command {
+			c := helperCommand(tc.mode)
+			c.Stdin = []byte(tc.stdin)
+			return c
+		}),
+		"it echoes its input", tbdd.JoinThens(
+			ExpectSuccess[TC](),
+			ExpectStdoutMatches[TC](`^stdin=hello$`),
+			ExpectStderrMatches[TC](`warning`),
+		),
+	)
+
+	f := b.New(t)
+	f(t)
+
+	{
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			Act[TC](nil)
+		}()
+
+		if exp := "tbddexec.Act: command function must be non-nil"; r != exp {
+			t.Errorf("expected panic '%s' but got '%v'", exp, r)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	{
+		r := Run(context.Background(), helperCommand("fail"))
+		if r.Err != nil || r.ExitCode != 3 || string(r.Stderr) != "boom" || r.TimedOut {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+
+	{
+		c := helperCommand("hang")
+		c.Timeout = 50 * time.Millisecond
+
+		r := Run(context.Background(), c)
+		if r.Err == nil || r.ExitCode != -1 || !r.TimedOut {
+			t.Errorf("expected a timed out result but got: %+v", r)
+		}
+	}
+
+	// the processes started by a timed out command are killed with it, so they
+	// cannot hold its output open
+	if _, err := exec.LookPath("sh"); err == nil && runtime.GOOS != "windows" {
+		r := Run(context.Background(), Command{Args: []string{"sh", "-c", "sleep 5; echo done"}, Timeout: 100 * time.Millisecond})
+		if r.Err == nil || !r.TimedOut || r.Duration > 2*time.Second || len(r.Stdout) != 0 {
+			t.Errorf("expected the forking command to time out promptly but got: %+v", r)
+		}
+	}
+
+	{
+		c := helperCommand("echo")
+		c.Dir = os.TempDir()
+
+		if r := Run(context.Background(), c); r.Err != nil || r.ExitCode != 0 {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+
+	{
+		r := Run(context.Background(), Command{Args: []string{"/definitely/not/a/program"}})
+		if r.Err == nil || r.ExitCode != -1 {
+			t.Errorf("expected a start failure but got: %+v", r)
+		}
+	}
+
+	{
+		r := Run(context.Background(), Command{})
+		if r.Err == nil || r.ExitCode != -1 {
+			t.Errorf("expected an error for an empty command but got: %+v", r)
+		}
+	}
+}
+
+func TestExpect_failures(t *testing.T) {
+	t.Parallel()
+
+	r := Result{ExitCode: 1, Stdout: []byte("out"), Stderr: []byte("err")}
+
+	for _, v := range []struct {
+		f   func(assertT)
+		exp string
+	}{
+		{func(t assertT) { expectExitCode(t, r, 0) }, "exit code: expected %d but got %d; stderr: %s"},
+		{func(t assertT) { expectExitCode(t, Result{Err: io.EOF}, 0) }, "command failed to run: %v; stderr: %s"},
+		{func(t assertT) { expectMatches(t, "stdout", r.Stdout, regexp.MustCompile("^x")) }, "%s: expected a match for %q but got: %s"},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package tbddexec
+
+import "os/exec"
+
+// killProcessGroup leaves cmd to kill only its own process when cancelled,
+// as process groups are not supported on this platform.
+func killProcessGroup(*exec.Cmd) {
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package tbddexec
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup starts cmd in its own process group and makes its
+// cancellation kill the whole group, so processes the command started do not
+// outlive it or hold its output open.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,309 @@
+// Package tbddfs provides tbdd assertion helpers for behaviors which produce
+// files and directories.
+//
+// Like tbdd itself, this package is intended exclusively for use in
+// *_test.go files.
+package tbddfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+// SnapshotOptions controls which files are part of a directory snapshot and
+// what is recorded about them.
+type SnapshotOptions struct {
+	// Include optionally limits the snapshot to files matching at least one
+	// of these globs.
+	Include []string
+	// Exclude removes files and whole directories matching any of these globs.
+	Exclude []string
+	// Hash records a SHA-256 hash of every file's content.
+	Hash bool
+	// Update rewrites golden manifests from the current tree instead of
+	// comparing against them. It is implied by the -tbdd.update-golden flag.
+	Update bool
+}
+
+// Entry describes one file of a directory snapshot.
+type Entry struct {
+	// Path is slash separated and relative to the snapshot root.
+	Path string
+	Size int64
+	// Hash is the hex encoded SHA-256 of the content, or empty when hashing
+	// was not requested.
+	Hash string
+}
+
+// Manifest is a directory snapshot sorted by path.
+type Manifest []Entry
+
+// Snapshot walks dir and records every regular file selected by opts.
+//
+// Globs use path.Match syntax. A glob containing a "/" is matched against
+// the slash separated path relative to dir; any other glob is matched
+// against the base name alone.
+func Snapshot(dir string, opts SnapshotOptions) (Manifest, error) {
+	var m Manifest
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// p is always within dir so Rel cannot fail
+		rel, _ := filepath.Rel(dir, p)
+		rel = filepath.ToSlash(rel)
+
+		if rel == "." {
+			return nil
+		}
+
+		if matchAny(opts.Exclude, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if len(opts.Include) > 0 && !matchAny(opts.Include, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		e := Entry{Path: rel, Size: info.Size()}
+		if opts.Hash {
+			e.Hash, err = hashFile(p)
+			if err != nil {
+				return err
+			}
+		}
+
+		m = append(m, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(m, func(a, b Entry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return m, nil
+}
+
+// String renders the manifest in the golden file format: one line per file
+// holding the path, size, and optional hash separated by tabs.
+func (m Manifest) String() string {
+	var sb strings.Builder
+
+	for _, e := range m {
+		sb.WriteString(e.Path)
+		sb.WriteByte('\t')
+		sb.WriteString(strconv.FormatInt(e.Size, 10))
+		if e.Hash != "" {
+			sb.WriteByte('\t')
+			sb.WriteString(e.Hash)
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// ParseManifest parses the golden file format produced by Manifest.String.
+func ParseManifest(s string) (Manifest, error) {
+	var m Manifest
+
+	for i, line := range strings.Split(s, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, errors.New("tbddfs: malformed manifest line " + strconv.Itoa(i+1) + ": " + line)
+		}
+
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.New("tbddfs: malformed size on manifest line " + strconv.Itoa(i+1) + ": " + line)
+		}
+
+		e := Entry{Path: fields[0], Size: size}
+		if len(fields) == 3 {
+			e.Hash = fields[2]
+		}
+
+		m = append(m, e)
+	}
+
+	return m, nil
+}
+
+// Compare returns a human readable line for every difference between want
+// and got. Lines start with "-" for missing files, "+" for unexpected files,
+// and "~" for files whose size or hash changed.
+func Compare(want, got Manifest) []string {
+	var diffs []string
+
+	wantByPath := make(map[string]Entry, len(want))
+	for _, e := range want {
+		wantByPath[e.Path] = e
+	}
+
+	gotByPath := make(map[string]Entry, len(got))
+	for _, e := range got {
+		gotByPath[e.Path] = e
+
+		w, ok := wantByPath[e.Path]
+		if !ok {
+			diffs = append(diffs, "+ "+e.Path)
+			continue
+		}
+
+		if w.Size != e.Size {
+			diffs = append(diffs, "~ "+e.Path+": size "+strconv.FormatInt(w.Size, 10)+" -> "+strconv.FormatInt(e.Size, 10))
+		} else if w.Hash != e.Hash {
+			diffs = append(diffs, "~ "+e.Path+": hash "+w.Hash+" -> "+e.Hash)
+		}
+	}
+
+	for _, e := range want {
+		if _, ok := gotByPath[e.Path]; !ok {
+			diffs = append(diffs, "- "+e.Path)
+		}
+	}
+
+	return diffs
+}
+
+// ExpectTree returns a then function which snapshots the directory returned
+// by dir and compares it against the golden manifest file at golden.
+//
+// When opts.Update is true, or the -tbdd.update-golden flag is set, the
+// golden file is (re)written instead.
+func ExpectTree[T, R any](dir func(T, R) string, golden string, opts SnapshotOptions) func(*testing.T, T, R) {
+	if dir == nil {
+		panic("tbddfs.ExpectTree: dir function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		AssertTree(t, dir(tc, r), golden, opts)
+	}
+}
+
+// AssertTree snapshots dir and compares it against the golden manifest file
+// at golden, failing the test with a diff when they differ.
+//
+// When opts.Update is true, or the -tbdd.update-golden flag is set, the
+// golden file is (re)written instead.
+func AssertTree(t *testing.T, dir, golden string, opts SnapshotOptions) {
+	t.Helper()
+
+	assertTree(t, dir, golden, opts)
+}
+
+// assertT is the subset of *testing.T that assertion helpers depend on.
+type assertT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+func assertTree(t assertT, dir, golden string, opts SnapshotOptions) {
+	t.Helper()
+
+	got, err := Snapshot(dir, opts)
+	if err != nil {
+		t.Fatalf("failed to snapshot %s: %v", dir, err)
+		return
+	}
+
+	if opts.Update || tbdd.UpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+			return
+		}
+
+		if err := os.WriteFile(golden, []byte(got.String()), 0o644); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", golden, err)
+			return
+		}
+
+		t.Logf("updated golden file %s", golden)
+		tbdd.RecordUpdate("snapshot", golden)
+		return
+	}
+
+	b, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", golden, err)
+		return
+	}
+
+	want, err := ParseManifest(string(b))
+	if err != nil {
+		t.Fatalf("failed to parse golden file %s: %v", golden, err)
+		return
+	}
+
+	if diffs := Compare(want, got); len(diffs) > 0 {
+		t.Fatalf("directory %s does not match golden file %s:\n\t%s", dir, golden, strings.Join(diffs, "\n\t"))
+	}
+}
+
+func matchAny(globs []string, rel string) bool {
+	base := path.Base(rel)
+
+	for _, g := range globs {
+		s := base
+		if strings.Contains(g, "/") {
+			s = rel
+		}
+
+		if ok, _ := path.Match(g, s); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,195 @@
+package tbddfs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+var _ assertT = (*testing.T)(nil)
+
+type mT struct {
+	fatalfCalls []string
+	logfCalls   []string
+}
+
+func (t *mT) Helper() {
+}
+
+func (t *mT) Fatalf(format string, args ...any) {
+	t.fatalfCalls = append(t.fatalfCalls, format)
+}
+
+func (t *mT) Logf(format string, args ...any) {
+	t.logfCalls = append(t.logfCalls, format)
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.txt":           "aaa",
+		"sub/b.txt":       "b",
+		"sub/c.log":       "c",
+		"tmp/ignored.txt": "x",
+	})
+
+	m, err := Snapshot(dir, SnapshotOptions{Include: []string{"*.txt"}, Exclude: []string{"tmp", "sub/c.*"}, Hash: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, e := range m {
+		paths = append(paths, e.Path)
+		if e.Hash == "" {
+			t.Errorf("expected a hash for %s", e.Path)
+		}
+	}
+
+	if !slices.Equal(paths, []string{"a.txt", "sub/b.txt"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+
+	parsed, err := ParseManifest(m.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(parsed, m) {
+		t.Errorf("expected manifest to round trip: %v != %v", parsed, m)
+	}
+
+	if _, err := Snapshot(filepath.Join(dir, "missing"), SnapshotOptions{}); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestParseManifest_errors(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"only-path\n", "a\t1\tb\tc\n", "a\tten\n"} {
+		if _, err := ParseManifest(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	want := Manifest{{"a", 1, "x"}, {"b", 2, ""}, {"c", 3, "y"}}
+	got := Manifest{{"a", 1, "z"}, {"c", 4, "y"}, {"d", 5, ""}}
+
+	exp := []string{"~ a: hash x -> z", "~ c: size 3 -> 4", "+ d", "- b"}
+	if diffs := Compare(want, got); !slices.Equal(diffs, exp) {
+		t.Errorf("expected diffs %v but got %v", exp, diffs)
+	}
+}
+
+func TestExpectTree(t *testing.T) {
+	type TC struct {
+		dir string
+	}
+
+	golden := filepath.Join(t.TempDir(), "testdata", "tree.golden")
+
+	newLifecycle := func(update bool) tbdd.Lifecycle[TC, struct{}] {
+		return tbdd.GWTN(
+			TC{},
+			"an empty output directory", func(t *testing.T, tc *TC) {
+				tc.dir = t.TempDir()
+			},
+			"files are generated", func(t *testing.T, tc TC) {
+				writeFiles(t, tc.dir, map[string]string{"out/report.txt": "ok"})
+			},
+			"the tree matches the golden manifest", func(t *testing.T, tc TC) {
+				ExpectTree(func(tc TC, _ struct{}) string { return tc.dir }, golden, SnapshotOptions{Hash: true, Update: update})(t, tc, struct{}{})
+			},
+		)
+	}
+
+	for _, update := range []bool{true, false} {
+		f := newLifecycle(update).New(t)
+		f(t)
+	}
+
+	b, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(b), "out/report.txt\t2\t") {
+		t.Errorf("unexpected golden content: %s", b)
+	}
+
+	if !slices.Contains(tbdd.Updates(), tbdd.Update{Kind: "snapshot", Path: golden}) {
+		t.Errorf("expected the update to be recorded as a snapshot but got %v", tbdd.Updates())
+	}
+
+	{
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			ExpectTree[TC, struct{}](nil, golden, SnapshotOptions{})
+		}()
+
+		if exp := "tbddfs.ExpectTree: dir function must be non-nil"; r != exp {
+			t.Errorf("expected panic '%s' but got '%v'", exp, r)
+		}
+	}
+}
+
+func Test_assertTree(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a"})
+
+	tmp := t.TempDir()
+	badGolden := filepath.Join(tmp, "bad.golden")
+	writeFiles(t, tmp, map[string]string{"bad.golden": "a.txt\n", "other.golden": "b.txt\t1\n", "blocker": ""})
+
+	for _, v := range []struct {
+		dir, golden string
+		update      bool
+		exp         string
+	}{
+		{filepath.Join(dir, "missing"), badGolden, false, "failed to snapshot %s: %v"},
+		{dir, filepath.Join(tmp, "missing.golden"), false, "failed to read golden file %s: %v"},
+		{dir, badGolden, false, "failed to parse golden file %s: %v"},
+		{dir, filepath.Join(tmp, "other.golden"), false, "directory %s does not match golden file %s:\n\t%s"},
+		{dir, filepath.Join(tmp, "blocker", "x.golden"), true, "failed to create golden directory: %v"},
+		{dir, tmp, true, "failed to update golden file %s: %v"},
+	} {
+		mt := &mT{}
+		assertTree(mt, v.dir, v.golden, SnapshotOptions{Update: v.update})
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package tbddhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+// Interaction is one expectation of a consumer driven contract: a request
+// the consumer sends and the response the provider must answer it with.
+type Interaction struct {
+	Description string
+	// ProviderState names the state the provider must be in before the
+	// request is sent, or is empty when no particular state is required.
+	ProviderState string
+	Request       Request
+	Response      ExpectedResponse
+}
+
+// ExpectedResponse is the part of a response a contract constrains.
+type ExpectedResponse struct {
+	StatusCode int
+	// Header holds headers which must be present with the given values.
+	// Headers not listed are not checked.
+	Header http.Header
+	// Body is the expected body, if any. JSON bodies match when every
+	// expected object member is present with a matching value, so providers
+	// may return additional members; any other body must match exactly.
+	Body []byte
+}
+
+// pactFile is the subset of the Pact specification file format supported by
+// LoadContract.
+type pactFile struct {
+	Interactions []pactInteraction `json:"interactions"`
+}
+
+type pactInteraction struct {
+	Description    string `json:"description"`
+	ProviderState  string `json:"providerState"`
+	ProviderStates []struct {
+		Name string `json:"name"`
+	} `json:"providerStates"`
+	Request struct {
+		Method  string            `json:"method"`
+		Path    string            `json:"path"`
+		Query   json.RawMessage   `json:"query"`
+		Headers map[string]string `json:"headers"`
+		Body    json.RawMessage   `json:"body"`
+	} `json:"request"`
+	Response struct {
+		Status  int               `json:"status"`
+		Headers map[string]string `json:"headers"`
+		Body    json.RawMessage   `json:"body"`
+	} `json:"response"`
+}
+
+// LoadContract reads the interactions of the Pact contract file at path.
+//
+// Interactions are read from the "interactions" member of version 2 and 3
+// Pact files. Matching rules are not supported: expected values are matched
+// as described by ExpectedResponse.
+func LoadContract(path string) ([]Interaction, error) {
+	var is []Interaction
+
+	for v, err := range contractVariants(path) {
+		if err != nil {
+			return nil, err
+		}
+
+		is = append(is, v.TC)
+	}
+
+	return is, nil
+}
+
+// ContractVariants returns a Variants2 function which yields a variant for
+// every interaction of the Pact contract file at path, with the interaction
+// description as its Kind. A file which cannot be read or parsed, and every
+// interaction which cannot be converted, yields an error instead.
+//
+// See VerifyContract for a Lifecycle which uses it.
+func ContractVariants(path string) func(*testing.T, Interaction) iter.Seq2[tbdd.TestVariant[Interaction], error] {
+	return func(*testing.T, Interaction) iter.Seq2[tbdd.TestVariant[Interaction], error] {
+		return contractVariants(path)
+	}
+}
+
+func contractVariants(path string) iter.Seq2[tbdd.TestVariant[Interaction], error] {
+	return func(yield func(tbdd.TestVariant[Interaction], error) bool) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			yield(tbdd.TestVariant[Interaction]{}, err)
+			return
+		}
+
+		var f pactFile
+		if err := json.Unmarshal(b, &f); err != nil {
+			yield(tbdd.TestVariant[Interaction]{}, errors.New("tbddhttp: invalid contract "+path+": "+err.Error()))
+			return
+		}
+
+		for i, pi := range f.Interactions {
+			in, err := pi.interaction()
+			if err != nil {
+				err = errors.New("tbddhttp: invalid interaction " + strconv.Itoa(i) + " of contract " + path + ": " + err.Error())
+			}
+
+			if !yield(tbdd.TestVariant[Interaction]{TC: in, Kind: in.Description}, err) {
+				return
+			}
+		}
+	}
+}
+
+func (pi pactInteraction) interaction() (Interaction, error) {
+	if pi.Description == "" {
+		return Interaction{}, errors.New("description must be non-empty")
+	}
+
+	in := Interaction{
+		Description:   pi.Description,
+		ProviderState: pi.ProviderState,
+	}
+
+	if len(pi.ProviderStates) > 0 {
+		names := make([]string, len(pi.ProviderStates))
+		for i, s := range pi.ProviderStates {
+			names[i] = s.Name
+		}
+
+		in.ProviderState = strings.Join(names, " and ")
+	}
+
+	target := pi.Request.Path
+	if target == "" {
+		target = "/"
+	}
+
+	query, err := pactQuery(pi.Request.Query)
+	if err != nil {
+		return Interaction{}, err
+	}
+	if query != "" {
+		target += "?" + query
+	}
+
+	in.Request = Request{
+		Method: pi.Request.Method,
+		Path:   target,
+		Header: pactHeader(pi.Request.Headers),
+	}
+	in.Request.Body = pactBody(pi.Request.Body, in.Request.Header)
+
+	in.Response = ExpectedResponse{
+		StatusCode: pi.Response.Status,
+		Header:     pactHeader(pi.Response.Headers),
+	}
+	in.Response.Body = pactBody(pi.Response.Body, in.Response.Header)
+
+	return in, nil
+}
+
+// pactQuery converts a query given either as a string or as an object of
+// value lists into an encoded query string.
+func pactQuery(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+
+	var q url.Values
+	if err := json.Unmarshal(raw, &q); err != nil {
+		return "", errors.New("query must be a string or an object of string arrays")
+	}
+
+	return q.Encode(), nil
+}
+
+func pactHeader(m map[string]string) http.Header {
+	if len(m) == 0 {
+		return nil
+	}
+
+	h := make(http.Header, len(m))
+	for k, v := range m {
+		h.Set(k, v)
+	}
+
+	return h
+}
+
+// pactBody returns the content of a body as sent on the wire: JSON string
+// values hold the literal content of non-JSON bodies.
+func pactBody(raw json.RawMessage, h http.Header) []byte {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var s string
+	if !isJSON(h) && json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+
+	var buf bytes.Buffer
+	// raw was validated by json.Unmarshal of the contract file
+	_ = json.Compact(&buf, raw)
+	return buf.Bytes()
+}
+
+// isJSON reports whether h declares a JSON content type, which is assumed
+// when no content type is declared at all.
+func isJSON(h http.Header) bool {
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		return true
+	}
+
+	mt, _, _ := mime.ParseMediaType(ct)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// VerifyContract returns a Lifecycle which verifies that h honors every
+// interaction of the Pact contract file at path, running each interaction
+// as a variant through ContractVariants.
+//
+// The provider state of an interaction is its given phase: states maps each
+// state name to a function which puts the provider into that state. An
+// interaction whose state has no entry in states fails.
+//
+// The basis test case of the Lifecycle is always skipped since it
+// corresponds to no interaction.
+func VerifyContract(h http.Handler, path string, states map[string]func(*testing.T)) tbdd.Lifecycle[Interaction, Response] {
+	if h == nil {
+		panic("tbddhttp.VerifyContract: handler must be non-nil")
+	}
+
+	return tbdd.Lifecycle[Interaction, Response]{
+		When: "the consumer request is sent",
+		Then: "the response honors the contract",
+		Arrange: func(_ *testing.T, a tbdd.Arrange[Interaction, Response]) (string, func(*testing.T)) {
+			in := *a.TC
+			if in.Description == "" {
+				return "a contract", func(t *testing.T) {
+					tbdd.Skip(t, "contract interactions run as variants")
+				}
+			}
+
+			if in.ProviderState == "" {
+				return "no particular provider state", func(*testing.T) {}
+			}
+
+			return in.ProviderState, func(t *testing.T) {
+				t.Helper()
+
+				setup, ok := states[in.ProviderState]
+				if !ok {
+					t.Fatalf("no setup registered for provider state %q", in.ProviderState)
+				}
+
+				setup(t)
+			}
+		},
+		Act: Handler(h, func(in Interaction) Request {
+			return in.Request
+		}),
+		Assert: func(t *testing.T, a tbdd.Assert[Interaction, Response]) {
+			t.Helper()
+
+			expectInteraction(t, a.Result, a.TC.Response)
+		},
+		Variants2: ContractVariants(path),
+	}
+}
+
+// ExpectInteraction returns a then function which fails the test unless the
+// response honors the test case's interaction; see ExpectedResponse.
+func ExpectInteraction() func(*testing.T, Interaction, Response) {
+	return func(t *testing.T, in Interaction, r Response) {
+		t.Helper()
+
+		expectInteraction(t, r, in.Response)
+	}
+}
+
+func expectInteraction(t assertT, r Response, want ExpectedResponse) {
+	t.Helper()
+
+	if want.StatusCode != 0 {
+		expectStatus(t, r, want.StatusCode)
+	}
+
+	for k := range want.Header {
+		expectHeader(t, r, k, want.Header.Get(k))
+	}
+
+	if want.Body == nil {
+		return
+	}
+
+	var w, g any
+	if json.Unmarshal(want.Body, &w) != nil {
+		if !bytes.Equal(r.Body, want.Body) {
+			t.Fatalf("body: expected %q but got %q", want.Body, r.Body)
+		}
+		return
+	}
+
+	if err := json.Unmarshal(r.Body, &g); err != nil {
+		t.Fatalf("body: failed to decode JSON: %v; body: %s", err, r.Body)
+		return
+	}
+
+	if path, err := jsonContains("$", w, g); err != nil {
+		t.Fatalf("body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s", path, err, jsonIndent(w), jsonIndent(g))
+	}
+}
+
+// jsonContains checks that got holds every value of want, allowing got
+// objects to hold additional members. It returns the location and nature
+// of the first mismatch, visiting object members in sorted order so the
+// same documents always report the same one.
+func jsonContains(path string, want, got any) (string, error) {
+	switch want := want.(type) {
+	case map[string]any:
+		obj, ok := got.(map[string]any)
+		if !ok {
+			return path, fmt.Errorf("expected an object but got %s", jsonText(got))
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(want)) {
+			wv := want[k]
+			p := path + "." + k
+			gv, ok := obj[k]
+			if !ok {
+				return p, errors.New("missing member")
+			}
+
+			if p, err := jsonContains(p, wv, gv); err != nil {
+				return p, err
+			}
+		}
+
+		return "", nil
+	case []any:
+		arr, ok := got.([]any)
+		if !ok {
+			return path, fmt.Errorf("expected an array but got %s", jsonText(got))
+		}
+
+		if len(want) != len(arr) {
+			return path, fmt.Errorf("expected %d elements but got %d", len(want), len(arr))
+		}
+
+		for i := range want {
+			if p, err := jsonContains(path+"["+strconv.Itoa(i)+"]", want[i], arr[i]); err != nil {
+				return p, err
+			}
+		}
+
+		return "", nil
+	}
+
+	if want != got {
+		return path, fmt.Errorf("expected %s but got %s", jsonText(want), jsonText(got))
+	}
+
+	return "", nil
+}
+
+func jsonText(v any) string {
+	// values decoded from JSON always encode
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func jsonIndent(v any) string {
+	// values decoded from JSON always encode
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return string(b)
+}
@@ -0,0 +1,242 @@
+package tbddhttp
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+const contract = `{
+	"consumer": {"name": "web"},
+	"provider": {"name": "orders"},
+	"interactions": [
+		{
+			"description": "a new order",
+			"providerStates": [{"name": "the store is open"}, {"name": "apples are stocked"}],
+			"request": {
+				"method": "POST",
+				"path": "/orders",
+				"headers": {"Content-Type": "application/json"},
+				"body": {"item": "apple"}
+			},
+			"response": {
+				"status": 201,
+				"headers": {"Content-Type": "application/json"},
+				"body": {"item": "apple"}
+			}
+		},
+		{
+			"description": "a health check",
+			"request": {"method": "GET", "path": "/health", "query": {"verbose": ["1"]}},
+			"response": {"status": 200, "headers": {"Content-Type": "text/plain; charset=utf-8"}, "body": "ok"}
+		}
+	]
+}`
+
+func writeContract(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "contract.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestVerifyContract(t *testing.T) {
+	path := writeContract(t, contract)
+
+	// only the results of this run count, as -count runs the test again
+	n := len(tbdd.Results())
+
+	var prepared []string
+	f := VerifyContract(ordersHandler(), path, map[string]func(*testing.T){
+		"the store is open and apples are stocked": func(t *testing.T) {
+			prepared = append(prepared, t.Name())
+		},
+	}).New(t)
+	f(t)
+
+	if exp := []string{t.Name() + "/a_new_order/given_the_store_is_open_and_apples_are_stocked"}; !reflect.DeepEqual(prepared, exp) {
+		t.Errorf("expected provider states %v to be prepared but got %v", exp, prepared)
+	}
+
+	var kinds []string
+	for _, r := range tbdd.Results()[n:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			kinds = append(kinds, r.Kind+"="+r.Status.String())
+		}
+	}
+
+	if exp := "=skipped|a new order=passed|a health check=passed"; strings.Join(kinds, "|") != exp {
+		t.Errorf("expected results '%s' but got '%s'", exp, strings.Join(kinds, "|"))
+	}
+
+	defer func() {
+		if r := recover(); r != "tbddhttp.VerifyContract: handler must be non-nil" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	VerifyContract(nil, path, nil)
+}
+
+func TestExpectInteraction(t *testing.T) {
+	t.Parallel()
+
+	is, err := LoadContract(writeContract(t, contract))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, in := range is {
+		ExpectInteraction()(t, in, Serve(ordersHandler(), in.Request))
+	}
+}
+
+func TestLoadContract(t *testing.T) {
+	t.Parallel()
+
+	is, err := LoadContract(writeContract(t, `{"interactions": [
+		{
+			"description": "a search",
+			"providerState": "orders exist",
+			"request": {"query": "q=apple"},
+			"response": {"status": 200, "headers": {"Content-Type": "text/plain"}, "body": null}
+		},
+		{
+			"description": "a text post",
+			"request": {"method": "POST", "path": "/notes", "headers": {"Content-Type": "text/plain"}, "body": "hello"},
+			"response": {"status": 204}
+		},
+		{
+			"description": "a JSON post",
+			"request": {"method": "POST", "body": {"a": [1, 2]}},
+			"response": {"status": 201, "body": "created"}
+		}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Interaction{
+		{
+			Description:   "a search",
+			ProviderState: "orders exist",
+			Request:       Request{Path: "/?q=apple"},
+			Response:      ExpectedResponse{StatusCode: 200, Header: http.Header{"Content-Type": {"text/plain"}}},
+		},
+		{
+			Description: "a text post",
+			Request:     Request{Method: "POST", Path: "/notes", Header: http.Header{"Content-Type": {"text/plain"}}, Body: []byte("hello")},
+			Response:    ExpectedResponse{StatusCode: 204},
+		},
+		{
+			Description: "a JSON post",
+			Request:     Request{Method: "POST", Path: "/", Body: []byte(`{"a":[1,2]}`)},
+			Response:    ExpectedResponse{StatusCode: 201, Body: []byte(`"created"`)},
+		},
+	}
+
+	if !reflect.DeepEqual(is, exp) {
+		t.Errorf("expected %+v but got %+v", exp, is)
+	}
+}
+
+func TestLoadContract_errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, v := range []struct {
+		content string
+		exp     string
+	}{
+		{"{", "tbddhttp: invalid contract %s: unexpected end of JSON input"},
+		{`{"interactions": [{"request": {}}]}`, "tbddhttp: invalid interaction 0 of contract %s: description must be non-empty"},
+		{`{"interactions": [{"description": "x", "request": {"query": 1}}]}`, "tbddhttp: invalid interaction 0 of contract %s: query must be a string or an object of string arrays"},
+	} {
+		path := writeContract(t, v.content)
+
+		if _, err := LoadContract(path); err == nil || err.Error() != strings.Replace(v.exp, "%s", path, 1) {
+			t.Errorf("expected error '%s' but got '%v'", v.exp, err)
+		}
+	}
+
+	if _, err := LoadContract(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error but got '%v'", err)
+	}
+
+	// iteration stops when the consumer stops
+	for range ContractVariants(writeContract(t, contract))(t, Interaction{}) {
+		break
+	}
+}
+
+func TestExpectInteraction_failures(t *testing.T) {
+	t.Parallel()
+
+	r := Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"id":1,"items":[{"sku":"a"}],"note":"x"}`),
+	}
+
+	for _, v := range []struct {
+		want ExpectedResponse
+		r    Response
+		exp  string
+	}{
+		{ExpectedResponse{StatusCode: http.StatusCreated}, r, "status: expected %d but got %d; body: %s"},
+		{ExpectedResponse{Header: http.Header{"Content-Type": {"text/plain"}}}, r, "header %s: expected %q but got %q"},
+		{ExpectedResponse{Body: []byte("ok")}, r, "body: expected %q but got %q"},
+		{ExpectedResponse{Body: []byte(`{}`)}, Response{Body: []byte("ok")}, "body: failed to decode JSON: %v; body: %s"},
+		{ExpectedResponse{Body: []byte(`{"id":2}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+		{ExpectedResponse{Body: []byte(`{"missing":true}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+		{ExpectedResponse{Body: []byte(`{"note":{}}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+		{ExpectedResponse{Body: []byte(`{"note":[]}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+		{ExpectedResponse{Body: []byte(`{"items":[]}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+		{ExpectedResponse{Body: []byte(`{"items":[{"sku":"b"}]}`)}, r, "body: mismatch at %s: %v\nexpected:\n%s\nactual:\n%s"},
+	} {
+		mt := &mT{}
+		expectInteraction(mt, v.r, v.want)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
+
+func TestJSONContains(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		want, got any
+		path, err string
+	}{
+		{map[string]any{"a": []any{1.0}}, map[string]any{"a": []any{1.0}, "b": true}, "", ""},
+		{map[string]any{"a": map[string]any{"b": "c"}}, map[string]any{"a": map[string]any{"b": "d"}}, "$.a.b", `expected "c" but got "d"`},
+		{[]any{1.0}, []any{1.0, 2.0}, "$", "expected 1 elements but got 2"},
+		{[]any{}, "x", "$", `expected an array but got "x"`},
+		{map[string]any{}, nil, "$", "expected an object but got null"},
+		// the first mismatch in member order is reported
+		{map[string]any{"d": 1.0, "b": 1.0, "c": 1.0, "a": 1.0}, map[string]any{}, "$.a", "missing member"},
+	} {
+		path, err := jsonContains("$", v.want, v.got)
+
+		var msg string
+		if err != nil {
+			msg = err.Error()
+		}
+
+		if path != v.path || msg != v.err {
+			t.Errorf("expected mismatch '%s' '%s' but got '%s' '%s'", v.path, v.err, path, msg)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package tbddhttp provides tbdd Act adapters and assertion helpers for
+// behaviors exercised through net/http handlers.
+//
+// Like tbdd itself, this package is intended exclusively for use in
+// *_test.go files.
+package tbddhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Request declaratively describes an HTTP request to send to a handler.
+type Request struct {
+	// Method defaults to GET when empty.
+	Method string
+	// Path is the request target, including any query string.
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Response is the structured result of sending a Request.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler returns an Act function which sends the request described by req
+// for a test case to h and records the response.
+func Handler[T any](h http.Handler, req func(T) Request) func(*testing.T, T) Response {
+	if h == nil {
+		panic("tbddhttp.Handler: handler must be non-nil")
+	}
+
+	if req == nil {
+		panic("tbddhttp.Handler: request function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) Response {
+		t.Helper()
+
+		return Serve(h, req(tc))
+	}
+}
+
+// Serve sends r to h using an in-memory recorder and returns the response.
+func Serve(h http.Handler, r Request) Response {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req := httptest.NewRequest(method, r.Path, bytes.NewReader(r.Body))
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+
+	return Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       rec.Body.Bytes(),
+	}
+}
+
+// DecodeJSON decodes the JSON body of r into a new value of type B.
+func DecodeJSON[B any](r Response) (B, error) {
+	var v B
+	err := json.Unmarshal(r.Body, &v)
+	return v, err
+}
+
+// ExpectStatus returns a then function which fails the test unless the
+// response has the expected status code.
+func ExpectStatus[T any](code int) func(*testing.T, T, Response) {
+	return func(t *testing.T, _ T, r Response) {
+		t.Helper()
+
+		expectStatus(t, r, code)
+	}
+}
+
+// ExpectHeader returns a then function which fails the test unless the
+// response header key has the expected value.
+func ExpectHeader[T any](key, value string) func(*testing.T, T, Response) {
+	return func(t *testing.T, _ T, r Response) {
+		t.Helper()
+
+		expectHeader(t, r, key, value)
+	}
+}
+
+// ExpectJSONBody returns a then function which decodes the response body
+// into a value of type B and fails the test unless it deeply equals want.
+func ExpectJSONBody[T, B any](want B) func(*testing.T, T, Response) {
+	return func(t *testing.T, _ T, r Response) {
+		t.Helper()
+
+		expectJSONBody(t, r, want)
+	}
+}
+
+// assertT is the subset of *testing.T that assertion helpers depend on.
+type assertT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+func expectStatus(t assertT, r Response, code int) {
+	t.Helper()
+
+	if r.StatusCode != code {
+		t.Fatalf("status: expected %d but got %d; body: %s", code, r.StatusCode, r.Body)
+	}
+}
+
+func expectHeader(t assertT, r Response, key, value string) {
+	t.Helper()
+
+	if v := r.Header.Get(key); v != value {
+		t.Fatalf("header %s: expected %q but got %q", key, value, v)
+	}
+}
+
+func expectJSONBody[B any](t assertT, r Response, want B) {
+	t.Helper()
+
+	got, err := DecodeJSON[B](r)
+	if err != nil {
+		t.Fatalf("body: failed to decode JSON: %v; body: %s", err, r.Body)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("body: expected %+v but got %+v", want, got)
+	}
+}
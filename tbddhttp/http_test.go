@@ -0,0 +1,132 @@
+package tbddhttp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+var _ assertT = (*testing.T)(nil)
+
+type mT struct {
+	fatalfCalls []string
+}
+
+func (t *mT) Helper() {
+}
+
+func (t *mT) Fatalf(format string, args ...any) {
+	t.fatalfCalls = append(t.fatalfCalls, format)
+}
+
+type order struct {
+	ID   int    `json:"id"`
+	Item string `json:"item"`
+}
+
+func ordersHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /orders", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		var o order
+		if err := json.Unmarshal(b, &o); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		o.ID = 1
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(o)
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func TestHandler(t *testing.T) {
+	type TC struct {
+		item string
+	}
+
+	b := tbdd.WT(
+		TC{item: "apple"},
+		"an order is posted", Handler(ordersHandler(), func(tc TC) Request {
+			return Request{
+				Method: http.MethodPost,
+				Path:   "/orders",
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   []byte(`{"item":"` + tc.item + `"}`),
+			}
+		}),
+		"the order is created", tbdd.JoinThens(
+			ExpectStatus[TC](http.StatusCreated),
+			ExpectHeader[TC]("Content-Type", "application/json"),
+			ExpectJSONBody[TC](order{ID: 1, Item: "apple"}),
+		),
+	)
+
+	f := b.New(t)
+	f(t)
+
+	if r := Serve(ordersHandler(), Request{Path: "/health"}); r.StatusCode != http.StatusOK || string(r.Body) != "ok" {
+		t.Errorf("expected GET to be the default method but got status %d", r.StatusCode)
+	}
+
+	//
+	// validate panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { Handler[TC](nil, func(TC) Request { return Request{} }) }, "tbddhttp.Handler: handler must be non-nil"},
+		{func() { Handler[TC](ordersHandler(), nil) }, "tbddhttp.Handler: request function must be non-nil"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+func TestExpect_failures(t *testing.T) {
+	t.Parallel()
+
+	r := Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte(`{"id":2}`),
+	}
+
+	for _, v := range []struct {
+		f   func(assertT)
+		exp string
+	}{
+		{func(t assertT) { expectStatus(t, r, http.StatusOK) }, "status: expected %d but got %d; body: %s"},
+		{func(t assertT) { expectHeader(t, r, "Content-Type", "application/json") }, "header %s: expected %q but got %q"},
+		{func(t assertT) { expectJSONBody(t, r, order{ID: 1}) }, "body: expected %+v but got %+v"},
+		{func(t assertT) { expectJSONBody(t, Response{Body: []byte("{")}, order{}) }, "body: failed to decode JSON: %v; body: %s"},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package tbddhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+// ResponseMatcher checks one aspect of a Response, failing the test via
+// t.Fatalf when it does not match. Matchers are composed into a then
+// function by ExpectResponse.
+type ResponseMatcher func(*testing.T, Response)
+
+// ExpectResponse returns a then function which applies every matcher to the
+// response in order, stopping at the first which fails:
+//
+//	tbddhttp.ExpectResponse[TC](
+//		tbddhttp.Status(http.StatusCreated),
+//		tbddhttp.HeaderMatches("Location", `^/orders/\d+$`),
+//		tbddhttp.BodyJSONPath("$.item", "apple"),
+//	)
+//
+// ExpectResponse panics if a matcher is nil.
+func ExpectResponse[T any](matchers ...ResponseMatcher) func(*testing.T, T, Response) {
+	for _, m := range matchers {
+		if m == nil {
+			panic("tbddhttp.ExpectResponse: matchers must be non-nil")
+		}
+	}
+
+	return func(t *testing.T, _ T, r Response) {
+		t.Helper()
+
+		for _, m := range matchers {
+			m(t, r)
+		}
+	}
+}
+
+// Status matches responses with the status code.
+func Status(code int) ResponseMatcher {
+	return func(t *testing.T, r Response) {
+		t.Helper()
+
+		expectStatus(t, r, code)
+	}
+}
+
+// HeaderMatches matches responses whose header key has a value matching the
+// regular expression pattern.
+//
+// HeaderMatches panics if pattern is not a valid regular expression.
+func HeaderMatches(key, pattern string) ResponseMatcher {
+	re := regexp.MustCompile(pattern)
+
+	return func(t *testing.T, r Response) {
+		t.Helper()
+
+		expectHeaderMatches(t, r, key, re)
+	}
+}
+
+// BodyJSONEq matches responses whose body is a JSON document equivalent to
+// want, as described by tbdd.JSONEq.
+func BodyJSONEq[J tbdd.JSONText](want J) ResponseMatcher {
+	return func(t *testing.T, r Response) {
+		t.Helper()
+
+		tbdd.JSONEq(t, []byte(want), r.Body)
+	}
+}
+
+// BodyJSONPath matches responses whose JSON body holds a value at path, as
+// described by tbdd.JSONPath, which is equivalent to want once want is
+// encoded as JSON.
+func BodyJSONPath(path string, want any) ResponseMatcher {
+	return func(t *testing.T, r Response) {
+		t.Helper()
+
+		expectJSONValue(t, path, tbdd.JSONPath(t, r.Body, path), want)
+	}
+}
+
+func expectHeaderMatches(t assertT, r Response, key string, re *regexp.Regexp) {
+	t.Helper()
+
+	if v := r.Header.Get(key); !re.MatchString(v) {
+		t.Fatalf("header %s: expected a value matching %s but got %q", key, re, v)
+	}
+}
+
+// expectJSONValue fails the test unless the decoded JSON value got at path
+// equals want once it is encoded as JSON and decoded again.
+func expectJSONValue(t assertT, path string, got, want any) {
+	t.Helper()
+
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("JSON path %s: failed to encode expected value: %v", path, err)
+		return
+	}
+
+	// decoded like tbdd.JSONPath decodes got
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var w any
+	_ = d.Decode(&w)
+
+	if !jsonEqual(got, w) {
+		t.Fatalf("JSON path %s: expected %s but got %s", path, jsonText(w), jsonText(got))
+	}
+}
+
+// jsonEqual reports whether the decoded JSON values a and b are equal,
+// comparing numbers by their exact values.
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for k, av := range a {
+			if bv, ok := b[k]; !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, jsonEqual)
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		x, xok := new(big.Rat).SetString(a.String())
+		y, yok := new(big.Rat).SetString(b.String())
+		return xok && yok && x.Cmp(y) == 0
+	}
+
+	// the remaining decoded values are strings, booleans, and nil
+	return a == b
+}
@@ -0,0 +1,69 @@
+package tbddhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+func TestExpectResponse(t *testing.T) {
+	type TC struct{}
+
+	tbdd.WT(
+		TC{},
+		"an order is posted", Send(ordersHandler(), func(TC) Builder {
+			return Req().POST("/orders").JSON(order{Item: "apple"})
+		}),
+		"the order is created", ExpectResponse[TC](
+			Status(http.StatusCreated),
+			HeaderMatches("Content-Type", "^application/json"),
+			BodyJSONEq(`{"item": "apple", "id": 1}`),
+			BodyJSONPath("$.id", 1),
+			BodyJSONPath("$", order{ID: 1, Item: "apple"}),
+		),
+	).New(t)(t)
+
+	defer func() {
+		if r := recover(); r != "tbddhttp.ExpectResponse: matchers must be non-nil" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	ExpectResponse[TC](Status(http.StatusOK), nil)
+}
+
+func TestResponseMatcher_failures(t *testing.T) {
+	t.Parallel()
+
+	r := Response{Header: http.Header{"Location": {"/users/1"}}}
+
+	for _, v := range []struct {
+		f   func(assertT)
+		exp string
+	}{
+		{func(t assertT) { expectHeaderMatches(t, r, "Location", regexp.MustCompile(`^/orders/`)) }, "header %s: expected a value matching %s but got %q"},
+		{func(t assertT) { expectJSONValue(t, "$.id", json.Number("2"), 1) }, "JSON path %s: expected %s but got %s"},
+		{func(t assertT) { expectJSONValue(t, "$.id", json.Number("9007199254740993"), 9007199254740992) }, "JSON path %s: expected %s but got %s"},
+		{func(t assertT) { expectJSONValue(t, "$.id", json.Number("2"), make(chan int)) }, "JSON path %s: failed to encode expected value: %v"},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+
+	mt := &mT{}
+	expectHeaderMatches(mt, r, "Location", regexp.MustCompile(`^/users/\d+$`))
+	expectJSONValue(mt, "$.id", json.Number("1.0"), 1)
+	expectJSONValue(mt, "$.id", json.Number("9007199254740993"), 9007199254740993)
+	expectJSONValue(mt, "$", map[string]any{"ids": []any{json.Number("1e2"), "x", true, nil}}, map[string]any{"ids": []any{100, "x", true, nil}})
+
+	if len(mt.fatalfCalls) != 0 {
+		t.Errorf("expected matching values to pass but got %v", mt.fatalfCalls)
+	}
+}
@@ -0,0 +1,541 @@
+package tbddhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// OpenAPI is an OpenAPI 3 document which responses can be validated against.
+//
+// Only the parts of the document which describe responses are used:
+// operations are found by matching the request path against the path
+// templates of the document, then the documented response for the status
+// code is checked. Server URLs are not considered, so request paths must be
+// relative to the document paths.
+//
+// Schemas support $ref to locations within the same document, allOf, anyOf,
+// oneOf, enum, const, nullable, and the type, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum, and exclusiveMaximum
+// keywords. Other keywords, such as format, are ignored.
+type OpenAPI struct {
+	doc   map[string]any
+	paths map[string]any
+}
+
+// LoadOpenAPI reads the JSON encoded OpenAPI document at path.
+func LoadOpenAPI(path string) (*OpenAPI, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := ParseOpenAPI(b)
+	if err != nil {
+		return nil, errors.New("tbddhttp: invalid OpenAPI document " + path + ": " + err.Error())
+	}
+
+	return o, nil
+}
+
+// ParseOpenAPI parses a JSON encoded OpenAPI document.
+func ParseOpenAPI(b []byte) (*OpenAPI, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	paths, ok := doc["paths"].(map[string]any)
+	if !ok {
+		return nil, errors.New("document has no paths object")
+	}
+
+	return &OpenAPI{doc, paths}, nil
+}
+
+// Validate reports every way in which r deviates from the response the
+// document declares for req.
+func (o *OpenAPI) Validate(req Request, r Response) error {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	path, _, _ := strings.Cut(req.Path, "?")
+
+	tmpl, item := o.pathItem(path)
+	if item == nil {
+		return errors.New("path " + path + " is not documented")
+	}
+
+	op, ok := item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return errors.New("operation " + method + " " + tmpl + " is not documented")
+	}
+
+	ref := responseFor(op, r.StatusCode)
+	if ref == nil {
+		return fmt.Errorf("status %d of %s %s is not documented", r.StatusCode, method, tmpl)
+	}
+
+	resp := o.resolve(ref)
+
+	v := &schemaValidator{o: o}
+	v.headers(resp, r.Header)
+	v.body(resp, r)
+
+	return errors.Join(v.errs...)
+}
+
+// ExpectOpenAPI returns a then function which fails the test unless the
+// response conforms to the document's declaration of the operation req
+// returns for the test case; see OpenAPI.Validate.
+func ExpectOpenAPI[T any](o *OpenAPI, req func(T) Request) func(*testing.T, T, Response) {
+	if o == nil {
+		panic("tbddhttp.ExpectOpenAPI: document must be non-nil")
+	}
+
+	if req == nil {
+		panic("tbddhttp.ExpectOpenAPI: request function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T, r Response) {
+		t.Helper()
+
+		expectOpenAPI(t, o, req(tc), r)
+	}
+}
+
+func expectOpenAPI(t assertT, o *OpenAPI, req Request, r Response) {
+	t.Helper()
+
+	if err := o.Validate(req, r); err != nil {
+		t.Fatalf("response does not conform to the OpenAPI document: %v; body: %s", err, r.Body)
+	}
+}
+
+// pathItem returns the path item whose template matches path, preferring
+// templates with the most literal segments.
+func (o *OpenAPI) pathItem(path string) (string, map[string]any) {
+	segs := strings.Split(path, "/")
+
+	var best string
+	var bestItem map[string]any
+	bestLiterals := -1
+
+	for tmpl, item := range o.paths {
+		tsegs := strings.Split(tmpl, "/")
+		if len(tsegs) != len(segs) {
+			continue
+		}
+
+		literals := 0
+		matched := true
+		for i, ts := range tsegs {
+			if strings.HasPrefix(ts, "{") && strings.HasSuffix(ts, "}") {
+				if segs[i] == "" {
+					matched = false
+					break
+				}
+				continue
+			}
+
+			if ts != segs[i] {
+				matched = false
+				break
+			}
+			literals++
+		}
+
+		m, ok := item.(map[string]any)
+		if !matched || !ok {
+			continue
+		}
+
+		if literals > bestLiterals || literals == bestLiterals && tmpl < best {
+			best, bestItem, bestLiterals = tmpl, m, literals
+		}
+	}
+
+	return best, o.resolveMap(bestItem)
+}
+
+// responseFor returns the response object documented for status, falling
+// back to its range, such as "2XX", and then to "default".
+func responseFor(op map[string]any, status int) any {
+	responses, _ := op["responses"].(map[string]any)
+
+	code := strconv.Itoa(status)
+	for _, k := range []string{code, code[:1] + "XX", "default"} {
+		if r, ok := responses[k]; ok {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// resolve follows $ref values until reaching an object which is not a
+// reference, returning nil for references which cannot be resolved.
+func (o *OpenAPI) resolve(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return o.resolveMap(m)
+}
+
+func (o *OpenAPI) resolveMap(m map[string]any) map[string]any {
+	for range 64 {
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m
+		}
+
+		m, _ = o.pointer(ref).(map[string]any)
+	}
+
+	return nil
+}
+
+// pointer returns the value at a "#/..." JSON pointer within the document.
+func (o *OpenAPI) pointer(ref string) any {
+	rest, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+
+	var v any = o.doc
+	for _, tok := range strings.Split(rest, "/") {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		v = m[tok]
+	}
+
+	return v
+}
+
+type schemaValidator struct {
+	o    *OpenAPI
+	errs []error
+}
+
+func (v *schemaValidator) errorf(path, format string, args ...any) {
+	v.errs = append(v.errs, errors.New(path+": "+fmt.Sprintf(format, args...)))
+}
+
+func (v *schemaValidator) headers(resp map[string]any, h http.Header) {
+	headers, _ := resp["headers"].(map[string]any)
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		spec := v.o.resolve(headers[name])
+		path := "header " + name
+
+		values, present := h[http.CanonicalHeaderKey(name)]
+		if !present {
+			if required, _ := spec["required"].(bool); required {
+				v.errorf(path, "required header is missing")
+			}
+			continue
+		}
+
+		schema := v.o.resolve(spec["schema"])
+		if schema == nil {
+			continue
+		}
+
+		v.value(path, schema, headerValue(v.o, schema, values[0]))
+	}
+}
+
+// headerValue converts a header value into the JSON type its schema
+// declares so it can be validated like any other value.
+func headerValue(o *OpenAPI, schema map[string]any, s string) any {
+	for _, t := range schemaTypes(o.resolveMap(schema)) {
+		switch t {
+		case "integer", "number":
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+	}
+
+	return s
+}
+
+func (v *schemaValidator) body(resp map[string]any, r Response) {
+	content, ok := resp["content"].(map[string]any)
+	if !ok || len(content) == 0 {
+		if len(r.Body) > 0 {
+			v.errorf("body", "no body is documented but got %d bytes", len(r.Body))
+		}
+		return
+	}
+
+	ct := r.Header.Get("Content-Type")
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		v.errorf("body", "invalid Content-Type %q", ct)
+		return
+	}
+
+	media, ok := content[mt]
+	if !ok {
+		major, _, _ := strings.Cut(mt, "/")
+		if media, ok = content[major+"/*"]; !ok {
+			media, ok = content["*/*"]
+		}
+	}
+	if !ok {
+		v.errorf("body", "Content-Type %s is not documented", mt)
+		return
+	}
+
+	schema := v.o.resolve(v.o.resolve(media)["schema"])
+	if schema == nil || !(mt == "application/json" || strings.HasSuffix(mt, "+json")) {
+		return
+	}
+
+	var body any
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		v.errorf("body", "invalid JSON: %v", err)
+		return
+	}
+
+	v.value("$", schema, body)
+}
+
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var ts []string
+		for _, t := range t {
+			if s, ok := t.(string); ok {
+				ts = append(ts, s)
+			}
+		}
+		return ts
+	}
+
+	return nil
+}
+
+func jsonType(x any) string {
+	switch x := x.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+
+	return "object"
+}
+
+// value validates x against schema, recording every violation.
+func (v *schemaValidator) value(path string, schema map[string]any, x any) {
+	schema = v.o.resolveMap(schema)
+	if schema == nil {
+		return
+	}
+
+	// null satisfies a nullable schema whatever its other keywords demand
+	if x == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			return
+		}
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, s := range all {
+			v.value(path, v.o.resolve(s), x)
+		}
+	}
+
+	for _, k := range []string{"anyOf", "oneOf"} {
+		alts, ok := schema[k].([]any)
+		if !ok {
+			continue
+		}
+
+		matches := 0
+		for _, s := range alts {
+			if v.matches(v.o.resolve(s), x) {
+				matches++
+			}
+		}
+
+		switch {
+		case matches == 0:
+			v.errorf(path, "value matches none of the %s schemas: %s", k, jsonText(x))
+		case k == "oneOf" && matches > 1:
+			v.errorf(path, "value matches %d of the oneOf schemas but must match exactly one: %s", matches, jsonText(x))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return reflect.DeepEqual(e, x) }) {
+		v.errorf(path, "value %s is not one of %s", jsonText(x), jsonText(enum))
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, x) {
+		v.errorf(path, "expected %s but got %s", jsonText(c), jsonText(x))
+	}
+
+	if ts := schemaTypes(schema); len(ts) > 0 {
+		got := jsonType(x)
+		if !slices.Contains(ts, got) && !(got == "integer" && slices.Contains(ts, "number")) {
+			v.errorf(path, "expected %s but got %s", strings.Join(ts, " or "), jsonText(x))
+			return
+		}
+	}
+
+	switch x := x.(type) {
+	case map[string]any:
+		v.object(path, schema, x)
+	case []any:
+		v.array(path, schema, x)
+	case string:
+		v.string(path, schema, x)
+	case float64:
+		v.number(path, schema, x)
+	}
+}
+
+// matches reports whether x is valid against schema without recording
+// any violations.
+func (v *schemaValidator) matches(schema map[string]any, x any) bool {
+	sub := &schemaValidator{o: v.o}
+	sub.value("", schema, x)
+	return len(sub.errs) == 0
+}
+
+func (v *schemaValidator) object(path string, schema map[string]any, x map[string]any) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				if _, ok := x[s]; !ok {
+					v.errorf(path, "required property %q is missing", s)
+				}
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+
+	names := make([]string, 0, len(x))
+	for name := range x {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		p := path + "." + name
+
+		if ps, ok := props[name]; ok {
+			v.value(p, v.o.resolve(ps), x[name])
+			continue
+		}
+
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				v.errorf(p, "property is not allowed")
+			}
+		case map[string]any:
+			v.value(p, ap, x[name])
+		}
+	}
+}
+
+func (v *schemaValidator) array(path string, schema map[string]any, x []any) {
+	if n, ok := schema["minItems"].(float64); ok && float64(len(x)) < n {
+		v.errorf(path, "expected at least %v items but got %d", n, len(x))
+	}
+
+	if n, ok := schema["maxItems"].(float64); ok && float64(len(x)) > n {
+		v.errorf(path, "expected at most %v items but got %d", n, len(x))
+	}
+
+	if items := v.o.resolve(schema["items"]); items != nil {
+		for i, e := range x {
+			v.value(path+"["+strconv.Itoa(i)+"]", items, e)
+		}
+	}
+}
+
+func (v *schemaValidator) string(path string, schema map[string]any, x string) {
+	n := utf8.RuneCountInString(x)
+
+	if lo, ok := schema["minLength"].(float64); ok && float64(n) < lo {
+		v.errorf(path, "expected at least %v characters but got %q", lo, x)
+	}
+
+	if hi, ok := schema["maxLength"].(float64); ok && float64(n) > hi {
+		v.errorf(path, "expected at most %v characters but got %q", hi, x)
+	}
+
+	if p, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			v.errorf(path, "invalid pattern %q in document: %v", p, err)
+		} else if !re.MatchString(x) {
+			v.errorf(path, "value %q does not match pattern %q", x, p)
+		}
+	}
+}
+
+func (v *schemaValidator) number(path string, schema map[string]any, x float64) {
+	// OpenAPI 3.0 uses boolean exclusive flags while 3.1 uses numeric bounds
+	exclusiveMin, _ := schema["exclusiveMinimum"].(bool)
+	exclusiveMax, _ := schema["exclusiveMaximum"].(bool)
+
+	if lo, ok := schema["minimum"].(float64); ok && (x < lo || exclusiveMin && x == lo) {
+		v.errorf(path, "value %v is below the minimum of %v", x, lo)
+	}
+
+	if lo, ok := schema["exclusiveMinimum"].(float64); ok && x <= lo {
+		v.errorf(path, "value %v is not above the exclusive minimum of %v", x, lo)
+	}
+
+	if hi, ok := schema["maximum"].(float64); ok && (x > hi || exclusiveMax && x == hi) {
+		v.errorf(path, "value %v is above the maximum of %v", x, hi)
+	}
+
+	if hi, ok := schema["exclusiveMaximum"].(float64); ok && x >= hi {
+		v.errorf(path, "value %v is not below the exclusive maximum of %v", x, hi)
+	}
+}
@@ -0,0 +1,258 @@
+package tbddhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+const openAPIDoc = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/orders": {
+			"post": {
+				"responses": {
+					"201": {"$ref": "#/components/responses/Order"},
+					"4XX": {"description": "rejected"}
+				}
+			}
+		},
+		"/orders/{id}": {
+			"get": {"responses": {"200": {"$ref": "#/components/responses/Order"}}}
+		},
+		"/orders/latest": {
+			"get": {"responses": {"default": {"description": "latest", "content": {"text/*": {}}}}}
+		},
+		"/health": {
+			"get": {"responses": {"200": {"description": "ok", "content": {"*/*": {"schema": {"type": "string"}}}}}}
+		},
+		"/broken": {"get": "not an operation object"}
+	},
+	"components": {
+		"responses": {
+			"Order": {
+				"description": "an order",
+				"headers": {
+					"Content-Type": {"required": true, "schema": {"type": "string"}},
+					"X-Rate-Limit": {"schema": {"type": "integer", "maximum": 100}},
+					"X-Cached": {"schema": {"type": "boolean", "enum": [false]}},
+					"X-Trace": {}
+				},
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Order"}}}
+			}
+		},
+		"schemas": {
+			"Order": {
+				"type": "object",
+				"required": ["id", "item"],
+				"additionalProperties": false,
+				"properties": {
+					"id": {"type": "integer", "minimum": 1},
+					"item": {"type": "string", "minLength": 1}
+				}
+			},
+			"a/b~c": {"type": "string"}
+		}
+	}
+}`
+
+func TestExpectOpenAPI(t *testing.T) {
+	o, err := ParseOpenAPI([]byte(openAPIDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(string) Request {
+		return Request{
+			Method: http.MethodPost,
+			Path:   "/orders?source=test",
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   []byte(`{"item":"apple"}`),
+		}
+	}
+
+	tbdd.WT(
+		"apple",
+		"an order is posted", Handler(ordersHandler(), post),
+		"the response conforms to the API document", ExpectOpenAPI(o, post),
+	).New(t)(t)
+
+	health := Request{Path: "/health"}
+	if err := o.Validate(health, Serve(ordersHandler(), health)); err != nil {
+		t.Errorf("expected the health response to conform but got '%v'", err)
+	}
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { ExpectOpenAPI[string](nil, post) }, "tbddhttp.ExpectOpenAPI: document must be non-nil"},
+		{func() { ExpectOpenAPI[string](o, nil) }, "tbddhttp.ExpectOpenAPI: request function must be non-nil"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != v.exp {
+					t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+				}
+			}()
+
+			v.f()
+		}()
+	}
+
+	mt := &mT{}
+	expectOpenAPI(mt, o, Request{Path: "/missing"}, Response{})
+
+	if exp := "response does not conform to the OpenAPI document: %v; body: %s"; len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != exp {
+		t.Errorf("expected one fatalf call with format '%s' but got %v", exp, mt.fatalfCalls)
+	}
+}
+
+func TestOpenAPI_Validate(t *testing.T) {
+	t.Parallel()
+
+	o, err := ParseOpenAPI([]byte(openAPIDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	for _, v := range []struct {
+		req Request
+		r   Response
+		exp string
+	}{
+		{Request{Path: "/orders/7"}, Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}, "X-Rate-Limit": {"5"}, "X-Cached": {"false"}, "X-Trace": {"t"}}, Body: []byte(`{"id":7,"item":"pear"}`)}, ""},
+		{Request{Method: "POST", Path: "/orders"}, Response{StatusCode: 422}, ""},
+		{Request{Path: "/orders/latest"}, Response{StatusCode: 500, Header: http.Header{"Content-Type": {"text/csv"}}, Body: []byte("a,b")}, ""},
+		{Request{Path: "/missing"}, Response{}, "path /missing is not documented"},
+		{Request{Path: "/orders/"}, Response{}, "path /orders/ is not documented"},
+		{Request{Method: "DELETE", Path: "/orders"}, Response{}, "operation DELETE /orders is not documented"},
+		{Request{Path: "/broken"}, Response{}, "operation GET /broken is not documented"},
+		{Request{Path: "/orders/7"}, Response{StatusCode: 404}, "status 404 of GET /orders/{id} is not documented"},
+		{
+			Request{Path: "/orders/7"},
+			Response{StatusCode: 200, Header: http.Header{"X-Rate-Limit": {"500"}, "X-Cached": {"true"}}},
+			"header Content-Type: required header is missing\n" +
+				"header X-Cached: value true is not one of [false]\n" +
+				"header X-Rate-Limit: value 500 is above the maximum of 100\n" +
+				`body: invalid Content-Type ""`,
+		},
+		{Request{Path: "/orders/7"}, Response{StatusCode: 200, Header: http.Header{"Content-Type": {"text/plain"}}}, "body: Content-Type text/plain is not documented"},
+		{Request{Path: "/orders/7"}, Response{StatusCode: 200, Header: jsonHeader, Body: []byte("{")}, "body: invalid JSON: unexpected end of JSON input"},
+		{
+			Request{Path: "/orders/7"},
+			Response{StatusCode: 200, Header: jsonHeader, Body: []byte(`{"id":0,"item":"","note":"x"}`)},
+			"$.id: value 0 is below the minimum of 1\n" +
+				`$.item: expected at least 1 characters but got ""` + "\n" +
+				"$.note: property is not allowed",
+		},
+		{Request{Method: "POST", Path: "/orders"}, Response{StatusCode: 400, Body: []byte("bad")}, "body: no body is documented but got 3 bytes"},
+	} {
+		err := o.Validate(v.req, v.r)
+
+		var msg string
+		if err != nil {
+			msg = err.Error()
+		}
+
+		if msg != v.exp {
+			t.Errorf("%s %s: expected error '%s' but got '%s'", v.req.Method, v.req.Path, v.exp, msg)
+		}
+	}
+}
+
+func TestOpenAPI_schemas(t *testing.T) {
+	t.Parallel()
+
+	o, err := ParseOpenAPI([]byte(openAPIDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		schema, value string
+		exp           string
+	}{
+		{`{"$ref": "#/components/schemas/a~1b~0c"}`, `"x"`, ""},
+		{`{"$ref": "#/components/schemas/a~1b~0c"}`, `1`, "$: expected string but got 1"},
+		{`{"$ref": "#/components/schemas/missing"}`, `1`, ""},
+		{`{"$ref": "https://example.com/schema"}`, `1`, ""},
+		{`{"$ref": "#/openapi/x"}`, `1`, ""},
+		{`{"$ref": "#/components/schemas/loop", "x": 1}`, `1`, ""},
+		{`{"type": "number"}`, `1`, ""},
+		{`{"type": ["integer", "null"]}`, `null`, ""},
+		{`{"type": "integer"}`, `1.5`, "$: expected integer but got 1.5"},
+		{`{"type": "object", "nullable": true}`, `null`, ""},
+		{`{"nullable": true, "allOf": [{"type": "string"}], "oneOf": [{"type": "string"}]}`, `null`, ""},
+		{`{"nullable": true, "anyOf": [{"type": "string"}]}`, `null`, ""},
+		{`{"anyOf": [{"type": "string"}]}`, `null`, "$: value matches none of the anyOf schemas: null"},
+		{`{"type": "boolean"}`, `{}`, "$: expected boolean but got {}"},
+		{`{"const": "a"}`, `"b"`, `$: expected "a" but got "b"`},
+		{`{"allOf": [{"type": "integer"}, {"minimum": 5}]}`, `3`, "$: value 3 is below the minimum of 5"},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `true`, "$: value matches none of the anyOf schemas: true"},
+		{`{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, `1`, "$: value matches 2 of the oneOf schemas but must match exactly one: 1"},
+		{`{"oneOf": [{"type": "number"}, {"type": "string"}]}`, `1`, ""},
+		{`{"type": "object", "required": ["a", 1], "additionalProperties": {"type": "string"}}`, `{"b": 1}`, "$: required property \"a\" is missing\n$.b: expected string but got 1"},
+		{`{"type": "array", "minItems": 2, "items": {"type": "string"}}`, `[1]`, "$: expected at least 2 items but got 1\n$[0]: expected string but got 1"},
+		{`{"type": "array", "maxItems": 0}`, `[1]`, "$: expected at most 0 items but got 1"},
+		{`{"maxLength": 1, "pattern": "^a"}`, `"bb"`, "$: expected at most 1 characters but got \"bb\"\n$: value \"bb\" does not match pattern \"^a\""},
+		{`{"pattern": "("}`, `"a"`, "$: invalid pattern \"(\" in document: error parsing regexp: missing closing ): `(`"},
+		{`{"minimum": 1, "exclusiveMinimum": true, "maximum": 3, "exclusiveMaximum": true}`, `1`, "$: value 1 is below the minimum of 1"},
+		{`{"minimum": 1, "exclusiveMinimum": true, "maximum": 3, "exclusiveMaximum": true}`, `3`, "$: value 3 is above the maximum of 3"},
+		{`{"exclusiveMinimum": 1, "exclusiveMaximum": 3}`, `1`, "$: value 1 is not above the exclusive minimum of 1"},
+		{`{"exclusiveMinimum": 1, "exclusiveMaximum": 3}`, `3`, "$: value 3 is not below the exclusive maximum of 3"},
+	} {
+		var schema map[string]any
+		var value any
+		if err := errors.Join(json.Unmarshal([]byte(v.schema), &schema), json.Unmarshal([]byte(v.value), &value)); err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(v.schema, "loop") {
+			o.doc["components"].(map[string]any)["schemas"].(map[string]any)["loop"] = schema
+		}
+
+		sv := &schemaValidator{o: o}
+		sv.value("$", schema, value)
+
+		if msg := errors.Join(sv.errs...); (msg == nil) != (v.exp == "") || msg != nil && msg.Error() != v.exp {
+			t.Errorf("%s with %s: expected '%s' but got '%v'", v.schema, v.value, v.exp, msg)
+		}
+	}
+}
+
+func TestLoadOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	if err := errors.Join(os.WriteFile(good, []byte(openAPIDoc), 0o644), os.WriteFile(bad, []byte(`{}`), 0o644)); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, err := LoadOpenAPI(good); err != nil || o == nil {
+		t.Errorf("expected the document to load but got '%v'", err)
+	}
+
+	if _, err := LoadOpenAPI(bad); err == nil || err.Error() != "tbddhttp: invalid OpenAPI document "+bad+": document has no paths object" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := LoadOpenAPI(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error but got '%v'", err)
+	}
+
+	if _, err := ParseOpenAPI([]byte("{")); err == nil {
+		t.Error("expected invalid JSON to fail")
+	}
+}
@@ -0,0 +1,224 @@
+package tbddhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Builder declaratively builds a Request so test cases can describe their
+// requests readably within scenario tables:
+//
+//	tbddhttp.Req().POST("/orders").JSON(order).Header("Authorization", "Bearer x")
+//
+// Builders are values; every method returns a modified copy, so a common
+// request can be shared and refined by several test cases.
+type Builder struct {
+	r Request
+	// err is the first error encountered while building, reported when the
+	// request is sent.
+	err error
+}
+
+// Req returns a Builder of a GET request for "/".
+func Req() Builder {
+	return Builder{r: Request{Method: http.MethodGet, Path: "/"}}
+}
+
+// GET returns a copy of b for a GET request of path.
+func (b Builder) GET(path string) Builder {
+	return b.to(http.MethodGet, path)
+}
+
+// POST returns a copy of b for a POST request of path.
+func (b Builder) POST(path string) Builder {
+	return b.to(http.MethodPost, path)
+}
+
+// PUT returns a copy of b for a PUT request of path.
+func (b Builder) PUT(path string) Builder {
+	return b.to(http.MethodPut, path)
+}
+
+// PATCH returns a copy of b for a PATCH request of path.
+func (b Builder) PATCH(path string) Builder {
+	return b.to(http.MethodPatch, path)
+}
+
+// DELETE returns a copy of b for a DELETE request of path.
+func (b Builder) DELETE(path string) Builder {
+	return b.to(http.MethodDelete, path)
+}
+
+func (b Builder) to(method, path string) Builder {
+	b.r.Method, b.r.Path = method, path
+	return b
+}
+
+// Header returns a copy of b with value added to the header key.
+func (b Builder) Header(key, value string) Builder {
+	b.cloneHeader()
+	b.r.Header.Add(key, value)
+	return b
+}
+
+// cloneHeader replaces the header of b with a copy it can modify without
+// affecting the builders it was copied from.
+func (b *Builder) cloneHeader() {
+	b.r.Header = b.r.Header.Clone()
+	if b.r.Header == nil {
+		b.r.Header = http.Header{}
+	}
+}
+
+// Query returns a copy of b with value added to the query parameter key of
+// its path.
+func (b Builder) Query(key, value string) Builder {
+	path, rawQuery, _ := strings.Cut(b.r.Path, "?")
+
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		b.fail(errors.New("invalid query of path " + b.r.Path + ": " + err.Error()))
+		return b
+	}
+
+	q.Add(key, value)
+	b.r.Path = path + "?" + q.Encode()
+	return b
+}
+
+// Body returns a copy of b sending body with the given Content-Type.
+func (b Builder) Body(contentType string, body []byte) Builder {
+	b.cloneHeader()
+	b.r.Header.Set("Content-Type", contentType)
+	b.r.Body = body
+	return b
+}
+
+// JSON returns a copy of b sending v encoded as JSON with the Content-Type
+// application/json.
+func (b Builder) JSON(v any) Builder {
+	body, err := json.Marshal(v)
+	if err != nil {
+		b.fail(errors.New("failed to encode JSON body: " + err.Error()))
+		return b
+	}
+
+	return b.Body("application/json", body)
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Request returns the built Request, or the first error encountered while
+// building it.
+func (b Builder) Request() (Request, error) {
+	if b.err != nil {
+		return Request{}, errors.New("tbddhttp: " + b.err.Error())
+	}
+
+	return b.r, nil
+}
+
+// Send returns an Act function which sends the request built by req for a
+// test case to h, as Handler does. The test is failed via t.Fatalf if the
+// request cannot be built.
+func Send[T any](h http.Handler, req func(T) Builder) func(*testing.T, T) Response {
+	if h == nil {
+		panic("tbddhttp.Send: handler must be non-nil")
+	}
+
+	if req == nil {
+		panic("tbddhttp.Send: request function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) Response {
+		t.Helper()
+
+		return send(t, req(tc), func(r Request) (Response, error) {
+			return Serve(h, r), nil
+		})
+	}
+}
+
+// SendTo returns an Act function which sends the request built by req for a
+// test case to the server at baseURL using c, or http.DefaultClient when c is
+// nil. The test is failed via t.Fatalf if the request cannot be built or
+// sent.
+func SendTo[T any](c *http.Client, baseURL string, req func(T) Builder) func(*testing.T, T) Response {
+	if req == nil {
+		panic("tbddhttp.SendTo: request function must be non-nil")
+	}
+
+	return func(t *testing.T, tc T) Response {
+		t.Helper()
+
+		return send(t, req(tc), func(r Request) (Response, error) {
+			return Do(c, baseURL, r)
+		})
+	}
+}
+
+// send builds the request of b and sends it with do, failing t if either
+// fails.
+func send(t assertT, b Builder, do func(Request) (Response, error)) Response {
+	t.Helper()
+
+	r, err := b.Request()
+	if err == nil {
+		var resp Response
+		if resp, err = do(r); err == nil {
+			return resp
+		}
+	}
+
+	t.Fatalf("%v", err)
+	return Response{}
+}
+
+// Do sends r to the server at baseURL using c, or http.DefaultClient when c
+// is nil, and returns the response once its body has been read.
+func Do(c *http.Client, baseURL string, r Request) (Response, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(baseURL, "/")+r.Path, bytes.NewReader(r.Body))
+	if err != nil {
+		return Response{}, errors.New("tbddhttp: invalid request: " + err.Error())
+	}
+
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return Response{}, errors.New("tbddhttp: request failed: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, errors.New("tbddhttp: failed to read response body: " + err.Error())
+	}
+
+	return Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
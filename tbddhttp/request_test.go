@@ -0,0 +1,142 @@
+package tbddhttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	base := Req().Header("Authorization", "Bearer x")
+	post := base.POST("/orders").JSON(order{Item: "apple"})
+
+	r, err := post.Query("b", "2").Query("a", "1").Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Method != http.MethodPost || r.Path != "/orders?a=1&b=2" || string(r.Body) != `{"id":0,"item":"apple"}` {
+		t.Errorf("unexpected request: %+v", r)
+	}
+
+	if r.Header.Get("Authorization") != "Bearer x" || r.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected header: %v", r.Header)
+	}
+
+	// builders are values which do not affect each other
+	if r, _ := base.Request(); r.Method != http.MethodGet || r.Path != "/" || len(r.Header) != 1 {
+		t.Errorf("expected the base builder to be unchanged but got %+v", r)
+	}
+
+	for method, b := range map[string]Builder{
+		http.MethodGet:    Req().GET("/x"),
+		http.MethodPut:    Req().PUT("/x"),
+		http.MethodPatch:  Req().PATCH("/x"),
+		http.MethodDelete: Req().DELETE("/x"),
+	} {
+		if r, _ := b.Request(); r.Method != method || r.Path != "/x" {
+			t.Errorf("expected a %s request of /x but got %+v", method, r)
+		}
+	}
+
+	//
+	// the first build error is reported
+	//
+
+	for _, v := range []struct {
+		b   Builder
+		exp string
+	}{
+		{Req().JSON(make(chan int)).GET("/%zz?x=%zz").Query("k", "v"), "tbddhttp: failed to encode JSON body: "},
+		{Req().GET("/x?k=%zz").Query("k", "v"), "tbddhttp: invalid query of path /x?k=%zz: "},
+	} {
+		if _, err := v.b.Request(); err == nil || !strings.HasPrefix(err.Error(), v.exp) {
+			t.Errorf("expected an error starting with '%s' but got %v", v.exp, err)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	type TC struct {
+		item string
+	}
+
+	req := func(tc TC) Builder {
+		return Req().POST("/orders").JSON(order{Item: tc.item})
+	}
+	then := tbdd.JoinThens(
+		ExpectStatus[TC](http.StatusCreated),
+		ExpectJSONBody[TC](order{ID: 1, Item: "apple"}),
+	)
+
+	srv := httptest.NewServer(ordersHandler())
+	defer srv.Close()
+
+	tbdd.WT(TC{"apple"}, "an order is posted to a handler", Send(ordersHandler(), req), "the order is created", then).New(t)(t)
+	tbdd.WT(TC{"apple"}, "an order is posted to a server", SendTo(nil, srv.URL+"/", req), "the order is created", then).New(t)(t)
+
+	//
+	// failures to build or send fail the test
+	//
+
+	for _, v := range []struct {
+		b   Builder
+		do  func(Request) (Response, error)
+		exp string
+	}{
+		{Req().JSON(make(chan int)), nil, "tbddhttp: failed to encode JSON body: "},
+		{Req().GET(" /x"), func(r Request) (Response, error) { return Do(srv.Client(), "http://[::1", r) }, "tbddhttp: invalid request: "},
+		{Req(), func(r Request) (Response, error) { return Do(nil, "http://127.0.0.1:0", r) }, "tbddhttp: request failed: "},
+	} {
+		mt := &mErrT{}
+		send(mt, v.b, v.do)
+
+		if len(mt.errs) != 1 || !strings.HasPrefix(mt.errs[0], v.exp) {
+			t.Errorf("expected one failure starting with '%s' but got %q", v.exp, mt.errs)
+		}
+	}
+
+	//
+	// validate panics
+	//
+
+	for _, v := range []struct {
+		f   func()
+		exp string
+	}{
+		{func() { Send[TC](nil, req) }, "tbddhttp.Send: handler must be non-nil"},
+		{func() { Send[TC](ordersHandler(), nil) }, "tbddhttp.Send: request function must be non-nil"},
+		{func() { SendTo[TC](nil, srv.URL, nil) }, "tbddhttp.SendTo: request function must be non-nil"},
+	} {
+		var r any
+		func() {
+			defer func() {
+				r = recover()
+			}()
+
+			v.f()
+		}()
+
+		if r != v.exp {
+			t.Errorf("expected panic '%s' but got '%v'", v.exp, r)
+		}
+	}
+}
+
+// mErrT records the formatted failures of a test.
+type mErrT struct {
+	errs []string
+}
+
+func (t *mErrT) Helper() {
+}
+
+func (t *mErrT) Fatalf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
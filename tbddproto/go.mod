@@ -0,0 +1,5 @@
+module github.com/josephcopenhaver/tbdd-go/tbddproto
+
+go 1.25.0
+
+require google.golang.org/protobuf v1.36.12
@@ -0,0 +1,111 @@
+// Package tbddproto provides tbdd assertion helpers for behaviors which
+// produce protocol buffer messages.
+//
+// Comparing messages with reflect.DeepEqual, as tbdd.Expect and similar
+// helpers do, reports false failures because generated messages carry
+// internal state alongside their fields. The helpers in this package compare
+// messages with protobuf equality semantics instead.
+//
+// It is a separate module so that tbdd itself does not depend on protobuf.
+// Like tbdd, this package is intended exclusively for use in *_test.go files.
+package tbddproto
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Equal fails the test unless want and got are equal according to
+// proto.Equal once unknown fields have been discarded from both.
+func Equal(t *testing.T, want, got proto.Message) {
+	t.Helper()
+
+	equal(t, want, got)
+}
+
+// ExpectEqual returns a then function which fails the test unless the Act
+// result equals want; see Equal.
+func ExpectEqual[T any, R proto.Message](want R) func(*testing.T, T, R) {
+	return func(t *testing.T, _ T, r R) {
+		t.Helper()
+
+		equal(t, want, r)
+	}
+}
+
+// assertT is the subset of *testing.T that assertion helpers depend on.
+type assertT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+func equal(t assertT, want, got proto.Message) {
+	t.Helper()
+
+	want, got = withoutUnknown(want), withoutUnknown(got)
+	if proto.Equal(want, got) {
+		return
+	}
+
+	wantName, gotName := name(want), name(got)
+	if wantName != gotName {
+		t.Fatalf("expected a %s message but got a %s message", wantName, gotName)
+		return
+	}
+
+	t.Fatalf("%s messages differ:\nexpected:\n%s\nactual:\n%s", wantName, format(want), format(got))
+}
+
+// withoutUnknown returns a copy of m with all unknown fields discarded,
+// leaving m itself untouched.
+func withoutUnknown(m proto.Message) proto.Message {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return m
+	}
+
+	m = proto.Clone(m)
+	discardUnknown(m.ProtoReflect())
+	return m
+}
+
+func discardUnknown(m protoreflect.Message) {
+	m.SetUnknown(nil)
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			l := v.List()
+			for i := range l.Len() {
+				discardUnknown(l.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				discardUnknown(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			discardUnknown(v.Message())
+		}
+
+		return true
+	})
+}
+
+func name(m proto.Message) string {
+	if m == nil {
+		return "<nil>"
+	}
+
+	return string(m.ProtoReflect().Descriptor().FullName())
+}
+
+func format(m proto.Message) string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return "<nil>"
+	}
+
+	return prototext.MarshalOptions{Multiline: true, Indent: "\t"}.Format(m)
+}
@@ -0,0 +1,103 @@
+package tbddproto
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var _ assertT = (*testing.T)(nil)
+
+type mT struct {
+	fatalfCalls []string
+}
+
+func (t *mT) Helper() {
+}
+
+func (t *mT) Fatalf(format string, args ...any) {
+	t.fatalfCalls = append(t.fatalfCalls, format)
+}
+
+// withUnknown adds an unknown varint field to m and returns it.
+func withUnknown[M proto.Message](m M) M {
+	b := protowire.AppendTag(nil, 999, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	m.ProtoReflect().SetUnknown(b)
+	return m
+}
+
+func document() *structpb.Struct {
+	s, err := structpb.NewStruct(map[string]any{
+		"name": "widget",
+		"tags": []any{"a", map[string]any{"b": 1.0}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	got := document()
+	withUnknown(got)
+	withUnknown(got.Fields["name"])
+	withUnknown(got.Fields["tags"].GetListValue().Values[1])
+	withUnknown(got.Fields["tags"].GetListValue().Values[1].GetStructValue().Fields["b"])
+
+	Equal(t, document(), got)
+	ExpectEqual[struct{}](wrapperspb.String("x"))(t, struct{}{}, withUnknown(wrapperspb.String("x")))
+	Equal(t, nil, nil)
+	Equal(t, (*structpb.Struct)(nil), (*structpb.Struct)(nil))
+
+	if len(got.ProtoReflect().GetUnknown()) == 0 {
+		t.Errorf("expected Equal to leave the unknown fields of its arguments untouched")
+	}
+}
+
+func TestEqual_failures(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		want, got proto.Message
+		exp       string
+	}{
+		{wrapperspb.String("x"), wrapperspb.String("y"), "%s messages differ:\nexpected:\n%s\nactual:\n%s"},
+		{wrapperspb.String("x"), (*wrapperspb.StringValue)(nil), "%s messages differ:\nexpected:\n%s\nactual:\n%s"},
+		{wrapperspb.String("x"), wrapperspb.Int32(1), "expected a %s message but got a %s message"},
+		{nil, wrapperspb.Int32(1), "expected a %s message but got a %s message"},
+	} {
+		mt := &mT{}
+		equal(mt, v.want, v.got)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0] != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []struct {
+		m   proto.Message
+		exp string
+	}{
+		{nil, "<nil>"},
+		{(*wrapperspb.StringValue)(nil), "<nil>"},
+	} {
+		if s := format(v.m); s != v.exp {
+			t.Errorf("expected %q but got %q", v.exp, s)
+		}
+	}
+
+	if s := format(wrapperspb.String("x")); s == "<nil>" || s == "" {
+		t.Errorf("expected a text rendering of the message but got %q", s)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/josephcopenhaver/tbdd-go/tbddtestify
+
+go 1.25.0
+
+require github.com/josephcopenhaver/tbdd-go v0.0.0
+
+require (
+	github.com/stretchr/testify v1.12.1
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+)
+
+replace github.com/josephcopenhaver/tbdd-go => ../
@@ -0,0 +1,127 @@
+// Package tbddtestify runs tbdd lifecycles as part of testify suites so that
+// code bases built on github.com/stretchr/testify/suite can adopt tbdd one
+// test method at a time.
+//
+// It is a separate module so that tbdd itself does not depend on testify.
+// Like tbdd, this package is intended exclusively for use in *_test.go files.
+package tbddtestify
+
+import (
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+	"github.com/stretchr/testify/suite"
+)
+
+// Run runs b within the suite test method currently executing on s:
+//
+//	func (s *CartSuite) TestAddItem() {
+//		tbddtestify.Run(s, tbdd.GWT(...))
+//	}
+//
+// See Adapt for how the suite's fixtures are shared with each scenario.
+func Run[S suite.TestingSuite, T, R any](s S, b tbdd.Lifecycle[T, R]) {
+	t := s.T()
+	t.Helper()
+
+	f := Adapt(s, b).New(t)
+	f(t)
+}
+
+// Adapt returns a copy of b which sets up each of its scenarios, including
+// every variant, as a test of the suite s:
+//
+//   - SetupTest is called, when s implements suite.SetupTestSuite, as the
+//     scenario's outermost subtest starts: before the given function of the
+//     Arrange phase, or before Act when there is no given phase.
+//   - s.T() returns the subtest of the phase running, or else the
+//     scenario's outermost subtest, so suite assertions such as s.Require()
+//     fail the right subtest; require assertions calling FailNow on a parent
+//     of the running subtest would panic.
+//   - TearDownTest is called, when s implements suite.TearDownTestSuite, once
+//     the scenario's subtests complete.
+//
+// Suite fields set by SetupTest therefore act as per-scenario fixtures which
+// the phase functions can share through the suite receiver. Testify also
+// calls SetupTest and TearDownTest around the test method itself, so they
+// should reset every fixture they manage.
+func Adapt[S suite.TestingSuite, T, R any](s S, b tbdd.Lifecycle[T, R]) tbdd.Lifecycle[T, R] {
+	// phase sets s.T() to the subtest t of a phase, returning a function
+	// restoring the previous one once the phase returns or exits
+	phase := func(t *testing.T) func() {
+		prev := s.T()
+		s.SetT(t)
+
+		return func() {
+			s.SetT(prev)
+		}
+	}
+
+	setup := func(t *testing.T) {
+		parent := s.T()
+		s.SetT(t)
+
+		t.Cleanup(func() {
+			if ts, ok := any(s).(suite.TearDownTestSuite); ok {
+				ts.TearDownTest()
+			}
+
+			s.SetT(parent)
+		})
+
+		if ss, ok := any(s).(suite.SetupTestSuite); ok {
+			ss.SetupTest()
+		}
+	}
+
+	if act := b.Act; act != nil {
+		b.Act = func(t *testing.T, tc T) R {
+			defer phase(t)()
+
+			return act(t, tc)
+		}
+	}
+
+	if assert := b.Assert; assert != nil {
+		b.Assert = func(t *testing.T, cfg tbdd.Assert[T, R]) {
+			defer phase(t)()
+
+			assert(t, cfg)
+		}
+	}
+
+	if b.Arrange == nil && b.Given == "" {
+		act := b.Act
+		if act == nil {
+			// left for the lifecycle to report as misconfigured
+			return b
+		}
+
+		b.Act = func(t *testing.T, tc T) R {
+			setup(t)
+
+			return act(t, tc)
+		}
+
+		return b
+	}
+
+	arrange := b.Arrange
+	b.Arrange = func(t *testing.T, cfg tbdd.Arrange[T, R]) (string, func(*testing.T)) {
+		given, givenF := cfg.Given, func(*testing.T) {}
+		if arrange != nil {
+			given, givenF = arrange(t, cfg)
+			if givenF == nil {
+				// left for the lifecycle to report as misconfigured
+				return given, nil
+			}
+		}
+
+		return given, func(t *testing.T) {
+			setup(t)
+			givenF(t)
+		}
+	}
+
+	return b
+}
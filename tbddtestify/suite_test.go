@@ -0,0 +1,193 @@
+package tbddtestify
+
+import (
+	"iter"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/tbdd-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type cartSuite struct {
+	suite.Suite
+
+	cart      []string
+	events    []string
+	scenarios []string
+}
+
+func (s *cartSuite) SetupTest() {
+	s.cart = nil
+	s.events = append(s.events, "setup "+s.T().Name())
+}
+
+func (s *cartSuite) TearDownTest() {
+	s.events = append(s.events, "teardown "+s.T().Name())
+}
+
+func (s *cartSuite) TestGWT() {
+	s.events = nil
+
+	b := tbdd.GWTN(
+		"apple",
+		"an empty cart", func(t *testing.T, item *string) {
+			s.Require().Empty(s.cart)
+			s.scenarios = append(s.scenarios, s.T().Name())
+		},
+		"an item is added", func(t *testing.T, item string) {
+			s.cart = append(s.cart, item)
+		},
+		"the cart holds it", func(t *testing.T, item string) {
+			s.Equal([]string{item}, s.cart)
+		},
+	).With(tbdd.WithVariants[string, struct{}](func(*testing.T, string) iter.Seq[tbdd.TestVariant[string]] {
+		return func(yield func(tbdd.TestVariant[string]) bool) {
+			yield(tbdd.TestVariant[string]{Kind: "pear", TC: "pear"})
+		}
+	}))
+
+	Run(s, b)
+
+	exp := []string{
+		"setup TestSuite/TestGWT/given_an_empty_cart",
+		"teardown TestSuite/TestGWT/given_an_empty_cart",
+		"setup TestSuite/TestGWT/pear/given_an_empty_cart",
+		"teardown TestSuite/TestGWT/pear/given_an_empty_cart",
+	}
+	s.Equal(exp, s.events)
+	s.Equal("TestSuite/TestGWT", s.T().Name())
+}
+
+func (s *cartSuite) TestGivenWithoutFunc() {
+	s.events = nil
+
+	b := tbdd.New(
+		"apple",
+		tbdd.WithGiven[string, struct{}]("an empty cart", nil),
+		tbdd.WithWhen("an item is added", func(t *testing.T, item string) struct{} {
+			s.cart = append(s.cart, item)
+			return struct{}{}
+		}),
+		tbdd.WithThen("the cart holds it", func(t *testing.T, item string, _ struct{}) {
+			s.Equal([]string{item}, s.cart)
+		}),
+	)
+
+	Run(s, b)
+
+	s.Equal([]string{
+		"setup TestSuite/TestGivenWithoutFunc/given_an_empty_cart",
+		"teardown TestSuite/TestGivenWithoutFunc/given_an_empty_cart",
+	}, s.events)
+}
+
+func (s *cartSuite) TestWT() {
+	s.events = nil
+
+	b := tbdd.WTN(
+		"apple",
+		"an item is added to an empty cart", func(t *testing.T, item string) {
+			s.cart = append(s.cart, item)
+		},
+		"the cart holds only it", func(t *testing.T, item string) {
+			s.Equal([]string{item}, s.cart)
+		},
+	)
+
+	Run(s, b)
+
+	s.Equal([]string{
+		"setup TestSuite/TestWT/when_an_item_is_added_to_an_empty_cart",
+		"teardown TestSuite/TestWT/when_an_item_is_added_to_an_empty_cart",
+	}, s.events)
+}
+
+func TestSuite(t *testing.T) {
+	s := &cartSuite{}
+	suite.Run(t, s)
+
+	if len(s.scenarios) != 2 || !strings.HasSuffix(s.scenarios[1], "/pear/given_an_empty_cart") {
+		t.Errorf("expected the given phases to see their scenario subtests but got %v", s.scenarios)
+	}
+}
+
+type stockSuite struct {
+	suite.Suite
+}
+
+func (s *stockSuite) TestRequire() {
+	Run(s, tbdd.WTN(
+		"apple",
+		"the stock is checked", func(*testing.T, string) {
+			s.Equal("TestAdapt_require/TestRequire/when_the_stock_is_checked", s.T().Name())
+		},
+		"it is in stock", func(_ *testing.T, item string) {
+			s.Require().Fail(item + " is out of stock")
+		},
+	))
+}
+
+func TestAdapt_require(t *testing.T) {
+	if os.Getenv("TBDDTESTIFY_REQUIRE_HELPER") == "1" {
+		suite.Run(t, &stockSuite{})
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDDTESTIFY_REQUIRE_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"--- FAIL: TestAdapt_require/TestRequire/when_the_stock_is_checked/then_it_is_in_stock ",
+		"apple is out of stock",
+		// testify names the test s.T() returned
+		"Test:       \tTestAdapt_require/TestRequire/when_the_stock_is_checked/then_it_is_in_stock\n",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+
+	// the when phase passed, and a require failure of a parent would panic
+	if strings.Count(string(out), "Error:") != 1 || strings.Contains(string(out), "FailNow on a parent test") {
+		t.Errorf("expected only the then subtest to fail:\n%s", out)
+	}
+}
+
+type plainSuite struct {
+	suite.Suite
+}
+
+func TestAdapt_misconfigured(t *testing.T) {
+	s := &plainSuite{}
+	s.SetT(t)
+
+	// a lifecycle without an Act is left unchanged
+	if b := Adapt(s, tbdd.Lifecycle[string, struct{}]{}); b.Act != nil || b.Arrange != nil {
+		t.Error("expected the lifecycle to be unchanged")
+	}
+
+	b := Adapt(s, tbdd.Lifecycle[string, struct{}]{
+		Arrange: func(*testing.T, tbdd.Arrange[string, struct{}]) (string, func(*testing.T)) {
+			return "a given", nil
+		},
+	})
+
+	if given, f := b.Arrange(t, tbdd.Arrange[string, struct{}]{}); given != "a given" || f != nil {
+		t.Errorf("expected a nil given function to be passed through but got '%s' %v", given, f != nil)
+	}
+
+	// suites need not implement the setup and teardown interfaces
+	Run(s, tbdd.WTN(
+		"x",
+		"a plain suite runs", func(*testing.T, string) {},
+		"it passes", func(*testing.T, string) {},
+	))
+}
@@ -0,0 +1,91 @@
+package tbdd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TCMarshaler encodes the test cases of a Lifecycle for persistence, such as
+// the copy of a failing scenario's test case kept in its Artifacts.
+type TCMarshaler[T any] interface {
+	MarshalTC(tc T) ([]byte, error)
+}
+
+// TCUnmarshaler decodes test cases encoded by the matching TCMarshaler, such
+// as to replay a persisted failure or load test cases from external data.
+type TCUnmarshaler[T any] interface {
+	UnmarshalTC(b []byte) (T, error)
+}
+
+// TCCodec is the encoding contract shared by every feature which persists or
+// loads test cases. JSONCodec is used when a Lifecycle has none.
+type TCCodec[T any] interface {
+	TCMarshaler[T]
+	TCUnmarshaler[T]
+}
+
+// JSONCodec encodes test cases as indented JSON with encoding/json, so only
+// exported fields are persisted.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) MarshalTC(tc T) ([]byte, error) {
+	return json.MarshalIndent(tc, "", "  ")
+}
+
+func (JSONCodec[T]) UnmarshalTC(b []byte) (T, error) {
+	var tc T
+	err := json.Unmarshal(b, &tc)
+
+	return tc, err
+}
+
+// tcCodec returns the TCCodec of b, or JSONCodec when it has none.
+func (b lifecycle[T, R]) tcCodec() TCCodec[T] {
+	if b.TCCodec == nil {
+		return JSONCodec[T]{}
+	}
+
+	return b.TCCodec
+}
+
+// tcFileName returns the name of the artifact holding a test case encoded by
+// codec.
+func tcFileName[T any](codec TCMarshaler[T]) string {
+	if _, ok := codec.(JSONCodec[T]); ok {
+		return "tc.json"
+	}
+
+	return "tc"
+}
+
+// persistFailingTC registers a cleanup on t which encodes the test case
+// returned by tc with codec into the artifact directory art if t fails.
+// Failures to persist it are logged since t can no longer fail.
+func persistFailingTC[T any](t *testing.T, art *Artifacts, codec TCMarshaler[T], tc func() *T) {
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		b, err := codec.MarshalTC(*tc())
+		if err != nil {
+			t.Logf("tbdd: test case not persisted: %v", err)
+			return
+		}
+
+		dir := art.create(nopFatalT{t})
+		if dir == "" {
+			return
+		}
+
+		path := filepath.Join(dir, tcFileName(codec))
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Logf("tbdd: test case not persisted: %v", err)
+			return
+		}
+
+		t.Logf("tbdd: test case persisted to %s", path)
+	})
+}
@@ -0,0 +1,141 @@
+package tbdd
+
+import (
+	"errors"
+	"iter"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type persistedTC struct {
+	Name string
+	Tags []string
+}
+
+// failingCodec encodes test cases as their names, failing for empty ones.
+type failingCodec struct{}
+
+func (failingCodec) MarshalTC(tc persistedTC) ([]byte, error) {
+	if tc.Name == "" {
+		return nil, errors.New("unnamed")
+	}
+
+	return []byte(tc.Name), nil
+}
+
+func (failingCodec) UnmarshalTC(b []byte) (persistedTC, error) {
+	return persistedTC{Name: string(b)}, nil
+}
+
+func TestLifecycle_persistFailingTC(t *testing.T) {
+	if os.Getenv("TBDD_TC_HELPER") == "1" {
+		b := GWTN(
+			persistedTC{Name: "ann"},
+			"a tagged user", func(_ *testing.T, tc *persistedTC) {
+				tc.Tags = append(tc.Tags, "admin")
+			},
+			"the behavior fails", func(t *testing.T, _ persistedTC) {
+				t.Error("failure")
+			},
+			"the test case is persisted", func(*testing.T, persistedTC) {},
+		)
+
+		f := b.New(t)
+		f(t)
+
+		f = b.With(
+			WithTCCodec[persistedTC, struct{}](failingCodec{}),
+			WithVariants[persistedTC, struct{}](func(*testing.T, persistedTC) iter.Seq[TestVariant[persistedTC]] {
+				return func(yield func(TestVariant[persistedTC]) bool) {
+					yield(TestVariant[persistedTC]{Kind: "unnamed"})
+				}
+			}),
+		).New(t)
+		t.Run("custom", f)
+		return
+	}
+
+	orig := config
+	defer func() {
+		config = orig
+	}()
+
+	// passing scenarios are not persisted
+	config.artifacts = t.TempDir()
+
+	f := WTN(
+		persistedTC{Name: "bob"},
+		"the behavior passes", func(*testing.T, persistedTC) {},
+		"the test case is not persisted", func(*testing.T, persistedTC) {},
+	).New(t)
+	f(t)
+
+	if entries, err := os.ReadDir(config.artifacts); err != nil || len(entries) != 0 {
+		t.Errorf("expected no artifacts but got %v: %v", entries, err)
+	}
+
+	//
+	// failing scenarios persist their test cases
+	//
+
+	root := t.TempDir()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v", "-tbdd.artifacts="+root)
+	cmd.Env = append(os.Environ(), "TBDD_TC_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	sentence := "given a tagged user when the behavior fails then the test case is persisted"
+	path := filepath.Join(root, t.Name(), "basis-"+scenarioID(t.Name(), "", sentence), "tc.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the test case to be persisted: %v\n%s", err, out)
+	}
+
+	tc, err := JSONCodec[persistedTC]{}.UnmarshalTC(b)
+	if err != nil || tc.Name != "ann" || len(tc.Tags) != 1 || tc.Tags[0] != "admin" {
+		t.Errorf("expected the test case modified by the given phase but got %+v: %v", tc, err)
+	}
+
+	if b, err := os.ReadFile(filepath.Join(root, t.Name(), "custom", "basis-"+scenarioID(t.Name()+"/custom", "", sentence), "tc")); err != nil || string(b) != "ann" {
+		t.Errorf("expected the custom codec to persist the test case but got %q: %v", b, err)
+	}
+
+	for _, exp := range []string{
+		"tbdd: test case persisted to " + path + "\n",
+		"tbdd: test case not persisted: unnamed\n",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func TestJSONCodec(t *testing.T) {
+	t.Parallel()
+
+	var c TCCodec[persistedTC] = JSONCodec[persistedTC]{}
+
+	b, err := c.MarshalTC(persistedTC{"ann", []string{"admin"}})
+	if exp := "{\n  \"Name\": \"ann\",\n  \"Tags\": [\n    \"admin\"\n  ]\n}"; err != nil || string(b) != exp {
+		t.Fatalf("expected %s but got %s: %v", exp, b, err)
+	}
+
+	if tc, err := c.UnmarshalTC(b); err != nil || tc.Name != "ann" || tc.Tags[0] != "admin" {
+		t.Errorf("expected the test case to round trip but got %+v: %v", tc, err)
+	}
+
+	if _, err := c.UnmarshalTC([]byte("{")); err == nil {
+		t.Error("expected invalid JSON to fail")
+	}
+
+	if name := tcFileName[persistedTC](failingCodec{}); name != "tc" {
+		t.Errorf("expected custom codecs to persist to tc but got %s", name)
+	}
+}
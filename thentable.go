@@ -0,0 +1,62 @@
+package tbdd
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// ThenTable returns a then function which runs every check as its own
+// "then <name>" subtest, in the order of their names, so a long assertion
+// can be split into independently reported checks:
+//
+//	tbdd.ThenTable(map[string]func(*testing.T, TC, *User){
+//		"the user is active":     expectActive,
+//		"the user has a profile": expectProfile,
+//	})
+//
+// A check which fails does not stop the others.
+//
+// ThenTable panics if a check is nil.
+func ThenTable[T, R any](checks map[string]func(*testing.T, T, R)) func(*testing.T, T, R) {
+	return thenTable("tbdd.ThenTable", checks, false)
+}
+
+// ParallelThenTable is like ThenTable except every check calls t.Parallel,
+// so the checks run concurrently once the enclosing Assert returns but
+// before its then subtest completes. The checks share the test case and
+// result and must not mutate them; ReadOnlyTC reports those which mutate the
+// test case once every check has finished.
+func ParallelThenTable[T, R any](checks map[string]func(*testing.T, T, R)) func(*testing.T, T, R) {
+	return thenTable("tbdd.ParallelThenTable", checks, true)
+}
+
+func thenTable[T, R any](fn string, checks map[string]func(*testing.T, T, R), parallel bool) func(*testing.T, T, R) {
+	names := make([]string, 0, len(checks))
+	for name, f := range checks {
+		if f == nil {
+			panic(fn + ": check " + strconv.Quote(name) + " must be non-nil")
+		}
+
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return func(t *testing.T, tc T, r R) {
+		t.Helper()
+
+		refuseBubble(t, fn)
+
+		for _, name := range names {
+			f := checks[name]
+			t.Run("then "+name, func(t *testing.T) {
+				if parallel {
+					t.Parallel()
+				}
+
+				f(t, tc, r)
+			})
+		}
+	}
+}
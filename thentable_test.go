@@ -0,0 +1,54 @@
+package tbdd
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestThenTable(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		var mu sync.Mutex
+		var got []string
+		check := func(t *testing.T, tc string, r int) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			got = append(got, t.Name()[strings.LastIndex(t.Name(), "/")+1:]+": "+tc+" "+string(rune('0'+r)))
+		}
+
+		checks := map[string]func(*testing.T, string, int){
+			"b is checked": check,
+			"a is checked": check,
+		}
+
+		thenF := ThenTable(checks)
+		if parallel {
+			thenF = ParallelThenTable(checks)
+		}
+
+		WT("tc", "acting", func(*testing.T, string) int {
+			return 1
+		}, "the checks pass", thenF).New(t)(t)
+
+		exp := []string{"then_a_is_checked: tc 1", "then_b_is_checked: tc 1"}
+		if parallel {
+			slices.Sort(got)
+		}
+
+		if !slices.Equal(got, exp) {
+			t.Errorf("expected (parallel = %v):\n%s\nbut got:\n%s", parallel, strings.Join(exp, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestThenTable_panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != `tbdd.ThenTable: check "x" must be non-nil` {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	ThenTable(map[string]func(*testing.T, int, int){"x": nil})
+}
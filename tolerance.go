@@ -0,0 +1,150 @@
+package tbdd
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Number is the set of types accepted by the numeric tolerance assertions.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// InDelta fails the test unless got is within delta of want.
+func InDelta[N Number](t *testing.T, want, got N, delta float64) {
+	t.Helper()
+
+	inDelta(linkedT{T: t}, "value", float64(want), float64(got), delta)
+}
+
+// InEpsilon fails the test unless the relative error between got and want
+// is at most epsilon. When want is zero got must be exactly zero.
+func InEpsilon[N Number](t *testing.T, want, got N, epsilon float64) {
+	t.Helper()
+
+	inEpsilon(linkedT{T: t}, "value", float64(want), float64(got), epsilon)
+}
+
+// WithinDuration fails the test unless got is within d of want.
+func WithinDuration(t *testing.T, want, got time.Time, d time.Duration) {
+	t.Helper()
+
+	withinDuration(linkedT{T: t}, "value", want, got, d)
+}
+
+// InDelta asserts the value is a number within delta of want.
+func (e *Expectation) InDelta(want, delta float64) *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.number(); ok {
+		inDelta(expectationT{e}, e.path, want, got, delta)
+	}
+
+	return e
+}
+
+// InEpsilon asserts the value is a number whose relative error from want is
+// at most epsilon.
+func (e *Expectation) InEpsilon(want, epsilon float64) *Expectation {
+	e.t.Helper()
+
+	if got, ok := e.number(); ok {
+		inEpsilon(expectationT{e}, e.path, want, got, epsilon)
+	}
+
+	return e
+}
+
+// WithinDuration asserts the value is a time.Time within d of want.
+func (e *Expectation) WithinDuration(want time.Time, d time.Duration) *Expectation {
+	e.t.Helper()
+
+	got, ok := e.value()
+	if !ok {
+		return e
+	}
+
+	tm, ok := got.(time.Time)
+	if !ok {
+		e.fail("expected %s to be a time.Time but it is a %T", e.path, got)
+		return e
+	}
+
+	withinDuration(expectationT{e}, e.path, want, tm, d)
+	return e
+}
+
+// expectationT reports assertion failures through an Expectation so that
+// it stops evaluating further checks.
+type expectationT struct {
+	e *Expectation
+}
+
+func (t expectationT) Helper() {
+	t.e.t.Helper()
+}
+
+func (t expectationT) Fatalf(format string, args ...any) {
+	t.e.t.Helper()
+
+	t.e.fail(format, args...)
+}
+
+// number returns the value converted to float64 and whether assertions
+// should still be evaluated.
+func (e *Expectation) number() (float64, bool) {
+	e.t.Helper()
+
+	got, ok := e.value()
+	if !ok {
+		return 0, false
+	}
+
+	switch e.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(e.v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(e.v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return e.v.Float(), true
+	}
+
+	e.fail("expected %s to be a number but it is a %T", e.path, got)
+	return 0, false
+}
+
+func inDelta(t assertT, name string, want, got, delta float64) {
+	t.Helper()
+
+	if diff := math.Abs(got - want); math.IsNaN(diff) || diff > delta {
+		t.Fatalf("expected %s to be within %v of %v but got %v (difference %v)", name, delta, want, got, diff)
+	}
+}
+
+func inEpsilon(t assertT, name string, want, got, epsilon float64) {
+	t.Helper()
+
+	if want == 0 {
+		if got != 0 {
+			t.Fatalf("expected %s to be 0 but got %v; relative error is undefined for 0", name, got)
+		}
+		return
+	}
+
+	if rel := math.Abs(got-want) / math.Abs(want); math.IsNaN(rel) || rel > epsilon {
+		t.Fatalf("expected %s to be within relative error %v of %v but got %v (relative error %v)", name, epsilon, want, got, rel)
+	}
+}
+
+func withinDuration(t assertT, name string, want, got time.Time, d time.Duration) {
+	t.Helper()
+
+	diff := got.Sub(want)
+	if diff < -d || diff > d {
+		t.Fatalf("expected %s to be within %v of %v but got %v (difference %v)", name, d, want, got, diff)
+	}
+}
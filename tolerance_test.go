@@ -0,0 +1,62 @@
+package tbdd
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTolerance(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	InDelta(t, 1.0, 1.05, 0.1)
+	InDelta(t, 10, 12, 2)
+	InEpsilon(t, 100.0, 101.0, 0.02)
+	InEpsilon(t, 0, 0, 0.1)
+	WithinDuration(t, now, now.Add(-time.Second), time.Second)
+
+	Expect(t, struct {
+		F float32
+		I int8
+		U uint
+		T time.Time
+	}{1.5, -2, 3, now}).
+		Field("F").InDelta(1.4, 0.2).InEpsilon(1.5, 0.01)
+
+	Expect(t, int8(-2)).InDelta(-2, 0)
+	Expect(t, uint(3)).InEpsilon(3, 0)
+	Expect(t, now).WithinDuration(now.Add(time.Millisecond), time.Millisecond)
+}
+
+func TestTolerance_failures(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	for _, v := range []struct {
+		f   func(assertT)
+		exp string
+	}{
+		{func(t assertT) { inDelta(t, "v", 1, 2, 0.5) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { inDelta(t, "v", 1, math.NaN(), 0.5) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { inEpsilon(t, "v", 0, 1, 0.5) }, "expected %s to be 0 but got %v; relative error is undefined for 0"},
+		{func(t assertT) { inEpsilon(t, "v", 1, 2, 0.5) }, "expected %s to be within relative error %v of %v but got %v (relative error %v)"},
+		{func(t assertT) { withinDuration(t, "v", now, now.Add(time.Hour), time.Second) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { withinDuration(t, "v", now, now.Add(-time.Hour), time.Second) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { expect(t, 1.0).InDelta(2, 0.5).InEpsilon(2, 0.5) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { expect(t, 1.0).InEpsilon(2, 0.1) }, "expected %s to be within relative error %v of %v but got %v (relative error %v)"},
+		{func(t assertT) { expect(t, "1").InDelta(1, 0) }, "expected %s to be a number but it is a %T"},
+		{func(t assertT) { expect(t, 1).WithinDuration(now, 0) }, "expected %s to be a time.Time but it is a %T"},
+		{func(t assertT) { expect(t, now).WithinDuration(now.Add(time.Hour), 0).WithinDuration(now, 0) }, "expected %s to be within %v of %v but got %v (difference %v)"},
+		{func(t assertT) { expect(t, struct{ f int }{}).Field("f").InDelta(0, 0).InEpsilon(0, 0) }, "cannot inspect %s: value is unexported"},
+	} {
+		mt := &mT{}
+		v.f(mt)
+
+		if len(mt.fatalfCalls) != 1 || mt.fatalfCalls[0].format != v.exp {
+			t.Errorf("expected one fatalf call with format '%s' but got %v", v.exp, mt.fatalfCalls)
+		}
+	}
+}
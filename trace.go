@@ -0,0 +1,32 @@
+package tbdd
+
+import (
+	"context"
+	"runtime/trace"
+	"testing"
+)
+
+// startTraceTask starts a runtime/trace task named after t which ends once
+// t completes, returning the context carrying it. It returns nil when t is
+// nil, as it is in self-test contexts.
+func startTraceTask(t *testing.T) context.Context {
+	if t == nil {
+		return nil
+	}
+
+	ctx, task := trace.NewTask(t.Context(), t.Name())
+	t.Cleanup(task.End)
+
+	return ctx
+}
+
+// traceRegion calls f within a runtime/trace region of the task in ctx, or
+// directly when ctx is nil.
+func traceRegion(ctx context.Context, name string, f func()) {
+	if ctx == nil {
+		f()
+		return
+	}
+
+	trace.WithRegion(ctx, name, f)
+}
@@ -0,0 +1,52 @@
+package tbdd
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+)
+
+func TestLifecycle_trace(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skip("a trace is already in progress:", err)
+	}
+
+	f := GWTN(
+		struct{}{},
+		"a traced context", func(*testing.T, *struct{}) {},
+		"the traced behavior runs", func(*testing.T, struct{}) {},
+		"its phases are regions", func(*testing.T, struct{}) {},
+	).With(WithTrace[struct{}, struct{}](), WithSynctest[struct{}, struct{}]()).New(t)
+	f(t)
+
+	f = WTN(
+		struct{}{},
+		"an untraced context runs", func(*testing.T, struct{}) {},
+		"its phases are regions", func(*testing.T, struct{}) {},
+	).With(WithTrace[struct{}, struct{}]()).New(t)
+	f(t)
+
+	trace.Stop()
+
+	for _, exp := range []string{
+		t.Name() + "/given_a_traced_context",
+		t.Name() + "/when_an_untraced_context_runs",
+		"given",
+		"act",
+		"assert",
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(exp)) {
+			t.Errorf("expected the trace to mention '%s'", exp)
+		}
+	}
+
+	// self-test contexts are not traced
+	var ran bool
+	traceRegion(startTraceTask(nil), "x", func() {
+		ran = true
+	})
+	if !ran {
+		t.Error("expected the function to run")
+	}
+}
@@ -0,0 +1,48 @@
+package tbdd
+
+import (
+	"slices"
+	"sync"
+)
+
+// Update is a file rewritten instead of compared against because the
+// -tbdd.update-golden flag, or a more specific update flag, was set.
+type Update struct {
+	// Kind names the kind of file, such as "golden", "examples",
+	// "snapshot", or "baseline".
+	Kind string
+	Path string
+}
+
+// updates holds every Update recorded by the process.
+var updates struct {
+	mu   sync.Mutex
+	list []Update
+}
+
+// RecordUpdate records that the file at path, of the given kind, was
+// rewritten so Main can summarize every updated file at the end of the run
+// and include them in its Report. A path recorded more than once is only
+// listed once.
+//
+// Packages which build golden or approval features on tbdd should call it
+// alongside logging the update, as the features of tbdd do.
+func RecordUpdate(kind, path string) {
+	updates.mu.Lock()
+	defer updates.mu.Unlock()
+
+	if slices.ContainsFunc(updates.list, func(u Update) bool { return u.Path == path }) {
+		return
+	}
+
+	updates.list = append(updates.list, Update{Kind: kind, Path: path})
+}
+
+// Updates returns a copy of every Update recorded so far, in the order they
+// were first recorded.
+func Updates() []Update {
+	updates.mu.Lock()
+	defer updates.mu.Unlock()
+
+	return slices.Clone(updates.list)
+}
@@ -0,0 +1,27 @@
+package tbdd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRecordUpdate(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "a.golden")
+	b := filepath.Join(t.TempDir(), "b.json")
+
+	RecordUpdate("golden", a)
+	RecordUpdate("baseline", b)
+	RecordUpdate("golden", a)
+
+	var found []Update
+	for _, u := range Updates() {
+		if u.Path == a || u.Path == b {
+			found = append(found, u)
+		}
+	}
+
+	if exp := []Update{{"golden", a}, {"baseline", b}}; !slices.Equal(found, exp) {
+		t.Errorf("expected updates %v but got %v", exp, found)
+	}
+}
@@ -0,0 +1,101 @@
+package tbdd
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Warning is a non-fatal observation recorded by Warn or Warnf.
+type Warning struct {
+	// Test is the full name of the test which recorded the warning.
+	Test string
+	// Message is the formatted warning text.
+	Message string
+	// Phase and Duration identify the phase of a scenario, and how long it
+	// took, for warnings recorded because it exceeded the SlowPhase threshold
+	// of its Lifecycle.
+	Phase    string        `json:",omitempty"`
+	Duration time.Duration `json:",omitempty"`
+}
+
+// warnings holds every Warning recorded by the process.
+var warnings struct {
+	mu   sync.Mutex
+	list []Warning
+}
+
+// Warn records a non-fatal observation, such as a deprecation or a soft
+// performance expectation, without failing the test.
+//
+// The message is logged with a "WARNING: " prefix and retained so it can be
+// inspected with Warnings and included in reports. Arguments are handled in
+// the manner of fmt.Sprint.
+func Warn(t *testing.T, args ...any) {
+	t.Helper()
+
+	warn(t, Warning{Message: fmt.Sprint(args...)})
+}
+
+// Warnf is like Warn but formats its arguments in the manner of fmt.Sprintf.
+func Warnf(t *testing.T, format string, args ...any) {
+	t.Helper()
+
+	warn(t, Warning{Message: fmt.Sprintf(format, args...)})
+}
+
+// Warnings returns a copy of every Warning recorded so far, in the order
+// they were recorded.
+func Warnings() []Warning {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+
+	return slices.Clone(warnings.list)
+}
+
+// warnT is the subset of *testing.T that warnings depend on.
+type warnT interface {
+	Helper()
+	Name() string
+	Log(args ...any)
+}
+
+// warn logs w on t and records it as a warning of t.
+func warn(t warnT, w Warning) {
+	t.Helper()
+
+	t.Log("WARNING: " + w.Message)
+
+	w.Test = t.Name()
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+
+	warnings.list = append(warnings.list, w)
+}
+
+// warnSlowPhase warns on t, and marks the result of sr, when the phase
+// which started at start took at least threshold. It does nothing when t is
+// nil, as it is in self-test contexts.
+func warnSlowPhase(t *testing.T, sr *scenario, phase string, start time.Time, threshold time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.Helper()
+
+	d := time.Since(start)
+	if d < threshold {
+		return
+	}
+
+	sr.SlowPhases = append(sr.SlowPhases, phase)
+
+	warn(t, Warning{
+		Message:  fmt.Sprintf("%s phase took %s, exceeding the slow phase threshold of %s", phase, d, threshold),
+		Phase:    phase,
+		Duration: d,
+	})
+}
@@ -0,0 +1,154 @@
+package tbdd
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ warnT = (*testing.T)(nil)
+
+func TestWarn(t *testing.T) {
+	b := WTN(
+		struct{}{},
+		"a deprecated endpoint is called", func(*testing.T, struct{}) {},
+		"a deprecation is noted", func(t *testing.T, _ struct{}) {
+			Warn(t, "endpoint ", 1, " is deprecated")
+			Warnf(t, "latency drifted by %dms", 5)
+		},
+	)
+
+	// only the warnings of this run count, as -count runs the test again
+	n := len(Warnings())
+
+	f := b.New(t)
+	f(t)
+
+	if t.Failed() {
+		t.Fatal("warnings must not fail the test")
+	}
+
+	var found []string
+	for _, w := range Warnings()[n:] {
+		if strings.HasPrefix(w.Test, t.Name()+"/") {
+			if !strings.HasSuffix(w.Test, "/then_a_deprecation_is_noted") {
+				t.Errorf("unexpected test name for warning: %s", w.Test)
+			}
+
+			found = append(found, w.Message)
+		}
+	}
+
+	if exp := "endpoint 1 is deprecated|latency drifted by 5ms"; strings.Join(found, "|") != exp {
+		t.Errorf("expected warnings '%s' but got '%s'", exp, strings.Join(found, "|"))
+	}
+}
+
+func TestLifecycle_slowPhase(t *testing.T) {
+	slow := func() {
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if os.Getenv("TBDD_SLOW_PHASE_HELPER") == "1" {
+		WTN(
+			struct{}{},
+			"the behavior is slow and fails", func(t *testing.T, _ struct{}) {
+				slow()
+				t.Fatal("gave up")
+			},
+			"it is not run", func(*testing.T, struct{}) {},
+		).With(WithSlowPhase[struct{}, struct{}](time.Millisecond)).New(t)(t)
+		return
+	}
+
+	// only the records of this run count, as -count runs the test again
+	nw, nr := len(Warnings()), len(Results())
+
+	f := New(
+		struct{}{},
+		WithArrange(func(*testing.T, Arrange[struct{}, struct{}]) (string, func(*testing.T)) {
+			slow()
+			return "a slow setup", func(*testing.T) {
+				slow()
+			}
+		}),
+		WithWhen("the behavior is slow", func(*testing.T, struct{}) struct{} {
+			slow()
+			return struct{}{}
+		}),
+		WithThen("every phase is reported", func(*testing.T, struct{}, struct{}) {
+			slow()
+		}),
+		WithSlowPhase[struct{}, struct{}](time.Millisecond),
+	).New(t)
+	f(t)
+
+	if t.Failed() {
+		t.Fatal("slow phases must not fail the test")
+	}
+
+	var phases []string
+	for _, w := range Warnings()[nw:] {
+		if strings.HasPrefix(w.Test, t.Name()) {
+			if w.Duration < 2*time.Millisecond || !strings.HasPrefix(w.Message, w.Phase+" phase took ") || !strings.HasSuffix(w.Message, ", exceeding the slow phase threshold of 1ms") {
+				t.Errorf("unexpected warning: %+v", w)
+			}
+
+			phases = append(phases, w.Phase)
+		}
+	}
+
+	exp := []string{"arrange", "given", "act", "assert"}
+	if !slices.Equal(phases, exp) {
+		t.Errorf("expected warnings for the phases %v but got %v", exp, phases)
+	}
+
+	var marked [][]string
+	for _, r := range Results()[nr:] {
+		if strings.HasPrefix(r.Test, t.Name()+"/") {
+			marked = append(marked, r.SlowPhases)
+		}
+	}
+
+	if len(marked) != 1 || !slices.Equal(marked[0], exp) {
+		t.Errorf("expected the result to be marked with the phases %v but got %v", exp, marked)
+	}
+
+	// phases within the threshold are not reported
+	f = WTN(
+		struct{}{},
+		"the behavior is fast", func(*testing.T, struct{}) {},
+		"nothing is reported", func(*testing.T, struct{}) {},
+	).With(WithSlowPhase[struct{}, struct{}](time.Hour)).New(t)
+	f(t)
+
+	for _, w := range Warnings() {
+		if strings.Contains(w.Test, "/when_the_behavior_is_fast") {
+			t.Errorf("unexpected warning: %+v", w)
+		}
+	}
+
+	//
+	// phases which end the test are still reported
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_SLOW_PHASE_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"gave up",
+		"WARNING: act phase took ",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
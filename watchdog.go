@@ -0,0 +1,66 @@
+package tbdd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// startWatchdog ends the test binary, after writing the stack of every
+// goroutine to stderr, if t and its cleanups have not completed within d.
+func startWatchdog(t *testing.T, d time.Duration) {
+	name := t.Name()
+
+	timer := time.AfterFunc(d, func() {
+		watchdogTimeout(os.Stderr, name, d)
+	})
+	t.Cleanup(func() {
+		timer.Stop()
+	})
+}
+
+// watchdogTimeout writes the stack of every goroutine to w and panics,
+// like the -test.timeout deadline does, since the hung scenario named name
+// cannot be ended any other way.
+func watchdogTimeout(w io.Writer, name string, d time.Duration) {
+	fmt.Fprintf(w, "tbdd: scenario %s exceeded its watchdog timeout of %s\n\n%s\n", name, d, goroutineStacks())
+
+	panic("tbdd: scenario " + name + " timed out after " + d.String())
+}
+
+// startActTimeout ends the test binary, after writing the stack of every
+// goroutine to stderr, unless the returned function is called within d to
+// report the Act phase of the variant of the given kind, running in t, has
+// completed.
+func startActTimeout(t *testing.T, kind string, d time.Duration) func() bool {
+	name := t.Name()
+
+	return time.AfterFunc(d, func() {
+		actTimeout(os.Stderr, name, kind, d)
+	}).Stop
+}
+
+// actTimeout writes the stack of every goroutine to w and panics, like
+// watchdogTimeout, attributing the timeout to the Timeout of the variant.
+func actTimeout(w io.Writer, name, kind string, d time.Duration) {
+	fmt.Fprintf(w, "tbdd: the Act of scenario %s exceeded the Timeout of variant %q of %s\n\n%s\n", name, kind, d, goroutineStacks())
+
+	panic("tbdd: the Act of scenario " + name + " timed out after " + d.String() + ", the Timeout of variant " + strconv.Quote(kind))
+}
+
+// goroutineStacks returns the formatted stack of every goroutine.
+func goroutineStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
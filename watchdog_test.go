@@ -0,0 +1,170 @@
+package tbdd
+
+import (
+	"bytes"
+	"iter"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_watchdog(t *testing.T) {
+	if os.Getenv("TBDD_WATCHDOG_HELPER") == "1" {
+		f := WTN(
+			struct{}{},
+			"the behavior hangs", func(*testing.T, struct{}) {
+				select {}
+			},
+			"it is never asserted", func(*testing.T, struct{}) {},
+		).With(WithWatchdog[struct{}, struct{}](10 * time.Millisecond)).New(t)
+		f(t)
+		return
+	}
+
+	// scenarios completing in time are unaffected
+	f := GWTN(
+		struct{}{},
+		"a watchdog", func(*testing.T, *struct{}) {},
+		"the behavior completes", func(*testing.T, struct{}) {},
+		"it passes", func(*testing.T, struct{}) {},
+	).With(WithWatchdog[struct{}, struct{}](time.Hour)).New(t)
+	f(t)
+
+	//
+	// hung scenarios end the test binary with a goroutine dump
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_WATCHDOG_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"tbdd: scenario " + t.Name() + "/when_the_behavior_hangs exceeded its watchdog timeout of 10ms\n",
+		"TestLifecycle_watchdog.func1(",
+		"panic: tbdd: scenario " + t.Name() + "/when_the_behavior_hangs timed out after 10ms",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func Test_watchdogTimeout(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	func() {
+		defer func() {
+			if r := recover(); r != "tbdd: scenario TestX timed out after 1s" {
+				t.Errorf("unexpected panic: %v", r)
+			}
+		}()
+
+		watchdogTimeout(&buf, "TestX", time.Second)
+	}()
+
+	if s := buf.String(); !strings.HasPrefix(s, "tbdd: scenario TestX exceeded its watchdog timeout of 1s\n\ngoroutine ") || !strings.Contains(s, "Test_watchdogTimeout") {
+		t.Errorf("expected a goroutine dump but got:\n%s", s)
+	}
+}
+
+func TestTestVariant_timeout(t *testing.T) {
+	slow := WithVariants[time.Duration, struct{}](func(*testing.T, time.Duration) iter.Seq[TestVariant[time.Duration]] {
+		return slices.Values([]TestVariant[time.Duration]{
+			{Kind: "slow", TC: 50 * time.Millisecond, Timeout: time.Hour},
+		})
+	})
+
+	if os.Getenv("TBDD_ACT_TIMEOUT_HELPER") == "1" {
+		f := WTN(
+			time.Duration(0),
+			"the behavior runs", func(_ *testing.T, d time.Duration) {
+				if d < 0 {
+					select {}
+				}
+			},
+			"it is never asserted", func(*testing.T, time.Duration) {},
+		).With(WithVariants[time.Duration, struct{}](func(*testing.T, time.Duration) iter.Seq[TestVariant[time.Duration]] {
+			return slices.Values([]TestVariant[time.Duration]{
+				{Kind: "hangs", TC: -1, Timeout: 10 * time.Millisecond},
+			})
+		})).New(t)
+		f(t)
+		return
+	}
+
+	// the Watchdog of a variant is extended by its Timeout
+	f := WTN(
+		time.Duration(0),
+		"the behavior takes a while", func(_ *testing.T, d time.Duration) {
+			time.Sleep(d)
+		},
+		"it passes", func(*testing.T, time.Duration) {},
+	).With(WithWatchdog[time.Duration, struct{}](10*time.Millisecond), slow).New(t)
+	f(t)
+
+	//
+	// hung variants end the test binary with a goroutine dump
+	//
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), "TBDD_ACT_TIMEOUT_HELPER=1")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the helper test to fail:\n%s", out)
+	}
+
+	for _, exp := range []string{
+		"tbdd: the Act of scenario " + t.Name() + "/hangs/when_the_behavior_runs exceeded the Timeout of variant \"hangs\" of 10ms\n",
+		"TestTestVariant_timeout.func2(",
+		"panic: tbdd: the Act of scenario " + t.Name() + "/hangs/when_the_behavior_runs timed out after 10ms, the Timeout of variant \"hangs\"",
+	} {
+		if !strings.Contains(string(out), exp) {
+			t.Errorf("expected helper output to contain '%s':\n%s", exp, out)
+		}
+	}
+}
+
+func Test_actTimeout(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	func() {
+		defer func() {
+			if r := recover(); r != `tbdd: the Act of scenario TestX/slow timed out after 1s, the Timeout of variant "slow"` {
+				t.Errorf("unexpected panic: %v", r)
+			}
+		}()
+
+		actTimeout(&buf, "TestX/slow", "slow", time.Second)
+	}()
+
+	if s := buf.String(); !strings.HasPrefix(s, "tbdd: the Act of scenario TestX/slow exceeded the Timeout of variant \"slow\" of 1s\n\ngoroutine ") || !strings.Contains(s, "Test_actTimeout") {
+		t.Errorf("expected a goroutine dump but got:\n%s", s)
+	}
+}
+
+func Test_goroutineStacks(t *testing.T) {
+	t.Parallel()
+
+	// enough goroutines to outgrow the initial buffer
+	done := make(chan struct{})
+	defer close(done)
+	for range 1000 {
+		go func() {
+			<-done
+		}()
+	}
+
+	if s := goroutineStacks(); len(s) <= 64<<10 || !bytes.Contains(s, []byte("Test_goroutineStacks.func1")) {
+		t.Errorf("expected the stacks of every goroutine but got %d bytes", len(s))
+	}
+}
@@ -0,0 +1,114 @@
+package tbdd
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// WhenThen is one when and then phase of a scenario built by WhenEach.
+type WhenThen[T, R any] struct {
+	When   string
+	Act    func(*testing.T, T) R
+	Then   string
+	Assert func(*testing.T, T, R)
+}
+
+// Whens is a Scenario which shares one arranged given context between
+// several when and then phases; see WhenEach.
+type Whens[T, R any] struct {
+	tc      T
+	given   string
+	givenF  func(*testing.T, *T)
+	cloneTC func(T) T
+	pairs   []WhenThen[T, R]
+}
+
+// WhenEach returns a Scenario described by given whose pairs each run as a
+// sibling "when" subtest of a single "given" subtest:
+//
+//	TestCart/given_a_full_cart/when_checking_out/then_an_order_is_placed
+//	TestCart/given_a_full_cart/when_emptying_it/then_no_items_remain
+//
+// givenF arranges tc once and every pair receives its own copy of the
+// arranged test case, so expensive arranges are not repeated. That is only
+// safe when the copies cannot share mutable memory: when T holds no
+// pointers, maps, slices, channels, or interfaces, or when CloneTC is used
+// to copy it deeply. Otherwise givenF is called again on a fresh copy of tc
+// before each pair.
+//
+// WhenEach panics if given is empty, givenF is nil, or a pair has an empty
+// description or a nil function.
+func WhenEach[T, R any](tc T, given string, givenF func(*testing.T, *T), pairs ...WhenThen[T, R]) Whens[T, R] {
+	if given == "" {
+		panic("tbdd.WhenEach: given description must be non-empty")
+	}
+
+	if givenF == nil {
+		panic("tbdd.WhenEach: given function must be non-nil")
+	}
+
+	for i, p := range pairs {
+		prefix := "tbdd.WhenEach: pair " + strconv.Itoa(i)
+		switch {
+		case p.When == "":
+			panic(prefix + ": when description must be non-empty")
+		case p.Act == nil:
+			panic(prefix + ": when function must be non-nil")
+		case p.Then == "":
+			panic(prefix + ": then description must be non-empty")
+		case p.Assert == nil:
+			panic(prefix + ": then function must be non-nil")
+		}
+	}
+
+	return Whens[T, R]{tc: tc, given: given, givenF: givenF, pairs: pairs}
+}
+
+// CloneTC returns a copy of w which gives every pair a copy of the arranged
+// test case made by clone, so the arrange runs once even when T refers to
+// mutable memory.
+func (w Whens[T, R]) CloneTC(clone func(T) T) Whens[T, R] {
+	w.cloneTC = clone
+	return w
+}
+
+// New takes a *testing.T to construct sub-tests for the given context and
+// every pair.
+func (w Whens[T, R]) New(t *testing.T) func(*testing.T) {
+	t.Helper()
+
+	return func(t *testing.T) {
+		t.Helper()
+
+		w.run(t)
+	}
+}
+
+func (w Whens[T, R]) run(t *testing.T) {
+	t.Helper()
+
+	once := w.cloneTC != nil || !mayRefer(reflect.TypeFor[T]())
+
+	t.Run("given "+w.given, func(t *testing.T) {
+		t.Helper()
+
+		arranged := w.tc
+		if once {
+			w.givenF(t, &arranged)
+		}
+
+		for _, p := range w.pairs {
+			tc := arranged
+			switch {
+			case w.cloneTC != nil:
+				tc = w.cloneTC(arranged)
+			case !once:
+				tc = w.tc
+				w.givenF(t, &tc)
+			}
+
+			WT(tc, p.When, p.Act, p.Then, p.Assert).New(t)(t)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package tbdd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWhenEach(t *testing.T) {
+	type counts struct {
+		a, b int
+	}
+
+	var arranges int
+	var got []string
+	record := func(t *testing.T, _ counts, r int) {
+		got = append(got, t.Name()[strings.Index(t.Name(), "/")+1:]+": "+string(rune('0'+r)))
+	}
+
+	w := WhenEach(
+		counts{}, "some counts", func(_ *testing.T, tc *counts) {
+			arranges++
+			tc.a, tc.b = 1, 2
+		},
+		WhenThen[counts, int]{"reading a", func(_ *testing.T, tc counts) int { return tc.a }, "it is one", record},
+		WhenThen[counts, int]{"reading b", func(_ *testing.T, tc counts) int { return tc.b }, "it is two", record},
+	)
+
+	w.New(t)(t)
+
+	exp := []string{
+		"given_some_counts/when_reading_a/then_it_is_one: 1",
+		"given_some_counts/when_reading_b/then_it_is_two: 2",
+	}
+	if arranges != 1 || !slices.Equal(got, exp) {
+		t.Errorf("expected one arrange and:\n%s\nbut got %d and:\n%s", strings.Join(exp, "\n"), arranges, strings.Join(got, "\n"))
+	}
+
+	//
+	// test cases which refer to mutable memory are arranged for every pair
+	// unless they are cloned
+	//
+
+	arranges = 0
+
+	mutate := func(_ *testing.T, tc *[]int) {
+		arranges++
+		*tc = append(*tc, 1)
+	}
+	grow := func(_ *testing.T, tc []int) int {
+		tc[0]++
+		return tc[0]
+	}
+	expectTwo := func(t *testing.T, _ []int, r int) {
+		if r != 2 {
+			t.Errorf("expected 2 but got %d", r)
+		}
+	}
+
+	pairs := []WhenThen[[]int, int]{
+		{"growing it", grow, "it is two", expectTwo},
+		{"growing it again", grow, "it is still two", expectTwo},
+	}
+
+	WhenEach([]int(nil), "a slice", mutate, pairs...).New(t)(t)
+	if arranges != 2 {
+		t.Errorf("expected 2 arranges but got %d", arranges)
+	}
+
+	arranges = 0
+
+	WhenEach([]int(nil), "a cloned slice", mutate, pairs...).CloneTC(slices.Clone).New(t)(t)
+	if arranges != 1 {
+		t.Errorf("expected 1 arrange but got %d", arranges)
+	}
+}
+
+func TestWhenEach_panics(t *testing.T) {
+	given := func(*testing.T, *int) {}
+	act := func(*testing.T, int) int { return 0 }
+	assert := func(*testing.T, int, int) {}
+
+	tests := []struct {
+		name  string
+		given string
+		f     func(*testing.T, *int)
+		pair  WhenThen[int, int]
+		exp   string
+	}{
+		{"empty given", "", given, WhenThen[int, int]{"w", act, "t", assert}, "tbdd.WhenEach: given description must be non-empty"},
+		{"nil given", "g", nil, WhenThen[int, int]{"w", act, "t", assert}, "tbdd.WhenEach: given function must be non-nil"},
+		{"empty when", "g", given, WhenThen[int, int]{"", act, "t", assert}, "tbdd.WhenEach: pair 1: when description must be non-empty"},
+		{"nil act", "g", given, WhenThen[int, int]{"w", nil, "t", assert}, "tbdd.WhenEach: pair 1: when function must be non-nil"},
+		{"empty then", "g", given, WhenThen[int, int]{"w", act, "", assert}, "tbdd.WhenEach: pair 1: then description must be non-empty"},
+		{"nil assert", "g", given, WhenThen[int, int]{"w", act, "t", nil}, "tbdd.WhenEach: pair 1: then function must be non-nil"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.exp {
+					t.Errorf("expected panic %q but got %v", tc.exp, r)
+				}
+			}()
+
+			WhenEach(0, tc.given, tc.f, WhenThen[int, int]{"w", act, "t", assert}, tc.pair)
+		})
+	}
+}